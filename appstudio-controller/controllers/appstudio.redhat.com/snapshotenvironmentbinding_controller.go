@@ -57,6 +57,11 @@ const (
 	applicationLabelKey = appstudioLabelKey + "/application"
 	componentLabelKey   = appstudioLabelKey + "/component"
 	environmentLabelKey = appstudioLabelKey + "/environment"
+
+	// If the 'snapshotImageOverrideAnnotationKey' annotation of a SnapshotEnvironmentBinding is "true", the child GitOpsDeployments
+	// of the SnapshotEnvironmentBinding will override the image of each component with the container image from the Snapshot,
+	// via a Kustomize image override.
+	snapshotImageOverrideAnnotationKey = appstudioLabelKey + "/snapshot-image-override"
 )
 
 // SnapshotEnvironmentBindingReconciler reconciles a SnapshotEnvironmentBinding object
@@ -73,6 +78,7 @@ type SnapshotEnvironmentBindingReconciler struct {
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=snapshotenvironmentbindings/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=snapshotenvironmentbindings/finalizers,verbs=update
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=environments,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=appstudio.redhat.com,resources=snapshots,verbs=get;list;watch
 //+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=gitopsdeployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;
 
@@ -181,6 +187,34 @@ func (r *SnapshotEnvironmentBindingReconciler) Reconcile(ctx context.Context, re
 		return ctrl.Result{}, nil
 	}
 
+	// If requested, retrieve the Snapshot, so that the container images of the components can be used by the GitOpsDeployments
+	var snapshot *appstudioshared.Snapshot
+	if binding.Annotations[snapshotImageOverrideAnnotationKey] == "true" {
+
+		snapshot = &appstudioshared.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      binding.Spec.Snapshot,
+				Namespace: binding.Namespace,
+			},
+		}
+		if err := rClient.Get(ctx, client.ObjectKeyFromObject(snapshot), snapshot); err != nil {
+
+			userError := "Unable to retrieve Snapshot '" + snapshot.Name + "' referenced by SnapshotEnvironmentBinding"
+			if apierr.IsNotFound(err) {
+				userError = "Snapshot '" + snapshot.Name + "' referenced by SnapshotEnvironmentBinding does not exist"
+			}
+
+			if err := updateBindingConditionOfSEB(ctx, rClient, userError,
+				binding, SnapshotEnvironmentBindingConditionErrorOccurred, metav1.ConditionTrue, SnapshotEnvironmentBindingReasonErrorOccurred, log); err != nil {
+
+				log.Error(err, "unable to update snapshotEnvironmentBinding status condition.")
+				return ctrl.Result{}, fmt.Errorf("unable to update snapshotEnvironmentBinding status condition. %v", err)
+			}
+
+			return ctrl.Result{RequeueAfter: time.Second * 10}, fmt.Errorf("unable to retrieve Snapshot '%s' referenced by Binding: %v", snapshot.Name, err)
+		}
+	}
+
 	// map: componentName (string) -> expected GitOpsDeployment for that component name
 	expectedDeployments := map[string]apibackend.GitOpsDeployment{}
 
@@ -200,7 +234,7 @@ func (r *SnapshotEnvironmentBindingReconciler) Reconcile(ctx context.Context, re
 		}
 
 		var userDevErr gitopserrors.UserError
-		expectedDeployments[component.Name], userDevErr = generateExpectedGitOpsDeployment(ctx, component, *binding, environment, rClient, log)
+		expectedDeployments[component.Name], userDevErr = generateExpectedGitOpsDeployment(ctx, component, *binding, environment, snapshot, rClient, log)
 
 		// If an error occurred while generating the GitOpsDeployment, report the error back to the user
 		if userDevErr != nil {
@@ -475,9 +509,12 @@ func GenerateBindingGitOpsDeploymentName(binding appstudioshared.SnapshotEnviron
 
 }
 
+// generateExpectedGitOpsDeployment generates the GitOpsDeployment that is expected for a component of the binding.
+// If snapshot is non-nil, the container image of the component in the Snapshot is used as a Kustomize image override.
 func generateExpectedGitOpsDeployment(ctx context.Context, component appstudioshared.BindingComponentStatus,
 	binding appstudioshared.SnapshotEnvironmentBinding,
 	environment appstudioshared.Environment,
+	snapshot *appstudioshared.Snapshot,
 	k8sClient client.Client, logger logr.Logger) (apibackend.GitOpsDeployment, gitopserrors.UserError) {
 
 	res := apibackend.GitOpsDeployment{
@@ -561,6 +598,18 @@ func generateExpectedGitOpsDeployment(ctx context.Context, component appstudiosh
 	// Else if neither of the above is true, it's necessarily just an Environment with no credentials specified,
 	// which means we should just deploy to the same Namespace as the Environment CR itself.
 
+	// Override the image of the component with the container image from the Snapshot, if available
+	if snapshot != nil {
+		for _, snapshotComponent := range snapshot.Spec.Components {
+			if snapshotComponent.Name == component.Name && snapshotComponent.ContainerImage != "" {
+				res.Spec.Source.Kustomize = &apibackend.ApplicationSourceKustomize{
+					Images: []string{snapshotComponent.ContainerImage},
+				}
+				break
+			}
+		}
+	}
+
 	res.ObjectMeta.Labels = make(map[string]string)
 
 	// Append ASEB labels with key "appstudio.openshift.io" to the gitopsDeployment labels
@@ -180,6 +180,77 @@ var _ = Describe("SnapshotEnvironmentBinding Reconciler Tests", func() {
 			Expect(gitopsDeploymentFirst).To(Equal(gitopsDeploymentSecond))
 		})
 
+		It("Should set the Kustomize image of the GitOpsDeployment from the Snapshot, if the Binding requests it.", func() {
+
+			By("creating a Snapshot containing the container image of the component")
+			snapshot := &appstudiosharedv1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      binding.Spec.Snapshot,
+					Namespace: binding.Namespace,
+				},
+				Spec: appstudiosharedv1.SnapshotSpec{
+					Application: binding.Spec.Application,
+					Components: []appstudiosharedv1.SnapshotComponent{
+						{Name: "component-a", ContainerImage: "quay.io/redhat-appstudio/user-workload:component-a-v2"},
+					},
+				},
+			}
+			err := bindingReconciler.Create(ctx, snapshot)
+			Expect(err).To(BeNil())
+
+			By("creating a Binding that requests the image override")
+			binding.Annotations = map[string]string{snapshotImageOverrideAnnotationKey: "true"}
+			err = bindingReconciler.Create(ctx, binding)
+			Expect(err).To(BeNil())
+
+			_, err = bindingReconciler.Reconcile(ctx, request)
+			Expect(err).To(BeNil())
+
+			gitopsDeployment := &apibackend.GitOpsDeployment{}
+			err = bindingReconciler.Get(ctx, client.ObjectKey{
+				Namespace: binding.Namespace,
+				Name:      GenerateBindingGitOpsDeploymentName(*binding, binding.Spec.Components[0].Name),
+			}, gitopsDeployment)
+			Expect(err).To(BeNil())
+
+			Expect(gitopsDeployment.Spec.Source.Path).To(Equal(binding.Status.Components[0].GitOpsRepository.Path))
+			Expect(gitopsDeployment.Spec.Source.Kustomize).To(Equal(&apibackend.ApplicationSourceKustomize{
+				Images: []string{"quay.io/redhat-appstudio/user-workload:component-a-v2"},
+			}))
+		})
+
+		It("Should not set the Kustomize image of the GitOpsDeployment, if the Binding does not request it.", func() {
+
+			err := bindingReconciler.Create(ctx, binding)
+			Expect(err).To(BeNil())
+
+			_, err = bindingReconciler.Reconcile(ctx, request)
+			Expect(err).To(BeNil())
+
+			gitopsDeployment := &apibackend.GitOpsDeployment{}
+			err = bindingReconciler.Get(ctx, client.ObjectKey{
+				Namespace: binding.Namespace,
+				Name:      GenerateBindingGitOpsDeploymentName(*binding, binding.Spec.Components[0].Name),
+			}, gitopsDeployment)
+			Expect(err).To(BeNil())
+			Expect(gitopsDeployment.Spec.Source.Kustomize).To(BeNil())
+		})
+
+		It("Should return an error and set a Binding condition, if the Snapshot requested for image override does not exist.", func() {
+
+			binding.Annotations = map[string]string{snapshotImageOverrideAnnotationKey: "true"}
+			err := bindingReconciler.Create(ctx, binding)
+			Expect(err).To(BeNil())
+
+			_, err = bindingReconciler.Reconcile(ctx, request)
+			Expect(err).ToNot(BeNil())
+
+			err = bindingReconciler.Get(ctx, request.NamespacedName, binding)
+			Expect(err).To(BeNil())
+			Expect(binding.Status.BindingConditions).To(HaveLen(1))
+			Expect(binding.Status.BindingConditions[0].Message).To(ContainSubstring("Snapshot '" + binding.Spec.Snapshot + "'"))
+		})
+
 		It("Should revert GitOpsDeploymentObject if it's spec is different than Binding Component.", func() {
 			// Create SnapshotEnvironmentBinding CR in cluster.
 			err := bindingReconciler.Create(ctx, binding)
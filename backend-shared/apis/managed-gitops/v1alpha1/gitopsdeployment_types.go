@@ -17,12 +17,22 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // GitOpsDeploymentSpec defines the desired state of GitOpsDeployment
 type GitOpsDeploymentSpec struct {
-	Source ApplicationSource `json:"source"`
+	// Source is a reference to the location of the GitOps repository to deploy from.
+	// Exactly one of Source or Sources should be set.
+	Source ApplicationSource `json:"source,omitempty"`
+
+	// Sources is a list of references to the locations of the GitOps repositories (and/or Helm charts) to deploy from.
+	// This allows, for example, a Helm chart from one repository to be combined with values files from another
+	// repository, via the 'ref' field of a source.
+	// Exactly one of Source or Sources should be set.
+	Sources ApplicationSources `json:"sources,omitempty"`
 
 	// Destination is a reference to a target namespace/cluster to deploy to.
 	// This field may be empty: if it is empty, it is assumed that the destination
@@ -32,6 +42,11 @@ type GitOpsDeploymentSpec struct {
 	// SyncPolicy controls when and how a sync will be performed.
 	SyncPolicy *SyncPolicy `json:"syncPolicy,omitempty"`
 
+	// IgnoreDifferences is a list of resources, and fields of those resources, which should be ignored when comparing
+	// the live state of the resources with the desired state in the GitOps repository.
+	// For example: the replicas of a Deployment that is scaled by a HorizontalPodAutoscaler.
+	IgnoreDifferences []ResourceIgnoreDifferences `json:"ignoreDifferences,omitempty"`
+
 	// Two possible values:
 	// - Automated: whenever a new commit occurs in the GitOps repository, or the Argo CD Application is out of sync, Argo CD should be told to (re)synchronize.
 	// - Manual: Argo CD should never be told to resynchronize. Instead, synchronize operations will be triggered via GitOpsDeploymentSyncRun operations only.
@@ -40,6 +55,46 @@ type GitOpsDeploymentSpec struct {
 	// Note: This is somewhat of a placeholder for more advanced logic that can be implemented in the future.
 	// For an example of this type of logic, see the 'syncPolicy' field of Argo CD Application.
 	Type string `json:"type"`
+
+	// Suspend, if true, freezes the GitOpsDeployment without deleting it or its deployed resources: automated sync (and
+	// self-heal) is disabled, and GitOpsDeploymentSyncRuns that target the GitOpsDeployment are not processed.
+	// Setting Suspend back to false restores the previous behaviour.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DependsOn references other GitOpsDeployments, in the same namespace, which must be synced and healthy before
+	// this GitOpsDeployment is deployed. Until then, the WaitingForDependencies condition is true.
+	DependsOn []GitOpsDeploymentDependency `json:"dependsOn,omitempty"`
+
+	// DeletionPolicy determines what happens to the deployed resources when the GitOpsDeployment is deleted:
+	// - Delete (the default): the Argo CD Application, and the resources that it deployed, are deleted.
+	// - Orphan: the Argo CD Application is deleted, but the deployed resources are left in place. This allows the
+	//   resources to be adopted by another GitOpsDeployment, without downtime.
+	// - OrphanAndRemoveTracking: as Orphan, but the labels/annotations that Argo CD uses to track the deployed
+	//   resources are also removed from them.
+	// - See `GitOpsDeploymentDeletionPolicy_*`
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// GitOpsDeploymentDependency references a GitOpsDeployment that another GitOpsDeployment depends on
+type GitOpsDeploymentDependency struct {
+	// Name is the name of the GitOpsDeployment, in the same namespace
+	Name string `json:"name"`
+}
+
+// ResourceIgnoreDifferences contains a resource filter, and the fields of the matching resources which should be ignored
+// during comparison with the live state. At least one of JSONPointers, JQPathExpressions or ManagedFieldsManagers must be set.
+type ResourceIgnoreDifferences struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// JSONPointers is a list of JSON pointers (RFC 6901) to fields which should be ignored, for example '/spec/replicas'
+	JSONPointers []string `json:"jsonPointers,omitempty"`
+	// JQPathExpressions is a list of JQ path expressions to fields which should be ignored
+	JQPathExpressions []string `json:"jqPathExpressions,omitempty"`
+	// ManagedFieldsManagers is a list of trusted managers. Fields mutated by those managers will take precedence over the
+	// desired state defined in the GitOps repository, and won't be displayed in diffs.
+	ManagedFieldsManagers []string `json:"managedFieldsManagers,omitempty"`
 }
 
 // ApplicationSource contains all required information about the source of an application
@@ -47,11 +102,61 @@ type ApplicationSource struct {
 	// RepoURL is the URL to the repository (Git or Helm) that contains the application manifests
 	RepoURL string `json:"repoURL"`
 	// Path is a directory path within the Git repository, and is only valid for applications sourced from Git.
-	Path string `json:"path"`
+	// Path is required, unless Chart is set.
+	Path string `json:"path,omitempty"`
 	// TargetRevision defines the revision of the source to sync the application to.
 	// In case of Git, this can be commit, tag, or branch. If omitted, will equal to HEAD.
 	// In case of Helm, this is a semver tag for the Chart's version.
 	TargetRevision string `json:"targetRevision,omitempty"`
+	// Chart is a Helm chart name, and must be specified for applications sourced from a Helm repository.
+	Chart string `json:"chart,omitempty"`
+	// Helm holds Helm specific options
+	Helm *ApplicationSourceHelm `json:"helm,omitempty"`
+	// Kustomize holds Kustomize specific options
+	Kustomize *ApplicationSourceKustomize `json:"kustomize,omitempty"`
+	// Ref is a reference to this source, for use by the other sources of a multi-source GitOpsDeployment.
+	// For example, a source with 'ref: values' may be referenced by a Helm value file of another source, as
+	// '$values/path/to/values.yaml'. Only valid within .spec.sources.
+	Ref string `json:"ref,omitempty"`
+}
+
+// ApplicationSourceHelm holds Helm specific options
+type ApplicationSourceHelm struct {
+	// ValueFiles is a list of Helm value files to use when generating a template
+	ValueFiles []string `json:"valueFiles,omitempty"`
+	// Parameters is a list of Helm parameters which are passed to the helm template command upon manifest generation
+	Parameters []HelmParameter `json:"parameters,omitempty"`
+	// ReleaseName is the Helm release name to use. If omitted it will use the application name
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Values specifies Helm values to be passed to helm template, typically defined as a block
+	Values string `json:"values,omitempty"`
+}
+
+// ApplicationSourceKustomize holds Kustomize specific options
+type ApplicationSourceKustomize struct {
+	// NamePrefix is a prefix appended to resources for Kustomize apps
+	NamePrefix string `json:"namePrefix,omitempty"`
+	// NameSuffix is a suffix appended to resources for Kustomize apps
+	NameSuffix string `json:"nameSuffix,omitempty"`
+	// Images is a list of Kustomize image override specifications, for example 'quay.io/org/app:v2' or
+	// 'quay.io/org/app=quay.io/other/app:v2'
+	Images []string `json:"images,omitempty"`
+	// CommonLabels is a list of additional labels to add to rendered manifests
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+	// CommonAnnotations is a list of additional annotations to add to rendered manifests
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+	// Version controls which version of Kustomize to use for rendering manifests
+	Version string `json:"version,omitempty"`
+}
+
+// HelmParameter is a parameter that's passed to helm template during manifest generation
+type HelmParameter struct {
+	// Name is the name of the Helm parameter
+	Name string `json:"name"`
+	// Value is the value for the Helm parameter
+	Value string `json:"value,omitempty"`
+	// ForceString determines whether to tell Helm to interpret booleans and numbers as strings
+	ForceString bool `json:"forceString,omitempty"`
 }
 
 // ApplicationDestination holds information about the application's destination
@@ -74,12 +179,70 @@ type SyncOption string
 const (
 	SyncOptions_CreateNamespace_true  SyncOption = "CreateNamespace=true"
 	SyncOptions_CreateNamespace_false SyncOption = "CreateNamespace=false"
+
+	SyncOptions_ServerSideApply_true  SyncOption = "ServerSideApply=true"
+	SyncOptions_ServerSideApply_false SyncOption = "ServerSideApply=false"
+
+	SyncOptions_PruneLast_true  SyncOption = "PruneLast=true"
+	SyncOptions_PruneLast_false SyncOption = "PruneLast=false"
+
+	SyncOptions_ApplyOutOfSyncOnly_true  SyncOption = "ApplyOutOfSyncOnly=true"
+	SyncOptions_ApplyOutOfSyncOnly_false SyncOption = "ApplyOutOfSyncOnly=false"
+
+	SyncOptions_Replace_true  SyncOption = "Replace=true"
+	SyncOptions_Replace_false SyncOption = "Replace=false"
+
+	SyncOptions_Validate_true  SyncOption = "Validate=true"
+	SyncOptions_Validate_false SyncOption = "Validate=false"
+
+	SyncOptions_RespectIgnoreDifferences_true  SyncOption = "RespectIgnoreDifferences=true"
+	SyncOptions_RespectIgnoreDifferences_false SyncOption = "RespectIgnoreDifferences=false"
+
+	SyncOptions_PrunePropagationPolicy_foreground SyncOption = "PrunePropagationPolicy=foreground"
+	SyncOptions_PrunePropagationPolicy_background SyncOption = "PrunePropagationPolicy=background"
+	SyncOptions_PrunePropagationPolicy_orphan     SyncOption = "PrunePropagationPolicy=orphan"
 )
 
+// supportedSyncOptions is the list of all SyncOptions that may be specified in .spec.syncPolicy.syncOptions
+var supportedSyncOptions = []SyncOption{
+	SyncOptions_CreateNamespace_true, SyncOptions_CreateNamespace_false,
+	SyncOptions_ServerSideApply_true, SyncOptions_ServerSideApply_false,
+	SyncOptions_PruneLast_true, SyncOptions_PruneLast_false,
+	SyncOptions_ApplyOutOfSyncOnly_true, SyncOptions_ApplyOutOfSyncOnly_false,
+	SyncOptions_Replace_true, SyncOptions_Replace_false,
+	SyncOptions_Validate_true, SyncOptions_Validate_false,
+	SyncOptions_RespectIgnoreDifferences_true, SyncOptions_RespectIgnoreDifferences_false,
+	SyncOptions_PrunePropagationPolicy_foreground, SyncOptions_PrunePropagationPolicy_background, SyncOptions_PrunePropagationPolicy_orphan,
+}
+
+// Key returns the name of the sync option, for example 'CreateNamespace' for 'CreateNamespace=true'.
+func (syncOption SyncOption) Key() string {
+	return strings.SplitN(string(syncOption), "=", 2)[0]
+}
+
 type SyncPolicy struct {
+	// Automated controls the behaviour of automated syncs, and may only be set when the GitOpsDeployment type is 'automated'.
+	// If not specified, prune, selfHeal and allowEmpty are all enabled.
+	Automated *SyncPolicyAutomated `json:"automated,omitempty"`
+
 	// Options allow you to specify whole app sync-options.
 	// This option may be empty, if and when it is empty it is considered that there are no SyncOptions present.
 	SyncOptions SyncOptions `json:"syncOptions,omitempty"`
+
+	// Retry controls failed sync retry behaviour, and may only be set when the GitOpsDeployment type is 'automated'.
+	// If not specified, failed syncs are retried indefinitely, with a backoff of 5s, a factor of 2, and a max duration of 3m.
+	Retry *RetryStrategy `json:"retry,omitempty"`
+}
+
+// SyncPolicyAutomated controls the behaviour of an automated sync.
+// Fields that are not specified default to true.
+type SyncPolicyAutomated struct {
+	// Prune specifies whether to delete resources from the cluster that are no longer found in the source
+	Prune *bool `json:"prune,omitempty"`
+	// SelfHeal specifies whether to revert resources back to their desired state upon modification in the cluster
+	SelfHeal *bool `json:"selfHeal,omitempty"`
+	// AllowEmpty allows the GitOpsDeployment to have zero live resources
+	AllowEmpty *bool `json:"allowEmpty,omitempty"`
 }
 type SyncOptions []SyncOption
 
@@ -88,6 +251,18 @@ const (
 	GitOpsDeploymentSpecType_Manual    = "manual"
 )
 
+const (
+	GitOpsDeploymentDeletionPolicy_Delete                  = "Delete"
+	GitOpsDeploymentDeletionPolicy_Orphan                  = "Orphan"
+	GitOpsDeploymentDeletionPolicy_OrphanAndRemoveTracking = "OrphanAndRemoveTracking"
+)
+
+// IsOrphanDeletionPolicy returns true if the resources deployed by a GitOpsDeployment with the given deletion policy should be left
+// in place when it is deleted.
+func IsOrphanDeletionPolicy(deletionPolicy string) bool {
+	return deletionPolicy == GitOpsDeploymentDeletionPolicy_Orphan || deletionPolicy == GitOpsDeploymentDeletionPolicy_OrphanAndRemoveTracking
+}
+
 func SyncOptionToStringSlice(syncOptions SyncOptions) []string {
 	if syncOptions == nil {
 		return nil
@@ -114,9 +289,42 @@ type ResourceStatus struct {
 	Health    *HealthStatus  `json:"health,omitempty"`
 }
 
+// MaxDriftedResources is the maximum number of drifted resources that are listed in .status.drift.resources
+const MaxDriftedResources = 20
+
+// DriftStatus summarizes the resources of a GitOpsDeployment that are OutOfSync with the GitOps repository
+type DriftStatus struct {
+	// DriftedResourceCount is the total number of resources that are OutOfSync
+	DriftedResourceCount int `json:"driftedResourceCount"`
+	// Kinds contains the number of OutOfSync resources of each kind
+	Kinds []DriftKindCount `json:"kinds,omitempty"`
+	// Resources contains the first OutOfSync resources (at most MaxDriftedResources), ordered by group, kind,
+	// namespace, and name
+	Resources []DriftedResource `json:"resources,omitempty"`
+	// DetectedAt is the time at which drift was first detected, since the resources last matched the GitOps repository
+	DetectedAt metav1.Time `json:"detectedAt"`
+}
+
+// DriftKindCount contains the number of OutOfSync resources of a kind
+type DriftKindCount struct {
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// DriftedResource identifies a resource that is OutOfSync with the GitOps repository
+type DriftedResource struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
 // ReconciledState contains the last version of the GitOpsDeployment resource that the ArgoCD Controller reconciled
 type ReconciledState struct {
-	Source      GitOpsDeploymentSource      `json:"source"`
+	Source GitOpsDeploymentSource `json:"source"`
+	// Sources contains the sources of a multi-source GitOpsDeployment, in the same order as .spec.sources
+	Sources     []GitOpsDeploymentSource    `json:"sources,omitempty"`
 	Destination GitOpsDeploymentDestination `json:"destination"`
 }
 
@@ -136,6 +344,10 @@ type GitOpsDeploymentDestination struct {
 
 // GitOpsDeploymentStatus defines the observed state of GitOpsDeployment
 type GitOpsDeploymentStatus struct {
+	// ObservedGeneration is the most recent generation of the GitOpsDeployment spec that has been processed: if it is
+	// lower than .metadata.generation, the status does not (yet) reflect the latest changes to the spec.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	Conditions []GitOpsDeploymentCondition `json:"conditions,omitempty"`
 	Sync       SyncStatus                  `json:"sync,omitempty"`
 	// Health contains information about the application's current health status
@@ -144,11 +356,41 @@ type GitOpsDeploymentStatus struct {
 	// List of Resource created by a deployment
 	Resources []ResourceStatus `json:"resources,omitempty" protobuf:"bytes,1,opt,name=resources"`
 
+	// Drift summarizes the resources of the GitOpsDeployment that do not match the GitOps repository. It is not set
+	// if all resources match.
+	Drift *DriftStatus `json:"drift,omitempty"`
+
 	// ReconciledState contains the last version of the GitOpsDeployment resource that the ArgoCD Controller reconciled
 	ReconciledState ReconciledState `json:"reconciledState"`
 
 	// OperationState contains information about any ongoing operations, such as a sync
 	OperationState *OperationState `json:"operationState,omitempty"`
+
+	// History contains information about the most recent successful syncs of the GitOpsDeployment, ordered from the
+	// most recent to the oldest.
+	History []RevisionHistory `json:"history,omitempty"`
+}
+
+// RevisionHistory contains information about a successful sync of a GitOpsDeployment
+type RevisionHistory struct {
+	// ID identifies the entry in the deployment history of the Argo CD Application. It may be used as the
+	// 'rollbackToHistoryID' of a GitOpsDeploymentSyncRun.
+	ID *int64 `json:"id,omitempty"`
+	// Revision is the revision that was deployed, for example the Git commit SHA
+	Revision string `json:"revision"`
+	// Revisions contains the revision of each source that was deployed, for multi-source GitOpsDeployments
+	Revisions []string `json:"revisions,omitempty"`
+	// Source is the source that was deployed
+	Source GitOpsDeploymentSource `json:"source"`
+	// Sources contains the sources that were deployed, for multi-source GitOpsDeployments
+	Sources []GitOpsDeploymentSource `json:"sources,omitempty"`
+	// DeployedAt is the time at which the sync operation finished
+	DeployedAt metav1.Time `json:"deployedAt"`
+	// InitiatedBy is either 'automated', for syncs that were started by the automated sync policy, or the name of the
+	// user that requested the sync
+	InitiatedBy string `json:"initiatedBy,omitempty"`
+	// SyncRunName is the name of the GitOpsDeploymentSyncRun that requested the sync, if any
+	SyncRunName string `json:"syncRunName,omitempty"`
 }
 
 // OperationState contains information about state of a running operation
@@ -189,7 +431,8 @@ type Info struct {
 // RetryStrategy contains information about the strategy to apply when a sync failed
 type RetryStrategy struct {
 	// Limit is the maximum number of attempts for retrying a failed sync. If set to 0, no retries will be performed.
-	Limit int64 `json:"limit,omitempty" protobuf:"bytes,1,opt,name=limit"`
+	// If not set, or set to a negative value, failed syncs are retried indefinitely.
+	Limit *int64 `json:"limit,omitempty" protobuf:"bytes,1,opt,name=limit"`
 	// Backoff controls how to backoff on subsequent retries of failed syncs
 	Backoff *Backoff `json:"backoff,omitempty" protobuf:"bytes,2,opt,name=backoff,casttype=Backoff"`
 }
@@ -389,6 +632,9 @@ type SyncStatus struct {
 	Status SyncStatusCode `json:"status"`
 	// Revision contains information about the revision the comparison has been performed to
 	Revision string `json:"revision,omitempty"`
+	// Revisions contains the revision the comparison has been performed to, for each source of a multi-source
+	// GitOpsDeployment, in the same order as .spec.sources
+	Revisions []string `json:"revisions,omitempty"`
 }
 
 // SyncStatusCode is a type which represents possible comparison results
@@ -427,6 +673,10 @@ type GitOpsDeploymentCondition struct {
 	// Reason is a unique, one-word, CamelCase reason for the condition's last transition.
 	// +optional
 	Reason GitOpsDeploymentReasonType `json:"reason"`
+
+	// ObservedGeneration is the .status.observedGeneration of the GitOpsDeployment, at the time the condition was set.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // GitOpsDeploymentConditionType represents type of GitOpsDeployment condition.
@@ -435,6 +685,24 @@ type GitOpsDeploymentConditionType string
 const (
 	GitOpsDeploymentConditionSyncError     GitOpsDeploymentConditionType = "SyncError"
 	GitOpsDeploymentConditionErrorOccurred GitOpsDeploymentConditionType = "ErrorOccurred"
+	GitOpsDeploymentConditionSuspended     GitOpsDeploymentConditionType = "Suspended"
+
+	// GitOpsDeploymentConditionSynced is true if the resources on the target cluster match the GitOps repository
+	GitOpsDeploymentConditionSynced GitOpsDeploymentConditionType = "Synced"
+	// GitOpsDeploymentConditionHealthy is true if the deployed resources are healthy
+	GitOpsDeploymentConditionHealthy GitOpsDeploymentConditionType = "Healthy"
+	// GitOpsDeploymentConditionProgressing is true while a sync operation is running, or the deployed resources are
+	// progressing towards a healthy state
+	GitOpsDeploymentConditionProgressing GitOpsDeploymentConditionType = "Progressing"
+	// GitOpsDeploymentConditionReady is true if the GitOpsDeployment is synced and healthy, is not progressing, and
+	// has no errors
+	GitOpsDeploymentConditionReady GitOpsDeploymentConditionType = "Ready"
+	// GitOpsDeploymentConditionWaitingForDependencies is true while the GitOpsDeployment is waiting for the
+	// GitOpsDeployments that it depends on (.spec.dependsOn) to be synced and healthy
+	GitOpsDeploymentConditionWaitingForDependencies GitOpsDeploymentConditionType = "WaitingForDependencies"
+	// GitOpsDeploymentConditionDriftDetected is true if one or more of the resources on the target cluster are
+	// OutOfSync with the GitOps repository: see .status.drift for details
+	GitOpsDeploymentConditionDriftDetected GitOpsDeploymentConditionType = "DriftDetected"
 )
 
 // GitOpsConditionStatus is a type which represents possible comparison results
@@ -455,11 +723,64 @@ type GitOpsDeploymentReasonType string
 const (
 	GitopsDeploymentReasonSyncError     GitOpsDeploymentReasonType = "SyncError"
 	GitopsDeploymentReasonErrorOccurred GitOpsDeploymentReasonType = "ErrorOccurred"
+	GitopsDeploymentReasonSuspended     GitOpsDeploymentReasonType = "Suspended"
+	GitopsDeploymentReasonResumed       GitOpsDeploymentReasonType = "Resumed"
+
+	GitopsDeploymentReasonSynced            GitOpsDeploymentReasonType = "Synced"
+	GitopsDeploymentReasonOutOfSync         GitOpsDeploymentReasonType = "OutOfSync"
+	GitopsDeploymentReasonSyncStatusUnknown GitOpsDeploymentReasonType = "SyncStatusUnknown"
+	GitopsDeploymentReasonHealthy           GitOpsDeploymentReasonType = "Healthy"
+	GitopsDeploymentReasonUnhealthy         GitOpsDeploymentReasonType = "Unhealthy"
+	GitopsDeploymentReasonHealthUnknown     GitOpsDeploymentReasonType = "HealthUnknown"
+	GitopsDeploymentReasonProgressing       GitOpsDeploymentReasonType = "Progressing"
+	GitopsDeploymentReasonSyncRunning       GitOpsDeploymentReasonType = "SyncRunning"
+	GitopsDeploymentReasonIdle              GitOpsDeploymentReasonType = "Idle"
+	GitopsDeploymentReasonReady             GitOpsDeploymentReasonType = "Ready"
+	GitopsDeploymentReasonNotReady          GitOpsDeploymentReasonType = "NotReady"
+
+	GitopsDeploymentReasonWaitingForDependencies GitOpsDeploymentReasonType = "WaitingForDependencies"
+	GitopsDeploymentReasonDependenciesSatisfied  GitOpsDeploymentReasonType = "DependenciesSatisfied"
+
+	GitopsDeploymentReasonDriftDetected GitOpsDeploymentReasonType = "DriftDetected"
+	GitopsDeploymentReasonNoDrift       GitOpsDeploymentReasonType = "NoDrift"
 )
 
 const (
 	GitOpsDeploymentUserError_InvalidPathSlash = "spec.source.path cannot be '/'"
 	GitOpsDeploymentUserError_PathIsRequired   = "spec.source.path is a required field and it cannot be empty"
+	GitOpsDeploymentUserError_PathAndChartSet  = "spec.source.path and spec.source.chart cannot both be set"
+
+	GitOpsDeploymentUserError_InvalidHelmParameter = "spec.source.helm.parameters must each have a non-empty name"
+	GitOpsDeploymentUserError_InvalidHelmValues    = "spec.source.helm.values must be a valid YAML object"
+
+	GitOpsDeploymentUserError_SourceAndSourcesSet   = "exactly one of spec.source and spec.sources must be set"
+	GitOpsDeploymentUserError_RefOutsideSources     = "spec.source.ref may only be set within spec.sources"
+	GitOpsDeploymentUserError_DuplicateSourceRef    = "spec.sources must not contain duplicate ref values"
+	GitOpsDeploymentUserError_UnknownSourceRef      = "spec.sources helm value files may only reference a ref defined by another source"
+	GitOpsDeploymentUserError_SourceRepoURLRequired = "spec.sources repoURL is a required field and it cannot be empty"
+
+	GitOpsDeploymentUserError_HelmAndKustomizeSet    = "spec.source.helm and spec.source.kustomize cannot both be set"
+	GitOpsDeploymentUserError_InvalidKustomizeImage  = "spec.source.kustomize.images must each be a non-empty image reference, without whitespace"
+	GitOpsDeploymentUserError_InvalidKustomizeLabels = "spec.source.kustomize.commonLabels and commonAnnotations must each have a non-empty key"
+
+	GitOpsDeploymentUserError_IgnoreDifferencesKindRequired = "spec.ignoreDifferences kind is a required field and it cannot be empty"
+	GitOpsDeploymentUserError_IgnoreDifferencesNoFields     = "spec.ignoreDifferences must each specify at least one of jsonPointers, jqPathExpressions or managedFieldsManagers"
+	GitOpsDeploymentUserError_InvalidJSONPointer            = "spec.ignoreDifferences jsonPointers must each begin with '/'"
+	GitOpsDeploymentUserError_InvalidIgnoreDifferencesEntry = "spec.ignoreDifferences jqPathExpressions and managedFieldsManagers must each be non-empty"
+
+	GitOpsDeploymentUserError_InvalidSyncOption     = "the specified sync option in .spec.syncPolicy.syncOptions is either mispelled or is not supported by GitOpsDeployment"
+	GitOpsDeploymentUserError_ConflictingSyncOption = "the sync options in .spec.syncPolicy.syncOptions must not specify conflicting values for the same option"
+
+	GitOpsDeploymentUserError_SyncPolicyOnManual          = "spec.syncPolicy.automated and spec.syncPolicy.retry may only be set when spec.type is 'automated'"
+	GitOpsDeploymentUserError_InvalidRetryBackoffDuration = "spec.syncPolicy.retry.backoff.duration and maxDuration must be a number of seconds, or a duration such as '2m'"
+	GitOpsDeploymentUserError_InvalidRetryBackoffFactor   = "spec.syncPolicy.retry.backoff.factor must be at least 1"
+
+	GitOpsDeploymentUserError_DependsOnNameRequired = "spec.dependsOn name is a required field and it cannot be empty"
+	GitOpsDeploymentUserError_DependsOnSelf         = "spec.dependsOn cannot reference the GitOpsDeployment itself"
+	GitOpsDeploymentUserError_DuplicateDependsOn    = "spec.dependsOn must not contain duplicate names"
+	GitOpsDeploymentUserError_DependencyCycle       = "spec.dependsOn must not contain a dependency cycle"
+
+	GitOpsDeploymentUserError_InvalidDeletionPolicy = "spec.deletionPolicy must be one of 'Delete', 'Orphan' or 'OrphanAndRemoveTracking'"
 )
 
 // +kubebuilder:object:root=true
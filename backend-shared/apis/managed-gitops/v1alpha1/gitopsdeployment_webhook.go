@@ -17,18 +17,26 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
 
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 const (
 	error_nonempty_namespace_empty_environment = "the environment field should not be empty when the namespace is non-empty"
-	error_invalid_sync_option                  = "the specified sync option in .spec.syncPolicy.syncOptions is either mispelled or is not supported by GitOpsDeployment"
+	error_invalid_sync_option                  = GitOpsDeploymentUserError_InvalidSyncOption
 	error_invalid_spec_type                    = "spec type must be manual or automated"
 )
 
@@ -41,6 +49,22 @@ func (r *GitOpsDeployment) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		Complete()
 }
 
+// GitOpsDeploymentSourceValidator verifies that the source of a GitOpsDeployment exists, for example, that its
+// repository is reachable and its revision resolves. The error message is suitable to be returned to the user.
+// +kubebuilder:object:generate=false
+type GitOpsDeploymentSourceValidator interface {
+	ValidateSource(ctx context.Context, gitopsDeployment *GitOpsDeployment) error
+}
+
+// SetupWebhookWithSourceValidator registers the webhooks of GitOpsDeployment, as SetupWebhookWithManager does, but the
+// validating webhook also verifies the source of a GitOpsDeployment when it is created, or its source is modified.
+func (r *GitOpsDeployment) SetupWebhookWithSourceValidator(mgr ctrl.Manager, sourceValidator GitOpsDeploymentSourceValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&gitopsDeploymentSourceValidatingWebhook{sourceValidator: sourceValidator}).
+		Complete()
+}
+
 //+kubebuilder:webhook:path=/mutate-managed-gitops-redhat-com-v1alpha1-gitopsdeployment,mutating=true,failurePolicy=fail,sideEffects=None,groups=managed-gitops.redhat.com,resources=gitopsdeployments,verbs=create;update,versions=v1alpha1,name=mgitopsdeployment.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Defaulter = &GitOpsDeployment{}
@@ -84,6 +108,57 @@ func (r *GitOpsDeployment) ValidateDelete() error {
 	return nil
 }
 
+var _ admission.CustomValidator = &gitopsDeploymentSourceValidatingWebhook{}
+
+// gitopsDeploymentSourceValidatingWebhook validates a GitOpsDeployment as the GitOpsDeployment webhook.Validator does, and
+// then verifies its source using a GitOpsDeploymentSourceValidator.
+type gitopsDeploymentSourceValidatingWebhook struct {
+	sourceValidator GitOpsDeploymentSourceValidator
+}
+
+func (w *gitopsDeploymentSourceValidatingWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	gitopsDeployment, ok := obj.(*GitOpsDeployment)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+
+	if err := gitopsDeployment.ValidateCreate(); err != nil {
+		return err
+	}
+
+	return w.sourceValidator.ValidateSource(ctx, gitopsDeployment)
+}
+
+func (w *gitopsDeploymentSourceValidatingWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	gitopsDeployment, ok := newObj.(*GitOpsDeployment)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", newObj)
+	}
+
+	if err := gitopsDeployment.ValidateUpdate(oldObj); err != nil {
+		return err
+	}
+
+	// Only verify the source if it was modified: otherwise, for example, the finalizer of a GitOpsDeployment could not
+	// be removed once its repository has been deleted.
+	if oldGitOpsDeployment, ok := oldObj.(*GitOpsDeployment); ok &&
+		reflect.DeepEqual(oldGitOpsDeployment.Spec.Source, gitopsDeployment.Spec.Source) &&
+		reflect.DeepEqual(oldGitOpsDeployment.Spec.Sources, gitopsDeployment.Spec.Sources) {
+		return nil
+	}
+
+	return w.sourceValidator.ValidateSource(ctx, gitopsDeployment)
+}
+
+func (w *gitopsDeploymentSourceValidatingWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	gitopsDeployment, ok := obj.(*GitOpsDeployment)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+
+	return gitopsDeployment.ValidateDelete()
+}
+
 func (r *GitOpsDeployment) ValidateGitOpsDeployment() error {
 
 	// Check whether Type is manual or automated
@@ -93,19 +168,328 @@ func (r *GitOpsDeployment) ValidateGitOpsDeployment() error {
 
 	// Check whether sync options are valid
 	if r.Spec.SyncPolicy != nil {
-		for _, syncOptionString := range r.Spec.SyncPolicy.SyncOptions {
+		if err := ValidateSyncOptions(r.Spec.SyncPolicy.SyncOptions); err != nil {
+			return err
+		}
+	}
 
-			if !(syncOptionString == SyncOptions_CreateNamespace_true ||
-				syncOptionString == SyncOptions_CreateNamespace_false) {
-				return fmt.Errorf(error_invalid_sync_option)
-			}
+	if err := ValidateSyncPolicy(r.Spec); err != nil {
+		return err
+	}
 
-		}
+	if err := ValidateIgnoreDifferences(r.Spec.IgnoreDifferences); err != nil {
+		return err
+	}
+
+	if err := ValidateDependsOn(r.Name, r.Spec.DependsOn); err != nil {
+		return err
+	}
+
+	if err := ValidateDeletionPolicy(r.Spec.DeletionPolicy); err != nil {
+		return err
 	}
 
 	if r.Spec.Destination.Environment == "" && r.Spec.Destination.Namespace != "" {
 		return fmt.Errorf(error_nonempty_namespace_empty_environment)
 	}
 
+	if len(r.Spec.Sources) > 0 {
+		if !reflect.DeepEqual(r.Spec.Source, ApplicationSource{}) {
+			return fmt.Errorf(GitOpsDeploymentUserError_SourceAndSourcesSet)
+		}
+
+		if err := ValidateApplicationSources(r.Spec.Sources); err != nil {
+			return err
+		}
+
+	} else {
+		if r.Spec.Source.Ref != "" {
+			return fmt.Errorf(GitOpsDeploymentUserError_RefOutsideSources)
+		}
+
+		if err := ValidateApplicationSource(r.Spec.Source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateIgnoreDifferences returns an error if any of the ignoreDifferences entries of a GitOpsDeployment is invalid.
+// The error message is suitable to be returned to the user.
+func ValidateIgnoreDifferences(ignoreDifferences []ResourceIgnoreDifferences) error {
+
+	for _, ignoreDifference := range ignoreDifferences {
+
+		if strings.TrimSpace(ignoreDifference.Kind) == "" {
+			return fmt.Errorf(GitOpsDeploymentUserError_IgnoreDifferencesKindRequired)
+		}
+
+		if len(ignoreDifference.JSONPointers) == 0 && len(ignoreDifference.JQPathExpressions) == 0 &&
+			len(ignoreDifference.ManagedFieldsManagers) == 0 {
+			return fmt.Errorf(GitOpsDeploymentUserError_IgnoreDifferencesNoFields)
+		}
+
+		for _, jsonPointer := range ignoreDifference.JSONPointers {
+			if !strings.HasPrefix(jsonPointer, "/") {
+				return fmt.Errorf(GitOpsDeploymentUserError_InvalidJSONPointer)
+			}
+		}
+
+		for _, entries := range [][]string{ignoreDifference.JQPathExpressions, ignoreDifference.ManagedFieldsManagers} {
+			for _, entry := range entries {
+				if strings.TrimSpace(entry) == "" {
+					return fmt.Errorf(GitOpsDeploymentUserError_InvalidIgnoreDifferencesEntry)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateDependsOn returns an error if any of the dependencies of a GitOpsDeployment (named 'name') is invalid.
+// Dependency cycles that span multiple GitOpsDeployments are detected by the backend, when the GitOpsDeployment is
+// reconciled. The error message is suitable to be returned to the user.
+func ValidateDependsOn(name string, dependsOn []GitOpsDeploymentDependency) error {
+
+	dependencyNames := map[string]bool{}
+
+	for _, dependency := range dependsOn {
+
+		if strings.TrimSpace(dependency.Name) == "" {
+			return fmt.Errorf(GitOpsDeploymentUserError_DependsOnNameRequired)
+		}
+
+		if dependency.Name == name {
+			return fmt.Errorf(GitOpsDeploymentUserError_DependsOnSelf)
+		}
+
+		if dependencyNames[dependency.Name] {
+			return fmt.Errorf(GitOpsDeploymentUserError_DuplicateDependsOn)
+		}
+		dependencyNames[dependency.Name] = true
+	}
+
+	return nil
+}
+
+// ValidateDeletionPolicy returns an error if the deletion policy of a GitOpsDeployment is not supported. An empty
+// deletion policy is equivalent to 'Delete'. The error message is suitable to be returned to the user.
+func ValidateDeletionPolicy(deletionPolicy string) error {
+
+	switch deletionPolicy {
+	case "", GitOpsDeploymentDeletionPolicy_Delete, GitOpsDeploymentDeletionPolicy_Orphan, GitOpsDeploymentDeletionPolicy_OrphanAndRemoveTracking:
+		return nil
+	default:
+		return fmt.Errorf(GitOpsDeploymentUserError_InvalidDeletionPolicy)
+	}
+}
+
+// ValidateSyncOptions returns an error if any of the sync options is not supported, or if two sync options specify
+// conflicting values for the same option (for example, 'CreateNamespace=true' and 'CreateNamespace=false').
+// The error message is suitable to be returned to the user.
+func ValidateSyncOptions(syncOptions SyncOptions) error {
+
+	valuesByKey := map[string]SyncOption{}
+
+	for _, syncOption := range syncOptions {
+
+		supported := false
+		for _, supportedSyncOption := range supportedSyncOptions {
+			if syncOption == supportedSyncOption {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf(GitOpsDeploymentUserError_InvalidSyncOption)
+		}
+
+		if existing, exists := valuesByKey[syncOption.Key()]; exists && existing != syncOption {
+			return fmt.Errorf(GitOpsDeploymentUserError_ConflictingSyncOption)
+		}
+		valuesByKey[syncOption.Key()] = syncOption
+	}
+
+	return nil
+}
+
+// ValidateSyncPolicy returns an error if the automated sync policy or retry strategy of the GitOpsDeployment are invalid.
+// The error message is suitable to be returned to the user.
+func ValidateSyncPolicy(spec GitOpsDeploymentSpec) error {
+	if spec.SyncPolicy == nil {
+		return nil
+	}
+
+	if spec.SyncPolicy.Automated == nil && spec.SyncPolicy.Retry == nil {
+		return nil
+	}
+
+	if !strings.EqualFold(spec.Type, GitOpsDeploymentSpecType_Automated) {
+		return fmt.Errorf(GitOpsDeploymentUserError_SyncPolicyOnManual)
+	}
+
+	if spec.SyncPolicy.Retry == nil || spec.SyncPolicy.Retry.Backoff == nil {
+		return nil
+	}
+
+	backoff := spec.SyncPolicy.Retry.Backoff
+
+	for _, duration := range []string{backoff.Duration, backoff.MaxDuration} {
+		if duration == "" {
+			continue
+		}
+		// Matches how Argo CD parses backoff durations: either a number of seconds, or a Go duration string
+		if _, err := strconv.Atoi(duration); err == nil {
+			continue
+		}
+		if _, err := time.ParseDuration(duration); err != nil {
+			return fmt.Errorf(GitOpsDeploymentUserError_InvalidRetryBackoffDuration)
+		}
+	}
+
+	if backoff.Factor != nil && *backoff.Factor < 1 {
+		return fmt.Errorf(GitOpsDeploymentUserError_InvalidRetryBackoffFactor)
+	}
+
+	return nil
+}
+
+// ValidateApplicationSource returns an error if the fields of an ApplicationSource are inconsistent.
+// The error message is suitable to be returned to the user.
+func ValidateApplicationSource(source ApplicationSource) error {
+
+	if source.Chart != "" && source.Path != "" {
+		return fmt.Errorf(GitOpsDeploymentUserError_PathAndChartSet)
+	}
+
+	if err := ValidateApplicationSourceHelm(source.Helm); err != nil {
+		return err
+	}
+
+	if source.Helm != nil && source.Kustomize != nil {
+		return fmt.Errorf(GitOpsDeploymentUserError_HelmAndKustomizeSet)
+	}
+
+	if err := ValidateApplicationSourceKustomize(source.Kustomize); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateApplicationSources returns an error if the sources of a multi-source GitOpsDeployment are invalid, including
+// if a Helm value file references (via '$ref/...') a ref that is not defined by any of the sources.
+// The error message is suitable to be returned to the user.
+func ValidateApplicationSources(sources ApplicationSources) error {
+
+	refs := map[string]bool{}
+	for _, source := range sources {
+
+		if source.RepoURL == "" {
+			return fmt.Errorf(GitOpsDeploymentUserError_SourceRepoURLRequired)
+		}
+
+		if err := ValidateApplicationSource(source); err != nil {
+			return err
+		}
+
+		if err := ValidateApplicationSourcePath(source, true); err != nil {
+			return err
+		}
+
+		if source.Ref != "" {
+			if refs[source.Ref] {
+				return fmt.Errorf(GitOpsDeploymentUserError_DuplicateSourceRef)
+			}
+			refs[source.Ref] = true
+		}
+	}
+
+	for _, source := range sources {
+		if source.Helm == nil {
+			continue
+		}
+		for _, valueFile := range source.Helm.ValueFiles {
+			if !strings.HasPrefix(valueFile, "$") {
+				continue
+			}
+			ref := strings.SplitN(strings.TrimPrefix(valueFile, "$"), "/", 2)[0]
+			if !refs[ref] {
+				return fmt.Errorf(GitOpsDeploymentUserError_UnknownSourceRef)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateApplicationSourcePath returns an error if the path of an ApplicationSource is invalid. Within a multi-source
+// GitOpsDeployment, a source that defines a 'ref' (for example, a repository containing only Helm values files) does
+// not require a path. Sources that reference a Helm chart do not use a path.
+// The error message is suitable to be returned to the user.
+func ValidateApplicationSourcePath(source ApplicationSource, multiSource bool) error {
+
+	if source.Chart != "" {
+		return nil
+	}
+
+	if source.Path == "" {
+		if !multiSource || source.Ref == "" {
+			return fmt.Errorf(GitOpsDeploymentUserError_PathIsRequired)
+		}
+
+	} else if source.Path == "/" {
+		return fmt.Errorf(GitOpsDeploymentUserError_InvalidPathSlash)
+	}
+
+	return nil
+}
+
+// ValidateApplicationSourceHelm returns an error if the Helm options of an ApplicationSource are invalid.
+// The error message is suitable to be returned to the user.
+func ValidateApplicationSourceHelm(helm *ApplicationSourceHelm) error {
+	if helm == nil {
+		return nil
+	}
+
+	for _, param := range helm.Parameters {
+		if strings.TrimSpace(param.Name) == "" {
+			return fmt.Errorf(GitOpsDeploymentUserError_InvalidHelmParameter)
+		}
+	}
+
+	if helm.Values != "" {
+		values := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(helm.Values), &values); err != nil {
+			return fmt.Errorf(GitOpsDeploymentUserError_InvalidHelmValues)
+		}
+	}
+
+	return nil
+}
+
+// ValidateApplicationSourceKustomize returns an error if the Kustomize options of an ApplicationSource are invalid.
+// The error message is suitable to be returned to the user.
+func ValidateApplicationSourceKustomize(kustomize *ApplicationSourceKustomize) error {
+	if kustomize == nil {
+		return nil
+	}
+
+	for _, image := range kustomize.Images {
+		if image == "" || strings.ContainsAny(image, " \t\r\n") {
+			return fmt.Errorf(GitOpsDeploymentUserError_InvalidKustomizeImage)
+		}
+	}
+
+	for _, m := range []map[string]string{kustomize.CommonLabels, kustomize.CommonAnnotations} {
+		for key := range m {
+			if strings.TrimSpace(key) == "" {
+				return fmt.Errorf(GitOpsDeploymentUserError_InvalidKustomizeLabels)
+			}
+		}
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -70,6 +71,55 @@ var _ = Describe("GitOpsDeployment validation webhook", func() {
 
 	})
 
+	Context("Create GitOpsDeployment CR with Argo CD sync options in .spec.syncPolicy.syncOptions field", func() {
+		It("Should succeed when all the sync options are supported", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				SyncOptions: SyncOptions{
+					SyncOptions_ServerSideApply_true,
+					SyncOptions_PruneLast_true,
+					SyncOptions_ApplyOutOfSyncOnly_true,
+					SyncOptions_Validate_false,
+					SyncOptions_RespectIgnoreDifferences_true,
+					SyncOptions_PrunePropagationPolicy_foreground,
+				},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Succeed())
+
+			err = k8sClient.Delete(context.Background(), gitopsDepl)
+			Expect(err).To(BeNil())
+		})
+
+		It("Should fail with error saying that the sync options conflict", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				SyncOptions: SyncOptions{
+					SyncOptions_PrunePropagationPolicy_foreground,
+					SyncOptions_PrunePropagationPolicy_orphan,
+				},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_ConflictingSyncOption))
+		})
+
+		It("Should fail with error saying the sync option is not supported, for an unsupported value of a known option", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				SyncOptions: SyncOptions{
+					"PrunePropagationPolicy=sometimes",
+				},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(error_invalid_sync_option))
+		})
+	})
+
 	Context("Update GitOpsDeployment CR with invalid .spec.Type field", func() {
 		It("Should fail with error saying spec type must be manual or automated", func() {
 			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
@@ -149,4 +199,308 @@ var _ = Describe("GitOpsDeployment validation webhook", func() {
 			Expect(err).To(BeNil())
 		})
 	})
+
+	Context("Create GitOpsDeployment CR with both .spec.source.path and .spec.source.chart fields", func() {
+		It("Should fail with error saying the path and chart fields cannot both be set", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Path = "charts/my-chart"
+			gitopsDepl.Spec.Source.Chart = "my-chart"
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_PathAndChartSet))
+		})
+	})
+
+	Context("Create GitOpsDeployment CR with invalid .spec.source.helm field", func() {
+		It("Should fail with error saying the Helm parameters must have a name", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Helm = &ApplicationSourceHelm{
+				Parameters: []HelmParameter{{Name: "", Value: "value"}},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidHelmParameter))
+		})
+
+		It("Should fail with error saying the Helm values must be a valid YAML object", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Helm = &ApplicationSourceHelm{
+				Values: "- not\n- an object",
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidHelmValues))
+		})
+	})
+
+	Context("Create GitOpsDeployment CR with invalid .spec.source.kustomize field", func() {
+		It("Should fail with error saying Helm and Kustomize options cannot both be set", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Helm = &ApplicationSourceHelm{ReleaseName: "my-release"}
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{NamePrefix: "dev-"}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_HelmAndKustomizeSet))
+		})
+
+		It("Should fail with error saying the Kustomize images must be valid", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{
+				Images: []string{"quay.io/org/app: v2"},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidKustomizeImage))
+		})
+
+		It("Should fail with error saying the Kustomize common labels must have a key", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{
+				CommonLabels: map[string]string{"": "value"},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidKustomizeLabels))
+		})
+	})
+
+	Context("Create GitOpsDeployment CR with invalid .spec.sources field", func() {
+		It("Should fail with error saying that source and sources cannot both be set", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Sources = ApplicationSources{
+				{RepoURL: "https://charts.example.com", Chart: "my-chart", TargetRevision: "1.0.0"},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_SourceAndSourcesSet))
+		})
+
+		It("Should fail with error saying that ref may only be set within sources", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Ref = "values"
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_RefOutsideSources))
+		})
+
+		It("Should fail with error saying that Helm value files must reference a known ref", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source = ApplicationSource{}
+			gitopsDepl.Spec.Sources = ApplicationSources{
+				{
+					RepoURL: "https://charts.example.com", Chart: "my-chart", TargetRevision: "1.0.0",
+					Helm: &ApplicationSourceHelm{ValueFiles: []string{"$values/charts/my-chart/values.yaml"}},
+				},
+				{RepoURL: "https://github.com/redhat-appstudio/managed-gitops", Ref: "other"},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_UnknownSourceRef))
+		})
+
+		It("Should fail with error saying that refs must be unique", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source = ApplicationSource{}
+			gitopsDepl.Spec.Sources = ApplicationSources{
+				{RepoURL: "https://github.com/redhat-appstudio/managed-gitops", Ref: "values"},
+				{RepoURL: "https://github.com/redhat-appstudio/other", Ref: "values"},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_DuplicateSourceRef))
+		})
+
+		It("Should fail with error saying that the path of a source is required, unless the source defines a ref", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source = ApplicationSource{}
+			gitopsDepl.Spec.Sources = ApplicationSources{
+				{RepoURL: "https://github.com/redhat-appstudio/managed-gitops", Ref: "values"},
+				{RepoURL: "https://github.com/redhat-appstudio/other"},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_PathIsRequired))
+		})
+
+		It("Should fail with error saying that the path of a source cannot be '/'", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source = ApplicationSource{}
+			gitopsDepl.Spec.Sources = ApplicationSources{
+				{RepoURL: "https://github.com/redhat-appstudio/managed-gitops", Path: "/"},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidPathSlash))
+		})
+	})
+
+	Context("Create GitOpsDeployment CR with invalid .spec.syncPolicy automated/retry fields", func() {
+		It("Should fail with error saying that automated and retry may only be set for automated GitOpsDeployments", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Manual
+			prune := false
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Automated: &SyncPolicyAutomated{Prune: &prune},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_SyncPolicyOnManual))
+		})
+
+		It("Should fail with error saying that the retry backoff duration is invalid", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Retry: &RetryStrategy{
+					Backoff: &Backoff{Duration: "5s", MaxDuration: "three minutes"},
+				},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidRetryBackoffDuration))
+		})
+
+		It("Should fail with error saying that the retry backoff factor is invalid", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			factor := int64(0)
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Retry: &RetryStrategy{
+					Backoff: &Backoff{Duration: "10", Factor: &factor},
+				},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidRetryBackoffFactor))
+		})
+	})
+
+	Context("Create GitOpsDeployment CR with invalid .spec.ignoreDifferences field", func() {
+		It("Should fail with error saying that the kind is required", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.IgnoreDifferences = []ResourceIgnoreDifferences{
+				{Group: "apps", JSONPointers: []string{"/spec/replicas"}},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_IgnoreDifferencesKindRequired))
+		})
+
+		It("Should fail with error saying that at least one field must be specified", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.IgnoreDifferences = []ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment"},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_IgnoreDifferencesNoFields))
+		})
+
+		It("Should fail with error saying that JSON pointers must begin with '/'", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.IgnoreDifferences = []ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"spec/replicas"}},
+			}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidJSONPointer))
+		})
+	})
+
+	Context("Create GitOpsDeployment CR with invalid .spec.dependsOn field", func() {
+		It("Should fail with error saying that the name is required", func() {
+			gitopsDepl.Spec.DependsOn = []GitOpsDeploymentDependency{{Name: ""}}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_DependsOnNameRequired))
+		})
+
+		It("Should fail with error saying that a GitOpsDeployment cannot depend on itself", func() {
+			gitopsDepl.Spec.DependsOn = []GitOpsDeploymentDependency{{Name: gitopsDepl.Name}}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_DependsOnSelf))
+		})
+
+		It("Should fail with error saying that the dependencies must not be duplicated", func() {
+			gitopsDepl.Spec.DependsOn = []GitOpsDeploymentDependency{{Name: "database"}, {Name: "database"}}
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_DuplicateDependsOn))
+		})
+	})
+
+	Context("Create GitOpsDeployment CR with invalid .spec.deletionPolicy field", func() {
+		It("Should fail with error saying that the deletion policy is not supported", func() {
+			gitopsDepl.Spec.DeletionPolicy = "Retain"
+
+			err := k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentUserError_InvalidDeletionPolicy))
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with a source validator", func() {
+		It("Should only verify the source of a valid GitOpsDeployment, when it is created or its source is modified", func() {
+			sourceValidator := &fakeSourceValidator{}
+			webhook := &gitopsDeploymentSourceValidatingWebhook{sourceValidator: sourceValidator}
+
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source = ApplicationSource{RepoURL: "https://github.com/my-org/my-repo", Path: "environments/dev"}
+
+			By("verifying the source is verified when the GitOpsDeployment is created")
+			Expect(webhook.ValidateCreate(ctx, gitopsDepl)).To(Succeed())
+			Expect(sourceValidator.calls).To(Equal(1))
+
+			By("verifying the source is not verified if it is unmodified")
+			updated := gitopsDepl.DeepCopy()
+			updated.Finalizers = []string{"my-finalizer"}
+			Expect(webhook.ValidateUpdate(ctx, gitopsDepl, updated)).To(Succeed())
+			Expect(sourceValidator.calls).To(Equal(1))
+
+			By("verifying the error of the source validator is returned, when the source is modified")
+			sourceValidator.err = fmt.Errorf("spec.source.targetRevision 'mian' was not found")
+			updated.Spec.Source.TargetRevision = "mian"
+			err := webhook.ValidateUpdate(ctx, gitopsDepl, updated)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring("was not found"))
+			Expect(sourceValidator.calls).To(Equal(2))
+
+			By("verifying the source is not verified if the spec is invalid")
+			updated.Spec.Type = "invalid"
+			err = webhook.ValidateCreate(ctx, updated)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(error_invalid_spec_type))
+			Expect(sourceValidator.calls).To(Equal(2))
+		})
+	})
 })
+
+// fakeSourceValidator counts the calls to ValidateSource, and returns 'err'
+type fakeSourceValidator struct {
+	calls int
+	err   error
+}
+
+func (f *fakeSourceValidator) ValidateSource(ctx context.Context, gitopsDeployment *GitOpsDeployment) error {
+	f.calls++
+	return f.err
+}
@@ -72,8 +72,33 @@ type GitOpsDeploymentManagedEnvironmentSpec struct {
 	//
 	// Optional, default to false.
 	ClusterResources bool `json:"clusterResources,omitempty"`
+
+	// CABundleSecretRef is a reference to a Secret that contains the PEM-encoded certificate authority (CA) bundle that
+	// Argo CD should use to verify the TLS certificate of the cluster, for clusters whose certificate is signed by a
+	// private CA.
+	//
+	// Optional. If not specified, the 'certificate-authority-data' of the kubeconfig context is used, if any. If
+	// specified, the CA bundle of the Secret is used instead. Ignored if AllowInsecureSkipTLSVerify is true.
+	//
+	// The Secret must be in the same Namespace as the GitOpsDeploymentManagedEnvironment, and must be of type
+	// managed-gitops.redhat.com/managed-environment.
+	CABundleSecretRef *CABundleSecretReference `json:"caBundleSecretRef,omitempty"`
+}
+
+// CABundleSecretReference references the key of a Secret that contains a PEM-encoded CA bundle
+type CABundleSecretReference struct {
+	// Name is the name of the Secret
+	Name string `json:"name"`
+
+	// Key is the key of the Secret that contains the CA bundle.
+	//
+	// Optional, defaults to 'ca.crt'.
+	Key string `json:"key,omitempty"`
 }
 
+// DefaultCABundleSecretKey is the key of the CA bundle Secret that is used, if CABundleSecretReference.Key is not set
+const DefaultCABundleSecretKey = "ca.crt"
+
 type AllowInsecureSkipTLSVerify bool
 
 // Insecure TLS Status types
@@ -119,6 +144,7 @@ const (
 	ConditionReasonInvalidNamespaceList               ManagedEnvironmentConditionReason = "InvalidNamespaceList"
 	ConditionReasonUnableToRetrieveRestConfig         ManagedEnvironmentConditionReason = "UnableToRetrieveRestConfig"
 	ConditionReasonUnknownError                       ManagedEnvironmentConditionReason = "UnknownError"
+	ConditionReasonInvalidCABundle                    ManagedEnvironmentConditionReason = "InvalidCABundle"
 )
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,163 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitOpsDeploymentNotificationSpec defines the desired state of GitOpsDeploymentNotification
+type GitOpsDeploymentNotificationSpec struct {
+	// Triggers is the list of triggers that the notification subscribes to
+	// +kubebuilder:validation:MinItems=1
+	Triggers []NotificationTrigger `json:"triggers"`
+
+	// Optional: The names of the GitOpsDeployments, in the namespace of the GitOpsDeploymentNotification, to send
+	// notifications for. If neither gitopsDeployments nor selector is specified, notifications are sent for all the
+	// GitOpsDeployments in the namespace.
+	GitOpsDeployments []string `json:"gitopsDeployments,omitempty"`
+
+	// Optional: A label selector of the GitOpsDeployments, in the namespace of the GitOpsDeploymentNotification, to
+	// send notifications for. If both gitopsDeployments and selector are specified, a GitOpsDeployment must match both.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Sink is the destination that notifications are sent to
+	Sink NotificationSink `json:"sink"`
+
+	// Optional: A Go template (text/template) of the notification message. See the documentation for the fields that
+	// are available to the template. If not specified, a default message is sent.
+	Template string `json:"template,omitempty"`
+}
+
+// NotificationTrigger is a deployment lifecycle transition that notifications may be sent for
+// +kubebuilder:validation:Enum=on-sync-failed;on-health-degraded;on-deployed;on-env-disconnected
+type NotificationTrigger string
+
+const (
+	// NotificationTrigger_SyncFailed is triggered when a sync operation of a GitOpsDeployment fails
+	NotificationTrigger_SyncFailed NotificationTrigger = "on-sync-failed"
+
+	// NotificationTrigger_HealthDegraded is triggered when the health of the resources of a GitOpsDeployment becomes Degraded
+	NotificationTrigger_HealthDegraded NotificationTrigger = "on-health-degraded"
+
+	// NotificationTrigger_Deployed is triggered when a sync operation of a GitOpsDeployment succeeds
+	NotificationTrigger_Deployed NotificationTrigger = "on-deployed"
+
+	// NotificationTrigger_EnvDisconnected is triggered when the GitOps Service is unable to connect to the
+	// GitOpsDeploymentManagedEnvironment that a GitOpsDeployment is deployed to
+	NotificationTrigger_EnvDisconnected NotificationTrigger = "on-env-disconnected"
+)
+
+// NotificationSinkType is the format in which notifications are sent to a sink
+// +kubebuilder:validation:Enum=Webhook;Slack;CloudEvents
+type NotificationSinkType string
+
+const (
+	// NotificationSinkType_Webhook sends the notification as a JSON object, via an HTTP POST request
+	NotificationSinkType_Webhook NotificationSinkType = "Webhook"
+
+	// NotificationSinkType_Slack sends the notification message as a Slack-compatible incoming webhook payload
+	NotificationSinkType_Slack NotificationSinkType = "Slack"
+
+	// NotificationSinkType_CloudEvents sends the notification as a CloudEvent (v1.0), in structured content mode
+	NotificationSinkType_CloudEvents NotificationSinkType = "CloudEvents"
+)
+
+// NotificationSink is the destination that notifications are sent to
+type NotificationSink struct {
+	// Type is the format in which notifications are sent: 'Webhook', 'Slack' or 'CloudEvents'
+	Type NotificationSinkType `json:"type"`
+
+	// Optional: The http(s) URL that notifications are sent to. Either url or secret must be specified.
+	URL string `json:"url,omitempty"`
+
+	// Optional: The name of a Secret, in the namespace of the GitOpsDeploymentNotification, whose 'url' key contains
+	// the URL that notifications are sent to. This may be used for URLs that contain a token, such as Slack webhook URLs.
+	Secret string `json:"secret,omitempty"`
+}
+
+// GitOpsDeploymentNotificationStatus defines the observed state of GitOpsDeploymentNotification
+type GitOpsDeploymentNotificationStatus struct {
+	// Deliveries contains the status of the most recent notifications that were sent to the sink, most recent last
+	Deliveries []NotificationDelivery `json:"deliveries,omitempty"`
+}
+
+// NotificationDeliveryPhase is the phase of the delivery of a notification
+type NotificationDeliveryPhase string
+
+const (
+	// NotificationDeliveryPhase_Retrying indicates that the delivery failed, and will be retried
+	NotificationDeliveryPhase_Retrying NotificationDeliveryPhase = "Retrying"
+
+	// NotificationDeliveryPhase_Succeeded indicates that the notification was delivered to the sink
+	NotificationDeliveryPhase_Succeeded NotificationDeliveryPhase = "Succeeded"
+
+	// NotificationDeliveryPhase_Failed indicates that the delivery failed, and will no longer be retried
+	NotificationDeliveryPhase_Failed NotificationDeliveryPhase = "Failed"
+)
+
+// MaxNotificationDeliveries is the maximum number of deliveries that are kept in the status of a GitOpsDeploymentNotification
+const MaxNotificationDeliveries = 20
+
+// NotificationDelivery is the status of the delivery of a single notification
+type NotificationDelivery struct {
+	// Key uniquely identifies the notification: a notification is only delivered once for a given key
+	Key string `json:"key"`
+
+	// Trigger is the trigger that caused the notification to be sent
+	Trigger NotificationTrigger `json:"trigger"`
+
+	// GitOpsDeployment is the name of the GitOpsDeployment that the notification was sent for
+	GitOpsDeployment string `json:"gitopsDeployment"`
+
+	// Phase is the phase of the delivery: 'Retrying', 'Succeeded' or 'Failed'
+	Phase NotificationDeliveryPhase `json:"phase"`
+
+	// Attempts is the number of attempts made to deliver the notification
+	Attempts int `json:"attempts"`
+
+	// Message contains the error of the most recent failed attempt, if any
+	Message string `json:"message,omitempty"`
+
+	// LastAttemptTime is the time of the most recent attempt to deliver the notification
+	LastAttemptTime metav1.Time `json:"lastAttemptTime"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// GitOpsDeploymentNotification is the Schema for the gitopsdeploymentnotifications API
+type GitOpsDeploymentNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitOpsDeploymentNotificationSpec   `json:"spec,omitempty"`
+	Status GitOpsDeploymentNotificationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GitOpsDeploymentNotificationList contains a list of GitOpsDeploymentNotification
+type GitOpsDeploymentNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitOpsDeploymentNotification `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitOpsDeploymentNotification{}, &GitOpsDeploymentNotificationList{})
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitOpsDeploymentPreviewSpec defines the desired state of GitOpsDeploymentPreview
+type GitOpsDeploymentPreviewSpec struct {
+	// Reference to the target GitOpsDeployment, in the namespace of the GitOpsDeploymentPreview, to preview
+	GitopsDeploymentName string `json:"gitopsDeploymentName"`
+
+	// Revision is the alternate revision (for example, the git commit SHA or branch of a pull request) of the source
+	// of the GitOpsDeployment, whose resources are compared with the live resources on the target cluster
+	Revision string `json:"revision"`
+}
+
+// GitOpsDeploymentPreviewPhase is the phase of the computation of a preview
+type GitOpsDeploymentPreviewPhase string
+
+const (
+	// GitOpsDeploymentPreviewPhase_Running indicates that the preview is being computed
+	GitOpsDeploymentPreviewPhase_Running GitOpsDeploymentPreviewPhase = "Running"
+
+	// GitOpsDeploymentPreviewPhase_Completed indicates that the preview was computed, and is available in the status
+	GitOpsDeploymentPreviewPhase_Completed GitOpsDeploymentPreviewPhase = "Completed"
+
+	// GitOpsDeploymentPreviewPhase_Failed indicates that the preview could not be computed: see the message for details
+	GitOpsDeploymentPreviewPhase_Failed GitOpsDeploymentPreviewPhase = "Failed"
+)
+
+// PreviewResourceChange is the type of change that would be made to a resource, by deploying the previewed revision
+type PreviewResourceChange string
+
+const (
+	// PreviewResourceChange_Added indicates that the resource is defined at the previewed revision, but does not exist
+	// on the target cluster
+	PreviewResourceChange_Added PreviewResourceChange = "Added"
+
+	// PreviewResourceChange_Changed indicates that the resource exists on the target cluster, but differs from the
+	// resource defined at the previewed revision
+	PreviewResourceChange_Changed PreviewResourceChange = "Changed"
+
+	// PreviewResourceChange_Removed indicates that the resource exists on the target cluster, but is no longer defined
+	// at the previewed revision
+	PreviewResourceChange_Removed PreviewResourceChange = "Removed"
+)
+
+const (
+	// MaxPreviewResourceDiffLength is the maximum length of the diff of a single resource, in the status of a
+	// GitOpsDeploymentPreview: longer diffs are truncated.
+	MaxPreviewResourceDiffLength = 16 * 1024
+
+	// MaxPreviewResources is the maximum number of resources in the status of a GitOpsDeploymentPreview: further
+	// resources are omitted.
+	MaxPreviewResources = 500
+
+	// MaxPreviewTotalDiffLength is the maximum total length of the diffs of all the resources, in the status of a
+	// GitOpsDeploymentPreview: once it is reached, the diffs of further resources are omitted.
+	MaxPreviewTotalDiffLength = 512 * 1024
+)
+
+// PreviewResourceDiff is the difference between a live resource on the target cluster, and the resource defined at
+// the previewed revision
+type PreviewResourceDiff struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Change is the type of change that would be made to the resource: 'Added', 'Changed' or 'Removed'
+	Change PreviewResourceChange `json:"change"`
+
+	// Diff is a unified diff, of the YAML of the live resource and of the resource defined at the previewed revision.
+	// Fields of the live resource that are not defined at the previewed revision (for example, fields that are set by
+	// the cluster) are not included in the diff.
+	Diff string `json:"diff,omitempty"`
+
+	// Truncated is true if the diff was longer than the maximum length, and was truncated, or if it was omitted
+	// because the diffs of the preview exceeded their maximum total length
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// GitOpsDeploymentPreviewStatus defines the observed state of GitOpsDeploymentPreview
+type GitOpsDeploymentPreviewStatus struct {
+	// Phase is the current phase of the preview: Running, Completed or Failed
+	Phase GitOpsDeploymentPreviewPhase `json:"phase,omitempty"`
+
+	// Message contains a human-readable message about the preview, for example the reason it failed
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the GitOpsDeploymentPreview that the status was computed for. If the spec
+	// is modified, the preview is computed again.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ResolvedRevision is the revision that spec.revision resolved to, for example the git commit SHA of a branch
+	ResolvedRevision string `json:"resolvedRevision,omitempty"`
+
+	// CompletedAt is the time at which the preview was computed
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Resources contains the resources that would be added, changed, or removed by deploying the previewed revision.
+	// Resources that would be unchanged are not included.
+	Resources []PreviewResourceDiff `json:"resources,omitempty"`
+
+	// Truncated is true if the preview exceeded the maximum number of resources, or the maximum total length of
+	// their diffs: in which case, not all resources are listed in .status.resources, or not all of their diffs are
+	// included.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Deployment",type=string,JSONPath=`.spec.gitopsDeploymentName`
+// +kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.spec.revision`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+
+// GitOpsDeploymentPreview is the Schema for the gitopsdeploymentpreviews API
+type GitOpsDeploymentPreview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitOpsDeploymentPreviewSpec   `json:"spec,omitempty"`
+	Status GitOpsDeploymentPreviewStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GitOpsDeploymentPreviewList contains a list of GitOpsDeploymentPreview
+type GitOpsDeploymentPreviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitOpsDeploymentPreview `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitOpsDeploymentPreview{}, &GitOpsDeploymentPreviewList{})
+}
@@ -34,6 +34,34 @@ type GitOpsDeploymentRepositoryCredentialSpec struct {
 	// Reference to a K8s Secret in the namespace that contains repository credentials (Git username/password, as of this writing)
 	// Required field
 	Secret string `json:"secret"`
+
+	// CommitStatus, if set, configures the GitOps service to post a commit status to the Git provider of the
+	// repository, once a GitOpsDeployment of the repository has synced a revision. The status is posted using the
+	// 'password' (personal access token) of the Secret.
+	// Optional field
+	CommitStatus *CommitStatusReporting `json:"commitStatus,omitempty"`
+}
+
+// CommitStatusProvider is the Git provider that commit statuses are posted to
+type CommitStatusProvider string
+
+const (
+	CommitStatusProvider_GitHub CommitStatusProvider = "GitHub"
+	CommitStatusProvider_GitLab CommitStatusProvider = "GitLab"
+)
+
+// CommitStatusReporting configures the reporting of commit statuses for a repository
+type CommitStatusReporting struct {
+
+	// Provider is the Git provider of the repository: GitHub or GitLab
+	// +kubebuilder:validation:Enum=GitHub;GitLab
+	Provider CommitStatusProvider `json:"provider"`
+
+	// APIURL is the URL of the API of the Git provider, for example, 'https://github.example.com/api/v3/' for GitHub
+	// Enterprise. It must be on the host of the repository (or, for GitHub, on its 'api.' subdomain), as the token
+	// is sent to it. Defaults to the API URL of github.com, or gitlab.com.
+	// Optional field
+	APIURL string `json:"apiURL,omitempty"`
 }
 
 // ErrorOccurred / ValidRepositoryURL / ValidRepositoryCredential
@@ -27,7 +27,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
-const error_invalid_repository = "repository must begin with ssh:// or https://"
+const (
+	error_invalid_repository             = "repository must begin with ssh:// or https://"
+	error_invalid_commit_status_provider = "commit status provider must be GitHub or GitLab"
+	error_invalid_commit_status_api_url  = "commit status api url must begin with https://"
+)
 
 // log is for logging in this package.
 var gitopsdeploymentrepositorycredentiallog = logf.Log.WithName(logutil.LogLogger_managed_gitops)
@@ -93,5 +97,18 @@ func (r *GitOpsDeploymentRepositoryCredential) ValidateGitOpsDeploymentRepoCred(
 		}
 	}
 
+	if r.Spec.CommitStatus != nil {
+		if r.Spec.CommitStatus.Provider != CommitStatusProvider_GitHub && r.Spec.CommitStatus.Provider != CommitStatusProvider_GitLab {
+			return fmt.Errorf(error_invalid_commit_status_provider)
+		}
+
+		if r.Spec.CommitStatus.APIURL != "" {
+			apiURL, err := url.ParseRequestURI(r.Spec.CommitStatus.APIURL)
+			if err != nil || apiURL.Scheme != "https" {
+				return fmt.Errorf(error_invalid_commit_status_api_url)
+			}
+		}
+	}
+
 	return nil
 }
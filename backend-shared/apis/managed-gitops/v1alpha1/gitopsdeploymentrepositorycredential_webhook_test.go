@@ -79,4 +79,20 @@ var _ = Describe("GitOpsDeploymentRepositoryCredential validation webhook", func
 		})
 	})
 
+	Context("Create GitOpsDeploymentRepositoryCredential CR with invalid commit status API URL", func() {
+		It("Should fail with error saying commit status api url must begin with https://", func() {
+
+			repoCredentialCr.Spec.Repository = "https://github.com/test/test-repo"
+			repoCredentialCr.Spec.CommitStatus = &CommitStatusReporting{
+				Provider: CommitStatusProvider_GitHub,
+				APIURL:   "http://github.example.com/api/v3/",
+			}
+
+			err := k8sClient.Create(ctx, repoCredentialCr)
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(error_invalid_commit_status_api_url))
+
+		})
+	})
+
 })
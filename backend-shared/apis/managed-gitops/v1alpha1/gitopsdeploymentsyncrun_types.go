@@ -27,13 +27,116 @@ type GitOpsDeploymentSyncRunSpec struct {
 
 	// Optional: If specified, tells the GitOps Service to deploy a particular git commit SHA
 	RevisionID string `json:"revisionID,omitempty"`
+
+	// Optional: If specified, tells the GitOps Service to roll back the GitOpsDeployment to the entry of its deployment
+	// history (.status.history of the GitOpsDeployment) with the given ID. Cannot be combined with revisionID, or
+	// rollbackToRevision.
+	RollbackToHistoryID *int64 `json:"rollbackToHistoryID,omitempty"`
+
+	// Optional: If specified, tells the GitOps Service to roll back the GitOpsDeployment to the most recent entry of its
+	// deployment history that deployed the given revision. Cannot be combined with revisionID, or rollbackToHistoryID.
+	RollbackToRevision string `json:"rollbackToRevision,omitempty"`
+
+	// Optional: If true, the automated sync policy of the target GitOpsDeployment is disabled for as long as this
+	// GitOpsDeploymentSyncRun exists. This is required to roll back a GitOpsDeployment of type 'automated', as otherwise
+	// the automated sync would immediately undo the rollback. Deleting the GitOpsDeploymentSyncRun re-enables the
+	// automated sync policy.
+	DisableAutomatedSync bool `json:"disableAutomatedSync,omitempty"`
+
+	// Optional: If true, resources that are no longer defined in the source are deleted from the cluster by the sync
+	Prune bool `json:"prune,omitempty"`
+
+	// Optional: If true, the sync is performed as a 'kubectl apply --dry-run': no resources are modified on the cluster
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Optional: If true, the '--force' flag is passed to 'kubectl apply', deleting and re-creating resources which
+	// cannot be patched.
+	Force bool `json:"force,omitempty"`
+
+	// Optional: If specified, only the given resources of the GitOpsDeployment are synchronized (selective sync).
+	// If not specified, all the resources of the GitOpsDeployment are synchronized.
+	Resources []SyncOperationResource `json:"resources,omitempty"`
+
+	// Optional: Sync options that only apply to this sync, e.g. 'Validate=false'. These are in addition to
+	// the sync options of the GitOpsDeployment.
+	SyncOptions SyncOptions `json:"syncOptions,omitempty"`
+
+	// Optional: The strategy used to perform the sync: 'apply' or 'hook'. If not specified, 'hook' is used.
+	// - 'apply' performs a 'kubectl apply', ignoring any resource hooks.
+	// - 'hook' submits the resource hooks as part of the sync, falling back to 'kubectl apply' for other resources.
+	Strategy SyncRunStrategy `json:"strategy,omitempty"`
+
+	// Optional: If set to true on an existing GitOpsDeploymentSyncRun, the sync operation is terminated, if it is still
+	// running: for example, to stop a stuck hook or a long sync. Once set, it cannot be unset.
+	Terminate bool `json:"terminate,omitempty"`
+}
+
+type SyncRunStrategy string
+
+const (
+	SyncRunStrategy_Apply SyncRunStrategy = "apply"
+	SyncRunStrategy_Hook  SyncRunStrategy = "hook"
+)
+
+// IsRollback returns true if the GitOpsDeploymentSyncRun requests a rollback, rather than a sync.
+func (spec GitOpsDeploymentSyncRunSpec) IsRollback() bool {
+	return spec.RollbackToHistoryID != nil || spec.RollbackToRevision != ""
 }
 
 // GitOpsDeploymentSyncRunStatus defines the observed state of GitOpsDeploymentSyncRun
 type GitOpsDeploymentSyncRunStatus struct {
 	Conditions []GitOpsDeploymentSyncRunCondition `json:"conditions,omitempty"`
+
+	// Rollback contains the target and the result of the rollback requested by the GitOpsDeploymentSyncRun, if any
+	Rollback *GitOpsDeploymentSyncRunRollbackStatus `json:"rollback,omitempty"`
+
+	// Phase is the current phase of the sync operation: Running, Terminating, Failed, Error or Succeeded.
+	// If the sync operation was terminated, using spec.terminate, the phase is Terminated.
+	Phase OperationPhase `json:"phase,omitempty"`
+
+	// Message contains a human-readable message about the sync operation, for example the reason it failed
+	Message string `json:"message,omitempty"`
+
+	// StartedAt is the time at which the sync operation was started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time at which the sync operation completed
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// SyncedRevision is the revision that the sync operation was performed to
+	SyncedRevision string `json:"syncedRevision,omitempty"`
+
+	// Resources contains the result of the sync operation for each individual resource
+	Resources ResourceResults `json:"resources,omitempty"`
+}
+
+// GitOpsDeploymentSyncRunRollbackStatus contains the target and the result of a rollback
+type GitOpsDeploymentSyncRunRollbackStatus struct {
+	// HistoryID is the ID of the deployment history entry that the GitOpsDeployment is rolled back to
+	HistoryID int64 `json:"historyID"`
+
+	// Revision is the revision that the GitOpsDeployment is rolled back to
+	Revision string `json:"revision,omitempty"`
+
+	// Phase is the current phase of the rollback: Running, Succeeded or Failed
+	Phase RollbackPhase `json:"phase,omitempty"`
+
+	// Message contains a human-readable message about the result of the rollback, for example the reason it failed
+	Message string `json:"message,omitempty"`
 }
 
+// SyncRunPhaseTerminated is the phase of a GitOpsDeploymentSyncRun whose sync operation was terminated by the user,
+// using spec.terminate. The other phases are those of the Argo CD sync operation.
+const SyncRunPhaseTerminated OperationPhase = "Terminated"
+
+type RollbackPhase string
+
+const (
+	RollbackPhaseRunning   RollbackPhase = "Running"
+	RollbackPhaseSucceeded RollbackPhase = "Succeeded"
+	RollbackPhaseFailed    RollbackPhase = "Failed"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -64,6 +167,21 @@ type GitOpsDeploymentSyncRunCondition struct {
 	Reason SyncRunReasonType `json:"reason"`
 }
 
+const (
+	GitOpsDeploymentSyncRunUserError_RollbackTargetConflict  = "spec.rollbackToHistoryID and spec.rollbackToRevision cannot both be set"
+	GitOpsDeploymentSyncRunUserError_RollbackWithRevisionID  = "spec.revisionID cannot be set when rolling back, using spec.rollbackToHistoryID or spec.rollbackToRevision"
+	GitOpsDeploymentSyncRunUserError_InvalidHistoryID        = "spec.rollbackToHistoryID must not be negative"
+	GitOpsDeploymentSyncRunUserError_DisableAutomatedSync    = "spec.disableAutomatedSync may only be set when rolling back, using spec.rollbackToHistoryID or spec.rollbackToRevision"
+	GitOpsDeploymentSyncRunUserError_RollbackIsImmutable     = "spec.rollbackToHistoryID, spec.rollbackToRevision and spec.disableAutomatedSync cannot be changed"
+	GitOpsDeploymentSyncRunUserError_InvalidStrategy         = "spec.strategy must be either 'apply' or 'hook'"
+	GitOpsDeploymentSyncRunUserError_InvalidResource         = "spec.resources must specify the kind and name of each resource"
+	GitOpsDeploymentSyncRunUserError_InvalidSyncOption       = "spec.syncOptions contains a sync option that is either not supported, or conflicts with another sync option"
+	GitOpsDeploymentSyncRunUserError_SyncOptionsOnRollback   = "spec.dryRun, spec.force, spec.resources, spec.syncOptions and spec.strategy cannot be set when rolling back"
+	GitOpsDeploymentSyncRunUserError_SyncOptionsImmutable    = "spec.prune, spec.dryRun, spec.force, spec.resources, spec.syncOptions and spec.strategy cannot be changed"
+	GitOpsDeploymentSyncRunUserError_TerminateOnCreate       = "spec.terminate cannot be set when creating a GitOpsDeploymentSyncRun: it may only be set on an existing GitOpsDeploymentSyncRun"
+	GitOpsDeploymentSyncRunUserError_TerminateIsIrreversible = "spec.terminate cannot be unset, once it has been set"
+)
+
 type SyncRunReasonType string
 
 const (
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"reflect"
 
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -62,6 +63,18 @@ func (r *GitOpsDeploymentSyncRun) ValidateCreate() error {
 		return fmt.Errorf(error_invalid_name)
 	}
 
+	if err := ValidateGitOpsDeploymentSyncRunRollback(r.Spec); err != nil {
+		return err
+	}
+
+	if err := ValidateGitOpsDeploymentSyncRunOptions(r.Spec); err != nil {
+		return err
+	}
+
+	if r.Spec.Terminate {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_TerminateOnCreate)
+	}
+
 	return nil
 }
 
@@ -69,6 +82,87 @@ func (r *GitOpsDeploymentSyncRun) ValidateCreate() error {
 func (r *GitOpsDeploymentSyncRun) ValidateUpdate(old runtime.Object) error {
 	gitopsdeploymentsyncrunlog.Info("validate update", "name", r.Name)
 
+	if err := ValidateGitOpsDeploymentSyncRunRollback(r.Spec); err != nil {
+		return err
+	}
+
+	if err := ValidateGitOpsDeploymentSyncRunOptions(r.Spec); err != nil {
+		return err
+	}
+
+	oldSyncRun, ok := old.(*GitOpsDeploymentSyncRun)
+	if !ok {
+		return fmt.Errorf("unable to convert object to GitOpsDeploymentSyncRun")
+	}
+
+	if !reflect.DeepEqual(oldSyncRun.Spec.RollbackToHistoryID, r.Spec.RollbackToHistoryID) ||
+		oldSyncRun.Spec.RollbackToRevision != r.Spec.RollbackToRevision ||
+		oldSyncRun.Spec.DisableAutomatedSync != r.Spec.DisableAutomatedSync {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_RollbackIsImmutable)
+	}
+
+	if oldSyncRun.Spec.Prune != r.Spec.Prune ||
+		oldSyncRun.Spec.DryRun != r.Spec.DryRun ||
+		oldSyncRun.Spec.Force != r.Spec.Force ||
+		!reflect.DeepEqual(oldSyncRun.Spec.Resources, r.Spec.Resources) ||
+		!reflect.DeepEqual(oldSyncRun.Spec.SyncOptions, r.Spec.SyncOptions) ||
+		oldSyncRun.Spec.Strategy != r.Spec.Strategy {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_SyncOptionsImmutable)
+	}
+
+	if oldSyncRun.Spec.Terminate && !r.Spec.Terminate {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_TerminateIsIrreversible)
+	}
+
+	return nil
+}
+
+// ValidateGitOpsDeploymentSyncRunRollback returns an error if the rollback fields of a GitOpsDeploymentSyncRun are
+// invalid. The error message is suitable to be returned to the user.
+func ValidateGitOpsDeploymentSyncRunRollback(spec GitOpsDeploymentSyncRunSpec) error {
+
+	if spec.RollbackToHistoryID != nil && spec.RollbackToRevision != "" {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_RollbackTargetConflict)
+	}
+
+	if spec.RollbackToHistoryID != nil && *spec.RollbackToHistoryID < 0 {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_InvalidHistoryID)
+	}
+
+	if spec.IsRollback() && spec.RevisionID != "" {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_RollbackWithRevisionID)
+	}
+
+	if !spec.IsRollback() && spec.DisableAutomatedSync {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_DisableAutomatedSync)
+	}
+
+	return nil
+}
+
+// ValidateGitOpsDeploymentSyncRunOptions returns an error if the sync options of a GitOpsDeploymentSyncRun are
+// invalid. The error message is suitable to be returned to the user.
+func ValidateGitOpsDeploymentSyncRunOptions(spec GitOpsDeploymentSyncRunSpec) error {
+
+	if spec.Strategy != "" && spec.Strategy != SyncRunStrategy_Apply && spec.Strategy != SyncRunStrategy_Hook {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_InvalidStrategy)
+	}
+
+	for _, resource := range spec.Resources {
+		if resource.Kind == "" || resource.Name == "" {
+			return fmt.Errorf(GitOpsDeploymentSyncRunUserError_InvalidResource)
+		}
+	}
+
+	if err := ValidateSyncOptions(spec.SyncOptions); err != nil {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_InvalidSyncOption)
+	}
+
+	// A rollback only supports pruning: the remaining options are specific to a sync
+	if spec.IsRollback() && (spec.DryRun || spec.Force || len(spec.Resources) > 0 || len(spec.SyncOptions) > 0 || spec.Strategy != "") {
+		return fmt.Errorf(GitOpsDeploymentSyncRunUserError_SyncOptionsOnRollback)
+	}
+
 	return nil
 }
 
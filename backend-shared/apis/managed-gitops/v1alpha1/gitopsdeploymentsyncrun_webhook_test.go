@@ -55,4 +55,135 @@ var _ = Describe("GitOpsDeploymentSyncRun validation webhook", func() {
 		})
 	})
 
+	Context("Create GitOpsDeploymentSyncRun CR with invalid rollback fields", func() {
+		It("Should fail with an error if both rollbackToHistoryID and rollbackToRevision are set", func() {
+			historyID := int64(1)
+			gitopsDeplSyncRunCr.Name = "rollback-conflict"
+			gitopsDeplSyncRunCr.Spec.RevisionID = ""
+			gitopsDeplSyncRunCr.Spec.RollbackToHistoryID = &historyID
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "abc123"
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_RollbackTargetConflict))
+		})
+
+		It("Should fail with an error if a rollback also sets revisionID", func() {
+			gitopsDeplSyncRunCr.Name = "rollback-revision-id"
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "abc123"
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_RollbackWithRevisionID))
+		})
+
+		It("Should fail with an error if disableAutomatedSync is set without a rollback", func() {
+			gitopsDeplSyncRunCr.Name = "disable-automated-sync"
+			gitopsDeplSyncRunCr.Spec.DisableAutomatedSync = true
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_DisableAutomatedSync))
+		})
+	})
+
+	Context("Update GitOpsDeploymentSyncRun CR rollback fields", func() {
+		It("Should fail with an error if the rollback target is changed", func() {
+			gitopsDeplSyncRunCr.Name = "rollback-immutable"
+			gitopsDeplSyncRunCr.Spec.RevisionID = ""
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "abc123"
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+			Expect(err).To(BeNil())
+
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "def456"
+			err = k8sClient.Update(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_RollbackIsImmutable))
+		})
+	})
+
+	Context("Create GitOpsDeploymentSyncRun CR with invalid sync options", func() {
+		It("Should fail with an error if the strategy is neither apply nor hook", func() {
+			gitopsDeplSyncRunCr.Name = "invalid-strategy"
+			gitopsDeplSyncRunCr.Spec.Strategy = "replace"
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_InvalidStrategy))
+		})
+
+		It("Should fail with an error if a resource does not specify a kind and a name", func() {
+			gitopsDeplSyncRunCr.Name = "invalid-resource"
+			gitopsDeplSyncRunCr.Spec.Resources = []SyncOperationResource{{Kind: "ConfigMap"}}
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_InvalidResource))
+		})
+
+		It("Should fail with an error if a sync option is not supported", func() {
+			gitopsDeplSyncRunCr.Name = "invalid-sync-option"
+			gitopsDeplSyncRunCr.Spec.SyncOptions = SyncOptions{"Unknown=true"}
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_InvalidSyncOption))
+		})
+
+		It("Should fail with an error if a rollback specifies sync options other than prune", func() {
+			gitopsDeplSyncRunCr.Name = "rollback-sync-options"
+			gitopsDeplSyncRunCr.Spec.RevisionID = ""
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "abc123"
+			gitopsDeplSyncRunCr.Spec.DryRun = true
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_SyncOptionsOnRollback))
+		})
+	})
+
+	Context("Update GitOpsDeploymentSyncRun CR sync options", func() {
+		It("Should fail with an error if the sync options are changed", func() {
+			gitopsDeplSyncRunCr.Name = "sync-options-immutable"
+			gitopsDeplSyncRunCr.Spec.Prune = true
+			gitopsDeplSyncRunCr.Spec.Strategy = SyncRunStrategy_Apply
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+			Expect(err).To(BeNil())
+
+			gitopsDeplSyncRunCr.Spec.Strategy = SyncRunStrategy_Hook
+			err = k8sClient.Update(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_SyncOptionsImmutable))
+		})
+	})
+
+	Context("GitOpsDeploymentSyncRun CR terminate field", func() {
+		It("Should fail with an error if terminate is set on creation", func() {
+			gitopsDeplSyncRunCr.Name = "terminate-on-create"
+			gitopsDeplSyncRunCr.Spec.Terminate = true
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_TerminateOnCreate))
+		})
+
+		It("Should allow terminate to be set on an existing SyncRun, but not to be unset", func() {
+			gitopsDeplSyncRunCr.Name = "terminate-on-update"
+			err := k8sClient.Create(ctx, gitopsDeplSyncRunCr)
+			Expect(err).To(BeNil())
+
+			gitopsDeplSyncRunCr.Spec.Terminate = true
+			err = k8sClient.Update(ctx, gitopsDeplSyncRunCr)
+			Expect(err).To(BeNil())
+
+			gitopsDeplSyncRunCr.Spec.Terminate = false
+			err = k8sClient.Update(ctx, gitopsDeplSyncRunCr)
+
+			Expect(err).Should(Not(Succeed()))
+			Expect(err.Error()).Should(ContainSubstring(GitOpsDeploymentSyncRunUserError_TerminateIsIrreversible))
+		})
+	})
+
 })
@@ -77,6 +77,16 @@ func (in *ApplicationOperation) DeepCopy() *ApplicationOperation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSource) DeepCopyInto(out *ApplicationSource) {
 	*out = *in
+	if in.Helm != nil {
+		in, out := &in.Helm, &out.Helm
+		*out = new(ApplicationSourceHelm)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kustomize != nil {
+		in, out := &in.Kustomize, &out.Kustomize
+		*out = new(ApplicationSourceKustomize)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSource.
@@ -89,12 +99,73 @@ func (in *ApplicationSource) DeepCopy() *ApplicationSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceHelm) DeepCopyInto(out *ApplicationSourceHelm) {
+	*out = *in
+	if in.ValueFiles != nil {
+		in, out := &in.ValueFiles, &out.ValueFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]HelmParameter, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceHelm.
+func (in *ApplicationSourceHelm) DeepCopy() *ApplicationSourceHelm {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceHelm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceKustomize) DeepCopyInto(out *ApplicationSourceKustomize) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceKustomize.
+func (in *ApplicationSourceKustomize) DeepCopy() *ApplicationSourceKustomize {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceKustomize)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ApplicationSources) DeepCopyInto(out *ApplicationSources) {
 	{
 		in := &in
 		*out = make(ApplicationSources, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -128,6 +199,92 @@ func (in *Backoff) DeepCopy() *Backoff {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleSecretReference) DeepCopyInto(out *CABundleSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleSecretReference.
+func (in *CABundleSecretReference) DeepCopy() *CABundleSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitStatusReporting) DeepCopyInto(out *CommitStatusReporting) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStatusReporting.
+func (in *CommitStatusReporting) DeepCopy() *CommitStatusReporting {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitStatusReporting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftKindCount) DeepCopyInto(out *DriftKindCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftKindCount.
+func (in *DriftKindCount) DeepCopy() *DriftKindCount {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftKindCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftStatus) DeepCopyInto(out *DriftStatus) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]DriftKindCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]DriftedResource, len(*in))
+		copy(*out, *in)
+	}
+	in.DetectedAt.DeepCopyInto(&out.DetectedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftStatus.
+func (in *DriftStatus) DeepCopy() *DriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedResource) DeepCopyInto(out *DriftedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedResource.
+func (in *DriftedResource) DeepCopy() *DriftedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitOpsDeployment) DeepCopyInto(out *GitOpsDeployment) {
 	*out = *in
@@ -175,6 +332,21 @@ func (in *GitOpsDeploymentCondition) DeepCopy() *GitOpsDeploymentCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentDependency) DeepCopyInto(out *GitOpsDeploymentDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentDependency.
+func (in *GitOpsDeploymentDependency) DeepCopy() *GitOpsDeploymentDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitOpsDeploymentDestination) DeepCopyInto(out *GitOpsDeploymentDestination) {
 	*out = *in
@@ -289,6 +461,11 @@ func (in *GitOpsDeploymentManagedEnvironmentSpec) DeepCopyInto(out *GitOpsDeploy
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CABundleSecretRef != nil {
+		in, out := &in.CABundleSecretRef, &out.CABundleSecretRef
+		*out = new(CABundleSecretReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentManagedEnvironmentSpec.
@@ -324,7 +501,119 @@ func (in *GitOpsDeploymentManagedEnvironmentStatus) DeepCopy() *GitOpsDeployment
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitOpsDeploymentRepositoryCredential) DeepCopyInto(out *GitOpsDeploymentRepositoryCredential) {
+func (in *GitOpsDeploymentNotification) DeepCopyInto(out *GitOpsDeploymentNotification) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentNotification.
+func (in *GitOpsDeploymentNotification) DeepCopy() *GitOpsDeploymentNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitOpsDeploymentNotification) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentNotificationList) DeepCopyInto(out *GitOpsDeploymentNotificationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitOpsDeploymentNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentNotificationList.
+func (in *GitOpsDeploymentNotificationList) DeepCopy() *GitOpsDeploymentNotificationList {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentNotificationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitOpsDeploymentNotificationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentNotificationSpec) DeepCopyInto(out *GitOpsDeploymentNotificationSpec) {
+	*out = *in
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]NotificationTrigger, len(*in))
+		copy(*out, *in)
+	}
+	if in.GitOpsDeployments != nil {
+		in, out := &in.GitOpsDeployments, &out.GitOpsDeployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Sink = in.Sink
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentNotificationSpec.
+func (in *GitOpsDeploymentNotificationSpec) DeepCopy() *GitOpsDeploymentNotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentNotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentNotificationStatus) DeepCopyInto(out *GitOpsDeploymentNotificationStatus) {
+	*out = *in
+	if in.Deliveries != nil {
+		in, out := &in.Deliveries, &out.Deliveries
+		*out = make([]NotificationDelivery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentNotificationStatus.
+func (in *GitOpsDeploymentNotificationStatus) DeepCopy() *GitOpsDeploymentNotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentNotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentPreview) DeepCopyInto(out *GitOpsDeploymentPreview) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -332,6 +621,104 @@ func (in *GitOpsDeploymentRepositoryCredential) DeepCopyInto(out *GitOpsDeployme
 	in.Status.DeepCopyInto(&out.Status)
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentPreview.
+func (in *GitOpsDeploymentPreview) DeepCopy() *GitOpsDeploymentPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitOpsDeploymentPreview) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentPreviewList) DeepCopyInto(out *GitOpsDeploymentPreviewList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitOpsDeploymentPreview, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentPreviewList.
+func (in *GitOpsDeploymentPreviewList) DeepCopy() *GitOpsDeploymentPreviewList {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentPreviewList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitOpsDeploymentPreviewList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentPreviewSpec) DeepCopyInto(out *GitOpsDeploymentPreviewSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentPreviewSpec.
+func (in *GitOpsDeploymentPreviewSpec) DeepCopy() *GitOpsDeploymentPreviewSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentPreviewSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentPreviewStatus) DeepCopyInto(out *GitOpsDeploymentPreviewStatus) {
+	*out = *in
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]PreviewResourceDiff, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentPreviewStatus.
+func (in *GitOpsDeploymentPreviewStatus) DeepCopy() *GitOpsDeploymentPreviewStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentPreviewStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentRepositoryCredential) DeepCopyInto(out *GitOpsDeploymentRepositoryCredential) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentRepositoryCredential.
 func (in *GitOpsDeploymentRepositoryCredential) DeepCopy() *GitOpsDeploymentRepositoryCredential {
 	if in == nil {
@@ -385,6 +772,11 @@ func (in *GitOpsDeploymentRepositoryCredentialList) DeepCopyObject() runtime.Obj
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitOpsDeploymentRepositoryCredentialSpec) DeepCopyInto(out *GitOpsDeploymentRepositoryCredentialSpec) {
 	*out = *in
+	if in.CommitStatus != nil {
+		in, out := &in.CommitStatus, &out.CommitStatus
+		*out = new(CommitStatusReporting)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentRepositoryCredentialSpec.
@@ -437,13 +829,32 @@ func (in *GitOpsDeploymentSource) DeepCopy() *GitOpsDeploymentSource {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitOpsDeploymentSpec) DeepCopyInto(out *GitOpsDeploymentSpec) {
 	*out = *in
-	out.Source = in.Source
+	in.Source.DeepCopyInto(&out.Source)
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make(ApplicationSources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.Destination = in.Destination
 	if in.SyncPolicy != nil {
 		in, out := &in.SyncPolicy, &out.SyncPolicy
 		*out = new(SyncPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IgnoreDifferences != nil {
+		in, out := &in.IgnoreDifferences, &out.IgnoreDifferences
+		*out = make([]ResourceIgnoreDifferences, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]GitOpsDeploymentDependency, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentSpec.
@@ -466,7 +877,7 @@ func (in *GitOpsDeploymentStatus) DeepCopyInto(out *GitOpsDeploymentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.Sync = in.Sync
+	in.Sync.DeepCopyInto(&out.Sync)
 	out.Health = in.Health
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
@@ -475,12 +886,24 @@ func (in *GitOpsDeploymentStatus) DeepCopyInto(out *GitOpsDeploymentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.ReconciledState = in.ReconciledState
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(DriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ReconciledState.DeepCopyInto(&out.ReconciledState)
 	if in.OperationState != nil {
 		in, out := &in.OperationState, &out.OperationState
 		*out = new(OperationState)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RevisionHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentStatus.
@@ -498,7 +921,7 @@ func (in *GitOpsDeploymentSyncRun) DeepCopyInto(out *GitOpsDeploymentSyncRun) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -571,9 +994,39 @@ func (in *GitOpsDeploymentSyncRunList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsDeploymentSyncRunRollbackStatus) DeepCopyInto(out *GitOpsDeploymentSyncRunRollbackStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentSyncRunRollbackStatus.
+func (in *GitOpsDeploymentSyncRunRollbackStatus) DeepCopy() *GitOpsDeploymentSyncRunRollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsDeploymentSyncRunRollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitOpsDeploymentSyncRunSpec) DeepCopyInto(out *GitOpsDeploymentSyncRunSpec) {
 	*out = *in
+	if in.RollbackToHistoryID != nil {
+		in, out := &in.RollbackToHistoryID, &out.RollbackToHistoryID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]SyncOperationResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.SyncOptions != nil {
+		in, out := &in.SyncOptions, &out.SyncOptions
+		*out = make(SyncOptions, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentSyncRunSpec.
@@ -596,6 +1049,30 @@ func (in *GitOpsDeploymentSyncRunStatus) DeepCopyInto(out *GitOpsDeploymentSyncR
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(GitOpsDeploymentSyncRunRollbackStatus)
+		**out = **in
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(ResourceResults, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ResourceResult)
+				**out = **in
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsDeploymentSyncRunStatus.
@@ -623,6 +1100,21 @@ func (in *HealthStatus) DeepCopy() *HealthStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmParameter) DeepCopyInto(out *HelmParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmParameter.
+func (in *HelmParameter) DeepCopy() *HelmParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Info) DeepCopyInto(out *Info) {
 	*out = *in
@@ -667,6 +1159,37 @@ func (in *ManagedNamespaceMetadata) DeepCopy() *ManagedNamespaceMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationDelivery) DeepCopyInto(out *NotificationDelivery) {
+	*out = *in
+	in.LastAttemptTime.DeepCopyInto(&out.LastAttemptTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationDelivery.
+func (in *NotificationDelivery) DeepCopy() *NotificationDelivery {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationDelivery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSink) DeepCopyInto(out *NotificationSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSink.
+func (in *NotificationSink) DeepCopy() *NotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Operation) DeepCopyInto(out *Operation) {
 	*out = *in
@@ -797,10 +1320,30 @@ func (in *OperationStatus) DeepCopy() *OperationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewResourceDiff) DeepCopyInto(out *PreviewResourceDiff) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewResourceDiff.
+func (in *PreviewResourceDiff) DeepCopy() *PreviewResourceDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewResourceDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReconciledState) DeepCopyInto(out *ReconciledState) {
 	*out = *in
 	out.Source = in.Source
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]GitOpsDeploymentSource, len(*in))
+		copy(*out, *in)
+	}
 	out.Destination = in.Destination
 }
 
@@ -814,6 +1357,36 @@ func (in *ReconciledState) DeepCopy() *ReconciledState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceIgnoreDifferences) DeepCopyInto(out *ResourceIgnoreDifferences) {
+	*out = *in
+	if in.JSONPointers != nil {
+		in, out := &in.JSONPointers, &out.JSONPointers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.JQPathExpressions != nil {
+		in, out := &in.JQPathExpressions, &out.JQPathExpressions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedFieldsManagers != nil {
+		in, out := &in.ManagedFieldsManagers, &out.ManagedFieldsManagers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceIgnoreDifferences.
+func (in *ResourceIgnoreDifferences) DeepCopy() *ResourceIgnoreDifferences {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceIgnoreDifferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceResult) DeepCopyInto(out *ResourceResult) {
 	*out = *in
@@ -877,6 +1450,11 @@ func (in *ResourceStatus) DeepCopy() *ResourceStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetryStrategy) DeepCopyInto(out *RetryStrategy) {
 	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int64)
+		**out = **in
+	}
 	if in.Backoff != nil {
 		in, out := &in.Backoff, &out.Backoff
 		*out = new(Backoff)
@@ -894,6 +1472,38 @@ func (in *RetryStrategy) DeepCopy() *RetryStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionHistory) DeepCopyInto(out *RevisionHistory) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Source = in.Source
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]GitOpsDeploymentSource, len(*in))
+		copy(*out, *in)
+	}
+	in.DeployedAt.DeepCopyInto(&out.DeployedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionHistory.
+func (in *RevisionHistory) DeepCopy() *RevisionHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncOperation) DeepCopyInto(out *SyncOperation) {
 	*out = *in
@@ -910,7 +1520,7 @@ func (in *SyncOperation) DeepCopyInto(out *SyncOperation) {
 	if in.Source != nil {
 		in, out := &in.Source, &out.Source
 		*out = new(ApplicationSource)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Manifests != nil {
 		in, out := &in.Manifests, &out.Manifests
@@ -925,7 +1535,9 @@ func (in *SyncOperation) DeepCopyInto(out *SyncOperation) {
 	if in.Sources != nil {
 		in, out := &in.Sources, &out.Sources
 		*out = make(ApplicationSources, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Revisions != nil {
 		in, out := &in.Revisions, &out.Revisions
@@ -973,11 +1585,13 @@ func (in *SyncOperationResult) DeepCopyInto(out *SyncOperationResult) {
 			}
 		}
 	}
-	out.Source = in.Source
+	in.Source.DeepCopyInto(&out.Source)
 	if in.Sources != nil {
 		in, out := &in.Sources, &out.Sources
 		*out = make(ApplicationSources, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Revisions != nil {
 		in, out := &in.Revisions, &out.Revisions
@@ -1023,11 +1637,21 @@ func (in SyncOptions) DeepCopy() SyncOptions {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncPolicy) DeepCopyInto(out *SyncPolicy) {
 	*out = *in
+	if in.Automated != nil {
+		in, out := &in.Automated, &out.Automated
+		*out = new(SyncPolicyAutomated)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SyncOptions != nil {
 		in, out := &in.SyncOptions, &out.SyncOptions
 		*out = make(SyncOptions, len(*in))
 		copy(*out, *in)
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncPolicy.
@@ -1040,9 +1664,44 @@ func (in *SyncPolicy) DeepCopy() *SyncPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncPolicyAutomated) DeepCopyInto(out *SyncPolicyAutomated) {
+	*out = *in
+	if in.Prune != nil {
+		in, out := &in.Prune, &out.Prune
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SelfHeal != nil {
+		in, out := &in.SelfHeal, &out.SelfHeal
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowEmpty != nil {
+		in, out := &in.AllowEmpty, &out.AllowEmpty
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncPolicyAutomated.
+func (in *SyncPolicyAutomated) DeepCopy() *SyncPolicyAutomated {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncPolicyAutomated)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncStatus) DeepCopyInto(out *SyncStatus) {
 	*out = *in
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncStatus.
@@ -295,9 +295,11 @@ func (obj *ClusterCredentials) GetAsLogKeyValues() []interface{} {
 		return []interface{}{}
 	}
 
-	// We avoid logging the bearer_token or kube_config, as these contain sensitive user data.
+	// We avoid logging the bearer_token, client key or kube_config, as these contain sensitive user data.
 	return []interface{}{"host", obj.Host, "kube-config-length", len(obj.Kube_config),
 		"kube-config-context", len(obj.Kube_config_context), "serviceaccount_ns", obj.Serviceaccount_ns,
-		"serviceaccount-bearer-token-length", len(obj.Serviceaccount_bearer_token), "cluster_resources", obj.ClusterResources,
+		"serviceaccount-bearer-token-length", len(obj.Serviceaccount_bearer_token),
+		"client-certificate-data-length", len(obj.Client_certificate_data), "client-key-data-length", len(obj.Client_key_data),
+		"certificate-authority-data-length", len(obj.Certificate_authority_data), "cluster_resources", obj.ClusterResources,
 		"cluster_namespaces", obj.Namespaces}
 }
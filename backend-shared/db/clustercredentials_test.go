@@ -25,6 +25,9 @@ var _ = Describe("ClusterCredentials Tests", func() {
 				Kube_config_context:         "test-kube_config_context",
 				Serviceaccount_bearer_token: "test-serviceaccount_bearer_token",
 				Serviceaccount_ns:           "test-serviceaccount_ns",
+				Client_certificate_data:     "test-client_certificate_data",
+				Client_key_data:             "test-client_key_data",
+				Certificate_authority_data:  "test-certificate_authority_data",
 			}
 			err = dbq.CreateClusterCredentials(ctx, &clusterCreds)
 			Expect(err).To(BeNil())
@@ -14,6 +14,9 @@ const (
 	ClusterCredentialsServiceaccountBearerTokenLength                       = 2048
 	ClusterCredentialsServiceaccountNsLength                                = 128
 	ClusterCredentialsNamespacesLength                                      = 4096
+	ClusterCredentialsClientCertificateDataLength                           = 16384
+	ClusterCredentialsClientKeyDataLength                                   = 16384
+	ClusterCredentialsCertificateAuthorityDataLength                        = 65000
 	GitopsEngineClusterGitopsengineclusterIDLength                          = 48
 	GitopsEngineInstanceGitopsengineinstanceIDLength                        = 48
 	GitopsEngineInstanceNamespaceNameLength                                 = 48
@@ -69,6 +72,7 @@ const (
 	SyncOperationDeploymentNameLength                                       = 256
 	SyncOperationRevisionLength                                             = 256
 	SyncOperationDesiredStateLength                                         = 16
+	SyncOperationOptionsLength                                              = 4096
 	RepositoryCredentialsRepositorycredentialsIDLength                      = 48
 	RepositoryCredentialsRepoCredUserIDLength                               = 48
 	RepositoryCredentialsRepoCredURLLength                                  = 512
@@ -86,6 +90,25 @@ const (
 	AppProjectManagedEnvironmentClusteruserIDLength                         = 48
 	ApplicationOwnerApplicationOwnerApplicationIDLength                     = 48
 	ApplicationOwnerApplicationOwnerUserIDLength                            = 48
+	DeploymentHistoryDeploymenthistoryIDLength                              = 48
+	DeploymentHistoryApplicationIDLength                                    = 48
+	DeploymentHistoryRevisionLength                                         = 1024
+	DeploymentHistorySourceLength                                           = 4096
+	DeploymentHistoryInitiatedByLength                                      = 256
+	DeploymentHistorySyncRunNameLength                                      = 256
+	PreviewPreviewIDLength                                                  = 48
+	PreviewApplicationIDLength                                              = 48
+	PreviewRevisionLength                                                   = 1024
+	PreviewStateLength                                                      = 30
+	PreviewResolvedRevisionLength                                           = 1024
+	PreviewMessageLength                                                    = 4096
+	DeploymentDependencyGateDeploymentdependencygateUIDIDLength             = 48
+	DeploymentDependencyGateNameLength                                      = 256
+	DeploymentDependencyGateNamespaceLength                                 = 96
+	DeploymentDependencyGateNamespaceUIDLength                              = 48
+	DeploymentDependencyGateStateLength                                     = 32
+	DeploymentDependencyGatePendingDependenciesLength                       = 4096
+	DeploymentDependencyGateDependenciesHashLength                          = 64
 )
 
 // TruncateVarchar converts string to "str..." if chars is > maxLength
@@ -125,6 +148,9 @@ var DbFieldMap = map[string]int{
 	"ClusterCredentialsServiceaccountBearerTokenLength":                       ClusterCredentialsServiceaccountBearerTokenLength,
 	"ClusterCredentialsServiceaccountNsLength":                                ClusterCredentialsServiceaccountNsLength,
 	"ClusterCredentialsNamespacesLength":                                      ClusterCredentialsNamespacesLength,
+	"ClusterCredentialsClientCertificateDataLength":                           ClusterCredentialsClientCertificateDataLength,
+	"ClusterCredentialsClientKeyDataLength":                                   ClusterCredentialsClientKeyDataLength,
+	"ClusterCredentialsCertificateAuthorityDataLength":                        ClusterCredentialsCertificateAuthorityDataLength,
 	"GitopsEngineClusterGitopsengineclusterIDLength":                          GitopsEngineClusterGitopsengineclusterIDLength,
 	"GitopsEngineInstanceGitopsengineinstanceIDLength":                        GitopsEngineInstanceGitopsengineinstanceIDLength,
 	"GitopsEngineInstanceNamespaceNameLength":                                 GitopsEngineInstanceNamespaceNameLength,
@@ -195,6 +221,7 @@ var DbFieldMap = map[string]int{
 	"SyncOperationDeploymentNameFieldLength":                                  SyncOperationDeploymentNameLength,
 	"SyncOperationRevisionLength":                                             SyncOperationRevisionLength,
 	"SyncOperationDesiredStateLength":                                         SyncOperationDesiredStateLength,
+	"SyncOperationOptionsLength":                                              SyncOperationOptionsLength,
 	"RepositoryCredentialsRepositorycredentialsIDLength":                      RepositoryCredentialsRepositorycredentialsIDLength,
 	"RepositoryCredentialsRepoCredUserIDLength":                               RepositoryCredentialsRepoCredUserIDLength,
 	"RepositoryCredentialsRepoCredURLLength":                                  RepositoryCredentialsRepoCredURLLength,
@@ -212,6 +239,25 @@ var DbFieldMap = map[string]int{
 	"AppProjectManagedEnvironmentClusteruserIDLength":                         AppProjectManagedEnvironmentClusteruserIDLength,
 	"ApplicationOwnerApplicationOwnerApplicationIDLength":                     ApplicationOwnerApplicationOwnerApplicationIDLength,
 	"ApplicationOwnerApplicationOwnerUserIDLength":                            ApplicationOwnerApplicationOwnerUserIDLength,
+	"DeploymentHistoryDeploymenthistoryIDLength":                              DeploymentHistoryDeploymenthistoryIDLength,
+	"DeploymentHistoryApplicationIDLength":                                    DeploymentHistoryApplicationIDLength,
+	"DeploymentHistoryRevisionLength":                                         DeploymentHistoryRevisionLength,
+	"DeploymentHistorySourceLength":                                           DeploymentHistorySourceLength,
+	"DeploymentHistoryInitiatedByLength":                                      DeploymentHistoryInitiatedByLength,
+	"DeploymentHistorySyncRunNameLength":                                      DeploymentHistorySyncRunNameLength,
+	"PreviewPreviewIDLength":                                                  PreviewPreviewIDLength,
+	"PreviewApplicationIDLength":                                              PreviewApplicationIDLength,
+	"PreviewRevisionLength":                                                   PreviewRevisionLength,
+	"PreviewStateLength":                                                      PreviewStateLength,
+	"PreviewResolvedRevisionLength":                                           PreviewResolvedRevisionLength,
+	"PreviewMessageLength":                                                    PreviewMessageLength,
+	"DeploymentDependencyGateDeploymentdependencygateUIDIDLength":             DeploymentDependencyGateDeploymentdependencygateUIDIDLength,
+	"DeploymentDependencyGateNameLength":                                      DeploymentDependencyGateNameLength,
+	"DeploymentDependencyGateNamespaceLength":                                 DeploymentDependencyGateNamespaceLength,
+	"DeploymentDependencyGateNamespaceUIDLength":                              DeploymentDependencyGateNamespaceUIDLength,
+	"DeploymentDependencyGateStateLength":                                     DeploymentDependencyGateStateLength,
+	"DeploymentDependencyGatePendingDependenciesLength":                       DeploymentDependencyGatePendingDependenciesLength,
+	"DeploymentDependencyGateDependenciesHashLength":                          DeploymentDependencyGateDependenciesHashLength,
 }
 
 // Get value of constants based on constant variable name given as String.
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (dbq *PostgreSQLDatabaseQueries) CreateDeploymentDependencyGate(ctx context.Context, obj *DeploymentDependencyGate) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if err := isEmptyValues("CreateDeploymentDependencyGate",
+		"Deploymentdependencygate_uid_id", obj.Deploymentdependencygate_uid_id,
+		"Name", obj.Name,
+		"Namespace", obj.Namespace,
+		"Namespace_uid", obj.Namespace_uid,
+		"State", obj.State); err != nil {
+		return err
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	obj.Created_on = time.Now()
+
+	result, err := dbq.dbConnection.Model(obj).Context(ctx).Insert()
+	if err != nil {
+		return fmt.Errorf("error on inserting deployment dependency gate: %v", err)
+	}
+
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("unexpected number of rows affected: %d", result.RowsAffected())
+	}
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) GetDeploymentDependencyGateById(ctx context.Context, obj *DeploymentDependencyGate) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if err := isEmptyValues("GetDeploymentDependencyGateById",
+		"Deploymentdependencygate_uid_id", obj.Deploymentdependencygate_uid_id); err != nil {
+		return err
+	}
+
+	var dbResults []DeploymentDependencyGate
+
+	if err := dbq.dbConnection.Model(&dbResults).
+		Where("ddg.deploymentdependencygate_uid_id = ?", obj.Deploymentdependencygate_uid_id).
+		Context(ctx).
+		Select(); err != nil {
+
+		return fmt.Errorf("error on retrieving GetDeploymentDependencyGateById: %v", err)
+	}
+
+	if len(dbResults) >= 2 {
+		return fmt.Errorf("multiple results returned from GetDeploymentDependencyGateById")
+	}
+
+	if len(dbResults) == 0 {
+		return NewResultNotFoundError("GetDeploymentDependencyGateById")
+	}
+
+	*obj = dbResults[0]
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) UpdateDeploymentDependencyGate(ctx context.Context, obj *DeploymentDependencyGate) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if err := isEmptyValues("UpdateDeploymentDependencyGate",
+		"Deploymentdependencygate_uid_id", obj.Deploymentdependencygate_uid_id,
+		"Name", obj.Name,
+		"Namespace", obj.Namespace,
+		"Namespace_uid", obj.Namespace_uid,
+		"State", obj.State); err != nil {
+		return err
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	result, err := dbq.dbConnection.Model(obj).WherePK().Context(ctx).Update()
+	if err != nil {
+		return fmt.Errorf("error on updating deployment dependency gate: %v", err)
+	}
+
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("%s: %d", ErrorUnexpectedNumberOfRowsAffected, result.RowsAffected())
+	}
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) DeleteDeploymentDependencyGateById(ctx context.Context, id string) (int, error) {
+
+	if err := validateQueryParams(id, dbq); err != nil {
+		return 0, err
+	}
+
+	result := &DeploymentDependencyGate{
+		Deploymentdependencygate_uid_id: id,
+	}
+
+	deleteResult, err := dbq.dbConnection.Model(result).WherePK().Context(ctx).Delete()
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting deployment dependency gate: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+// DeleteDeploymentDependencyGatesByNamespaceAndName deletes the DeploymentDependencyGates of GitOpsDeployments with the
+// given name and namespace: for example, once the GitOpsDeployment has been deleted.
+func (dbq *PostgreSQLDatabaseQueries) DeleteDeploymentDependencyGatesByNamespaceAndName(ctx context.Context, deploymentName string,
+	deploymentNamespace string, namespaceUID string) (int, error) {
+
+	if err := validateQueryParamsNoPK(dbq); err != nil {
+		return 0, err
+	}
+
+	if err := isEmptyValues("DeleteDeploymentDependencyGatesByNamespaceAndName",
+		"deploymentName", deploymentName,
+		"deploymentNamespace", deploymentNamespace,
+		"namespaceUID", namespaceUID); err != nil {
+
+		return 0, err
+	}
+
+	// Index Name is idx_deploymentdependencygate_2
+	deleteResult, err := dbq.dbConnection.Model(&DeploymentDependencyGate{}).
+		Where("ddg.name = ?", deploymentName).
+		Where("ddg.namespace = ?", deploymentNamespace).
+		Where("ddg.namespace_uid = ?", namespaceUID).Context(ctx).Delete()
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting deployment dependency gates: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) UnsafeListAllDeploymentDependencyGates(ctx context.Context, deploymentDependencyGates *[]DeploymentDependencyGate) error {
+
+	if err := validateUnsafeQueryParamsNoPK(dbq); err != nil {
+		return err
+	}
+
+	if err := dbq.dbConnection.Model(deploymentDependencyGates).Context(ctx).Select(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAsLogKeyValues returns an []interface that can be passed to log.Info(...).
+// e.g. log.Info("Creating database resource", obj.GetAsLogKeyValues()...)
+func (obj *DeploymentDependencyGate) GetAsLogKeyValues() []interface{} {
+	if obj == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{"deploymentDependencyGateUID", obj.Deploymentdependencygate_uid_id,
+		"name", obj.Name,
+		"namespace", obj.Namespace,
+		"namespaceUID", obj.Namespace_uid,
+		"state", obj.State,
+		"pendingDependencies", obj.Pending_dependencies}
+}
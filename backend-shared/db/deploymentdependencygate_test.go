@@ -0,0 +1,110 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+)
+
+var _ = Describe("DeploymentDependencyGate Tests", func() {
+	Context("It should execute all DB functions for DeploymentDependencyGate", func() {
+
+		var ctx context.Context
+		var dbq db.AllDatabaseQueries
+
+		BeforeEach(func() {
+			err := db.SetupForTestingDBGinkgo()
+			Expect(err).To(BeNil())
+
+			ctx = context.Background()
+
+			dbq, err = db.NewUnsafePostgresDBQueries(true, true)
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			dbq.CloseDatabase()
+		})
+
+		It("Should create, get, update, and delete a DeploymentDependencyGate", func() {
+
+			gate := &db.DeploymentDependencyGate{
+				Deploymentdependencygate_uid_id: "test-gitopsdepl-uid",
+				Name:                            "my-gitopsdepl",
+				Namespace:                       "jane",
+				Namespace_uid:                   "test-namespace-uid",
+				State:                           db.DeploymentDependencyGateState_Waiting,
+				Pending_dependencies:            "database-operator,database",
+			}
+
+			By("creating the gate")
+			err := dbq.CreateDeploymentDependencyGate(ctx, gate)
+			Expect(err).To(BeNil())
+
+			By("retrieving the gate")
+			fetched := &db.DeploymentDependencyGate{Deploymentdependencygate_uid_id: gate.Deploymentdependencygate_uid_id}
+			err = dbq.GetDeploymentDependencyGateById(ctx, fetched)
+			Expect(err).To(BeNil())
+			Expect(fetched.Name).To(Equal(gate.Name))
+			Expect(fetched.State).To(Equal(db.DeploymentDependencyGateState_Waiting))
+			Expect(fetched.Pending_dependencies).To(Equal(gate.Pending_dependencies))
+
+			By("updating the gate, once the dependencies are satisfied")
+			fetched.State = db.DeploymentDependencyGateState_Satisfied
+			fetched.Pending_dependencies = ""
+			err = dbq.UpdateDeploymentDependencyGate(ctx, fetched)
+			Expect(err).To(BeNil())
+
+			err = dbq.GetDeploymentDependencyGateById(ctx, fetched)
+			Expect(err).To(BeNil())
+			Expect(fetched.State).To(Equal(db.DeploymentDependencyGateState_Satisfied))
+			Expect(fetched.Pending_dependencies).To(BeEmpty())
+
+			By("deleting the gate")
+			rowsAffected, err := dbq.DeleteDeploymentDependencyGateById(ctx, gate.Deploymentdependencygate_uid_id)
+			Expect(err).To(BeNil())
+			Expect(rowsAffected).To(Equal(1))
+
+			err = dbq.GetDeploymentDependencyGateById(ctx, fetched)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
+		It("Should delete the DeploymentDependencyGates of a GitOpsDeployment by name and namespace", func() {
+
+			gate := &db.DeploymentDependencyGate{
+				Deploymentdependencygate_uid_id: "test-gitopsdepl-uid",
+				Name:                            "my-gitopsdepl",
+				Namespace:                       "jane",
+				Namespace_uid:                   "test-namespace-uid",
+				State:                           db.DeploymentDependencyGateState_Satisfied,
+			}
+			err := dbq.CreateDeploymentDependencyGate(ctx, gate)
+			Expect(err).To(BeNil())
+
+			rowsAffected, err := dbq.DeleteDeploymentDependencyGatesByNamespaceAndName(ctx, gate.Name, gate.Namespace, gate.Namespace_uid)
+			Expect(err).To(BeNil())
+			Expect(rowsAffected).To(Equal(1))
+
+			err = dbq.GetDeploymentDependencyGateById(ctx, gate)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
+		It("Should not create a DeploymentDependencyGate with a value that exceeds the maximum length", func() {
+
+			gate := &db.DeploymentDependencyGate{
+				Deploymentdependencygate_uid_id: "test-gitopsdepl-uid",
+				Name:                            "my-gitopsdepl",
+				Namespace:                       "jane",
+				Namespace_uid:                   "test-namespace-uid",
+				State:                           db.DeploymentDependencyGateState_Waiting,
+				Pending_dependencies:            strings.Repeat("abc", 2000),
+			}
+
+			err := dbq.CreateDeploymentDependencyGate(ctx, gate)
+			Expect(db.IsMaxLengthError(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// MaxDeploymentHistoryEntriesPerApplication is the maximum number of DeploymentHistory rows that are retained for
+	// a single Application: when a new entry is added, the oldest entries beyond this number are removed.
+	MaxDeploymentHistoryEntriesPerApplication = 10
+
+	// DeploymentHistory_InitiatedBy_Automated is the value of the 'initiated_by' field, for syncs that were started by
+	// Argo CD's automated sync policy.
+	DeploymentHistory_InitiatedBy_Automated = "automated"
+)
+
+func (dbq *PostgreSQLDatabaseQueries) CreateDeploymentHistory(ctx context.Context, obj *DeploymentHistory) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if dbq.allowTestUuids {
+		if IsEmpty(obj.Deploymenthistory_id) {
+			obj.Deploymenthistory_id = generateUuid()
+		}
+	} else {
+		if !IsEmpty(obj.Deploymenthistory_id) {
+			return fmt.Errorf("primary key should be empty")
+		}
+
+		obj.Deploymenthistory_id = generateUuid()
+	}
+
+	if err := isEmptyValues("CreateDeploymentHistory",
+		"Application_id", obj.Application_id,
+		"Revision", obj.Revision,
+		"Source", obj.Source); err != nil {
+		return err
+	}
+
+	if obj.Deployed_at.IsZero() {
+		return fmt.Errorf("deployed_at field should not be empty")
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	obj.Created_on = time.Now()
+
+	result, err := dbq.dbConnection.Model(obj).Context(ctx).Insert()
+	if err != nil {
+		return fmt.Errorf("error on inserting deployment history: %v", err)
+	}
+
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("unexpected number of rows affected: %d", result.RowsAffected())
+	}
+
+	return nil
+}
+
+// ListDeploymentHistoryByApplicationId returns the DeploymentHistory rows of an Application, ordered from the most
+// recent deployment to the oldest.
+func (dbq *PostgreSQLDatabaseQueries) ListDeploymentHistoryByApplicationId(ctx context.Context, applicationId string,
+	deploymentHistory *[]DeploymentHistory) error {
+
+	if err := validateQueryParamsEntity(deploymentHistory, dbq); err != nil {
+		return err
+	}
+
+	if err := isEmptyValues("ListDeploymentHistoryByApplicationId",
+		"applicationId", applicationId); err != nil {
+		return err
+	}
+
+	var dbResults []DeploymentHistory
+
+	// Index Name is idx_deploymenthistory_application_id
+	if err := dbq.dbConnection.Model(&dbResults).
+		Where("dh.application_id = ?", applicationId).
+		Order("deployed_at DESC", "seq_id DESC").
+		Context(ctx).
+		Select(); err != nil {
+
+		return fmt.Errorf("error on retrieving ListDeploymentHistoryByApplicationId: %v", err)
+	}
+
+	*deploymentHistory = dbResults
+
+	return nil
+}
+
+// PruneDeploymentHistoryByApplicationId deletes all but the 'entriesToKeep' most recent DeploymentHistory rows of an
+// Application. The number of deleted rows is returned.
+func (dbq *PostgreSQLDatabaseQueries) PruneDeploymentHistoryByApplicationId(ctx context.Context, applicationId string, entriesToKeep int) (int, error) {
+
+	if err := validateQueryParams(applicationId, dbq); err != nil {
+		return 0, err
+	}
+
+	if entriesToKeep < 0 {
+		return 0, fmt.Errorf("number of deployment history entries to keep should not be negative: %d", entriesToKeep)
+	}
+
+	entriesToKeepQuery := dbq.dbConnection.Model((*DeploymentHistory)(nil)).
+		Column("deploymenthistory_id").
+		Where("application_id = ?", applicationId).
+		Order("deployed_at DESC", "seq_id DESC").
+		Limit(entriesToKeep)
+
+	deleteResult, err := dbq.dbConnection.Model((*DeploymentHistory)(nil)).
+		Where("application_id = ?", applicationId).
+		Where("deploymenthistory_id NOT IN (?)", entriesToKeepQuery).
+		Context(ctx).
+		Delete()
+	if err != nil {
+		return 0, fmt.Errorf("error on pruning deployment history: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+// DeleteDeploymentHistoryByApplicationId deletes all the DeploymentHistory rows of an Application.
+func (dbq *PostgreSQLDatabaseQueries) DeleteDeploymentHistoryByApplicationId(ctx context.Context, applicationId string) (int, error) {
+
+	if err := validateQueryParams(applicationId, dbq); err != nil {
+		return 0, err
+	}
+
+	deleteResult, err := dbq.dbConnection.Model((*DeploymentHistory)(nil)).
+		Where("application_id = ?", applicationId).
+		Context(ctx).
+		Delete()
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting deployment history: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) UnsafeListAllDeploymentHistory(ctx context.Context, deploymentHistory *[]DeploymentHistory) error {
+
+	if err := validateUnsafeQueryParamsNoPK(dbq); err != nil {
+		return err
+	}
+
+	if err := dbq.dbConnection.Model(deploymentHistory).Context(ctx).Select(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAsLogKeyValues returns an []interface that can be passed to log.Info(...).
+// e.g. log.Info("Creating database resource", obj.GetAsLogKeyValues()...)
+func (obj *DeploymentHistory) GetAsLogKeyValues() []interface{} {
+	if obj == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{"deploymentHistoryID", obj.Deploymenthistory_id,
+		"applicationID", obj.Application_id,
+		"revision", obj.Revision,
+		"deployedAt", obj.Deployed_at,
+		"initiatedBy", obj.Initiated_by,
+		"syncRunName", obj.Sync_run_name,
+		"historyID", obj.History_id}
+}
@@ -0,0 +1,153 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+)
+
+var _ = Describe("DeploymentHistory Tests", func() {
+	Context("It should execute all DB functions for DeploymentHistory", func() {
+
+		var ctx context.Context
+		var dbq db.AllDatabaseQueries
+		var application *db.Application
+
+		BeforeEach(func() {
+			err := db.SetupForTestingDBGinkgo()
+			Expect(err).To(BeNil())
+
+			ctx = context.Background()
+
+			dbq, err = db.NewUnsafePostgresDBQueries(true, true)
+			Expect(err).To(BeNil())
+
+			_, managedEnvironment, _, gitopsEngineInstance, _, err := db.CreateSampleData(dbq)
+			Expect(err).To(BeNil())
+
+			application = &db.Application{
+				Application_id:          "test-my-application",
+				Name:                    "my-application",
+				Spec_field:              "{}",
+				Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+				Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+			}
+
+			err = dbq.CreateApplication(ctx, application)
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			dbq.CloseDatabase()
+		})
+
+		It("Should create, list, and delete DeploymentHistory rows", func() {
+
+			deployedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			historyID := int64(1)
+
+			older := &db.DeploymentHistory{
+				Deploymenthistory_id: "test-deployment-history-1",
+				Application_id:       application.Application_id,
+				Revision:             "abc",
+				Source:               `{"source":{"repoURL":"https://github.com/test/test"}}`,
+				Deployed_at:          deployedAt,
+				Initiated_by:         db.DeploymentHistory_InitiatedBy_Automated,
+			}
+			err := dbq.CreateDeploymentHistory(ctx, older)
+			Expect(err).To(BeNil())
+
+			newer := &db.DeploymentHistory{
+				Deploymenthistory_id: "test-deployment-history-2",
+				Application_id:       application.Application_id,
+				Revision:             "def",
+				Source:               `{"source":{"repoURL":"https://github.com/test/test"}}`,
+				Deployed_at:          deployedAt.Add(time.Minute),
+				Initiated_by:         "admin",
+				Sync_run_name:        "my-sync-run",
+				History_id:           &historyID,
+			}
+			err = dbq.CreateDeploymentHistory(ctx, newer)
+			Expect(err).To(BeNil())
+
+			By("verifying the rows are returned from most recent to oldest")
+			var deploymentHistory []db.DeploymentHistory
+			err = dbq.ListDeploymentHistoryByApplicationId(ctx, application.Application_id, &deploymentHistory)
+			Expect(err).To(BeNil())
+			Expect(deploymentHistory).To(HaveLen(2))
+			Expect(deploymentHistory[0].Deploymenthistory_id).To(Equal(newer.Deploymenthistory_id))
+			Expect(deploymentHistory[0].Revision).To(Equal(newer.Revision))
+			Expect(deploymentHistory[0].Sync_run_name).To(Equal(newer.Sync_run_name))
+			Expect(deploymentHistory[0].Deployed_at.Equal(newer.Deployed_at)).To(BeTrue())
+			Expect(deploymentHistory[0].History_id).ToNot(BeNil())
+			Expect(*deploymentHistory[0].History_id).To(Equal(historyID))
+			Expect(deploymentHistory[1].Deploymenthistory_id).To(Equal(older.Deploymenthistory_id))
+			Expect(deploymentHistory[1].History_id).To(BeNil())
+
+			By("verifying that pruning keeps only the most recent entries")
+			rowsAffected, err := dbq.PruneDeploymentHistoryByApplicationId(ctx, application.Application_id, 1)
+			Expect(err).To(BeNil())
+			Expect(rowsAffected).To(Equal(1))
+
+			err = dbq.ListDeploymentHistoryByApplicationId(ctx, application.Application_id, &deploymentHistory)
+			Expect(err).To(BeNil())
+			Expect(deploymentHistory).To(HaveLen(1))
+			Expect(deploymentHistory[0].Deploymenthistory_id).To(Equal(newer.Deploymenthistory_id))
+
+			By("verifying that all rows of the Application are deleted")
+			rowsAffected, err = dbq.DeleteDeploymentHistoryByApplicationId(ctx, application.Application_id)
+			Expect(err).To(BeNil())
+			Expect(rowsAffected).To(Equal(1))
+
+			err = dbq.ListDeploymentHistoryByApplicationId(ctx, application.Application_id, &deploymentHistory)
+			Expect(err).To(BeNil())
+			Expect(deploymentHistory).To(BeEmpty())
+		})
+
+		It("Should delete the DeploymentHistory rows of an Application when the Application is deleted", func() {
+
+			err := dbq.CreateDeploymentHistory(ctx, &db.DeploymentHistory{
+				Deploymenthistory_id: "test-deployment-history-1",
+				Application_id:       application.Application_id,
+				Revision:             "abc",
+				Source:               `{"source":{"repoURL":"https://github.com/test/test"}}`,
+				Deployed_at:          time.Now(),
+				Initiated_by:         db.DeploymentHistory_InitiatedBy_Automated,
+			})
+			Expect(err).To(BeNil())
+
+			rowsAffected, err := dbq.DeleteApplicationById(ctx, application.Application_id)
+			Expect(err).To(BeNil())
+			Expect(rowsAffected).To(Equal(1))
+
+			var deploymentHistory []db.DeploymentHistory
+			err = dbq.ListDeploymentHistoryByApplicationId(ctx, application.Application_id, &deploymentHistory)
+			Expect(err).To(BeNil())
+			Expect(deploymentHistory).To(BeEmpty())
+		})
+
+		It("Should return an error if the DeploymentHistory is missing required fields, or exceeds field lengths", func() {
+
+			deploymentHistory := &db.DeploymentHistory{
+				Application_id: application.Application_id,
+				Revision:       "abc",
+				Source:         "{}",
+			}
+			err := dbq.CreateDeploymentHistory(ctx, deploymentHistory)
+			Expect(err).ToNot(BeNil())
+
+			deploymentHistory = &db.DeploymentHistory{
+				Application_id: application.Application_id,
+				Revision:       strings.Repeat("abc", 1024),
+				Source:         "{}",
+				Deployed_at:    time.Now(),
+			}
+			err = dbq.CreateDeploymentHistory(ctx, deploymentHistory)
+			Expect(db.IsMaxLengthError(err)).To(BeTrue())
+		})
+	})
+})
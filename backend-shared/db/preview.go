@@ -0,0 +1,175 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (dbq *PostgreSQLDatabaseQueries) CreatePreview(ctx context.Context, obj *Preview) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if dbq.allowTestUuids {
+		if IsEmpty(obj.Preview_id) {
+			obj.Preview_id = generateUuid()
+		}
+	} else {
+		if !IsEmpty(obj.Preview_id) {
+			return fmt.Errorf("primary key should be empty")
+		}
+
+		obj.Preview_id = generateUuid()
+	}
+
+	if err := isEmptyValues("CreatePreview",
+		"Application_id", obj.Application_id,
+		"Revision", obj.Revision,
+		"State", obj.State); err != nil {
+		return err
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	obj.Created_on = time.Now()
+
+	result, err := dbq.dbConnection.Model(obj).Context(ctx).Insert()
+	if err != nil {
+		return fmt.Errorf("error on inserting preview: %v", err)
+	}
+
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("unexpected number of rows affected: %d", result.RowsAffected())
+	}
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) GetPreviewById(ctx context.Context, preview *Preview) error {
+
+	if err := validateQueryParamsEntity(preview, dbq); err != nil {
+		return err
+	}
+
+	if IsEmpty(preview.Preview_id) {
+		return fmt.Errorf("preview id is empty")
+	}
+
+	var dbResults []Preview
+
+	if err := dbq.dbConnection.Model(&dbResults).
+		Where("pv.preview_id = ?", preview.Preview_id).
+		Context(ctx).
+		Select(); err != nil {
+
+		return fmt.Errorf("error on retrieving GetPreviewById: %v", err)
+	}
+
+	if len(dbResults) >= 2 {
+		return fmt.Errorf("multiple results returned from GetPreviewById")
+	}
+
+	if len(dbResults) == 0 {
+		return NewResultNotFoundError("no results found for GetPreviewById")
+	}
+
+	*preview = dbResults[0]
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) UpdatePreview(ctx context.Context, obj *Preview) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if err := isEmptyValues("UpdatePreview",
+		"preview_id", obj.Preview_id,
+		"application_id", obj.Application_id,
+		"revision", obj.Revision,
+		"state", obj.State,
+	); err != nil {
+		return err
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	result, err := dbq.dbConnection.Model(obj).WherePK().Context(ctx).Update()
+	if err != nil {
+		return fmt.Errorf("error on updating Preview: %v, %v", err, obj.Preview_id)
+	}
+
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("unexpected number of rows affected: %d, %v", result.RowsAffected(), obj.Preview_id)
+	}
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) DeletePreviewById(ctx context.Context, id string) (int, error) {
+
+	if err := validateQueryParams(id, dbq); err != nil {
+		return 0, err
+	}
+
+	deleteResult, err := dbq.dbConnection.Model(&Preview{}).
+		Where("pv.preview_id = ?", id).
+		Context(ctx).
+		Delete()
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting preview: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+// DeletePreviewsByApplicationId deletes all the Preview rows of an Application.
+func (dbq *PostgreSQLDatabaseQueries) DeletePreviewsByApplicationId(ctx context.Context, applicationId string) (int, error) {
+
+	if err := validateQueryParams(applicationId, dbq); err != nil {
+		return 0, err
+	}
+
+	deleteResult, err := dbq.dbConnection.Model((*Preview)(nil)).
+		Where("application_id = ?", applicationId).
+		Context(ctx).
+		Delete()
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting previews: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) UnsafeListAllPreviews(ctx context.Context, previews *[]Preview) error {
+
+	if err := validateUnsafeQueryParamsNoPK(dbq); err != nil {
+		return err
+	}
+
+	if err := dbq.dbConnection.Model(previews).Context(ctx).Select(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAsLogKeyValues returns an []interface that can be passed to log.Info(...).
+// e.g. log.Info("Creating database resource", obj.GetAsLogKeyValues()...)
+func (obj *Preview) GetAsLogKeyValues() []interface{} {
+	if obj == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{"previewID", obj.Preview_id,
+		"applicationID", obj.Application_id,
+		"revision", obj.Revision,
+		"state", obj.State}
+}
@@ -0,0 +1,134 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+)
+
+var _ = Describe("Preview Tests", func() {
+	Context("It should execute all DB functions for Preview", func() {
+
+		var ctx context.Context
+		var dbq db.AllDatabaseQueries
+		var application *db.Application
+
+		BeforeEach(func() {
+			err := db.SetupForTestingDBGinkgo()
+			Expect(err).To(BeNil())
+
+			ctx = context.Background()
+
+			dbq, err = db.NewUnsafePostgresDBQueries(true, true)
+			Expect(err).To(BeNil())
+
+			_, managedEnvironment, _, gitopsEngineInstance, _, err := db.CreateSampleData(dbq)
+			Expect(err).To(BeNil())
+
+			application = &db.Application{
+				Application_id:          "test-my-application",
+				Name:                    "my-application",
+				Spec_field:              "{}",
+				Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+				Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+			}
+
+			err = dbq.CreateApplication(ctx, application)
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			dbq.CloseDatabase()
+		})
+
+		It("Should create, get, update, and delete a Preview", func() {
+
+			preview := &db.Preview{
+				Preview_id:     "test-preview-1",
+				Application_id: application.Application_id,
+				Revision:       "my-branch",
+				State:          db.PreviewState_Waiting,
+			}
+			err := dbq.CreatePreview(ctx, preview)
+			Expect(err).To(BeNil())
+
+			fetched := &db.Preview{Preview_id: preview.Preview_id}
+			err = dbq.GetPreviewById(ctx, fetched)
+			Expect(err).To(BeNil())
+			Expect(fetched.Revision).To(Equal("my-branch"))
+			Expect(fetched.State).To(Equal(db.PreviewState_Waiting))
+			Expect(fetched.Created_on.IsZero()).To(BeFalse())
+
+			By("updating the Preview with the result")
+			fetched.State = db.PreviewState_Completed
+			fetched.Resolved_revision = "abc123"
+			fetched.Resources = []byte("resources")
+			err = dbq.UpdatePreview(ctx, fetched)
+			Expect(err).To(BeNil())
+
+			updated := &db.Preview{Preview_id: preview.Preview_id}
+			err = dbq.GetPreviewById(ctx, updated)
+			Expect(err).To(BeNil())
+			Expect(updated.State).To(Equal(db.PreviewState_Completed))
+			Expect(updated.Resolved_revision).To(Equal("abc123"))
+			Expect(updated.Resources).To(Equal([]byte("resources")))
+
+			By("deleting the Preview")
+			rowsDeleted, err := dbq.DeletePreviewById(ctx, preview.Preview_id)
+			Expect(err).To(BeNil())
+			Expect(rowsDeleted).To(Equal(1))
+
+			err = dbq.GetPreviewById(ctx, &db.Preview{Preview_id: preview.Preview_id})
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
+		It("Should delete all the Previews of an Application", func() {
+
+			for _, id := range []string{"test-preview-1", "test-preview-2"} {
+				err := dbq.CreatePreview(ctx, &db.Preview{
+					Preview_id:     id,
+					Application_id: application.Application_id,
+					Revision:       "my-branch",
+					State:          db.PreviewState_Waiting,
+				})
+				Expect(err).To(BeNil())
+			}
+
+			rowsDeleted, err := dbq.DeletePreviewsByApplicationId(ctx, application.Application_id)
+			Expect(err).To(BeNil())
+			Expect(rowsDeleted).To(Equal(2))
+		})
+
+		It("Should delete the Previews of an Application when the Application is deleted", func() {
+
+			preview := &db.Preview{
+				Preview_id:     "test-preview-1",
+				Application_id: application.Application_id,
+				Revision:       "my-branch",
+				State:          db.PreviewState_Waiting,
+			}
+			Expect(dbq.CreatePreview(ctx, preview)).To(Succeed())
+
+			rowsDeleted, err := dbq.DeleteApplicationById(ctx, application.Application_id)
+			Expect(err).To(BeNil())
+			Expect(rowsDeleted).To(Equal(1))
+
+			err = dbq.GetPreviewById(ctx, &db.Preview{Preview_id: preview.Preview_id})
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
+		It("Should return an error if the revision exceeds the maximum length", func() {
+
+			err := dbq.CreatePreview(ctx, &db.Preview{
+				Preview_id:     "test-preview-1",
+				Application_id: application.Application_id,
+				Revision:       strings.Repeat("abc", 1000),
+				State:          db.PreviewState_Waiting,
+			})
+			Expect(db.IsMaxLengthError(err)).To(BeTrue())
+		})
+	})
+})
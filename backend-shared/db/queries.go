@@ -45,6 +45,9 @@ import (
 type UnsafeDatabaseQueries interface {
 	UnsafeListAllApplications(ctx context.Context, applications *[]Application) error
 	UnsafeListAllApplicationStates(ctx context.Context, applicationStates *[]ApplicationState) error
+	UnsafeListAllDeploymentHistory(ctx context.Context, deploymentHistory *[]DeploymentHistory) error
+	UnsafeListAllPreviews(ctx context.Context, previews *[]Preview) error
+	UnsafeListAllDeploymentDependencyGates(ctx context.Context, deploymentDependencyGates *[]DeploymentDependencyGate) error
 	UnsafeListAllClusterAccess(ctx context.Context, clusterAccess *[]ClusterAccess) error
 	UnsafeListAllClusterCredentials(ctx context.Context, clusterCredentials *[]ClusterCredentials) error
 	UnsafeListAllClusterUsers(ctx context.Context, clusterUsers *[]ClusterUser) error
@@ -202,6 +205,9 @@ type DatabaseQueries interface {
 // ApplicationScopedQueries are the set of database queries that act on application DB resources:
 // - Application
 // - ApplicateState
+// - DeploymentHistory
+// - Preview
+// - DeploymentDependencyGate
 // - Operation
 // - SyncOperation
 // - APICRToDatabaseMapping
@@ -283,6 +289,36 @@ type ApplicationScopedQueries interface {
 	UpdateApplicationState(ctx context.Context, obj *ApplicationState) error
 	DeleteApplicationStateById(ctx context.Context, id string) (int, error)
 
+	CreateDeploymentHistory(ctx context.Context, obj *DeploymentHistory) error
+
+	// ListDeploymentHistoryByApplicationId returns the DeploymentHistory rows of an Application, ordered from the most
+	// recent deployment to the oldest.
+	ListDeploymentHistoryByApplicationId(ctx context.Context, applicationId string, deploymentHistory *[]DeploymentHistory) error
+
+	// PruneDeploymentHistoryByApplicationId deletes all but the 'entriesToKeep' most recent DeploymentHistory rows of an
+	// Application.
+	PruneDeploymentHistoryByApplicationId(ctx context.Context, applicationId string, entriesToKeep int) (int, error)
+
+	// DeleteDeploymentHistoryByApplicationId deletes all the DeploymentHistory rows of an Application.
+	DeleteDeploymentHistoryByApplicationId(ctx context.Context, applicationId string) (int, error)
+
+	CreatePreview(ctx context.Context, obj *Preview) error
+	GetPreviewById(ctx context.Context, preview *Preview) error
+	UpdatePreview(ctx context.Context, obj *Preview) error
+	DeletePreviewById(ctx context.Context, id string) (int, error)
+
+	// DeletePreviewsByApplicationId deletes all the Preview rows of an Application.
+	DeletePreviewsByApplicationId(ctx context.Context, applicationId string) (int, error)
+
+	CreateDeploymentDependencyGate(ctx context.Context, obj *DeploymentDependencyGate) error
+	GetDeploymentDependencyGateById(ctx context.Context, obj *DeploymentDependencyGate) error
+	UpdateDeploymentDependencyGate(ctx context.Context, obj *DeploymentDependencyGate) error
+	DeleteDeploymentDependencyGateById(ctx context.Context, id string) (int, error)
+
+	// DeleteDeploymentDependencyGatesByNamespaceAndName deletes the DeploymentDependencyGates of GitOpsDeployments with
+	// the given name and namespace.
+	DeleteDeploymentDependencyGatesByNamespaceAndName(ctx context.Context, deploymentName string, deploymentNamespace string, namespaceUID string) (int, error)
+
 	GetManagedEnvironmentById(ctx context.Context, managedEnvironment *ManagedEnvironment) error
 
 	GetGitopsEngineInstanceById(ctx context.Context, engineInstanceParam *GitopsEngineInstance) error
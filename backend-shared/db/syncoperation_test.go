@@ -83,6 +83,29 @@ var _ = Describe("SyncOperation Tests", func() {
 			err = dbq.GetSyncOperationById(ctx, &fetchRow)
 			Expect(err).To(BeNil())
 			Expect(fetchRow.DesiredState).Should(Equal(updatedSyncOperation.DesiredState))
+			Expect(fetchRow.RollbackHistoryID).To(BeNil())
+
+			By("verifying that a rollback history ID of 0 is distinguished from no rollback")
+			rollbackHistoryID := int64(0)
+			updatedSyncOperation.RollbackHistoryID = &rollbackHistoryID
+
+			err = dbq.UpdateSyncOperation(ctx, &updatedSyncOperation)
+			Expect(err).To(BeNil())
+
+			err = dbq.GetSyncOperationById(ctx, &fetchRow)
+			Expect(err).To(BeNil())
+			Expect(fetchRow.RollbackHistoryID).ToNot(BeNil())
+			Expect(*fetchRow.RollbackHistoryID).To(Equal(rollbackHistoryID))
+
+			By("verifying that the sync options are stored and retrieved")
+			updatedSyncOperation.Options = `{"prune":true,"strategy":"apply"}`
+
+			err = dbq.UpdateSyncOperation(ctx, &updatedSyncOperation)
+			Expect(err).To(BeNil())
+
+			err = dbq.GetSyncOperationById(ctx, &fetchRow)
+			Expect(err).To(BeNil())
+			Expect(fetchRow.Options).To(Equal(updatedSyncOperation.Options))
 
 			rowCount, err := dbq.DeleteSyncOperationById(ctx, insertRow.SyncOperation_id)
 			Expect(err).To(BeNil())
@@ -111,6 +111,15 @@ type ClusterCredentials struct {
 	// -- State 2) The namespace of the ServiceAccount
 	Serviceaccount_ns string `pg:"serviceaccount_ns"`
 
+	// -- State 2) As an alternative to a ServiceAccount bearer token: the PEM-encoded client certificate and key of
+	// -- the user in the kubeconfig context
+	Client_certificate_data string `pg:"client_certificate_data"`
+	Client_key_data         string `pg:"client_key_data"`
+
+	// -- The PEM-encoded certificate authorities of the cluster, from the kubeconfig context. If empty, the system
+	// -- certificate authorities are used.
+	Certificate_authority_data string `pg:"certificate_authority_data"`
+
 	// -- Indicates that ArgoCD/GitOps Service should not check the TLS certificate.
 	AllowInsecureSkipTLSVerify bool `pg:"allowinsecure_skiptlsverify"`
 
@@ -183,6 +192,18 @@ const (
 	OperationResourceType_Application           OperationResourceType = "Application"
 	OperationResourceType_RepositoryCredentials OperationResourceType = "RepositoryCredentials"
 	OperationResourceType_GitOpsEngineInstance  OperationResourceType = "GitOpsEngineInstance"
+
+	// OperationResourceType_TerminateSyncOperation requests that the cluster-agent terminates the Argo CD sync operation
+	// of a SyncOperation, if it is still running. The resource ID is the ID of the SyncOperation.
+	OperationResourceType_TerminateSyncOperation OperationResourceType = "TerminateSyncOperation"
+
+	// OperationResourceType_RefreshApplication requests that the cluster-agent asks Argo CD to refresh an Application,
+	// for example because new commits were pushed to its repository. The resource ID is the ID of the Application.
+	OperationResourceType_RefreshApplication OperationResourceType = "RefreshApplication"
+
+	// OperationResourceType_Preview requests that the cluster-agent computes the difference between the live resources
+	// of an Application, and the resources at an alternate revision. The resource ID is the ID of the Preview.
+	OperationResourceType_Preview OperationResourceType = "Preview"
 )
 
 // Operation
@@ -438,6 +459,14 @@ type SyncOperation struct {
 
 	DesiredState string `pg:"desired_state"`
 
+	// RollbackHistoryID is the ID of the entry of the Argo CD Application's deployment history to roll back to.
+	// It is nil if the SyncOperation is a regular sync, rather than a rollback.
+	RollbackHistoryID *int64 `pg:"rollback_history_id"`
+
+	// Options contains the options of the sync (prune, dryRun, force, resources, syncOptions and strategy), as
+	// a JSON-serialized fauxargocd.FauxSyncOperationOptions. It is empty if no options were specified.
+	Options string `pg:"options"`
+
 	Created_on time.Time `pg:"created_on"`
 }
 
@@ -562,6 +591,136 @@ type ApplicationOwner struct {
 	Created_on time.Time `pg:"created_on"`
 }
 
+// DeploymentHistory is a record of a successful sync of an Application, by Argo CD.
+// A bounded number of entries are kept per Application: see MaxDeploymentHistoryEntriesPerApplication.
+type DeploymentHistory struct {
+
+	//lint:ignore U1000 used by go-pg
+	tableName struct{} `pg:"deploymenthistory,alias:dh"` //nolint
+
+	// -- Primary key for the DeploymentHistory (UID), is a random UUID
+	Deploymenthistory_id string `pg:"deploymenthistory_id,pk"`
+
+	// -- Foreign key to: Application.application_id
+	Application_id string `pg:"application_id,notnull"`
+
+	// -- The revision that was deployed
+	// -- For multi-source Applications, this is a comma-separated list of the revision of each source.
+	Revision string `pg:"revision,notnull"`
+
+	// -- JSON string containing the source (or sources) that was deployed: see fauxargocd.FauxDeploymentHistorySource
+	Source string `pg:"source,notnull"`
+
+	// -- When the sync operation finished
+	Deployed_at time.Time `pg:"deployed_at,notnull"`
+
+	// -- Who initiated the sync: either 'automated', or the name of the user that requested the sync
+	Initiated_by string `pg:"initiated_by"`
+
+	// -- The name of the GitOpsDeploymentSyncRun CR that requested the sync, if any
+	Sync_run_name string `pg:"sync_run_name"`
+
+	// -- The ID of the corresponding entry of the Argo CD Application's deployment history, if known
+	History_id *int64 `pg:"history_id"`
+
+	SeqID int64 `pg:"seq_id"`
+
+	// -- When DeploymentHistory was created, which allows us to tell how old the resources are
+	Created_on time.Time `pg:"created_on"`
+}
+
+// DeploymentDependencyGate records whether a GitOpsDeployment that depends on other GitOpsDeployments (via its
+// .spec.dependsOn field) is still waiting for those dependencies to be synced and healthy, before its Application is
+// created.
+type DeploymentDependencyGate struct {
+
+	//lint:ignore U1000 used by go-pg
+	tableName struct{} `pg:"deploymentdependencygate,alias:ddg"` //nolint
+
+	// -- uid of our gitops deployment CR within the K8s namespace
+	Deploymentdependencygate_uid_id string `pg:"deploymentdependencygate_uid_id,pk"`
+
+	// -- name of the GitOpsDeployment CR in the API namespace
+	Name string `pg:"name,notnull"`
+	// -- name of the API namespace
+	Namespace string `pg:"namespace,notnull"`
+	// -- uid of the API namespace
+	Namespace_uid string `pg:"namespace_uid,notnull"`
+
+	// -- Whether the dependencies of the GitOpsDeployment have been satisfied: see DeploymentDependencyGateState
+	State DeploymentDependencyGateState `pg:"state,notnull"`
+
+	// -- Comma-separated list of the names of the dependencies that are not yet synced and healthy
+	Pending_dependencies string `pg:"pending_dependencies"`
+
+	// -- SHA-256 hash of the names in .spec.dependsOn of the GitOpsDeployment, when the gate was last evaluated
+	Dependencies_hash string `pg:"dependencies_hash"`
+
+	SeqID int64 `pg:"seq_id"`
+
+	// -- When DeploymentDependencyGate was created, which allows us to tell how old the resources are
+	Created_on time.Time `pg:"created_on"`
+}
+
+type DeploymentDependencyGateState string
+
+const (
+	// DeploymentDependencyGateState_Waiting indicates that at least one dependency is not yet synced and healthy, and
+	// thus the Application of the GitOpsDeployment should not (yet) be created.
+	DeploymentDependencyGateState_Waiting DeploymentDependencyGateState = "Waiting"
+
+	// DeploymentDependencyGateState_Satisfied indicates that all the dependencies were synced and healthy, and thus
+	// the Application of the GitOpsDeployment may be created.
+	DeploymentDependencyGateState_Satisfied DeploymentDependencyGateState = "Satisfied"
+)
+
+// PreviewState is the state of the computation of a Preview, by the cluster-agent
+type PreviewState string
+
+const (
+	PreviewState_Waiting   PreviewState = "Waiting"
+	PreviewState_Completed PreviewState = "Completed"
+	PreviewState_Failed    PreviewState = "Failed"
+)
+
+// Preview is a request, from the backend to the cluster-agent, to compute the difference between the live resources of
+// an Application, and the resources at an alternate revision. A Preview row only exists for as long as the backend is
+// waiting for the result.
+type Preview struct {
+
+	//lint:ignore U1000 used by go-pg
+	tableName struct{} `pg:"preview,alias:pv"` //nolint
+
+	// -- Primary key for the Preview (UID), is a random UUID
+	Preview_id string `pg:"preview_id,pk"`
+
+	// -- Foreign key to: Application.application_id
+	Application_id string `pg:"application_id,notnull"`
+
+	// -- The alternate revision to compare the live resources with
+	Revision string `pg:"revision,notnull"`
+
+	// -- Possible values: Waiting, Completed, Failed (see PreviewState)
+	State PreviewState `pg:"state,notnull"`
+
+	// -- The revision that 'revision' resolved to, once the Preview is Completed
+	Resolved_revision string `pg:"resolved_revision"`
+
+	// -- If the Preview Failed, the reason it failed
+	Message string `pg:"message"`
+
+	// -- Compressed JSON, containing the resources that would be added, changed, or removed ([]PreviewResourceDiff)
+	Resources []byte `pg:"resources"`
+
+	// -- True if not all resources, or not all of their diffs, are included in 'resources', as the preview was too large
+	Resources_truncated bool `pg:"resources_truncated"`
+
+	SeqID int64 `pg:"seq_id"`
+
+	// -- When Preview was created, which allows us to tell how old the resources are
+	Created_on time.Time `pg:"created_on"`
+}
+
 // hasEmptyValues returns error if any of the notnull tagged fields are empty.
 func (rc *RepositoryCredentials) hasEmptyValues(fieldNamesToIgnore ...string) error {
 	s := reflect.ValueOf(rc).Elem()
@@ -37,6 +37,18 @@ var _ = Describe("Types Test", func() {
 			err = dbq.UnsafeListAllApplications(ctx, &applications)
 			Expect(err).To(BeNil())
 
+			var deploymentHistory []db.DeploymentHistory
+			err = dbq.UnsafeListAllDeploymentHistory(ctx, &deploymentHistory)
+			Expect(err).To(BeNil())
+
+			var deploymentDependencyGates []db.DeploymentDependencyGate
+			err = dbq.UnsafeListAllDeploymentDependencyGates(ctx, &deploymentDependencyGates)
+			Expect(err).To(BeNil())
+
+			var previews []db.Preview
+			err = dbq.UnsafeListAllPreviews(ctx, &previews)
+			Expect(err).To(BeNil())
+
 			var clusterAccess []db.ClusterAccess
 			err = dbq.UnsafeListAllClusterAccess(ctx, &clusterAccess)
 			Expect(err).To(BeNil())
@@ -375,6 +375,146 @@ func (cdb *ChaosDBClient) DeleteApplicationStateById(ctx context.Context, id str
 
 }
 
+func (cdb *ChaosDBClient) CreateDeploymentHistory(ctx context.Context, obj *DeploymentHistory) error {
+
+	if err := shouldSimulateFailure("CreateDeploymentHistory", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.CreateDeploymentHistory(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) ListDeploymentHistoryByApplicationId(ctx context.Context, applicationId string, deploymentHistory *[]DeploymentHistory) error {
+
+	if err := shouldSimulateFailure("ListDeploymentHistoryByApplicationId", applicationId, deploymentHistory); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.ListDeploymentHistoryByApplicationId(ctx, applicationId, deploymentHistory)
+
+}
+
+func (cdb *ChaosDBClient) PruneDeploymentHistoryByApplicationId(ctx context.Context, applicationId string, entriesToKeep int) (int, error) {
+
+	if err := shouldSimulateFailure("PruneDeploymentHistoryByApplicationId", applicationId, entriesToKeep); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.PruneDeploymentHistoryByApplicationId(ctx, applicationId, entriesToKeep)
+
+}
+
+func (cdb *ChaosDBClient) DeleteDeploymentHistoryByApplicationId(ctx context.Context, applicationId string) (int, error) {
+
+	if err := shouldSimulateFailure("DeleteDeploymentHistoryByApplicationId", applicationId); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeleteDeploymentHistoryByApplicationId(ctx, applicationId)
+
+}
+
+func (cdb *ChaosDBClient) CreateDeploymentDependencyGate(ctx context.Context, obj *DeploymentDependencyGate) error {
+
+	if err := shouldSimulateFailure("CreateDeploymentDependencyGate", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.CreateDeploymentDependencyGate(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) GetDeploymentDependencyGateById(ctx context.Context, obj *DeploymentDependencyGate) error {
+
+	if err := shouldSimulateFailure("GetDeploymentDependencyGateById", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.GetDeploymentDependencyGateById(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) UpdateDeploymentDependencyGate(ctx context.Context, obj *DeploymentDependencyGate) error {
+
+	if err := shouldSimulateFailure("UpdateDeploymentDependencyGate", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.UpdateDeploymentDependencyGate(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) DeleteDeploymentDependencyGateById(ctx context.Context, id string) (int, error) {
+
+	if err := shouldSimulateFailure("DeleteDeploymentDependencyGateById", id); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeleteDeploymentDependencyGateById(ctx, id)
+
+}
+
+func (cdb *ChaosDBClient) DeleteDeploymentDependencyGatesByNamespaceAndName(ctx context.Context, deploymentName string, deploymentNamespace string, namespaceUID string) (int, error) {
+
+	if err := shouldSimulateFailure("DeleteDeploymentDependencyGatesByNamespaceAndName", deploymentName, deploymentNamespace, namespaceUID); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeleteDeploymentDependencyGatesByNamespaceAndName(ctx, deploymentName, deploymentNamespace, namespaceUID)
+
+}
+
+func (cdb *ChaosDBClient) CreatePreview(ctx context.Context, obj *Preview) error {
+
+	if err := shouldSimulateFailure("CreatePreview", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.CreatePreview(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) GetPreviewById(ctx context.Context, preview *Preview) error {
+
+	if err := shouldSimulateFailure("GetPreviewById", preview); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.GetPreviewById(ctx, preview)
+
+}
+
+func (cdb *ChaosDBClient) UpdatePreview(ctx context.Context, obj *Preview) error {
+
+	if err := shouldSimulateFailure("UpdatePreview", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.UpdatePreview(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) DeletePreviewById(ctx context.Context, id string) (int, error) {
+
+	if err := shouldSimulateFailure("DeletePreviewById", id); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeletePreviewById(ctx, id)
+
+}
+
+func (cdb *ChaosDBClient) DeletePreviewsByApplicationId(ctx context.Context, applicationId string) (int, error) {
+
+	if err := shouldSimulateFailure("DeletePreviewsByApplicationId", applicationId); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeletePreviewsByApplicationId(ctx, applicationId)
+
+}
+
 func (cdb *ChaosDBClient) GetManagedEnvironmentById(ctx context.Context, managedEnvironment *ManagedEnvironment) error {
 
 	if err := shouldSimulateFailure("GetManagedEnvironmentById", managedEnvironment); err != nil {
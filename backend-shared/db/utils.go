@@ -337,6 +337,28 @@ func SetupForTestingDBGinkgo() error {
 		}
 	}
 
+	var previews []Preview
+	err = dbq.UnsafeListAllPreviews(ctx, &previews)
+	Expect(err).To(BeNil())
+
+	for _, preview := range previews {
+		if strings.HasPrefix(preview.Application_id, "test-") {
+			_, err := dbq.DeletePreviewById(ctx, preview.Preview_id)
+			Expect(err).To(BeNil())
+		}
+	}
+
+	var deploymentHistory []DeploymentHistory
+	err = dbq.UnsafeListAllDeploymentHistory(ctx, &deploymentHistory)
+	Expect(err).To(BeNil())
+
+	for _, deploymentHistoryEntry := range deploymentHistory {
+		if strings.HasPrefix(deploymentHistoryEntry.Application_id, "test-") {
+			_, err := dbq.DeleteDeploymentHistoryByApplicationId(ctx, deploymentHistoryEntry.Application_id)
+			Expect(err).To(BeNil())
+		}
+	}
+
 	var applicationStates []ApplicationState
 	err = dbq.UnsafeListAllApplicationStates(ctx, &applicationStates)
 	Expect(err).To(BeNil())
@@ -422,6 +444,17 @@ func SetupForTestingDBGinkgo() error {
 	err = removeAnyRepositoryCredentialsTestEntries(ctx, dbq)
 	Expect(err).To(BeNil())
 
+	var deploymentDependencyGates []DeploymentDependencyGate
+	err = dbq.UnsafeListAllDeploymentDependencyGates(ctx, &deploymentDependencyGates)
+	Expect(err).To(BeNil())
+
+	for _, deploymentDependencyGate := range deploymentDependencyGates {
+		if strings.HasPrefix(deploymentDependencyGate.Deploymentdependencygate_uid_id, "test-") {
+			_, err := dbq.DeleteDeploymentDependencyGateById(ctx, deploymentDependencyGate.Deploymentdependencygate_uid_id)
+			Expect(err).To(BeNil())
+		}
+	}
+
 	var deploymentToApplicationMappings []DeploymentToApplicationMapping
 
 	err = dbq.UnsafeListAllDeploymentToApplicationMapping(ctx, &deploymentToApplicationMappings)
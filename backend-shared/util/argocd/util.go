@@ -1,10 +1,13 @@
 package argocd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
 )
 
 const (
@@ -13,6 +16,15 @@ const (
 	// ArgoCDDefaultDestinationInCluster is 'in-cluster' which is the spec destination value that Argo CD recognizes
 	// as indicating that Argo CD should deploy to the local cluster (the cluster that Argo CD is installed on).
 	ArgoCDDefaultDestinationInCluster = "in-cluster"
+
+	// ArgoCDOperationInfoSyncOperationIDKey is the name of the Argo CD operation info item that is added to the sync
+	// operations started by the cluster-agent on behalf of a SyncOperation: the value is the SyncOperation's primary key.
+	ArgoCDOperationInfoSyncOperationIDKey = "managed-gitops.redhat.com/syncoperation-id"
+
+	// ArgoCDApplicationDeletionPolicyAnnotation is the annotation of an Argo CD Application that contains the deletion
+	// policy of the corresponding GitOpsDeployment: for example, 'Orphan' if the deployed resources should be left in
+	// place when the Application is deleted. If absent, the deployed resources are deleted along with the Application.
+	ArgoCDApplicationDeletionPolicyAnnotation = "managed-gitops.redhat.com/deletion-policy"
 )
 
 // GenerateArgoCDClusterSecretName generates the name of the Argo CD cluster secret (and the name of the server within Argo CD).
@@ -58,8 +70,48 @@ func ConvertArgoCDClusterSecretNameToManagedIdDatabaseRowId(argoCDClusterSecretN
 
 type ClusterSecretTLSClientConfigJSON struct {
 	Insecure bool `json:"insecure"`
+
+	// CertData and KeyData are the PEM-encoded client certificate and key, for clusters that authenticate with a
+	// client certificate rather than a bearer token.
+	CertData []byte `json:"certData,omitempty"`
+	KeyData  []byte `json:"keyData,omitempty"`
+
+	// CAData is the PEM-encoded certificate authority of the cluster, if it is not signed by a trusted CA.
+	CAData []byte `json:"caData,omitempty"`
 }
 type ClusterSecretConfigJSON struct {
 	BearerToken     string                           `json:"bearerToken"`
 	TLSClientConfig ClusterSecretTLSClientConfigJSON `json:"tlsClientConfig"`
 }
+
+// GenerateRESTConfigFromClusterSecret returns a REST config for the cluster that is described by an Argo CD cluster
+// secret, such as the cluster secrets that the cluster-agent generates for managed environments.
+func GenerateRESTConfigFromClusterSecret(clusterSecret corev1.Secret) (*rest.Config, error) {
+
+	server := string(clusterSecret.Data["server"])
+	if server == "" {
+		return nil, fmt.Errorf("cluster secret '%s' does not contain a server", clusterSecret.Name)
+	}
+
+	// The cluster-agent appends a '?managedEnvironment=(id)' query parameter to the API URL of the cluster, which must
+	// not be included in the URL used by a K8s client.
+	if index := strings.Index(server, "?"); index != -1 {
+		server = server[:index]
+	}
+
+	configJSON := ClusterSecretConfigJSON{}
+	if err := json.Unmarshal(clusterSecret.Data["config"], &configJSON); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal config of cluster secret '%s': %v", clusterSecret.Name, err)
+	}
+
+	return &rest.Config{
+		Host:        server,
+		BearerToken: configJSON.BearerToken,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure: configJSON.TLSClientConfig.Insecure,
+			CertData: configJSON.TLSClientConfig.CertData,
+			KeyData:  configJSON.TLSClientConfig.KeyData,
+			CAData:   configJSON.TLSClientConfig.CAData,
+		},
+	}, nil
+}
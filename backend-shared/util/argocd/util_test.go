@@ -3,6 +3,8 @@ package argocd
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("Test Argo CD utility functions", func() {
@@ -47,4 +49,46 @@ var _ = Describe("Test Argo CD utility functions", func() {
 			})
 		})
 	})
+
+	Context("Test GenerateRESTConfigFromClusterSecret", func() {
+
+		It("should return a REST config for the cluster, without the managed environment query parameter", func() {
+			clusterSecret := corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: managedEnvPrefix + "1234"},
+				Data: map[string][]byte{
+					"server": []byte("https://api.my-cluster.com:6443?managedEnvironment=1234"),
+					"config": []byte(`{"bearerToken":"my-token","tlsClientConfig":{"insecure":true}}`),
+				},
+			}
+
+			restConfig, err := GenerateRESTConfigFromClusterSecret(clusterSecret)
+			Expect(err).To(BeNil())
+			Expect(restConfig.Host).To(Equal("https://api.my-cluster.com:6443"))
+			Expect(restConfig.BearerToken).To(Equal("my-token"))
+			Expect(restConfig.TLSClientConfig.Insecure).To(BeTrue())
+		})
+
+		It("should return a REST config that authenticates with the client certificate of the cluster secret", func() {
+			clusterSecret := corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: managedEnvPrefix + "1234"},
+				Data: map[string][]byte{
+					"server": []byte("https://api.my-cluster.com:6443"),
+					// 'Y2VydA==', 'a2V5' and 'Y2E=' are the base64 encoding of 'cert', 'key' and 'ca'
+					"config": []byte(`{"bearerToken":"","tlsClientConfig":{"insecure":false,"certData":"Y2VydA==","keyData":"a2V5","caData":"Y2E="}}`),
+				},
+			}
+
+			restConfig, err := GenerateRESTConfigFromClusterSecret(clusterSecret)
+			Expect(err).To(BeNil())
+			Expect(restConfig.BearerToken).To(BeEmpty())
+			Expect(restConfig.TLSClientConfig.CertData).To(Equal([]byte("cert")))
+			Expect(restConfig.TLSClientConfig.KeyData).To(Equal([]byte("key")))
+			Expect(restConfig.TLSClientConfig.CAData).To(Equal([]byte("ca")))
+		})
+
+		It("should return an error if the cluster secret does not contain a server", func() {
+			_, err := GenerateRESTConfigFromClusterSecret(corev1.Secret{})
+			Expect(err).ToNot(BeNil())
+		})
+	})
 })
@@ -16,6 +16,8 @@ type FauxApplication struct {
 type FauxObjectMeta struct {
 	Name      string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
 	Namespace string `json:"namespace,omitempty" protobuf:"bytes,3,opt,name=namespace"`
+	// Annotations of the Application: for example, the deletion policy of the corresponding GitOpsDeployment
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty" protobuf:"bytes,12,rep,name=annotations"`
 }
 
 type FauxTypeMeta struct {
@@ -26,7 +28,8 @@ type FauxTypeMeta struct {
 // ApplicationSpec represents desired application state. Contains link to repository with application definition and additional parameters link definition revision.
 type FauxApplicationSpec struct {
 	// Source is a reference to the location of the application's manifests or chart
-	Source ApplicationSource `json:"source" protobuf:"bytes,1,opt,name=source"`
+	// - Source is omitted for multi-source Applications, which use Sources instead.
+	Source ApplicationSource `json:"source,omitempty" yaml:"source,omitempty" protobuf:"bytes,1,opt,name=source"`
 	// Destination is a reference to the target Kubernetes server and namespace
 	Destination ApplicationDestination `json:"destination" protobuf:"bytes,2,name=destination"`
 	// Project is a reference to the project this application belongs to.
@@ -34,8 +37,28 @@ type FauxApplicationSpec struct {
 	Project string `json:"project" protobuf:"bytes,3,name=project"`
 	// SyncPolicy controls when and how a sync will be performed
 	SyncPolicy *SyncPolicy `json:"syncPolicy,omitempty" protobuf:"bytes,4,name=syncPolicy"`
+	// Sources is a reference to the location of the application's manifests or chart, for multi-source Applications
+	Sources ApplicationSources `json:"sources,omitempty" yaml:"sources,omitempty" protobuf:"bytes,8,opt,name=sources"`
+	// IgnoreDifferences is a list of resources and their fields which should be ignored during comparison
+	IgnoreDifferences []ResourceIgnoreDifferences `json:"ignoreDifferences,omitempty" yaml:"ignoredifferences,omitempty" protobuf:"bytes,5,name=ignoreDifferences"`
 }
 
+// ResourceIgnoreDifferences contains resource filter and list of json paths which should be ignored during comparison with live state.
+type ResourceIgnoreDifferences struct {
+	Group             string   `json:"group,omitempty" yaml:"group,omitempty" protobuf:"bytes,1,opt,name=group"`
+	Kind              string   `json:"kind" protobuf:"bytes,2,opt,name=kind"`
+	Name              string   `json:"name,omitempty" yaml:"name,omitempty" protobuf:"bytes,3,opt,name=name"`
+	Namespace         string   `json:"namespace,omitempty" yaml:"namespace,omitempty" protobuf:"bytes,4,opt,name=namespace"`
+	JSONPointers      []string `json:"jsonPointers,omitempty" yaml:"jsonpointers,omitempty" protobuf:"bytes,5,opt,name=jsonPointers"`
+	JQPathExpressions []string `json:"jqPathExpressions,omitempty" yaml:"jqpathexpressions,omitempty" protobuf:"bytes,6,opt,name=jqPathExpressions"`
+	// ManagedFieldsManagers is a list of trusted managers. Fields mutated by those managers will take precedence over the
+	// desired state defined in the SCM and won't be displayed in diffs
+	ManagedFieldsManagers []string `json:"managedFieldsManagers,omitempty" yaml:"managedfieldsmanagers,omitempty" protobuf:"bytes,7,opt,name=managedFieldsManagers"`
+}
+
+// ApplicationSources contains list of required information about the sources of an application
+type ApplicationSources []ApplicationSource
+
 // ApplicationSource contains all required information about the source of an application
 type ApplicationSource struct {
 
@@ -49,6 +72,58 @@ type ApplicationSource struct {
 	// In case of Git, this can be commit, tag, or branch. If omitted, will equal to HEAD.
 	// In case of Helm, this is a semver tag for the Chart's version.
 	TargetRevision string `json:"targetRevision,omitempty" protobuf:"bytes,4,opt,name=targetRevision"`
+
+	// Helm holds helm specific options
+	// - The yaml tags on this field (and those below) ensure that the generated spec field is unchanged for
+	//   Applications that do not use them.
+	Helm *ApplicationSourceHelm `json:"helm,omitempty" yaml:"helm,omitempty" protobuf:"bytes,7,opt,name=helm"`
+
+	// Chart is a Helm chart name, and must be specified for applications sourced from a Helm repo.
+	Chart string `json:"chart,omitempty" yaml:"chart,omitempty" protobuf:"bytes,12,opt,name=chart"`
+
+	// Kustomize holds kustomize specific options
+	Kustomize *ApplicationSourceKustomize `json:"kustomize,omitempty" yaml:"kustomize,omitempty" protobuf:"bytes,8,opt,name=kustomize"`
+
+	// Ref is reference to another source within sources field. This field will not be used if used with a `source` tag.
+	Ref string `json:"ref,omitempty" yaml:"ref,omitempty" protobuf:"bytes,13,opt,name=ref"`
+}
+
+// ApplicationSourceKustomize holds options specific to an Application source specific to Kustomize
+type ApplicationSourceKustomize struct {
+	// NamePrefix is a prefix appended to resources for Kustomize apps
+	NamePrefix string `json:"namePrefix,omitempty" yaml:"nameprefix,omitempty" protobuf:"bytes,1,opt,name=namePrefix"`
+	// NameSuffix is a suffix appended to resources for Kustomize apps
+	NameSuffix string `json:"nameSuffix,omitempty" yaml:"namesuffix,omitempty" protobuf:"bytes,2,opt,name=nameSuffix"`
+	// Images is a list of Kustomize image override specifications
+	Images []string `json:"images,omitempty" yaml:"images,omitempty" protobuf:"bytes,3,opt,name=images"`
+	// CommonLabels is a list of additional labels to add to rendered manifests
+	CommonLabels map[string]string `json:"commonLabels,omitempty" yaml:"commonlabels,omitempty" protobuf:"bytes,4,opt,name=commonLabels"`
+	// Version controls which version of Kustomize to use for rendering manifests
+	Version string `json:"version,omitempty" yaml:"version,omitempty" protobuf:"bytes,5,opt,name=version"`
+	// CommonAnnotations is a list of additional annotations to add to rendered manifests
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty" yaml:"commonannotations,omitempty" protobuf:"bytes,6,opt,name=commonAnnotations"`
+}
+
+// ApplicationSourceHelm holds helm specific options
+type ApplicationSourceHelm struct {
+	// ValuesFiles is a list of Helm value files to use when generating a template
+	ValueFiles []string `json:"valueFiles,omitempty" yaml:"valuefiles,omitempty" protobuf:"bytes,1,opt,name=valueFiles"`
+	// Parameters is a list of Helm parameters which are passed to the helm template command upon manifest generation
+	Parameters []HelmParameter `json:"parameters,omitempty" yaml:"parameters,omitempty" protobuf:"bytes,2,opt,name=parameters"`
+	// ReleaseName is the Helm release name to use. If omitted it will use the application name
+	ReleaseName string `json:"releaseName,omitempty" yaml:"releasename,omitempty" protobuf:"bytes,3,opt,name=releaseName"`
+	// Values specifies Helm values to be passed to helm template, typically defined as a block
+	Values string `json:"values,omitempty" yaml:"values,omitempty" protobuf:"bytes,4,opt,name=values"`
+}
+
+// HelmParameter is a parameter that's passed to helm template during manifest generation
+type HelmParameter struct {
+	// Name is the name of the Helm parameter
+	Name string `json:"name,omitempty" yaml:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// Value is the value for the Helm parameter
+	Value string `json:"value,omitempty" yaml:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
+	// ForceString determines whether to tell Helm to interpret booleans and numbers as strings
+	ForceString bool `json:"forceString,omitempty" yaml:"forcestring,omitempty" protobuf:"bytes,3,opt,name=forceString"`
 }
 
 // ApplicationDestination holds information about the application's destination
@@ -70,6 +145,45 @@ type FauxComparedTo struct {
 	Source ApplicationSource `json:"source"`
 	// Destination is a reference to the target Kubernetes server and namespace
 	Destination ApplicationDestination `json:"destination"`
+	// Sources is a reference to the application's multiple sources used for comparison
+	Sources ApplicationSources `json:"sources,omitempty"`
+	// Revisions contains the revision of each source of a multi-source application, in the same order as Sources
+	// - This is not part of the Argo CD ComparedTo struct, but is instead read from .status.sync.revisions.
+	Revisions []string `json:"revisions,omitempty"`
+}
+
+// FauxDeploymentHistorySource is the source (or sources, for multi-source applications) that was deployed by a sync
+// operation. It is stored as JSON in the 'source' field of the DeploymentHistory database table.
+type FauxDeploymentHistorySource struct {
+	// Source is a reference to the location of the application's manifests or chart
+	Source ApplicationSource `json:"source"`
+	// Sources is a reference to the application's multiple sources
+	Sources ApplicationSources `json:"sources,omitempty"`
+}
+
+// FauxSyncOperationOptions contains the options of a sync operation requested by a GitOpsDeploymentSyncRun. It is
+// stored as JSON in the 'options' field of the SyncOperation database table.
+type FauxSyncOperationOptions struct {
+	// Prune specifies to delete resources from the cluster that are no longer tracked in git
+	Prune bool `json:"prune,omitempty"`
+	// DryRun specifies to perform a `kubectl apply --dry-run` without actually performing the sync
+	DryRun bool `json:"dryRun,omitempty"`
+	// Force specifies to supply the --force flag to `kubectl apply`
+	Force bool `json:"force,omitempty"`
+	// Strategy is the sync strategy: 'apply' or 'hook'. If empty, 'hook' is used.
+	Strategy string `json:"strategy,omitempty"`
+	// SyncOptions provide per-sync sync-options, e.g. Validate=false
+	SyncOptions SyncOptions `json:"syncOptions,omitempty"`
+	// Resources describes which resources shall be part of the sync. If empty, all resources are synced.
+	Resources []FauxSyncOperationResource `json:"resources,omitempty"`
+}
+
+// FauxSyncOperationResource identifies a resource to sync, as part of a selective sync.
+type FauxSyncOperationResource struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // SyncPolicy controls when a sync will be performed in response to updates in git
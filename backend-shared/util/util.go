@@ -31,6 +31,12 @@ const (
 
 	ManagedEnvironmentSecretType = "managed-gitops.redhat.com/managed-environment"
 
+	// WebhookSecretType is the type of the Secrets that hold the secret of the webhook of a Git repository: the secret
+	// is used to validate the signature of the webhook events that are sent for the repository.
+	WebhookSecretType             = "managed-gitops.redhat.com/webhook"
+	WebhookSecretRepositoryURLKey = "url"    // Key of the Secret data that contains the URL of the Git repository
+	WebhookSecretSecretKey        = "secret" // Key of the Secret data that contains the webhook secret
+
 	JobKey      = "job"                    // Clean up job key
 	JobKeyValue = "managed-gitops-cleanup" // Clean up job value
 	JobTypeKey  = "jobType"                // Key to identify clean up job type
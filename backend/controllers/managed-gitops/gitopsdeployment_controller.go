@@ -33,6 +33,7 @@ import (
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/preprocess_event_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
 )
 
 // GitOpsDeploymentReconciler reconciles a GitOpsDeployment object
@@ -47,6 +48,7 @@ type GitOpsDeploymentReconciler struct {
 //+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=gitopsdeployments/finalizers,verbs=update
 //+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=operations,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -73,8 +75,40 @@ func (r *GitOpsDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GitOpsDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	// Index the GitOpsDeployments by the repository URLs of their sources, so that the GitOpsDeployments that are
+	// affected by a push to a repository can be found without reading every Application row from the database
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &managedgitopsv1alpha1.GitOpsDeployment{},
+		eventlooptypes.GitOpsDeploymentRepoURLIndexKey, func(obj client.Object) []string {
+			gitopsDeployment, ok := obj.(*managedgitopsv1alpha1.GitOpsDeployment)
+			if !ok {
+				return nil
+			}
+			return getNormalizedRepositoryURLs(*gitopsDeployment)
+		}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&managedgitopsv1alpha1.GitOpsDeployment{},
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
+
+// getNormalizedRepositoryURLs returns the normalized repository URLs of the sources of the GitOpsDeployment, which are
+// the values of the GitOpsDeploymentRepoURLIndexKey field index.
+func getNormalizedRepositoryURLs(gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment) []string {
+
+	sources := append([]managedgitopsv1alpha1.ApplicationSource{gitopsDeployment.Spec.Source}, gitopsDeployment.Spec.Sources...)
+
+	var repoURLs []string
+	seen := map[string]bool{}
+	for _, source := range sources {
+		if repoURL := shared_resource_loop.NormalizeGitURL(source.RepoURL); repoURL != "" && !seen[repoURL] {
+			seen[repoURL] = true
+			repoURLs = append(repoURLs, repoURL)
+		}
+	}
+
+	return repoURLs
+}
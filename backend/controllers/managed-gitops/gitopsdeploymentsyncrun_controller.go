@@ -71,6 +71,20 @@ func (r *GitOpsDeploymentSyncRunReconciler) Reconcile(ctx context.Context, req c
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GitOpsDeploymentSyncRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	// Index the GitOpsDeploymentSyncRuns by the GitOpsDeployment they target, so that the GitOpsDeploymentSyncRuns of a
+	// GitOpsDeployment can be listed without listing every GitOpsDeploymentSyncRun of the namespace
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &managedgitopsv1alpha1.GitOpsDeploymentSyncRun{},
+		eventlooptypes.SyncRunGitOpsDeploymentNameIndexKey, func(obj client.Object) []string {
+			syncRun, ok := obj.(*managedgitopsv1alpha1.GitOpsDeploymentSyncRun)
+			if !ok || syncRun.Spec.GitopsDeploymentName == "" {
+				return nil
+			}
+			return []string{syncRun.Spec.GitopsDeploymentName}
+		}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&managedgitopsv1alpha1.GitOpsDeploymentSyncRun{},
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
@@ -138,7 +138,9 @@ func processSecret(ctx context.Context, secret corev1.Secret, k8sClient client.C
 			continue
 		}
 
-		if managedEnvCR.Spec.ClusterCredentialsSecret == secret.Name {
+		// The Secret may be referenced either as the credentials of the managed environment, or as its CA bundle
+		if managedEnvCR.Spec.ClusterCredentialsSecret == secret.Name ||
+			(managedEnvCR.Spec.CABundleSecretRef != nil && managedEnvCR.Spec.CABundleSecretRef.Name == secret.Name) {
 			listOfManagedEnvCRsThatReferenceSecret = append(listOfManagedEnvCRsThatReferenceSecret, managedEnvCR)
 		}
 	}
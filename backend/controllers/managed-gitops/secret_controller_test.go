@@ -132,6 +132,34 @@ var _ = Describe("Secret Controller Test", func() {
 
 		})
 
+		It("reconciles on a managed-env secret that is referenced as the CA bundle of a managed env CR", func() {
+			// secret with the right type, and 1 managed env referring to it via .spec.caBundleSecretRef
+			// expect: 1
+			secret := createSecretForManagedEnv("my-secret", true, *namespace, k8sClient)
+			managedEnv := managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "managed-env",
+					Namespace: namespace.Name,
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironmentSpec{
+					ClusterCredentialsSecret: "my-credentials-secret",
+					CABundleSecretRef:        &managedgitopsv1alpha1.CABundleSecretReference{Name: secret.Name},
+				},
+			}
+			err := k8sClient.Create(context.Background(), &managedEnv)
+			Expect(err).To(BeNil())
+
+			_, err = reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: secret.Namespace,
+					Name:      secret.Name,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(len(mockProcessor.requestsReceived)).Should(Equal(1))
+
+		})
+
 	})
 
 	Context("Test filterManagedEnvSecrets predicate", func() {
@@ -9,6 +9,7 @@ import (
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -81,6 +82,9 @@ type ApplicationEventQueueLoop struct {
 
 	// Client is a K8s client for accessing GitOps service resources
 	Client client.Client
+
+	// EventRecorder is used to record Kubernetes Events on GitOps service resources
+	EventRecorder record.EventRecorder
 }
 
 // StartApplicationEventQueueLoop will start the Application Event Loop for the GitOpsDeployment referenced
@@ -94,6 +98,7 @@ func StartApplicationEventQueueLoop(ctx context.Context, aeqlParam ApplicationEv
 		aeqlParam.GitopsDeploymentNamespace,
 		aeqlParam.WorkspaceID,
 		aeqlParam.SharedResourceEventLoop,
+		aeqlParam.EventRecorder,
 		defaultApplicationEventRunnerFactory{}, // use the default factory
 	)
 }
@@ -109,6 +114,7 @@ func startApplicationEventQueueLoopWithFactory(ctx context.Context, aeqlParam Ap
 		aeqlParam.GitopsDeploymentNamespace,
 		aeqlParam.WorkspaceID,
 		aeqlParam.SharedResourceEventLoop,
+		aeqlParam.EventRecorder,
 		aerFactory, // use parameter-provided factory
 	)
 
@@ -121,6 +127,7 @@ func applicationEventQueueLoop(ctx context.Context, k8sClient client.Client,
 	gitopsDeploymentName string, gitopsDeploymentNamespace string,
 	workspaceID string,
 	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
+	eventRecorder record.EventRecorder,
 	aerFactory applicationEventRunnerFactory) {
 
 	log := log.FromContext(ctx).
@@ -142,11 +149,11 @@ func applicationEventQueueLoop(ctx context.Context, k8sClient client.Client,
 	var activeSyncOperationEvent *RequestMessage
 	waitingSyncOperationEvents := []*RequestMessage{}
 
-	deploymentEventRunner := aerFactory.createNewApplicationEventLoopRunner(input, sharedResourceEventLoop, gitopsDeploymentName,
+	deploymentEventRunner := aerFactory.createNewApplicationEventLoopRunner(input, sharedResourceEventLoop, eventRecorder, gitopsDeploymentName,
 		gitopsDeploymentNamespace, workspaceID, "deployment")
 	deploymentEventRunnerShutdown := false
 
-	syncOperationEventRunner := aerFactory.createNewApplicationEventLoopRunner(input, sharedResourceEventLoop, gitopsDeploymentName,
+	syncOperationEventRunner := aerFactory.createNewApplicationEventLoopRunner(input, sharedResourceEventLoop, eventRecorder, gitopsDeploymentName,
 		gitopsDeploymentNamespace, workspaceID, "sync-operation")
 	syncOperationEventRunnerShutdown := false
 
@@ -211,7 +218,8 @@ out:
 					log.V(logutil.LogLevel_Debug).Info("Ignoring post-shutdown deployment event")
 				}
 
-			} else if eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName {
+			} else if eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName ||
+				eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentPreviewTypeName {
 
 				if !syncOperationEventRunnerShutdown {
 					waitingSyncOperationEvents = append(waitingSyncOperationEvents, &newEvent)
@@ -277,7 +285,8 @@ out:
 					log.Info("Deployment signalled shutdown")
 				}
 
-			} else if eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName {
+			} else if eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName ||
+				eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentPreviewTypeName {
 
 				if activeSyncOperationEvent.Message.Event != newEvent.Message.Event {
 					log.Error(nil, "SEVERE: unmatched sync operation event work item",
@@ -401,7 +410,7 @@ func startNewStatusUpdateTimer(ctx context.Context, k8sClient client.Client, inp
 // The defaultApplicationEventRunnerFactory should be used in all cases, except for when writing mocks for unit tests.
 type applicationEventRunnerFactory interface {
 	createNewApplicationEventLoopRunner(informWorkCompleteChan chan RequestMessage,
-		sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
+		sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, eventRecorder record.EventRecorder,
 		gitopsDeplName string, gitopsDeplNamespace string, workspaceID string, debugContext string) chan *eventlooptypes.EventLoopEvent
 }
 
@@ -412,9 +421,9 @@ var _ applicationEventRunnerFactory = defaultApplicationEventRunnerFactory{}
 
 // createNewApplicationEventLoopRunner is a simple wrapper around the default function.
 func (defaultApplicationEventRunnerFactory) createNewApplicationEventLoopRunner(informWorkCompleteChan chan RequestMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, eventRecorder record.EventRecorder,
 	gitopsDeplName string, gitopsDeplNamespace string, workspaceID string, debugContext string) chan *eventlooptypes.EventLoopEvent {
 
-	return startNewApplicationEventLoopRunner(informWorkCompleteChan, sharedResourceEventLoop, gitopsDeplName, gitopsDeplNamespace,
+	return startNewApplicationEventLoopRunner(informWorkCompleteChan, sharedResourceEventLoop, eventRecorder, gitopsDeplName, gitopsDeplNamespace,
 		workspaceID, debugContext)
 }
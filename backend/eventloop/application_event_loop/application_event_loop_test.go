@@ -10,6 +10,7 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -180,8 +181,8 @@ type mockApplicationEventLoopRunnerFactory struct {
 var _ applicationEventRunnerFactory = &mockApplicationEventLoopRunnerFactory{}
 
 func (fact *mockApplicationEventLoopRunnerFactory) createNewApplicationEventLoopRunner(informWorkCompleteChan chan RequestMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, gitopsDeplName string, gitopsDeplNamespace string,
-	workspaceID string, debugContext string) chan *eventlooptypes.EventLoopEvent {
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, eventRecorder record.EventRecorder, gitopsDeplName string,
+	gitopsDeplNamespace string, workspaceID string, debugContext string) chan *eventlooptypes.EventLoopEvent {
 
 	return fact.mockChannel
 
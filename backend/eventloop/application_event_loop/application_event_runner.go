@@ -18,6 +18,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -62,13 +63,13 @@ import (
 // https://miro.com/app/board/o9J_lgiqJAs=/?moveToWidget=3458764514216218600&cot=14
 
 func startNewApplicationEventLoopRunner(informWorkCompleteChan chan RequestMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, eventRecorder record.EventRecorder,
 	gitopsDeplName string, gitopsDeplNamespace, workspaceID string, debugContext string) chan *eventlooptypes.EventLoopEvent {
 
 	inputChannel := make(chan *eventlooptypes.EventLoopEvent)
 
 	go func() {
-		applicationEventLoopRunner(inputChannel, informWorkCompleteChan, sharedResourceEventLoop, gitopsDeplName, gitopsDeplNamespace,
+		applicationEventLoopRunner(inputChannel, informWorkCompleteChan, sharedResourceEventLoop, eventRecorder, gitopsDeplName, gitopsDeplNamespace,
 			workspaceID, debugContext)
 	}()
 
@@ -78,7 +79,7 @@ func startNewApplicationEventLoopRunner(informWorkCompleteChan chan RequestMessa
 
 func applicationEventLoopRunner(inputChannel chan *eventlooptypes.EventLoopEvent,
 	informWorkCompleteChan chan RequestMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, gitopsDeploymentName string,
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, eventRecorder record.EventRecorder, gitopsDeploymentName string,
 	gitopsDeploymentNamespace string, namespaceID string, debugContext string) {
 
 	outerContext := context.Background()
@@ -126,6 +127,7 @@ func applicationEventLoopRunner(inputChannel chan *eventlooptypes.EventLoopEvent
 					eventResourceName:       newEvent.Request.Name,
 					eventResourceNamespace:  newEvent.Request.Namespace,
 					workspaceClient:         newEvent.Client,
+					eventRecorder:           eventRecorder,
 					sharedResourceEventLoop: sharedResourceEventLoop,
 					log:                     log,
 					workspaceID:             namespaceID,
@@ -162,8 +164,13 @@ func applicationEventLoopRunner(inputChannel chan *eventlooptypes.EventLoopEvent
 					// Handle all SyncRun related events
 					err = action.applicationEventRunner_handleSyncRunModified(ctx, scopedDBQueries)
 
+				} else if newEvent.EventType == eventlooptypes.PreviewModified {
+
+					// Handle all Preview related events
+					err = action.applicationEventRunner_handlePreviewModified(ctx, scopedDBQueries)
+
 				} else if newEvent.EventType == eventlooptypes.UpdateDeploymentStatusTick {
-					_, err = action.applicationEventRunner_handleUpdateDeploymentStatusTick(ctx, gitopsDeploymentName, gitopsDeploymentNamespace, scopedDBQueries)
+					signalledShutdown, err = handleUpdateDeploymentStatusTick(ctx, gitopsDeploymentName, gitopsDeploymentNamespace, newEvent, action, scopedDBQueries, log)
 
 				} else if newEvent.EventType == eventlooptypes.ManagedEnvironmentModified {
 
@@ -339,6 +346,7 @@ func handleManagedEnvironmentModified(ctx context.Context, expectedResourceName
 			eventResourceName:       gitopsDeployment.Name,
 			eventResourceNamespace:  gitopsDeployment.Namespace,
 			workspaceClient:         action.workspaceClient,
+			eventRecorder:           action.eventRecorder,
 			sharedResourceEventLoop: action.sharedResourceEventLoop,
 			log:                     action.log,
 			workspaceID:             action.workspaceID,
@@ -353,6 +361,52 @@ func handleManagedEnvironmentModified(ctx context.Context, expectedResourceName
 
 }
 
+// handleUpdateDeploymentStatusTick updates the status of the GitOpsDeployment that is handled by this event runner. However,
+// if the GitOpsDeployment is waiting for the GitOpsDeployments it depends on to be synced and healthy, the GitOpsDeployment
+// is instead reconciled, so that its Application is created once its dependencies are satisfied.
+//
+// returns true if shutdown was signalled by 'handleDeploymentModified', false otherwise.
+func handleUpdateDeploymentStatusTick(ctx context.Context, gitopsDeploymentName string, gitopsDeploymentNamespace string,
+	newEvent *eventlooptypes.EventLoopEvent, action applicationEventLoopRunner_Action, scopedDBQueries db.ApplicationScopedQueries,
+	log logr.Logger) (bool, error) {
+
+	gitopsDeployment := &managedgitopsv1alpha1.GitOpsDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gitopsDeploymentName,
+			Namespace: gitopsDeploymentNamespace,
+		},
+	}
+	if err := action.workspaceClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeployment), gitopsDeployment); err != nil {
+		if apierr.IsNotFound(err) {
+			// gitopsdeployment doesn't exist; no work for us to do here.
+			return false, nil
+		} else {
+			return false, fmt.Errorf("unable to retrieve gitopsdeployment: %v", err)
+		}
+	}
+
+	waitingForDependencies, err := isWaitingForDependencies(ctx, gitopsDeployment, scopedDBQueries)
+	if err != nil {
+		return false, err
+	}
+
+	if !waitingForDependencies {
+		_, err := action.applicationEventRunner_handleUpdateDeploymentStatusTick(ctx, gitopsDeploymentName, gitopsDeploymentNamespace, scopedDBQueries)
+		return false, err
+	}
+
+	// Update the existing action and event, but change the name and namespace to point to the GitOpsDeployment.
+	deploymentEvent := *newEvent
+	deploymentEvent.EventType = eventlooptypes.DeploymentModified
+	deploymentEvent.Request.Name = gitopsDeploymentName
+	deploymentEvent.Request.Namespace = gitopsDeploymentNamespace
+
+	action.eventResourceName = gitopsDeploymentName
+	action.eventResourceNamespace = gitopsDeploymentNamespace
+
+	return handleDeploymentModified(ctx, &deploymentEvent, action, scopedDBQueries, log)
+}
+
 // Handle events originating from the GitOpsDeployment controller
 func handleDeploymentModified(ctx context.Context, newEvent *eventlooptypes.EventLoopEvent, action applicationEventLoopRunner_Action,
 	scopedDBQueries db.ApplicationScopedQueries, log logr.Logger) (bool, error) {
@@ -385,6 +439,11 @@ func handleDeploymentModified(ctx context.Context, newEvent *eventlooptypes.Even
 	}
 
 	if err == nil {
+		// The spec of the GitOpsDeployment was successfully processed, so report the generation that we processed
+		if setGenerationError := adapter.setObservedGeneration(); setGenerationError != nil {
+			return false, setGenerationError
+		}
+
 		return signalledShutdown, nil
 	} else {
 		return signalledShutdown, err.DevError()
@@ -408,6 +467,9 @@ type applicationEventLoopRunner_Action struct {
 	// The K8s client that can be used to read/write objects on the workspace cluster. This client is aware of virtual workspaces.
 	workspaceClient client.Client
 
+	// eventRecorder is used to record Kubernetes Events on the GitOpsDeployment (and related resources). May be nil in unit tests.
+	eventRecorder record.EventRecorder
+
 	// The UID of the API namespace (namespace containing GitOps API types)
 	workspaceID string
 
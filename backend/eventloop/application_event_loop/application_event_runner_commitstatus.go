@@ -0,0 +1,226 @@
+package application_event_loop
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	sharedloop "github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/util"
+)
+
+// commitStatusContextPrefix is the prefix of the context (the label shown by the Git provider) of the commit statuses
+// that are posted for a GitOpsDeployment, for example 'gitops/my-deployment'.
+const commitStatusContextPrefix = "gitops/"
+
+// commitStatusRequestTimeout is the maximum time that reporting a single commit status may take
+const commitStatusRequestTimeout = 1 * time.Minute
+
+// newCommitStatusReporter returns the reporter used to post commit statuses. It is a variable so that it may be replaced by unit tests.
+var newCommitStatusReporter = util.NewCommitStatusReporter
+
+var (
+	commitStatusTaskRetryLoop     *sharedutil.TaskRetryLoop
+	commitStatusTaskRetryLoopOnce sync.Once
+)
+
+// getCommitStatusTaskRetryLoop returns the task retry loop that commit statuses are posted from, so that a slow or
+// unavailable Git provider does not block the application event runner.
+func getCommitStatusTaskRetryLoop() *sharedutil.TaskRetryLoop {
+	commitStatusTaskRetryLoopOnce.Do(func() {
+		commitStatusTaskRetryLoop = sharedutil.NewTaskRetryLoop("commit-status-retry-loop")
+	})
+	return commitStatusTaskRetryLoop
+}
+
+// reportCommitStatus queues a task to post a commit status to the synced revision of the GitOpsDeployment, if the
+// commit status of the GitOpsDeployment has changed between the previous and the updated status field.
+//
+// A commit status is only posted when a GitOpsDeploymentRepositoryCredential in the namespace of the GitOpsDeployment
+// matches the repository of the GitOpsDeployment, and has commit status reporting enabled (see commitStatusTask).
+func reportCommitStatus(previous managedgitopsv1alpha1.GitOpsDeploymentStatus, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment,
+	k8sClient client.Client, log logr.Logger) {
+
+	task := newCommitStatusTask(previous, gitopsDeployment, k8sClient, log)
+	if task == nil {
+		return
+	}
+
+	getCommitStatusTaskRetryLoop().AddTaskIfNotPresent(task.taskName(), task,
+		sharedutil.ExponentialBackoff{Factor: 2, Min: time.Second, Max: time.Minute, Jitter: true})
+}
+
+// newCommitStatusTask returns a task that posts the commit status of the GitOpsDeployment, or nil if the commit status
+// has not changed since it was last reported.
+func newCommitStatusTask(previous managedgitopsv1alpha1.GitOpsDeploymentStatus, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment,
+	k8sClient client.Client, log logr.Logger) *commitStatusTask {
+
+	// Commit statuses are only supported for GitOpsDeployments with a single source
+	if len(gitopsDeployment.Spec.Sources) > 0 || gitopsDeployment.Spec.Source.RepoURL == "" {
+		return nil
+	}
+
+	newStatus, revision := generateCommitStatus(gitopsDeployment, gitopsDeployment.Status)
+	if newStatus == nil || revision == "" {
+		return nil
+	}
+
+	previousStatus, previousRevision := generateCommitStatus(gitopsDeployment, previous)
+	if previousStatus != nil && *previousStatus == *newStatus && previousRevision == revision {
+		// The commit status of the revision has not changed since it was last reported
+		return nil
+	}
+
+	return &commitStatusTask{
+		k8sClient:        k8sClient,
+		gitopsDeployment: gitopsDeployment.DeepCopy(),
+		revision:         revision,
+		status:           *newStatus,
+		log:              log.WithValues("revision", revision, "state", newStatus.State),
+	}
+}
+
+// commitStatusTask posts a commit status to the Git provider of the repository of a GitOpsDeployment. It runs in the
+// commit status task retry loop, which logs its errors: failing to report a commit status should not prevent the
+// status of the GitOpsDeployment from being updated.
+type commitStatusTask struct {
+	k8sClient        client.Client
+	gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment
+	revision         string
+	status           util.CommitStatus
+
+	log logr.Logger
+}
+
+// taskName returns the name of the task in the task retry loop: the same status of the same revision is only posted once.
+func (t *commitStatusTask) taskName() string {
+	return fmt.Sprintf("%s/%s/%s/%s", t.gitopsDeployment.Namespace, t.gitopsDeployment.Name, t.revision, t.status.State)
+}
+
+// Returns true if the task should be retried, false otherwise, plus an error
+func (t *commitStatusTask) PerformTask(taskContext context.Context) (bool, error) {
+	const retry, noRetry = true, false
+
+	ctx, cancel := context.WithTimeout(taskContext, commitStatusRequestTimeout)
+	defer cancel()
+
+	repoCred, token, err := getCommitStatusRepositoryCredential(ctx, t.gitopsDeployment, t.k8sClient)
+	if err != nil {
+		return retry, fmt.Errorf("unable to retrieve the repository credential for reporting the commit status: %v", err)
+	}
+	if repoCred == nil {
+		// Commit status reporting is not enabled for the repository
+		return noRetry, nil
+	}
+
+	reporter, err := newCommitStatusReporter(ctx, repoCred.Spec.CommitStatus.Provider, repoCred.Spec.CommitStatus.APIURL,
+		t.gitopsDeployment.Spec.Source.RepoURL, token)
+	if err != nil {
+		// The commit status reporting configuration is invalid, so retrying would not succeed
+		return noRetry, fmt.Errorf("unable to create the commit status reporter of GitOpsDeploymentRepositoryCredential '%s': %v", repoCred.Name, err)
+	}
+
+	if err := reporter.CreateCommitStatus(ctx, t.gitopsDeployment.Spec.Source.RepoURL, t.revision, t.status); err != nil {
+		shouldRetry := noRetry
+		if util.IsTransientCommitStatusError(err) {
+			shouldRetry = retry
+		}
+		// Otherwise, for example, the token was rejected, or the commit does not exist: retrying would not succeed
+		return shouldRetry, fmt.Errorf("unable to report the commit status of revision '%s': %v", t.revision, err)
+	}
+
+	t.log.Info("Reported commit status")
+
+	return noRetry, nil
+}
+
+// generateCommitStatus returns the commit status, and the revision it should be posted to, for the given status field of
+// the GitOpsDeployment. Returns nil, if the status field does not (yet) correspond to a commit status.
+//
+// - pending: a sync operation is running, the resources are progressing, or the resources are not yet synced
+// - success: the resources are synced and healthy
+// - failure: the sync operation failed, or the resources are degraded
+func generateCommitStatus(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, status managedgitopsv1alpha1.GitOpsDeploymentStatus) (*util.CommitStatus, string) {
+
+	var state util.CommitState
+
+	operationPhase := managedgitopsv1alpha1.OperationPhase("")
+	if status.OperationState != nil {
+		operationPhase = status.OperationState.Phase
+	}
+
+	switch {
+	case operationPhase == managedgitopsv1alpha1.OperationFailed || operationPhase == managedgitopsv1alpha1.OperationError ||
+		status.Health.Status == managedgitopsv1alpha1.HeathStatusCodeDegraded:
+		state = util.CommitState_Failure
+
+	case operationPhase == managedgitopsv1alpha1.OperationRunning || status.Health.Status == managedgitopsv1alpha1.HeathStatusCodeProgressing ||
+		status.Sync.Status == managedgitopsv1alpha1.SyncStatusCodeOutOfSync:
+		state = util.CommitState_Pending
+
+	case status.Sync.Status == managedgitopsv1alpha1.SyncStatusCodeSynced && status.Health.Status == managedgitopsv1alpha1.HeathStatusCodeHealthy:
+		state = util.CommitState_Success
+
+	default:
+		return nil, ""
+	}
+
+	environment := gitopsDeployment.Spec.Destination.Environment
+	if environment == "" {
+		environment = gitopsDeployment.Namespace
+	}
+
+	description := fmt.Sprintf("Environment '%s': %s", environment, status.Sync.Status)
+	if status.Health.Status != "" {
+		description += ", " + string(status.Health.Status)
+	}
+
+	return &util.CommitStatus{
+		State:       state,
+		Context:     commitStatusContextPrefix + gitopsDeployment.Name,
+		Description: description,
+	}, status.Sync.Revision
+}
+
+// getCommitStatusRepositoryCredential returns the GitOpsDeploymentRepositoryCredential in the namespace of the
+// GitOpsDeployment that matches its repository, and has commit status reporting enabled, along with the token from
+// its Secret. Returns nil, if there is no such GitOpsDeploymentRepositoryCredential.
+func getCommitStatusRepositoryCredential(ctx context.Context, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment,
+	k8sClient client.Client) (*managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential, string, error) {
+
+	var repoCredList managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredentialList
+	if err := k8sClient.List(ctx, &repoCredList, &client.ListOptions{Namespace: gitopsDeployment.Namespace}); err != nil {
+		return nil, "", fmt.Errorf("unable to list GitOpsDeploymentRepositoryCredentials: %v", err)
+	}
+
+	repoURL := sharedloop.NormalizeGitURL(gitopsDeployment.Spec.Source.RepoURL)
+
+	for idx := range repoCredList.Items {
+		repoCred := repoCredList.Items[idx]
+
+		if repoCred.Spec.CommitStatus == nil || sharedloop.NormalizeGitURL(repoCred.Spec.Repository) != repoURL {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: repoCred.Namespace, Name: repoCred.Spec.Secret}, secret); err != nil {
+			return nil, "", fmt.Errorf("unable to retrieve Secret '%s' of GitOpsDeploymentRepositoryCredential '%s': %v", repoCred.Spec.Secret, repoCred.Name, err)
+		}
+
+		token := string(secret.Data["password"])
+		if token == "" {
+			return nil, "", fmt.Errorf("secret '%s' of GitOpsDeploymentRepositoryCredential '%s' does not contain a 'password' (token) value", secret.Name, repoCred.Name)
+		}
+
+		return &repoCred, token, nil
+	}
+
+	return nil, "", nil
+}
@@ -0,0 +1,227 @@
+package application_event_loop
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	"github.com/redhat-appstudio/managed-gitops/backend/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// fakeCommitStatusReporter records the commit statuses that are posted to it
+type fakeCommitStatusReporter struct {
+	provider managedgitopsv1alpha1.CommitStatusProvider
+	token    string
+	posted   []postedCommitStatus
+
+	// err, if set, is returned instead of recording the commit status
+	err error
+}
+
+type postedCommitStatus struct {
+	repoURL string
+	sha     string
+	status  util.CommitStatus
+}
+
+func (r *fakeCommitStatusReporter) CreateCommitStatus(ctx context.Context, repoURL string, sha string, status util.CommitStatus) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.posted = append(r.posted, postedCommitStatus{repoURL: repoURL, sha: sha, status: status})
+	return nil
+}
+
+var _ = Describe("Application Event Runner Commit Status", func() {
+
+	const (
+		repoURL  = "https://github.com/abc-org/abc-repo"
+		revision = "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c"
+	)
+
+	var (
+		ctx              context.Context
+		k8sClient        client.Client
+		gitopsDepl       *managedgitopsv1alpha1.GitOpsDeployment
+		repoCred         *managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential
+		reporter         *fakeCommitStatusReporter
+		originalReporter = newCommitStatusReporter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme, _, _, workspace, err := tests.GenericTestSetup()
+		Expect(err).To(BeNil())
+
+		gitopsDepl = &managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-gitops-depl",
+				Namespace: workspace.Name,
+			},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Source: managedgitopsv1alpha1.ApplicationSource{
+					RepoURL: repoURL + ".git",
+					Path:    "resources/test-data/sample-gitops-repository/environments/overlays/dev",
+				},
+				Destination: managedgitopsv1alpha1.ApplicationDestination{
+					Environment: "staging",
+				},
+				Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+			},
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "repo-secret",
+				Namespace: workspace.Name,
+			},
+			Data: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("my-token"),
+			},
+		}
+
+		repoCred = &managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "repo-cred",
+				Namespace: workspace.Name,
+			},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredentialSpec{
+				Repository: repoURL,
+				Secret:     secret.Name,
+				CommitStatus: &managedgitopsv1alpha1.CommitStatusReporting{
+					Provider: managedgitopsv1alpha1.CommitStatusProvider_GitLab,
+				},
+			},
+		}
+
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace, gitopsDepl, secret, repoCred).Build()
+
+		reporter = &fakeCommitStatusReporter{}
+		newCommitStatusReporter = func(ctx context.Context, provider managedgitopsv1alpha1.CommitStatusProvider, apiURL string, repoURL string,
+			token string) (util.CommitStatusReporter, error) {
+			reporter.provider = provider
+			reporter.token = token
+			return reporter, nil
+		}
+	})
+
+	AfterEach(func() {
+		newCommitStatusReporter = originalReporter
+	})
+
+	statusOf := func(syncStatus managedgitopsv1alpha1.SyncStatusCode, health managedgitopsv1alpha1.HealthStatusCode, phase managedgitopsv1alpha1.OperationPhase) managedgitopsv1alpha1.GitOpsDeploymentStatus {
+		status := managedgitopsv1alpha1.GitOpsDeploymentStatus{
+			Sync:   managedgitopsv1alpha1.SyncStatus{Status: syncStatus, Revision: revision},
+			Health: managedgitopsv1alpha1.HealthStatus{Status: health},
+		}
+		if phase != "" {
+			status.OperationState = &managedgitopsv1alpha1.OperationState{Phase: phase}
+		}
+		return status
+	}
+
+	DescribeTable("should generate the commit status from the status of the GitOpsDeployment",
+		func(status managedgitopsv1alpha1.GitOpsDeploymentStatus, expectedState util.CommitState) {
+			commitStatus, commitRevision := generateCommitStatus(gitopsDepl, status)
+			if expectedState == "" {
+				Expect(commitStatus).To(BeNil())
+				return
+			}
+			Expect(commitStatus).ToNot(BeNil())
+			Expect(commitStatus.State).To(Equal(expectedState))
+			Expect(commitStatus.Context).To(Equal("gitops/my-gitops-depl"))
+			Expect(commitStatus.Description).To(Equal(fmt.Sprintf("Environment 'staging': %s, %s", status.Sync.Status, status.Health.Status)))
+			Expect(commitRevision).To(Equal(revision))
+		},
+		Entry("synced and healthy", statusOf(managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy, managedgitopsv1alpha1.OperationSucceeded), util.CommitState_Success),
+		Entry("sync operation running", statusOf(managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy, managedgitopsv1alpha1.OperationRunning), util.CommitState_Pending),
+		Entry("progressing", statusOf(managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeProgressing, ""), util.CommitState_Pending),
+		Entry("out of sync", statusOf(managedgitopsv1alpha1.SyncStatusCodeOutOfSync, managedgitopsv1alpha1.HeathStatusCodeHealthy, ""), util.CommitState_Pending),
+		Entry("degraded", statusOf(managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeDegraded, ""), util.CommitState_Failure),
+		Entry("sync operation failed", statusOf(managedgitopsv1alpha1.SyncStatusCodeOutOfSync, managedgitopsv1alpha1.HeathStatusCodeMissing, managedgitopsv1alpha1.OperationFailed), util.CommitState_Failure),
+		Entry("unknown", statusOf(managedgitopsv1alpha1.SyncStatusCodeUnknown, managedgitopsv1alpha1.HeathStatusCodeMissing, ""), util.CommitState("")),
+	)
+
+	It("should report the commit status when the status of the GitOpsDeployment transitions, using the matching repository credential", func() {
+		previous := statusOf(managedgitopsv1alpha1.SyncStatusCodeOutOfSync, managedgitopsv1alpha1.HeathStatusCodeHealthy, managedgitopsv1alpha1.OperationRunning)
+		gitopsDepl.Status = statusOf(managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy, managedgitopsv1alpha1.OperationSucceeded)
+
+		task := newCommitStatusTask(previous, gitopsDepl, k8sClient, log.FromContext(ctx))
+		Expect(task).ToNot(BeNil())
+		Expect(task.taskName()).To(Equal(gitopsDepl.Namespace + "/my-gitops-depl/" + revision + "/success"))
+
+		shouldRetry, err := task.PerformTask(ctx)
+		Expect(err).To(BeNil())
+		Expect(shouldRetry).To(BeFalse())
+
+		Expect(reporter.provider).To(Equal(managedgitopsv1alpha1.CommitStatusProvider_GitLab))
+		Expect(reporter.token).To(Equal("my-token"))
+		Expect(reporter.posted).To(HaveLen(1))
+		Expect(reporter.posted[0].repoURL).To(Equal(gitopsDepl.Spec.Source.RepoURL))
+		Expect(reporter.posted[0].sha).To(Equal(revision))
+		Expect(reporter.posted[0].status.State).To(Equal(util.CommitState_Success))
+
+		By("not reporting the commit status again, if it has not changed")
+		previous = gitopsDepl.Status
+		gitopsDepl.Status.History = []managedgitopsv1alpha1.RevisionHistory{{Revision: revision}}
+		Expect(newCommitStatusTask(previous, gitopsDepl, k8sClient, log.FromContext(ctx))).To(BeNil())
+	})
+
+	It("should not report the commit status if commit status reporting is not enabled for the repository", func() {
+		repoCred.Spec.CommitStatus = nil
+		Expect(k8sClient.Update(ctx, repoCred)).To(Succeed())
+
+		gitopsDepl.Status = statusOf(managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy, managedgitopsv1alpha1.OperationSucceeded)
+		task := newCommitStatusTask(managedgitopsv1alpha1.GitOpsDeploymentStatus{}, gitopsDepl, k8sClient, log.FromContext(ctx))
+		Expect(task).ToNot(BeNil())
+
+		shouldRetry, err := task.PerformTask(ctx)
+		Expect(err).To(BeNil())
+		Expect(shouldRetry).To(BeFalse())
+
+		Expect(reporter.posted).To(BeEmpty())
+	})
+
+	It("should not retry reporting the commit status, if the commit status reporter cannot be created", func() {
+		newCommitStatusReporter = func(ctx context.Context, provider managedgitopsv1alpha1.CommitStatusProvider, apiURL string, repoURL string,
+			token string) (util.CommitStatusReporter, error) {
+			return nil, fmt.Errorf("the host of the API URL does not match the host of the repository")
+		}
+
+		gitopsDepl.Status = statusOf(managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy, managedgitopsv1alpha1.OperationSucceeded)
+		task := newCommitStatusTask(managedgitopsv1alpha1.GitOpsDeploymentStatus{}, gitopsDepl, k8sClient, log.FromContext(ctx))
+		Expect(task).ToNot(BeNil())
+
+		shouldRetry, err := task.PerformTask(ctx)
+		Expect(err).ToNot(BeNil())
+		Expect(shouldRetry).To(BeFalse())
+	})
+
+	It("should retry reporting the commit status only if the Git provider could not be reached", func() {
+		gitopsDepl.Status = statusOf(managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy, managedgitopsv1alpha1.OperationSucceeded)
+		task := newCommitStatusTask(managedgitopsv1alpha1.GitOpsDeploymentStatus{}, gitopsDepl, k8sClient, log.FromContext(ctx))
+		Expect(task).ToNot(BeNil())
+
+		reporter.err = fmt.Errorf("unable to create GitLab commit status: %w", &url.Error{Op: "Post", URL: "https://gitlab.com", Err: fmt.Errorf("connection refused")})
+		shouldRetry, err := task.PerformTask(ctx)
+		Expect(err).ToNot(BeNil())
+		Expect(shouldRetry).To(BeTrue())
+
+		By("not retrying if the Git provider rejected the commit status")
+		reporter.err = fmt.Errorf("unable to create GitLab commit status: 401 Unauthorized")
+		shouldRetry, err = task.PerformTask(ctx)
+		Expect(err).ToNot(BeNil())
+		Expect(shouldRetry).To(BeFalse())
+	})
+})
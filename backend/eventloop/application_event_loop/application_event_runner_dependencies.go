@@ -0,0 +1,309 @@
+package application_event_loop
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	gitopserrors "github.com/redhat-appstudio/managed-gitops/backend-shared/util/gitopserrors"
+	"github.com/redhat-appstudio/managed-gitops/backend/condition"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileDeploymentDependencies determines whether the GitOpsDeployments that a GitOpsDeployment depends on (via
+// .spec.dependsOn) are synced and healthy, and thus whether the Application of the GitOpsDeployment may be created.
+//
+// The result is recorded in the DeploymentDependencyGate table, so that it is not lost on restart: once the dependencies
+// have been satisfied, the GitOpsDeployment is no longer gated, even if a dependency later becomes unhealthy (but the
+// dependencies are evaluated again if .spec.dependsOn is modified). The WaitingForDependencies condition of the
+// GitOpsDeployment is updated to reflect the result.
+//
+// Returns true if the GitOpsDeployment may be deployed, false if it is still waiting for its dependencies.
+func (a applicationEventLoopRunner_Action) reconcileDeploymentDependencies(ctx context.Context, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment,
+	namespaceUID string, dbQueries db.ApplicationScopedQueries) (bool, gitopserrors.UserError) {
+
+	log := a.log.WithValues("gitOpsDeploymentName", gitopsDeployment.Name, "gitopsDeploymentNamespace", gitopsDeployment.Namespace)
+
+	gate := db.DeploymentDependencyGate{Deploymentdependencygate_uid_id: string(gitopsDeployment.UID)}
+	gateExists := true
+	if err := dbQueries.GetDeploymentDependencyGateById(ctx, &gate); err != nil {
+		if !db.IsResultNotFoundError(err) {
+			log.Error(err, "unable to retrieve deployment dependency gate")
+			return false, gitopserrors.NewDevOnlyError(err)
+		}
+		gateExists = false
+	}
+
+	// 1) If the GitOpsDeployment has no dependencies, there is nothing to wait for
+	if len(gitopsDeployment.Spec.DependsOn) == 0 {
+		if gateExists {
+			if _, err := dbQueries.DeleteDeploymentDependencyGateById(ctx, gate.Deploymentdependencygate_uid_id); err != nil {
+				log.Error(err, "unable to delete deployment dependency gate")
+				return false, gitopserrors.NewDevOnlyError(err)
+			}
+		}
+		return true, a.setWaitingForDependenciesCondition(ctx, gitopsDeployment, nil)
+	}
+
+	dependenciesHash := hashDependencies(gitopsDeployment.Spec.DependsOn)
+
+	// 2) Once the dependencies have been satisfied, the GitOpsDeployment is no longer gated, unless they were modified since
+	if gateExists && gate.State == db.DeploymentDependencyGateState_Satisfied && gate.Dependencies_hash == dependenciesHash {
+		return true, a.setWaitingForDependenciesCondition(ctx, gitopsDeployment, nil)
+	}
+
+	// 3) Ensure that the dependencies do not contain a cycle, otherwise the GitOpsDeployment would wait forever
+	cycle, err := findDependencyCycle(ctx, a.workspaceClient, gitopsDeployment)
+	if err != nil {
+		log.Error(err, "unable to check the dependencies of the GitOpsDeployment for cycles")
+		return false, gitopserrors.NewDevOnlyError(err)
+	}
+	if len(cycle) > 0 {
+		userError := fmt.Sprintf("%s: %s", managedgitopsv1alpha1.GitOpsDeploymentUserError_DependencyCycle, strings.Join(cycle, " -> "))
+		return false, gitopserrors.NewUserDevError(userError, fmt.Errorf(userError))
+	}
+
+	// 4) Determine which of the dependencies are not yet synced and healthy
+	pendingDependencies, err := getPendingDependencies(ctx, a.workspaceClient, gitopsDeployment)
+	if err != nil {
+		log.Error(err, "unable to retrieve the dependencies of the GitOpsDeployment")
+		return false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	// 5) Record the result in the database
+	newGate := db.DeploymentDependencyGate{
+		Deploymentdependencygate_uid_id: string(gitopsDeployment.UID),
+		Name:                            gitopsDeployment.Name,
+		Namespace:                       gitopsDeployment.Namespace,
+		Namespace_uid:                   namespaceUID,
+		State:                           db.DeploymentDependencyGateState_Satisfied,
+		Pending_dependencies:            strings.Join(pendingDependencies, ","),
+		Dependencies_hash:               dependenciesHash,
+	}
+	if len(pendingDependencies) > 0 {
+		newGate.State = db.DeploymentDependencyGateState_Waiting
+	}
+
+	if !gateExists {
+		if err := dbQueries.CreateDeploymentDependencyGate(ctx, &newGate); err != nil {
+			log.Error(err, "unable to create deployment dependency gate", newGate.GetAsLogKeyValues()...)
+			return false, gitopserrors.NewDevOnlyError(err)
+		}
+		log.Info("Created deployment dependency gate", newGate.GetAsLogKeyValues()...)
+
+	} else if gate.State != newGate.State || gate.Pending_dependencies != newGate.Pending_dependencies ||
+		gate.Dependencies_hash != newGate.Dependencies_hash || gate.Name != newGate.Name || gate.Namespace != newGate.Namespace || gate.Namespace_uid != newGate.Namespace_uid {
+
+		newGate.SeqID = gate.SeqID
+		newGate.Created_on = gate.Created_on
+		if err := dbQueries.UpdateDeploymentDependencyGate(ctx, &newGate); err != nil {
+			log.Error(err, "unable to update deployment dependency gate", newGate.GetAsLogKeyValues()...)
+			return false, gitopserrors.NewDevOnlyError(err)
+		}
+		log.Info("Updated deployment dependency gate", newGate.GetAsLogKeyValues()...)
+	}
+
+	return len(pendingDependencies) == 0, a.setWaitingForDependenciesCondition(ctx, gitopsDeployment, pendingDependencies)
+}
+
+// setWaitingForDependenciesCondition sets the WaitingForDependencies condition to true if there are pending
+// dependencies, and otherwise sets it to false, if the condition was previously set.
+func (a applicationEventLoopRunner_Action) setWaitingForDependenciesCondition(ctx context.Context, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment,
+	pendingDependencies []string) gitopserrors.UserError {
+
+	conditionType := managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingForDependencies
+
+	if len(pendingDependencies) == 0 && !condition.NewConditionManager().HasCondition(&gitopsDeployment.Status.Conditions, conditionType) {
+		// The GitOpsDeployment has never waited for its dependencies, so there is no need to add the condition
+		return nil
+	}
+
+	originalConditions := make([]managedgitopsv1alpha1.GitOpsDeploymentCondition, len(gitopsDeployment.Status.Conditions))
+	for i := range gitopsDeployment.Status.Conditions {
+		gitopsDeployment.Status.Conditions[i].DeepCopyInto(&originalConditions[i])
+	}
+
+	if len(pendingDependencies) > 0 {
+		setConditionIfChanged(gitopsDeployment, conditionType, managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			managedgitopsv1alpha1.GitopsDeploymentReasonWaitingForDependencies,
+			"waiting for the following GitOpsDeployments to be synced and healthy: "+strings.Join(pendingDependencies, ", "))
+	} else {
+		setConditionIfChanged(gitopsDeployment, conditionType, managedgitopsv1alpha1.GitOpsConditionStatusFalse,
+			managedgitopsv1alpha1.GitopsDeploymentReasonDependenciesSatisfied, "all the GitOpsDeployments that this GitOpsDeployment depends on are synced and healthy")
+	}
+
+	if reflect.DeepEqual(originalConditions, gitopsDeployment.Status.Conditions) {
+		return nil
+	}
+
+	if err := a.workspaceClient.Status().Update(ctx, gitopsDeployment, &client.UpdateOptions{}); err != nil {
+		a.log.Error(err, "unable to update the WaitingForDependencies condition of GitOpsDeployment")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	return nil
+}
+
+// hashDependencies returns the hex-encoded SHA-256 hash of the (sorted) names of the dependencies, which is stored in
+// the DeploymentDependencyGate to detect when the dependencies of the GitOpsDeployment are modified.
+func hashDependencies(dependsOn []managedgitopsv1alpha1.GitOpsDeploymentDependency) string {
+
+	names := make([]string, 0, len(dependsOn))
+	for _, dependency := range dependsOn {
+		names = append(names, dependency.Name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.Sum256([]byte(strings.Join(names, ",")))
+	return hex.EncodeToString(hash[:])
+}
+
+// getPendingDependencies returns the names of the GitOpsDeployments that the GitOpsDeployment depends on, which are not
+// yet synced and healthy (or that do not exist).
+func getPendingDependencies(ctx context.Context, k8sClient client.Client, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment) ([]string, error) {
+
+	pendingDependencies := []string{}
+
+	for _, dependency := range gitopsDeployment.Spec.DependsOn {
+
+		dependencyDepl := &managedgitopsv1alpha1.GitOpsDeployment{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: gitopsDeployment.Namespace, Name: dependency.Name}, dependencyDepl); err != nil {
+			if apierr.IsNotFound(err) {
+				pendingDependencies = append(pendingDependencies, dependency.Name)
+				continue
+			}
+			return nil, err
+		}
+
+		if !isDependencySyncedAndHealthy(dependencyDepl) {
+			pendingDependencies = append(pendingDependencies, dependency.Name)
+		}
+	}
+
+	return pendingDependencies, nil
+}
+
+// isDependencySyncedAndHealthy returns true if the GitOpsDeployment is synced and healthy, and its status reflects the
+// latest version of its spec.
+func isDependencySyncedAndHealthy(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment) bool {
+
+	return gitopsDeployment.DeletionTimestamp == nil &&
+		gitopsDeployment.Status.ObservedGeneration >= gitopsDeployment.Generation &&
+		gitopsDeployment.Status.Sync.Status == managedgitopsv1alpha1.SyncStatusCodeSynced &&
+		gitopsDeployment.Status.Health.Status == managedgitopsv1alpha1.HeathStatusCodeHealthy
+}
+
+// findDependencyCycle returns the names of the GitOpsDeployments that form a cycle, if the dependencies of the
+// GitOpsDeployment (including transitive dependencies) lead back to a GitOpsDeployment that was already visited.
+// For example, [a, b, c, a]. If there is no cycle, nil is returned.
+func findDependencyCycle(ctx context.Context, k8sClient client.Client, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment) ([]string, error) {
+
+	// dependsOnByName caches the dependencies of each GitOpsDeployment that we have retrieved
+	dependsOnByName := map[string][]managedgitopsv1alpha1.GitOpsDeploymentDependency{
+		gitopsDeployment.Name: gitopsDeployment.Spec.DependsOn,
+	}
+
+	getDependsOn := func(name string) ([]managedgitopsv1alpha1.GitOpsDeploymentDependency, error) {
+		if dependsOn, exists := dependsOnByName[name]; exists {
+			return dependsOn, nil
+		}
+
+		dependencyDepl := &managedgitopsv1alpha1.GitOpsDeployment{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: gitopsDeployment.Namespace, Name: name}, dependencyDepl); err != nil {
+			if !apierr.IsNotFound(err) {
+				return nil, err
+			}
+			// A GitOpsDeployment that does not exist has no dependencies
+			dependencyDepl = &managedgitopsv1alpha1.GitOpsDeployment{}
+		}
+
+		dependsOnByName[name] = dependencyDepl.Spec.DependsOn
+		return dependencyDepl.Spec.DependsOn, nil
+	}
+
+	// Depth-first search of the dependency graph: 'path' contains the GitOpsDeployments from the root to the current
+	// GitOpsDeployment, and 'visited' contains GitOpsDeployments whose dependencies were fully explored without a cycle.
+	visited := map[string]bool{}
+
+	var visit func(name string, path []string) ([]string, error)
+	visit = func(name string, path []string) ([]string, error) {
+
+		for i, pathEntry := range path {
+			if pathEntry == name {
+				return append(append([]string{}, path[i:]...), name), nil
+			}
+		}
+
+		if visited[name] {
+			return nil, nil
+		}
+
+		dependsOn, err := getDependsOn(name)
+		if err != nil {
+			return nil, err
+		}
+
+		path = append(path, name)
+		for _, dependency := range dependsOn {
+			cycle, err := visit(dependency.Name, path)
+			if err != nil || len(cycle) > 0 {
+				return cycle, err
+			}
+		}
+
+		visited[name] = true
+		return nil, nil
+	}
+
+	return visit(gitopsDeployment.Name, nil)
+}
+
+// isWaitingForDependencies returns true if the GitOpsDeployment is waiting for the GitOpsDeployments it depends on to
+// be synced and healthy, based on its DeploymentDependencyGate.
+func isWaitingForDependencies(ctx context.Context, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, dbQueries db.ApplicationScopedQueries) (bool, error) {
+
+	if len(gitopsDeployment.Spec.DependsOn) == 0 {
+		return false, nil
+	}
+
+	gate := db.DeploymentDependencyGate{Deploymentdependencygate_uid_id: string(gitopsDeployment.UID)}
+	if err := dbQueries.GetDeploymentDependencyGateById(ctx, &gate); err != nil {
+		if db.IsResultNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return gate.State == db.DeploymentDependencyGateState_Waiting, nil
+}
+
+// deleteDeploymentDependencyGates removes the DeploymentDependencyGates of GitOpsDeployments that no longer exist:
+// either the deleted GitOpsDeployment that we received the event for, or previous GitOpsDeployments with the same
+// name/namespace (referenced by the old DeploymentToApplicationMappings).
+func (a applicationEventLoopRunner_Action) deleteDeploymentDependencyGates(ctx context.Context, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment,
+	oldDeplToAppMappings []db.DeploymentToApplicationMapping, namespaceUID string, dbQueries db.ApplicationScopedQueries) gitopserrors.UserError {
+
+	if isGitOpsDeploymentDeleted(gitopsDeployment) {
+		if _, err := dbQueries.DeleteDeploymentDependencyGatesByNamespaceAndName(ctx, a.eventResourceName, a.eventResourceNamespace, namespaceUID); err != nil {
+			a.log.Error(err, "unable to delete deployment dependency gates of deleted GitOpsDeployment")
+			return gitopserrors.NewDevOnlyError(err)
+		}
+		return nil
+	}
+
+	for _, oldDTAM := range oldDeplToAppMappings {
+		if _, err := dbQueries.DeleteDeploymentDependencyGateById(ctx, oldDTAM.Deploymenttoapplicationmapping_uid_id); err != nil {
+			a.log.Error(err, "unable to delete deployment dependency gate of old GitOpsDeployment", "uid", oldDTAM.Deploymenttoapplicationmapping_uid_id)
+			return gitopserrors.NewDevOnlyError(err)
+		}
+	}
+
+	return nil
+}
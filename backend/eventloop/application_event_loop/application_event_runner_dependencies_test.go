@@ -0,0 +1,331 @@
+package application_event_loop
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	"github.com/redhat-appstudio/managed-gitops/backend/condition"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Application Event Runner Dependencies", func() {
+
+	newGitOpsDeployment := func(name string, namespace string, dependsOn ...string) *managedgitopsv1alpha1.GitOpsDeployment {
+		gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				UID:       types.UID("test-" + uuid.NewUUID()),
+			},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Source: managedgitopsv1alpha1.ApplicationSource{
+					RepoURL:        "https://github.com/abc-org/abc-repo",
+					Path:           "/abc-path",
+					TargetRevision: "abc-commit"},
+				Destination: managedgitopsv1alpha1.ApplicationDestination{
+					Namespace: "abc-namespace",
+				},
+				Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+			},
+		}
+		for _, dependency := range dependsOn {
+			gitopsDepl.Spec.DependsOn = append(gitopsDepl.Spec.DependsOn, managedgitopsv1alpha1.GitOpsDeploymentDependency{Name: dependency})
+		}
+		return gitopsDepl
+	}
+
+	markSyncedAndHealthy := func(gitopsDepl *managedgitopsv1alpha1.GitOpsDeployment) {
+		gitopsDepl.Status.Sync.Status = managedgitopsv1alpha1.SyncStatusCodeSynced
+		gitopsDepl.Status.Health.Status = managedgitopsv1alpha1.HeathStatusCodeHealthy
+		gitopsDepl.Status.ObservedGeneration = gitopsDepl.Generation
+	}
+
+	Context("findDependencyCycle should detect cycles between GitOpsDeployments", func() {
+
+		var ctx context.Context
+		var scheme *runtime.Scheme
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			var err error
+			scheme, _, _, _, err = tests.GenericTestSetup()
+			Expect(err).To(BeNil())
+		})
+
+		It("should not report a cycle for a chain of dependencies, including a dependency that doesn't exist", func() {
+			a := newGitOpsDeployment("a", "jane", "b", "c")
+			b := newGitOpsDeployment("b", "jane", "c")
+			c := newGitOpsDeployment("c", "jane", "does-not-exist")
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(a, b, c).Build()
+
+			cycle, err := findDependencyCycle(ctx, k8sClient, a)
+			Expect(err).To(BeNil())
+			Expect(cycle).To(BeEmpty())
+		})
+
+		It("should report the GitOpsDeployments that form a cycle", func() {
+			a := newGitOpsDeployment("a", "jane", "b")
+			b := newGitOpsDeployment("b", "jane", "c")
+			c := newGitOpsDeployment("c", "jane", "a")
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(a, b, c).Build()
+
+			cycle, err := findDependencyCycle(ctx, k8sClient, a)
+			Expect(err).To(BeNil())
+			Expect(cycle).To(Equal([]string{"a", "b", "c", "a"}))
+		})
+
+		It("should report a cycle between dependencies, that doesn't include the GitOpsDeployment itself", func() {
+			a := newGitOpsDeployment("a", "jane", "b")
+			b := newGitOpsDeployment("b", "jane", "c")
+			c := newGitOpsDeployment("c", "jane", "b")
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(a, b, c).Build()
+
+			cycle, err := findDependencyCycle(ctx, k8sClient, a)
+			Expect(err).To(BeNil())
+			Expect(cycle).To(Equal([]string{"b", "c", "b"}))
+		})
+
+		It("should only consider GitOpsDeployments in the same namespace", func() {
+			a := newGitOpsDeployment("a", "jane", "b")
+			b := newGitOpsDeployment("b", "john", "a")
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(a, b).Build()
+
+			cycle, err := findDependencyCycle(ctx, k8sClient, a)
+			Expect(err).To(BeNil())
+			Expect(cycle).To(BeEmpty())
+		})
+	})
+
+	Context("getPendingDependencies should return the dependencies that are not yet synced and healthy", func() {
+
+		It("should return missing, unhealthy, and out of date dependencies", func() {
+			ctx := context.Background()
+
+			scheme, _, _, _, err := tests.GenericTestSetup()
+			Expect(err).To(BeNil())
+
+			ready := newGitOpsDeployment("ready", "jane")
+			markSyncedAndHealthy(ready)
+
+			unhealthy := newGitOpsDeployment("unhealthy", "jane")
+			markSyncedAndHealthy(unhealthy)
+			unhealthy.Status.Health.Status = managedgitopsv1alpha1.HeathStatusCodeDegraded
+
+			outOfDate := newGitOpsDeployment("out-of-date", "jane")
+			outOfDate.Generation = 2
+			markSyncedAndHealthy(outOfDate)
+			outOfDate.Status.ObservedGeneration = 1
+
+			dependent := newGitOpsDeployment("dependent", "jane", "ready", "unhealthy", "out-of-date", "missing")
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ready, unhealthy, outOfDate, dependent).Build()
+
+			pendingDependencies, err := getPendingDependencies(ctx, k8sClient, dependent)
+			Expect(err).To(BeNil())
+			Expect(pendingDependencies).To(Equal([]string{"unhealthy", "out-of-date", "missing"}))
+		})
+	})
+
+	Context("hashDependencies should detect when the dependencies are modified", func() {
+
+		It("should return the same hash for the same dependencies, regardless of their order", func() {
+			Expect(hashDependencies(newGitOpsDeployment("a", "jane", "b", "c").Spec.DependsOn)).
+				To(Equal(hashDependencies(newGitOpsDeployment("a", "jane", "c", "b").Spec.DependsOn)))
+			Expect(hashDependencies(newGitOpsDeployment("a", "jane", "b", "c").Spec.DependsOn)).
+				ToNot(Equal(hashDependencies(newGitOpsDeployment("a", "jane", "b").Spec.DependsOn)))
+		})
+	})
+
+	Context("Handle deployment modified for a GitOpsDeployment with dependencies", func() {
+
+		var err error
+		var workspaceID string
+		var ctx context.Context
+		var workspace *corev1.Namespace
+		var dbQueries db.AllDatabaseQueries
+		var k8sClient client.WithWatch
+		var dependency *managedgitopsv1alpha1.GitOpsDeployment
+		var dependent *managedgitopsv1alpha1.GitOpsDeployment
+		var appEventLoopRunnerAction applicationEventLoopRunner_Action
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			err = db.SetupForTestingDBGinkgo()
+			Expect(err).To(BeNil())
+
+			scheme, argocdNamespace, kubesystemNamespace, ws, err := tests.GenericTestSetup()
+			Expect(err).To(BeNil())
+			workspace = ws
+			workspaceID = string(workspace.UID)
+
+			dependency = newGitOpsDeployment("database", workspace.Name)
+			dependent = newGitOpsDeployment("backend", workspace.Name, dependency.Name)
+
+			k8sClient = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(dependency, dependent, workspace, argocdNamespace, kubesystemNamespace).
+				Build()
+
+			dbQueries, err = db.NewUnsafePostgresDBQueries(false, false)
+			Expect(err).To(BeNil())
+
+			appEventLoopRunnerAction = applicationEventLoopRunner_Action{
+				eventResourceName:           dependent.Name,
+				eventResourceNamespace:      dependent.Namespace,
+				workspaceClient:             k8sClient,
+				log:                         log.FromContext(context.Background()),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				workspaceID:                 workspaceID,
+				testOnlySkipCreateOperation: true,
+				k8sClientFactory: MockSRLK8sClientFactory{
+					fakeClient: k8sClient,
+				},
+			}
+		})
+
+		AfterEach(func() {
+			dbQueries.CloseDatabase()
+		})
+
+		It("should not create the Application of a GitOpsDeployment until its dependencies are synced and healthy", func() {
+
+			By("processing the dependent GitOpsDeployment, while its dependency is not yet synced and healthy")
+			_, _, _, result, userDevErr := appEventLoopRunnerAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+			Expect(result).To(Equal(deploymentModifiedResult_NoChange))
+
+			var dtams []db.DeploymentToApplicationMapping
+			err = dbQueries.ListDeploymentToApplicationMappingByNamespaceAndName(ctx, dependent.Name, dependent.Namespace, workspaceID, &dtams)
+			Expect(err).To(BeNil())
+			Expect(dtams).To(BeEmpty(), "no Application should be created while waiting for dependencies")
+
+			By("verifying that the gating state is stored in the database")
+			gate := db.DeploymentDependencyGate{Deploymentdependencygate_uid_id: string(dependent.UID)}
+			err = dbQueries.GetDeploymentDependencyGateById(ctx, &gate)
+			Expect(err).To(BeNil())
+			Expect(gate.State).To(Equal(db.DeploymentDependencyGateState_Waiting))
+			Expect(gate.Pending_dependencies).To(Equal(dependency.Name))
+
+			waiting, err := isWaitingForDependencies(ctx, dependent, dbQueries)
+			Expect(err).To(BeNil())
+			Expect(waiting).To(BeTrue())
+
+			By("verifying that the WaitingForDependencies condition is set")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(dependent), dependent)
+			Expect(err).To(BeNil())
+			waitingCondition, _ := condition.NewConditionManager().FindCondition(&dependent.Status.Conditions,
+				managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingForDependencies)
+			Expect(waitingCondition).ToNot(BeNil())
+			Expect(waitingCondition.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+			Expect(waitingCondition.Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonWaitingForDependencies))
+			Expect(waitingCondition.Message).To(ContainSubstring(dependency.Name))
+
+			By("marking the dependency as synced and healthy")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(dependency), dependency)
+			Expect(err).To(BeNil())
+			markSyncedAndHealthy(dependency)
+			err = k8sClient.Status().Update(ctx, dependency)
+			Expect(err).To(BeNil())
+
+			By("processing the dependent GitOpsDeployment again, which should now create the Application")
+			_, _, _, result, userDevErr = appEventLoopRunnerAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+			Expect(result).To(Equal(deploymentModifiedResult_Created))
+
+			err = dbQueries.ListDeploymentToApplicationMappingByNamespaceAndName(ctx, dependent.Name, dependent.Namespace, workspaceID, &dtams)
+			Expect(err).To(BeNil())
+			Expect(dtams).To(HaveLen(1))
+
+			err = dbQueries.GetDeploymentDependencyGateById(ctx, &gate)
+			Expect(err).To(BeNil())
+			Expect(gate.State).To(Equal(db.DeploymentDependencyGateState_Satisfied))
+			Expect(gate.Pending_dependencies).To(BeEmpty())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(dependent), dependent)
+			Expect(err).To(BeNil())
+			waitingCondition, _ = condition.NewConditionManager().FindCondition(&dependent.Status.Conditions,
+				managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingForDependencies)
+			Expect(waitingCondition).ToNot(BeNil())
+			Expect(waitingCondition.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+			Expect(waitingCondition.Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonDependenciesSatisfied))
+
+			By("deleting the dependent GitOpsDeployment, which should delete its gate")
+			err = k8sClient.Delete(ctx, dependent)
+			Expect(err).To(BeNil())
+
+			_, _, _, result, userDevErr = appEventLoopRunnerAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+			Expect(result).To(Equal(deploymentModifiedResult_Deleted))
+
+			err = dbQueries.GetDeploymentDependencyGateById(ctx, &gate)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
+		It("should evaluate the dependencies again if they are modified, after they were satisfied", func() {
+
+			By("marking the dependency as synced and healthy, so that the gate is satisfied")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(dependency), dependency)
+			Expect(err).To(BeNil())
+			markSyncedAndHealthy(dependency)
+			err = k8sClient.Status().Update(ctx, dependency)
+			Expect(err).To(BeNil())
+
+			satisfied, userDevErr := appEventLoopRunnerAction.reconcileDeploymentDependencies(ctx, dependent, workspaceID, dbQueries)
+			Expect(userDevErr).To(BeNil())
+			Expect(satisfied).To(BeTrue())
+
+			By("adding a dependency that does not exist, which should close the gate again")
+			dependent.Spec.DependsOn = append(dependent.Spec.DependsOn, managedgitopsv1alpha1.GitOpsDeploymentDependency{Name: "cache"})
+
+			satisfied, userDevErr = appEventLoopRunnerAction.reconcileDeploymentDependencies(ctx, dependent, workspaceID, dbQueries)
+			Expect(userDevErr).To(BeNil())
+			Expect(satisfied).To(BeFalse())
+
+			gate := db.DeploymentDependencyGate{Deploymentdependencygate_uid_id: string(dependent.UID)}
+			err = dbQueries.GetDeploymentDependencyGateById(ctx, &gate)
+			Expect(err).To(BeNil())
+			Expect(gate.State).To(Equal(db.DeploymentDependencyGateState_Waiting))
+			Expect(gate.Pending_dependencies).To(Equal("cache"))
+			Expect(gate.Dependencies_hash).To(Equal(hashDependencies(dependent.Spec.DependsOn)))
+		})
+
+		It("should return a user error if the dependencies of a GitOpsDeployment contain a cycle", func() {
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(dependency), dependency)
+			Expect(err).To(BeNil())
+			dependency.Spec.DependsOn = []managedgitopsv1alpha1.GitOpsDeploymentDependency{{Name: dependent.Name}}
+			err = k8sClient.Update(ctx, dependency)
+			Expect(err).To(BeNil())
+
+			_, _, _, result, userDevErr := appEventLoopRunnerAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).ToNot(BeNil())
+			Expect(result).To(Equal(deploymentModifiedResult_Failed))
+			Expect(userDevErr.UserError()).To(HavePrefix(managedgitopsv1alpha1.GitOpsDeploymentUserError_DependencyCycle))
+			Expect(strings.Contains(userDevErr.UserError(), "backend -> database -> backend")).To(BeTrue())
+
+			var dtams []db.DeploymentToApplicationMapping
+			err = dbQueries.ListDeploymentToApplicationMappingByNamespaceAndName(ctx, dependent.Name, dependent.Namespace, workspaceID, &dtams)
+			Expect(err).To(BeNil())
+			Expect(dtams).To(BeEmpty())
+		})
+	})
+})
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/yaml"
 
@@ -111,17 +113,20 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleDeploym
 
 	if !isGitOpsDeploymentDeleted(gitopsDeployment) {
 		// Perform basic validation of GitOpsDeployment values
+		if userErr := checkValidGitOpsDeploymentSources(gitopsDeployment.Spec); userErr != nil {
+			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, userErr
+		}
 
-		if gitopsDeployment.Spec.Source.Path == "" {
-			userError := managedgitopsv1alpha1.GitOpsDeploymentUserError_PathIsRequired
-			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed,
-				gitopserrors.NewUserDevError(userError, fmt.Errorf(userError))
+		if userErr := checkValidSyncPolicy(gitopsDeployment.Spec); userErr != nil {
+			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, userErr
+		}
 
-		} else if gitopsDeployment.Spec.Source.Path == "/" {
-			userError := managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidPathSlash
-			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed,
-				gitopserrors.NewUserDevError(userError, fmt.Errorf(userError))
+		if userErr := checkValidIgnoreDifferences(gitopsDeployment.Spec.IgnoreDifferences); userErr != nil {
+			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, userErr
+		}
 
+		if userErr := checkValidDeletionPolicy(gitopsDeployment.Spec.DeletionPolicy); userErr != nil {
+			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, userErr
 		}
 	}
 
@@ -173,6 +178,12 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleDeploym
 			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, deleteErr
 		}
 		//}
+
+		// Remove the dependency gates of GitOpsDeployments that no longer exist
+		if userErr := a.deleteDeploymentDependencyGates(ctx, gitopsDeployment, oldDeplToAppMappings,
+			eventlooptypes.GetWorkspaceIDFromNamespaceID(gitopsDeplNamespace), dbQueries); userErr != nil {
+			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, userErr
+		}
 	}
 
 	// 5) Finally, handle the resource event, based on whether it is a create, update, or no-op
@@ -184,6 +195,18 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleDeploym
 			// 5a) If the gitopsdepl CR exists, but the database entry doesn't,
 			// then this is the first time we have seen the GitOpsDepl CR.
 			// Create it in the DB and create the operation.
+
+			// However, if the GitOpsDeployment depends on other GitOpsDeployments, wait for them to be synced and healthy first.
+			dependenciesSatisfied, userErr := a.reconcileDeploymentDependencies(ctx, gitopsDeployment,
+				eventlooptypes.GetWorkspaceIDFromNamespaceID(gitopsDeplNamespace), dbQueries)
+			if userErr != nil {
+				return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, userErr
+			}
+			if !dependenciesSatisfied {
+				a.log.Info("GitOpsDeployment is waiting for its dependencies to be synced and healthy")
+				return signalledShutdown_false, nil, nil, deploymentModifiedResult_NoChange, nil
+			}
+
 			application, gitopsEngineInstance, deplModifiedResult, err :=
 				a.handleNewGitOpsDeplEvent(ctx, *gitopsDeployment, clusterUser, dbQueries)
 
@@ -273,28 +296,41 @@ func (a applicationEventLoopRunner_Action) handleNewGitOpsDeplEvent(ctx context.
 		return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewUserDevError(userError, devError)
 	}
 
-	// Create AppProjectRepository row based on GitopsDeployment and
+	// Create AppProjectRepository row based on GitopsDeployment (one for each repository referenced by the GitOpsDeployment) and
 	// The RepositoryCredentialsID field is nil when creating an AppProjectRepository based on GitopsDeployment because the value of AppProjectRepository is generated based on an Application.
-	appProjectRepoCredDB := db.AppProjectRepository{
-		Clusteruser_id:          clusterUser.Clusteruser_id,
-		RepositorycredentialsID: "",
-		RepoURL:                 sharedloop.NormalizeGitURL(gitopsDeployment.Spec.Source.RepoURL),
-	}
+	for _, repoURL := range getSourceRepoURLs(gitopsDeployment.Spec) {
 
-	if err := dbQueries.GetAppProjectRepositoryByClusterUserAndRepoURL(ctx, &appProjectRepoCredDB); err != nil {
-		if db.IsResultNotFoundError(err) {
-			if err := dbQueries.CreateAppProjectRepository(ctx, &appProjectRepoCredDB); err != nil {
-				a.log.Error(err, "Unable to create appProjectRepository based on GitopsDeployment", appProjectRepoCredDB.GetAsLogKeyValues()...)
+		appProjectRepoCredDB := db.AppProjectRepository{
+			Clusteruser_id:          clusterUser.Clusteruser_id,
+			RepositorycredentialsID: "",
+			RepoURL:                 repoURL,
+		}
+
+		if err := dbQueries.GetAppProjectRepositoryByClusterUserAndRepoURL(ctx, &appProjectRepoCredDB); err != nil {
+			if db.IsResultNotFoundError(err) {
+				if err := dbQueries.CreateAppProjectRepository(ctx, &appProjectRepoCredDB); err != nil {
+					a.log.Error(err, "Unable to create appProjectRepository based on GitopsDeployment", appProjectRepoCredDB.GetAsLogKeyValues()...)
+
+					return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
+				}
+
+				a.log.Info("Created new AppProjectRepository in DB based on GitopsDeployment: "+appProjectRepoCredDB.AppprojectRepositoryID, appProjectRepoCredDB.GetAsLogKeyValues()...)
+			} else {
+				a.log.Error(err, "Unable to retrieve AppProjectRepository from database based on GitopsDeployment: "+appProjectRepoCredDB.AppprojectRepositoryID, appProjectRepoCredDB.GetAsLogKeyValues()...)
 
 				return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
 			}
+		}
+	}
 
-			a.log.Info("Created new AppProjectRepository in DB based on GitopsDeployment: "+appProjectRepoCredDB.AppprojectRepositoryID, appProjectRepoCredDB.GetAsLogKeyValues()...)
-		} else {
-			a.log.Error(err, "Unable to retrieve AppProjectRepository from database based on GitopsDeployment: "+appProjectRepoCredDB.AppprojectRepositoryID, appProjectRepoCredDB.GetAsLogKeyValues()...)
-
+	automated := strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated) && !gitopsDeployment.Spec.Suspend
+	if automated {
+		automatedSyncDisabled, err := isAutomatedSyncDisabledBySyncRun(ctx, gitopsDeployment, a.workspaceClient)
+		if err != nil {
+			a.log.Error(err, "unable to determine whether the automated sync policy is disabled by a GitOpsDeploymentSyncRun")
 			return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
 		}
+		automated = !automatedSyncDisabled
 	}
 
 	specFieldInput := argoCDSpecInput{
@@ -306,9 +342,17 @@ func (a applicationEventLoopRunner_Action) handleNewGitOpsDeplEvent(ctx context.
 		sourceRepoURL:        gitopsDeployment.Spec.Source.RepoURL,
 		sourcePath:           gitopsDeployment.Spec.Source.Path,
 		sourceTargetRevision: gitopsDeployment.Spec.Source.TargetRevision,
+		sourceChart:          gitopsDeployment.Spec.Source.Chart,
+		sourceHelm:           gitopsDeployment.Spec.Source.Helm,
+		sourceKustomize:      gitopsDeployment.Spec.Source.Kustomize,
+		sources:              gitopsDeployment.Spec.Sources,
+		ignoreDifferences:    gitopsDeployment.Spec.IgnoreDifferences,
 		// syncOptions:       if non-empty, it gets updated below.
-		automated: strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated),
-		project:   appProjectPrefix + appProjectRepoCredDB.Clusteruser_id,
+		// A suspended GitOpsDeployment is never automatically synced: resuming it restores the automated sync policy.
+		// Likewise, a rollback SyncRun may disable the automated sync policy for as long as it exists.
+		automated:      automated,
+		project:        appProjectPrefix + clusterUser.Clusteruser_id,
+		deletionPolicy: gitopsDeployment.Spec.DeletionPolicy,
 	}
 
 	// If AppProject-based isolation is disabled, then just default to using 'default' as the project field in the Argo CD Application
@@ -327,6 +371,11 @@ func (a applicationEventLoopRunner_Action) handleNewGitOpsDeplEvent(ctx context.
 
 	}
 
+	if gitopsDeployment.Spec.SyncPolicy != nil {
+		specFieldInput.automatedSyncPolicy = gitopsDeployment.Spec.SyncPolicy.Automated
+		specFieldInput.retry = gitopsDeployment.Spec.SyncPolicy.Retry
+	}
+
 	specFieldText, err := createSpecField(specFieldInput)
 	if err != nil {
 		a.log.Error(err, "SEVERE: unable to marshal generated YAML")
@@ -352,6 +401,9 @@ func (a applicationEventLoopRunner_Action) handleNewGitOpsDeplEvent(ctx context.
 	}
 	a.log.Info("Created new Application in DB: "+application.Application_id, application.GetAsLogKeyValues()...)
 
+	eventlooptypes.RecordNormalEvent(a.eventRecorder, &gitopsDeployment, eventlooptypes.EventReasonApplicationCreated,
+		fmt.Sprintf("Created Argo CD Application '%s' for the GitOpsDeployment", appName))
+
 	// Create ApplicationOwner row in DB
 	applicationOwner := &db.ApplicationOwner{
 		ApplicationOwnerApplicationID: application.Application_id,
@@ -657,35 +709,49 @@ func (a applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplEvent(ctx cont
 		return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewUserDevError(userError, devError)
 	}
 
-	// Before updating Application ensure that AppProjectRepository row has been created (if necessary)
-	appProjectRepoCredDB := db.AppProjectRepository{
-		Clusteruser_id: clusterUser.Clusteruser_id,
-		RepoURL:        sharedloop.NormalizeGitURL(gitopsDeployment.Spec.Source.RepoURL),
-	}
+	// Before updating Application ensure that AppProjectRepository rows have been created (if necessary), for each
+	// repository referenced by the GitOpsDeployment
+	for _, repoURL := range getSourceRepoURLs(gitopsDeployment.Spec) {
 
-	if err := dbQueries.GetAppProjectRepositoryByClusterUserAndRepoURL(ctx, &appProjectRepoCredDB); err != nil {
+		appProjectRepoCredDB := db.AppProjectRepository{
+			Clusteruser_id: clusterUser.Clusteruser_id,
+			RepoURL:        repoURL,
+		}
 
-		// If AppProjectRepository is not present in DB, create it.
-		if db.IsResultNotFoundError(err) {
-			appProjectRepoCredDB := db.AppProjectRepository{
-				Clusteruser_id:          clusterUser.Clusteruser_id,
-				RepositorycredentialsID: "",
-				RepoURL:                 sharedloop.NormalizeGitURL(gitopsDeployment.Spec.Source.RepoURL),
-			}
+		if err := dbQueries.GetAppProjectRepositoryByClusterUserAndRepoURL(ctx, &appProjectRepoCredDB); err != nil {
+
+			// If AppProjectRepository is not present in DB, create it.
+			if db.IsResultNotFoundError(err) {
+				appProjectRepoCredDB := db.AppProjectRepository{
+					Clusteruser_id:          clusterUser.Clusteruser_id,
+					RepositorycredentialsID: "",
+					RepoURL:                 repoURL,
+				}
 
-			if err := dbQueries.CreateAppProjectRepository(ctx, &appProjectRepoCredDB); err != nil {
-				a.log.Error(err, "Unable to create appProjectRepository based on GitopsDeployment", appProjectRepoCredDB.GetAsLogKeyValues()...)
+				if err := dbQueries.CreateAppProjectRepository(ctx, &appProjectRepoCredDB); err != nil {
+					a.log.Error(err, "Unable to create appProjectRepository based on GitopsDeployment", appProjectRepoCredDB.GetAsLogKeyValues()...)
 
+					return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
+				}
+
+				a.log.Info("Created new AppProjectRepository in DB based on GitopsDeployment: "+appProjectRepoCredDB.AppprojectRepositoryID, appProjectRepoCredDB.GetAsLogKeyValues()...)
+
+			} else {
+				a.log.Error(err, "Unable to retrieve appProjectRepository based on GitopsDeployment", appProjectRepoCredDB.GetAsLogKeyValues()...)
 				return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
 			}
 
-			a.log.Info("Created new AppProjectRepository in DB based on GitopsDeployment: "+appProjectRepoCredDB.AppprojectRepositoryID, appProjectRepoCredDB.GetAsLogKeyValues()...)
+		}
+	}
 
-		} else {
-			a.log.Error(err, "Unable to retrieve appProjectRepository based on GitopsDeployment", appProjectRepoCredDB.GetAsLogKeyValues()...)
+	automated := strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated) && !gitopsDeployment.Spec.Suspend
+	if automated {
+		automatedSyncDisabled, err := isAutomatedSyncDisabledBySyncRun(ctx, gitopsDeployment, a.workspaceClient)
+		if err != nil {
+			log.Error(err, "unable to determine whether the automated sync policy is disabled by a GitOpsDeploymentSyncRun")
 			return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
 		}
-
+		automated = !automatedSyncDisabled
 	}
 
 	specFieldInput := argoCDSpecInput{
@@ -696,9 +762,17 @@ func (a applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplEvent(ctx cont
 		sourceRepoURL:        gitopsDeployment.Spec.Source.RepoURL,
 		sourcePath:           gitopsDeployment.Spec.Source.Path,
 		sourceTargetRevision: gitopsDeployment.Spec.Source.TargetRevision,
+		sourceChart:          gitopsDeployment.Spec.Source.Chart,
+		sourceHelm:           gitopsDeployment.Spec.Source.Helm,
+		sourceKustomize:      gitopsDeployment.Spec.Source.Kustomize,
+		sources:              gitopsDeployment.Spec.Sources,
+		ignoreDifferences:    gitopsDeployment.Spec.IgnoreDifferences,
 		// syncOptions:       if non-empty, it gets updated below.
-		automated: strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated),
-		project:   appProjectPrefix + appProjectRepoCredDB.Clusteruser_id,
+		// A suspended GitOpsDeployment is never automatically synced: resuming it restores the automated sync policy.
+		// Likewise, a rollback SyncRun may disable the automated sync policy for as long as it exists.
+		automated:      automated,
+		project:        appProjectPrefix + clusterUser.Clusteruser_id,
+		deletionPolicy: gitopsDeployment.Spec.DeletionPolicy,
 	}
 
 	// If AppProject-based isolation is disabled, then just default to using 'default' as the project field in the Argo CD Application
@@ -713,6 +787,11 @@ func (a applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplEvent(ctx cont
 
 		specFieldInput.syncOptions = managedgitopsv1alpha1.SyncOptionToStringSlice(gitopsDeployment.Spec.SyncPolicy.SyncOptions)
 	}
+
+	if gitopsDeployment.Spec.SyncPolicy != nil {
+		specFieldInput.automatedSyncPolicy = gitopsDeployment.Spec.SyncPolicy.Automated
+		specFieldInput.retry = gitopsDeployment.Spec.SyncPolicy.Retry
+	}
 	shouldUpdateApplication := false
 
 	// If the spec field changed from what is in the database, we should update the application
@@ -851,7 +930,27 @@ func (a applicationEventLoopRunner_Action) cleanOldGitOpsDeploymentEntry(ctx con
 		log.Info("ApplicationState rows were successfully deleted, while cleaning up after deleted GitOpsDeployment", "rowsDeleted", rowsDeleted)
 	}
 
-	// 2) Set the application field of SyncOperations to nil, for all SyncOperations that point to this Application
+	// 2) Remove the DeploymentHistory rows of the Application from the database
+	// - Any rows that are recorded after this point are deleted with the Application row, by the foreign key constraint.
+	rowsDeleted, err = dbQueries.DeleteDeploymentHistoryByApplicationId(ctx, deplToAppMapping.Application_id)
+	if err != nil {
+		log.V(logutil.LogLevel_Warn).Error(err, "unable to delete deployment history by application id")
+		return false, err
+	} else if rowsDeleted > 0 {
+		log.Info("DeploymentHistory rows were successfully deleted, while cleaning up after deleted GitOpsDeployment", "rowsDeleted", rowsDeleted)
+	}
+
+	// Remove any Preview rows of the Application from the database
+	// - As with DeploymentHistory, any rows that are created after this point are deleted with the Application row.
+	rowsDeleted, err = dbQueries.DeletePreviewsByApplicationId(ctx, deplToAppMapping.Application_id)
+	if err != nil {
+		log.V(logutil.LogLevel_Warn).Error(err, "unable to delete previews by application id")
+		return false, err
+	} else if rowsDeleted > 0 {
+		log.Info("Preview rows were successfully deleted, while cleaning up after deleted GitOpsDeployment", "rowsDeleted", rowsDeleted)
+	}
+
+	// 3) Set the application field of SyncOperations to nil, for all SyncOperations that point to this Application
 	// - this ensures that the foreign key constraint of SyncOperation doesn't prevent us from deletion the Application
 	rowsUpdated, err := dbQueries.UpdateSyncOperationRemoveApplicationField(ctx, deplToAppMapping.Application_id)
 	if err != nil {
@@ -864,7 +963,7 @@ func (a applicationEventLoopRunner_Action) cleanOldGitOpsDeploymentEntry(ctx con
 		log.Info("Removed references to Application from all SyncOperations that reference it")
 	}
 
-	// 3) Delete DeplToAppMapping row that points to this Application
+	// 4) Delete DeplToAppMapping row that points to this Application
 	rowsDeleted, err = dbQueries.DeleteDeploymentToApplicationMappingByDeplId(ctx, deplToAppMapping.Deploymenttoapplicationmapping_uid_id)
 	if err != nil {
 		log.Error(err, "unable to delete deplToAppMapping by id", "deplToAppMapUid", deplToAppMapping.Deploymenttoapplicationmapping_uid_id)
@@ -883,7 +982,7 @@ func (a applicationEventLoopRunner_Action) cleanOldGitOpsDeploymentEntry(ctx con
 		return true, nil
 	}
 
-	// 4) Remove ApplicationOwner from database
+	// 5) Remove ApplicationOwner from database
 	log.Info("GitOpsDeployment was deleted, so deleting ApplicationOwner row from database")
 	rowsDeleted, err = dbQueries.DeleteApplicationOwner(ctx, deplToAppMapping.Application_id)
 	if err != nil {
@@ -896,7 +995,7 @@ func (a applicationEventLoopRunner_Action) cleanOldGitOpsDeploymentEntry(ctx con
 
 	// If the Application table entry still exists, finish the cleanup...
 
-	// 5) Remove the Application from the database
+	// 6) Remove the Application from the database
 	log.Info("GitOpsDeployment was deleted, so deleting Application row from database")
 	rowsDeleted, err = dbQueries.DeleteApplicationById(ctx, deplToAppMapping.Application_id)
 	if err != nil {
@@ -914,20 +1013,35 @@ func (a applicationEventLoopRunner_Action) cleanOldGitOpsDeploymentEntry(ctx con
 		return false, err
 	}
 
-	appProjectRepoCredDB := db.AppProjectRepository{
-		Clusteruser_id: clusterUser.Clusteruser_id,
-		RepoURL:        sharedloop.NormalizeGitURL(specFieldAppFromDB.Spec.Source.RepoURL),
+	// A multi-source Application references a repository for each of its sources
+	sourceRepoURLs := []string{sharedloop.NormalizeGitURL(specFieldAppFromDB.Spec.Source.RepoURL)}
+	if len(specFieldAppFromDB.Spec.Sources) > 0 {
+		sourceRepoURLs = []string{}
+		for _, source := range specFieldAppFromDB.Spec.Sources {
+			repoURL := sharedloop.NormalizeGitURL(source.RepoURL)
+			if !slicesContainsString(sourceRepoURLs, repoURL) {
+				sourceRepoURLs = append(sourceRepoURLs, repoURL)
+			}
+		}
 	}
 
-	// 5) Remove AppProjectRepository from database as GitopsDeployment is deleted
-	a.log.Info("GitOpsDeployment was deleted, so deleting AppProjectRepository row from database")
-	rowsDeleted, err = dbQueries.DeleteAppProjectRepositoryByClusterUserAndRepoURL(ctx, &appProjectRepoCredDB)
-	if err != nil {
-		// Log the error, but continue
-		log.Error(err, "unable to delete appProject by cluster_user_id and repoURL")
-	} else if rowsDeleted == 0 {
-		// Log the error, but continue
-		log.V(logutil.LogLevel_Warn).Error(nil, "unexpected number of rows deleted for appProject", "rowsDeleted", rowsDeleted)
+	for _, sourceRepoURL := range sourceRepoURLs {
+
+		appProjectRepoCredDB := db.AppProjectRepository{
+			Clusteruser_id: clusterUser.Clusteruser_id,
+			RepoURL:        sourceRepoURL,
+		}
+
+		// 5) Remove AppProjectRepository from database as GitopsDeployment is deleted
+		a.log.Info("GitOpsDeployment was deleted, so deleting AppProjectRepository row from database")
+		rowsDeleted, err = dbQueries.DeleteAppProjectRepositoryByClusterUserAndRepoURL(ctx, &appProjectRepoCredDB)
+		if err != nil {
+			// Log the error, but continue
+			log.Error(err, "unable to delete appProject by cluster_user_id and repoURL")
+		} else if rowsDeleted == 0 {
+			// Log the error, but continue
+			log.V(logutil.LogLevel_Warn).Error(nil, "unexpected number of rows deleted for appProject", "rowsDeleted", rowsDeleted)
+		}
 	}
 
 	gitopsEngineInstance, err := a.sharedResourceEventLoop.GetGitopsEngineInstanceById(ctx, dbApplication.Engine_instance_inst_id, a.workspaceClient, apiNamespace, a.log)
@@ -1070,6 +1184,8 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 		}
 	}
 
+	setSuspendedCondition(gitopsDeployment)
+
 	// Fetch the list of resources created by deployment from table and update local gitopsDeployment instance.
 	var err error
 	gitopsDeployment.Status.Resources, err = decompressResourceData(applicationState.Resources)
@@ -1078,12 +1194,24 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 		return crUpdated_false, err
 	}
 
+	// Summarize the resources that are OutOfSync, so that users can tell why the GitOpsDeployment is OutOfSync
+	setDriftStatus(gitopsDeployment, originalGitOpsDeployment.Status.Drift, metav1.Now())
+
 	gitopsDeployment.Status.OperationState, err = decompressOperationState(applicationState.OperationState)
 	if err != nil {
 		log.Error(err, "unable to decompress operationState byte array received from table.")
 		return crUpdated_false, err
 	}
 
+	// Update the Synced, Healthy, Progressing and Ready conditions, based on the sync/health status and operation state
+	setReadinessConditions(gitopsDeployment)
+
+	// Report the phase and result of the sync operation to the GitOpsDeploymentSyncRun that requested it, if any
+	if err := updateSyncRunStatusFromOperationState(ctx, gitopsDeployment.Status.OperationState, dbQueries, a.workspaceClient, a.eventRecorder); err != nil {
+		// The status of the GitOpsDeployment is still updated: the GitOpsDeploymentSyncRun will be updated on the next tick
+		log.Error(err, "unable to update the status of GitOpsDeploymentSyncRun from the operation state")
+	}
+
 	var comparedTo fauxargocd.FauxComparedTo
 	comparedTo, err = retrieveComparedToFieldInApplicationState(applicationState.ReconciledState)
 	if err != nil {
@@ -1116,6 +1244,37 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 	gitopsDeployment.Status.ReconciledState.Destination.Name = comparedTo.Destination.Name
 	gitopsDeployment.Status.ReconciledState.Destination.Namespace = comparedTo.Destination.Namespace
 
+	// For multi-source GitOpsDeployments, report the reconciled state and revision of each source
+	var reconciledSources []managedgitopsv1alpha1.GitOpsDeploymentSource
+	for _, source := range comparedTo.Sources {
+		reconciledSources = append(reconciledSources, managedgitopsv1alpha1.GitOpsDeploymentSource{
+			Path:    source.Path,
+			RepoURL: source.RepoURL,
+			Branch:  source.TargetRevision,
+		})
+	}
+	gitopsDeployment.Status.ReconciledState.Sources = reconciledSources
+
+	if len(comparedTo.Revisions) > 0 {
+		gitopsDeployment.Status.Sync.Revisions = comparedTo.Revisions
+	} else {
+		gitopsDeployment.Status.Sync.Revisions = nil
+	}
+
+	// Update gitopsDeployment status with the history of the most recent successful syncs
+	gitopsDeployment.Status.History, err = retrieveDeploymentHistory(ctx, mapping.Application_id, dbQueries)
+	if err != nil {
+		log.Error(err, "unable to retrieve deployment history of Application")
+		return crUpdated_false, err
+	}
+
+	// Report the drifted resources of the GitOpsDeployment, so that long-lived drift can be alerted on
+	if gitopsDeployment.Status.Drift != nil {
+		metrics.SetDriftedResources(gitopsDeployment.Name, gitopsDeployment.Namespace, gitopsDeployment.Status.Drift.DriftedResourceCount)
+	} else {
+		metrics.SetDriftedResources(gitopsDeployment.Name, gitopsDeployment.Namespace, 0)
+	}
+
 	// If nothing has changed in the status field, our work is done.
 	if reflect.DeepEqual(gitopsDeployment.Status, originalGitOpsDeployment.Status) {
 		return crUpdated_false, nil
@@ -1128,6 +1287,12 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 
 	log.V(logutil.LogLevel_Debug).Info("Updated status in deploymentStatusTick")
 
+	// Report the updated sync/health status of the synced revision to the Git provider, if enabled for the repository
+	reportCommitStatus(originalGitOpsDeployment.Status, gitopsDeployment, a.workspaceClient, log)
+
+	// Record Kubernetes Events for the sync operation and health transitions, so that they are visible via 'kubectl describe'
+	recordDeploymentLifecycleEvents(a.eventRecorder, originalGitOpsDeployment.Status, gitopsDeployment)
+
 	// NOTE: make sure to preserve the existing conditions fields that are in the status field of the CR, when updating the status!
 
 	return crUpdated_true, nil
@@ -1173,6 +1338,226 @@ func getMatchingGitOpsDeployment(ctx context.Context, name, namespace string, k8
 	return gitopsDepl, nil
 }
 
+// setSuspendedCondition sets the Suspended condition to true while the GitOpsDeployment is suspended, and to false once
+// it has been resumed. The condition is only modified when its status changes, so that the GitOpsDeployment is not
+// needlessly updated on every status tick.
+func setSuspendedCondition(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment) {
+
+	conditionManager := condition.NewConditionManager()
+	conditions := &gitopsDeployment.Status.Conditions
+
+	if gitopsDeployment.Spec.Suspend {
+		if conditionManager.HasCondition(conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionSuspended) {
+			if cond, _ := conditionManager.FindCondition(conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionSuspended); cond.Status == managedgitopsv1alpha1.GitOpsConditionStatusTrue {
+				return
+			}
+		}
+		conditionManager.SetCondition(conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionSuspended, managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			managedgitopsv1alpha1.GitopsDeploymentReasonSuspended, "GitOpsDeployment is suspended: automated sync is disabled, and GitOpsDeploymentSyncRuns are not processed")
+
+	} else if conditionManager.HasCondition(conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionSuspended) {
+		if cond, _ := conditionManager.FindCondition(conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionSuspended); cond.Status != managedgitopsv1alpha1.GitOpsConditionStatusFalse {
+			conditionManager.SetCondition(conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionSuspended, managedgitopsv1alpha1.GitOpsConditionStatusFalse,
+				managedgitopsv1alpha1.GitopsDeploymentReasonResumed, "GitOpsDeployment has been resumed")
+		}
+	}
+}
+
+// setReadinessConditions sets the Synced, Healthy, Progressing and Ready conditions of the GitOpsDeployment, based on
+// the sync status, health status and operation state that were retrieved from the ApplicationState. These allow users
+// to wait for a GitOpsDeployment to be deployed, for example via 'kubectl wait --for=condition=Ready'.
+func setReadinessConditions(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment) {
+
+	status := &gitopsDeployment.Status
+
+	// Synced
+	switch status.Sync.Status {
+	case managedgitopsv1alpha1.SyncStatusCodeSynced:
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionSynced, managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			managedgitopsv1alpha1.GitopsDeploymentReasonSynced, "the resources on the target cluster match the GitOps repository")
+	case managedgitopsv1alpha1.SyncStatusCodeOutOfSync:
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionSynced, managedgitopsv1alpha1.GitOpsConditionStatusFalse,
+			managedgitopsv1alpha1.GitopsDeploymentReasonOutOfSync, "the resources on the target cluster do not match the GitOps repository")
+	default:
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionSynced, managedgitopsv1alpha1.GitOpsConditionStatusUnknown,
+			managedgitopsv1alpha1.GitopsDeploymentReasonSyncStatusUnknown, "the sync status of the GitOpsDeployment is not yet known")
+	}
+
+	// Healthy
+	switch status.Health.Status {
+	case managedgitopsv1alpha1.HeathStatusCodeHealthy:
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy, managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			managedgitopsv1alpha1.GitopsDeploymentReasonHealthy, "the deployed resources are healthy")
+	case "", managedgitopsv1alpha1.HeathStatusCodeUnknown:
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy, managedgitopsv1alpha1.GitOpsConditionStatusUnknown,
+			managedgitopsv1alpha1.GitopsDeploymentReasonHealthUnknown, "the health of the deployed resources is not yet known")
+	default:
+		message := fmt.Sprintf("the health status of the deployed resources is '%s'", status.Health.Status)
+		if status.Health.Message != "" {
+			message += ": " + status.Health.Message
+		}
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy, managedgitopsv1alpha1.GitOpsConditionStatusFalse,
+			managedgitopsv1alpha1.GitopsDeploymentReasonUnhealthy, message)
+	}
+
+	// Progressing
+	if status.OperationState != nil && status.OperationState.Phase == managedgitopsv1alpha1.OperationRunning {
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionProgressing, managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			managedgitopsv1alpha1.GitopsDeploymentReasonSyncRunning, "a sync operation is running")
+	} else if status.Health.Status == managedgitopsv1alpha1.HeathStatusCodeProgressing {
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionProgressing, managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			managedgitopsv1alpha1.GitopsDeploymentReasonProgressing, "the deployed resources are progressing towards a healthy state")
+	} else {
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionProgressing, managedgitopsv1alpha1.GitOpsConditionStatusFalse,
+			managedgitopsv1alpha1.GitopsDeploymentReasonIdle, "no sync operation is running, and the deployed resources are not progressing")
+	}
+
+	// Ready: the GitOpsDeployment is ready if it is synced and healthy, is not progressing, and has no errors
+	notReadyMessage := ""
+	if isConditionTrue(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionErrorOccurred) {
+		notReadyMessage = "an error occurred while processing the GitOpsDeployment"
+	} else if isConditionTrue(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionSyncError) {
+		notReadyMessage = "an error occurred while syncing the GitOpsDeployment"
+	} else if status.Sync.Status != managedgitopsv1alpha1.SyncStatusCodeSynced {
+		notReadyMessage = "the GitOpsDeployment is not synced"
+	} else if status.Health.Status != managedgitopsv1alpha1.HeathStatusCodeHealthy {
+		notReadyMessage = "the GitOpsDeployment is not healthy"
+	} else if isConditionTrue(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionProgressing) {
+		notReadyMessage = "the GitOpsDeployment is progressing"
+	}
+
+	if notReadyMessage == "" {
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionReady, managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			managedgitopsv1alpha1.GitopsDeploymentReasonReady, "the GitOpsDeployment is synced and healthy")
+	} else {
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionReady, managedgitopsv1alpha1.GitOpsConditionStatusFalse,
+			managedgitopsv1alpha1.GitopsDeploymentReasonNotReady, notReadyMessage)
+	}
+}
+
+// setDriftStatus sets .status.drift and the DriftDetected condition of the GitOpsDeployment, based on the resources in
+// .status.resources that are OutOfSync. The time at which drift was first detected is preserved from the previous
+// status, until none of the resources are OutOfSync.
+func setDriftStatus(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, previousDrift *managedgitopsv1alpha1.DriftStatus, now metav1.Time) {
+
+	var drifted []managedgitopsv1alpha1.DriftedResource
+	for _, resource := range gitopsDeployment.Status.Resources {
+		if resource.Status != managedgitopsv1alpha1.SyncStatusCodeOutOfSync {
+			continue
+		}
+		drifted = append(drifted, managedgitopsv1alpha1.DriftedResource{
+			Group:     resource.Group,
+			Kind:      resource.Kind,
+			Namespace: resource.Namespace,
+			Name:      resource.Name,
+		})
+	}
+
+	if len(drifted) == 0 {
+		gitopsDeployment.Status.Drift = nil
+		setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionDriftDetected, managedgitopsv1alpha1.GitOpsConditionStatusFalse,
+			managedgitopsv1alpha1.GitopsDeploymentReasonNoDrift, "the resources on the target cluster match the GitOps repository")
+		return
+	}
+
+	sort.Slice(drifted, func(i, j int) bool {
+		a, b := drifted[i], drifted[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	// Count the drifted resources of each kind: as the resources are sorted by group and kind, resources of the same
+	// kind are adjacent.
+	var kinds []managedgitopsv1alpha1.DriftKindCount
+	for _, resource := range drifted {
+		if len(kinds) > 0 && kinds[len(kinds)-1].Group == resource.Group && kinds[len(kinds)-1].Kind == resource.Kind {
+			kinds[len(kinds)-1].Count++
+			continue
+		}
+		kinds = append(kinds, managedgitopsv1alpha1.DriftKindCount{Group: resource.Group, Kind: resource.Kind, Count: 1})
+	}
+
+	detectedAt := now
+	if previousDrift != nil && !previousDrift.DetectedAt.IsZero() {
+		detectedAt = previousDrift.DetectedAt
+	}
+
+	driftedResourceCount := len(drifted)
+	if len(drifted) > managedgitopsv1alpha1.MaxDriftedResources {
+		drifted = drifted[:managedgitopsv1alpha1.MaxDriftedResources]
+	}
+
+	gitopsDeployment.Status.Drift = &managedgitopsv1alpha1.DriftStatus{
+		DriftedResourceCount: driftedResourceCount,
+		Kinds:                kinds,
+		Resources:            drifted,
+		DetectedAt:           detectedAt,
+	}
+
+	setConditionIfChanged(gitopsDeployment, managedgitopsv1alpha1.GitOpsDeploymentConditionDriftDetected, managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+		managedgitopsv1alpha1.GitopsDeploymentReasonDriftDetected,
+		fmt.Sprintf("%d resource(s) on the target cluster do not match the GitOps repository: see .status.drift for details", driftedResourceCount))
+}
+
+// setConditionIfChanged sets a condition of the GitOpsDeployment, but only if its status, reason, message or observed
+// generation have changed, so that the GitOpsDeployment is not needlessly updated on every status tick. The
+// lastTransitionTime of the condition is only updated when its status changes.
+func setConditionIfChanged(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, conditionType managedgitopsv1alpha1.GitOpsDeploymentConditionType,
+	status managedgitopsv1alpha1.GitOpsConditionStatus, reason managedgitopsv1alpha1.GitOpsDeploymentReasonType, message string) {
+
+	conditionManager := condition.NewConditionManager()
+	conditions := &gitopsDeployment.Status.Conditions
+	observedGeneration := gitopsDeployment.Status.ObservedGeneration
+
+	var previous *managedgitopsv1alpha1.GitOpsDeploymentCondition
+	if conditionManager.HasCondition(conditions, conditionType) {
+		cond, _ := conditionManager.FindCondition(conditions, conditionType)
+		if cond.Status == status && cond.Reason == reason && cond.Message == message && cond.ObservedGeneration == observedGeneration {
+			return
+		}
+		previous = cond.DeepCopy()
+	}
+
+	conditionManager.SetCondition(conditions, conditionType, status, reason, message)
+
+	cond, _ := conditionManager.FindCondition(conditions, conditionType)
+	cond.ObservedGeneration = observedGeneration
+	if previous != nil && previous.Status == status {
+		cond.LastTransitionTime = previous.LastTransitionTime
+	}
+}
+
+// isConditionTrue returns true if the GitOpsDeployment has a condition of the given type, with a status of true.
+func isConditionTrue(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, conditionType managedgitopsv1alpha1.GitOpsDeploymentConditionType) bool {
+	for _, cond := range gitopsDeployment.Status.Conditions {
+		if cond.Type == conditionType {
+			return cond.Status == managedgitopsv1alpha1.GitOpsConditionStatusTrue
+		}
+	}
+	return false
+}
+
+// setObservedGeneration sets .status.observedGeneration to the generation of the GitOpsDeployment, once its spec has
+// been successfully processed.
+func (g *gitOpsDeploymentAdapter) setObservedGeneration() error {
+
+	if g.gitOpsDeployment.Status.ObservedGeneration == g.gitOpsDeployment.Generation {
+		return nil
+	}
+
+	g.gitOpsDeployment.Status.ObservedGeneration = g.gitOpsDeployment.Generation
+
+	return g.client.Status().Update(g.ctx, g.gitOpsDeployment, &client.UpdateOptions{})
+}
+
 // setGitOpsDeploymentCondition calls SetCondition() with GitOpsDeployment conditions
 func (g *gitOpsDeploymentAdapter) setGitOpsDeploymentCondition(conditionType managedgitopsv1alpha1.GitOpsDeploymentConditionType,
 	reason managedgitopsv1alpha1.GitOpsDeploymentReasonType, errMessage gitopserrors.UserError) error {
@@ -1212,29 +1597,183 @@ func (g *gitOpsDeploymentAdapter) setGitOpsDeploymentCondition(conditionType man
 	return nil
 }
 
-func checkValidSyncOption(syncOptions []managedgitopsv1alpha1.SyncOption) gitopserrors.UserError {
+// checkValidGitOpsDeploymentSources returns a user error if the source (or sources, for a multi-source GitOpsDeployment)
+// of the GitOpsDeployment are invalid.
+func checkValidGitOpsDeploymentSources(spec managedgitopsv1alpha1.GitOpsDeploymentSpec) gitopserrors.UserError {
+
+	if len(spec.Sources) == 0 {
+		if spec.Source.Ref != "" {
+			userError := managedgitopsv1alpha1.GitOpsDeploymentUserError_RefOutsideSources
+			return gitopserrors.NewUserDevError(userError, fmt.Errorf(userError))
+		}
+		return checkValidApplicationSource(spec.Source, false)
+	}
+
+	if !reflect.DeepEqual(spec.Source, managedgitopsv1alpha1.ApplicationSource{}) {
+		userError := managedgitopsv1alpha1.GitOpsDeploymentUserError_SourceAndSourcesSet
+		return gitopserrors.NewUserDevError(userError, fmt.Errorf(userError))
+	}
+
+	for _, source := range spec.Sources {
+		if userErr := checkValidApplicationSource(source, true); userErr != nil {
+			return userErr
+		}
+	}
+
+	if err := managedgitopsv1alpha1.ValidateApplicationSources(spec.Sources); err != nil {
+		return gitopserrors.NewUserDevError(err.Error(), fmt.Errorf("invalid sources: %v", err))
+	}
+
+	return nil
+}
+
+// checkValidApplicationSource returns a user error if a source of the GitOpsDeployment is invalid.
+// Within a multi-source GitOpsDeployment, a source that defines a 'ref' (for example, a repository containing
+// only Helm values files) does not require a path.
+func checkValidApplicationSource(source managedgitopsv1alpha1.ApplicationSource, multiSource bool) gitopserrors.UserError {
+
+	// Applications sourced from a Helm repository reference a chart, rather than a path
+	if source.Chart != "" && source.Path != "" {
+		userError := managedgitopsv1alpha1.GitOpsDeploymentUserError_PathAndChartSet
+		return gitopserrors.NewUserDevError(userError, fmt.Errorf(userError))
+	}
+
+	// The path is validated by the same function as the GitOpsDeployment validating webhook
+	if err := managedgitopsv1alpha1.ValidateApplicationSourcePath(source, multiSource); err != nil {
+		return gitopserrors.NewUserDevError(err.Error(), err)
+	}
+
+	if userErr := checkValidHelmSource(source.Helm); userErr != nil {
+		return userErr
+	}
 
-	for _, syncOptionString := range syncOptions {
+	if userErr := checkValidKustomizeSource(source); userErr != nil {
+		return userErr
+	}
 
-		match := true
+	return nil
+}
 
-		// Check for SyncOption string
-		if !(syncOptionString == managedgitopsv1alpha1.SyncOptions_CreateNamespace_true ||
-			syncOptionString == managedgitopsv1alpha1.SyncOptions_CreateNamespace_false) {
-			match = false
+// getSourceRepoURLs returns the normalized URLs of the repositories referenced by the source (or sources) of a GitOpsDeployment,
+// without duplicates.
+func getSourceRepoURLs(spec managedgitopsv1alpha1.GitOpsDeploymentSpec) []string {
+
+	sources := spec.Sources
+	if len(sources) == 0 {
+		sources = managedgitopsv1alpha1.ApplicationSources{spec.Source}
+	}
+
+	res := []string{}
+	for _, source := range sources {
+		repoURL := sharedloop.NormalizeGitURL(source.RepoURL)
+		if !slicesContainsString(res, repoURL) {
+			res = append(res, repoURL)
 		}
+	}
 
-		// If at least one of the options don't match, return a user  error
-		if !match {
-			userError := "the specified sync option in .spec.syncPolicy.syncOptions is either mispelled or is not supported by GitOpsDeployment"
-			devError := fmt.Errorf("invalid SyncOption : %s", syncOptionString)
+	return res
+}
 
-			return gitopserrors.NewUserDevError(userError, devError)
+func slicesContainsString(slice []string, str string) bool {
+	for _, s := range slice {
+		if s == str {
+			return true
 		}
 	}
+	return false
+}
+
+// checkValidHelmSource returns a user error if the Helm options of the GitOpsDeployment source are invalid.
+func checkValidHelmSource(helm *managedgitopsv1alpha1.ApplicationSourceHelm) gitopserrors.UserError {
+
+	if err := managedgitopsv1alpha1.ValidateApplicationSourceHelm(helm); err != nil {
+		return gitopserrors.NewUserDevError(err.Error(), fmt.Errorf("invalid Helm source: %v", err))
+	}
+
+	return nil
+}
+
+// checkValidKustomizeSource returns a user error if the Kustomize options of the GitOpsDeployment source are invalid.
+func checkValidKustomizeSource(source managedgitopsv1alpha1.ApplicationSource) gitopserrors.UserError {
+
+	if source.Helm != nil && source.Kustomize != nil {
+		userError := managedgitopsv1alpha1.GitOpsDeploymentUserError_HelmAndKustomizeSet
+		return gitopserrors.NewUserDevError(userError, fmt.Errorf(userError))
+	}
+
+	if err := managedgitopsv1alpha1.ValidateApplicationSourceKustomize(source.Kustomize); err != nil {
+		return gitopserrors.NewUserDevError(err.Error(), fmt.Errorf("invalid Kustomize source: %v", err))
+	}
+
+	return nil
+}
+
+// checkValidIgnoreDifferences returns a user error if any of the ignoreDifferences entries of the GitOpsDeployment is invalid.
+func checkValidIgnoreDifferences(ignoreDifferences []managedgitopsv1alpha1.ResourceIgnoreDifferences) gitopserrors.UserError {
+
+	if err := managedgitopsv1alpha1.ValidateIgnoreDifferences(ignoreDifferences); err != nil {
+		return gitopserrors.NewUserDevError(err.Error(), fmt.Errorf("invalid ignoreDifferences: %v", err))
+	}
+
+	return nil
+}
+
+// checkValidDeletionPolicy returns a user error if the deletion policy of the GitOpsDeployment is not supported.
+func checkValidDeletionPolicy(deletionPolicy string) gitopserrors.UserError {
+
+	if err := managedgitopsv1alpha1.ValidateDeletionPolicy(deletionPolicy); err != nil {
+		return gitopserrors.NewUserDevError(err.Error(), fmt.Errorf("invalid deletion policy: %v", err))
+	}
+
 	return nil
 }
 
+// checkValidSyncPolicy returns a user error if the automated sync policy or retry strategy of the GitOpsDeployment are invalid.
+func checkValidSyncPolicy(spec managedgitopsv1alpha1.GitOpsDeploymentSpec) gitopserrors.UserError {
+
+	if err := managedgitopsv1alpha1.ValidateSyncPolicy(spec); err != nil {
+		return gitopserrors.NewUserDevError(err.Error(), fmt.Errorf("invalid sync policy: %v", err))
+	}
+
+	return nil
+}
+
+func checkValidSyncOption(syncOptions []managedgitopsv1alpha1.SyncOption) gitopserrors.UserError {
+
+	if err := managedgitopsv1alpha1.ValidateSyncOptions(syncOptions); err != nil {
+		return gitopserrors.NewUserDevError(err.Error(), fmt.Errorf("invalid SyncOptions: %v: %v", syncOptions, err))
+	}
+
+	return nil
+}
+
+// mergeSyncOptions combines the sync options that the GitOps Service sets by default (for example, the prune
+// propagation policy of automated GitOpsDeployments) with the sync options specified by the user:
+// a user sync option replaces any default sync option with the same name, and duplicates are removed.
+// The default sync options come first, followed by the user sync options in the order they were specified.
+func mergeSyncOptions(defaultSyncOptions []string, userSyncOptions []string) []string {
+
+	userKeys := map[string]bool{}
+	for _, userSyncOption := range userSyncOptions {
+		userKeys[managedgitopsv1alpha1.SyncOption(userSyncOption).Key()] = true
+	}
+
+	res := []string{}
+	for _, defaultSyncOption := range defaultSyncOptions {
+		if !userKeys[managedgitopsv1alpha1.SyncOption(defaultSyncOption).Key()] {
+			res = append(res, defaultSyncOption)
+		}
+	}
+
+	for _, userSyncOption := range userSyncOptions {
+		if !slicesContainsString(res, userSyncOption) {
+			res = append(res, userSyncOption)
+		}
+	}
+
+	return res
+}
+
 type argoCDSpecInput struct {
 	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 	crName      string
@@ -1246,11 +1785,25 @@ type argoCDSpecInput struct {
 	sourceRepoURL        string
 	sourcePath           string
 	sourceTargetRevision string
-	syncOptions          []string
+	sourceChart          string
+	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
+	sourceHelm      *managedgitopsv1alpha1.ApplicationSourceHelm
+	sourceKustomize *managedgitopsv1alpha1.ApplicationSourceKustomize
+	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
+	// sources is only set for multi-source GitOpsDeployments, in which case the source* fields above are empty
+	sources []managedgitopsv1alpha1.ApplicationSource
+	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
+	ignoreDifferences []managedgitopsv1alpha1.ResourceIgnoreDifferences
+	syncOptions       []string
 	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 	automated bool
+	// automatedSyncPolicy and retry are only used when automated is true: if nil, the defaults are used.
+	automatedSyncPolicy *managedgitopsv1alpha1.SyncPolicyAutomated
+	retry               *managedgitopsv1alpha1.RetryStrategy
 	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 	project string
+	// deletionPolicy is recorded as an annotation of the Argo CD Application, for use by the cluster-agent on deletion
+	deletionPolicy string
 
 	// Hopefully you are getting the message, here :)
 }
@@ -1278,6 +1831,147 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 		return res
 	}
 
+	// Helm values and parameter values are passed to Helm as-is, and so may legitimately contain the characters
+	// removed by sanitize(...): instead, the values block is re-marshalled, and line breaks are removed from parameter values.
+	sanitizeHelm := func(input *managedgitopsv1alpha1.ApplicationSourceHelm) (*fauxargocd.ApplicationSourceHelm, error) {
+		if input == nil {
+			return nil, nil
+		}
+
+		res := &fauxargocd.ApplicationSourceHelm{
+			ReleaseName: sanitize(input.ReleaseName),
+		}
+
+		if len(input.ValueFiles) > 0 {
+			res.ValueFiles = sanitizeArray(input.ValueFiles)
+		}
+
+		for _, param := range input.Parameters {
+			res.Parameters = append(res.Parameters, fauxargocd.HelmParameter{
+				Name:        sanitize(param.Name),
+				Value:       strings.NewReplacer("\r", "", "\n", "").Replace(param.Value),
+				ForceString: param.ForceString,
+			})
+		}
+
+		if input.Values != "" {
+			values := goyaml.MapSlice{}
+			if err := goyaml.Unmarshal([]byte(input.Values), &values); err != nil {
+				return nil, fmt.Errorf("unable to unmarshal Helm values: %v", err)
+			}
+
+			valuesBytes, err := goyaml.Marshal(values)
+			if err != nil {
+				return nil, fmt.Errorf("unable to marshal Helm values: %v", err)
+			}
+			res.Values = string(valuesBytes)
+		}
+
+		return res, nil
+	}
+
+	sanitizedHelm, err := sanitizeHelm(fieldsParam.sourceHelm)
+	if err != nil {
+		return "", err
+	}
+
+	// Kustomize annotation values may legitimately contain the characters removed by sanitize(...), so
+	// only line breaks are removed from them.
+	sanitizeKustomize := func(input *managedgitopsv1alpha1.ApplicationSourceKustomize) *fauxargocd.ApplicationSourceKustomize {
+		if input == nil {
+			return nil
+		}
+
+		res := &fauxargocd.ApplicationSourceKustomize{
+			NamePrefix: sanitize(input.NamePrefix),
+			NameSuffix: sanitize(input.NameSuffix),
+			Version:    sanitize(input.Version),
+		}
+
+		if len(input.Images) > 0 {
+			res.Images = sanitizeArray(input.Images)
+		}
+
+		if len(input.CommonLabels) > 0 {
+			res.CommonLabels = map[string]string{}
+			for key, value := range input.CommonLabels {
+				res.CommonLabels[sanitize(key)] = sanitize(value)
+			}
+		}
+
+		if len(input.CommonAnnotations) > 0 {
+			res.CommonAnnotations = map[string]string{}
+			for key, value := range input.CommonAnnotations {
+				res.CommonAnnotations[sanitize(key)] = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+			}
+		}
+
+		return res
+	}
+
+	sanitizedKustomize := sanitizeKustomize(fieldsParam.sourceKustomize)
+
+	var sanitizedSources fauxargocd.ApplicationSources
+	for _, source := range fieldsParam.sources {
+
+		helm, err := sanitizeHelm(source.Helm)
+		if err != nil {
+			return "", err
+		}
+
+		sanitizedSources = append(sanitizedSources, fauxargocd.ApplicationSource{
+			RepoURL:        sanitize(source.RepoURL),
+			Path:           sanitize(source.Path),
+			TargetRevision: sanitize(source.TargetRevision),
+			Chart:          sanitize(source.Chart),
+			Helm:           helm,
+			Kustomize:      sanitizeKustomize(source.Kustomize),
+			Ref:            sanitize(source.Ref),
+		})
+	}
+
+	// JQ path expressions may legitimately contain the characters removed by sanitize(...) (for example, quotes
+	// within a 'select(...)'): as with Helm values, only line breaks are removed from them.
+	var sanitizedIgnoreDifferences []fauxargocd.ResourceIgnoreDifferences
+	for _, ignoreDifference := range fieldsParam.ignoreDifferences {
+
+		res := fauxargocd.ResourceIgnoreDifferences{
+			Group:     sanitize(ignoreDifference.Group),
+			Kind:      sanitize(ignoreDifference.Kind),
+			Name:      sanitize(ignoreDifference.Name),
+			Namespace: sanitize(ignoreDifference.Namespace),
+		}
+		if len(ignoreDifference.JSONPointers) > 0 {
+			res.JSONPointers = sanitizeArray(ignoreDifference.JSONPointers)
+		}
+		for _, jqPathExpression := range ignoreDifference.JQPathExpressions {
+			res.JQPathExpressions = append(res.JQPathExpressions, strings.NewReplacer("\r", "", "\n", "").Replace(jqPathExpression))
+		}
+		if len(ignoreDifference.ManagedFieldsManagers) > 0 {
+			res.ManagedFieldsManagers = sanitizeArray(ignoreDifference.ManagedFieldsManagers)
+		}
+
+		sanitizedIgnoreDifferences = append(sanitizedIgnoreDifferences, res)
+	}
+
+	var sanitizedRetry *fauxargocd.RetryStrategy
+	if fieldsParam.retry != nil {
+		// An unset limit retries indefinitely, as when no retry strategy is specified
+		sanitizedRetry = &fauxargocd.RetryStrategy{
+			Limit: -1,
+		}
+		if fieldsParam.retry.Limit != nil {
+			sanitizedRetry.Limit = *fieldsParam.retry.Limit
+		}
+		if backoff := fieldsParam.retry.Backoff; backoff != nil {
+			sanitizedRetry.Backoff = &fauxargocd.Backoff{
+				Duration:    sanitize(backoff.Duration),
+				Factor:      backoff.Factor,
+				MaxDuration: sanitize(backoff.MaxDuration),
+			}
+		}
+	}
+
 	fields := argoCDSpecInput{
 		// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 		crName:               sanitize(fieldsParam.crName),
@@ -1288,9 +1982,17 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 		sourceRepoURL:        sanitize(fieldsParam.sourceRepoURL),
 		sourcePath:           sanitize(fieldsParam.sourcePath),
 		sourceTargetRevision: sanitize(fieldsParam.sourceTargetRevision),
-		syncOptions:          sanitizeArray(fieldsParam.syncOptions),
-		automated:            fieldsParam.automated,
-		project:              sanitize(fieldsParam.project),
+		sourceChart:          sanitize(fieldsParam.sourceChart),
+		// sourceHelm:        sanitized above, see 'sanitizedHelm'
+		// sourceKustomize:   sanitized above, see 'sanitizedKustomize'
+		// sources:           sanitized above, see 'sanitizedSources'
+		// ignoreDifferences: sanitized above, see 'sanitizedIgnoreDifferences'
+		syncOptions:         sanitizeArray(fieldsParam.syncOptions),
+		automated:           fieldsParam.automated,
+		automatedSyncPolicy: fieldsParam.automatedSyncPolicy,
+		// retry:             sanitized below, see 'sanitizedRetry'
+		project:        sanitize(fieldsParam.project),
+		deletionPolicy: sanitize(fieldsParam.deletionPolicy),
 		// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 		// Hopefully you are getting the message, here :)
 	}
@@ -1309,15 +2011,32 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 				RepoURL:        fields.sourceRepoURL,
 				Path:           fields.sourcePath,
 				TargetRevision: fields.sourceTargetRevision,
+				Chart:          fields.sourceChart,
+				Helm:           sanitizedHelm,
+				Kustomize:      sanitizedKustomize,
 			},
 			Destination: fauxargocd.ApplicationDestination{
 				Name:      fields.destinationName,
 				Namespace: fields.destinationNamespace,
 			},
-			Project: fields.project,
+			Project:           fields.project,
+			IgnoreDifferences: sanitizedIgnoreDifferences,
 		},
 	}
 
+	// The resources of the Application are left in place on deletion, if the GitOpsDeployment asks for it
+	if managedgitopsv1alpha1.IsOrphanDeletionPolicy(fields.deletionPolicy) {
+		application.FauxObjectMeta.Annotations = map[string]string{
+			argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation: fields.deletionPolicy,
+		}
+	}
+
+	// A multi-source Application uses only the sources field
+	if len(sanitizedSources) > 0 {
+		application.Spec.Source = fauxargocd.ApplicationSource{}
+		application.Spec.Sources = sanitizedSources
+	}
+
 	if fields.automated {
 		application.Spec.SyncPolicy = &fauxargocd.SyncPolicy{
 			Automated: &fauxargocd.SyncPolicyAutomated{
@@ -1338,6 +2057,23 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 			},
 		}
 
+		// Fields of the automated sync policy that are not specified by the user keep their default value (true)
+		if automated := fields.automatedSyncPolicy; automated != nil {
+			if automated.Prune != nil {
+				application.Spec.SyncPolicy.Automated.Prune = *automated.Prune
+			}
+			if automated.SelfHeal != nil {
+				application.Spec.SyncPolicy.Automated.SelfHeal = *automated.SelfHeal
+			}
+			if automated.AllowEmpty != nil {
+				application.Spec.SyncPolicy.Automated.AllowEmpty = *automated.AllowEmpty
+			}
+		}
+
+		if sanitizedRetry != nil {
+			application.Spec.SyncPolicy.Retry = sanitizedRetry
+		}
+
 	} else {
 		// !fields.automated
 		application.Spec.SyncPolicy = nil
@@ -1349,14 +2085,7 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 			application.Spec.SyncPolicy = &fauxargocd.SyncPolicy{}
 		}
 
-		if application.Spec.SyncPolicy.SyncOptions == nil {
-			application.Spec.SyncPolicy.SyncOptions = fauxargocd.SyncOptions{}
-		}
-
-		for _, syncOptionString := range fields.syncOptions {
-			application.Spec.SyncPolicy.SyncOptions = append(application.Spec.SyncPolicy.SyncOptions,
-				syncOptionString)
-		}
+		application.Spec.SyncPolicy.SyncOptions = mergeSyncOptions(application.Spec.SyncPolicy.SyncOptions, fields.syncOptions)
 	}
 
 	resBytes, err := goyaml.Marshal(application)
@@ -1407,6 +2136,58 @@ func decompressOperationState(operationStateBytes []byte) (*managedgitopsv1alpha
 	return operationState, nil
 }
 
+// retrieveDeploymentHistory converts the DeploymentHistory rows of an Application into the .status.history field of
+// a GitOpsDeployment, ordered from the most recent sync to the oldest.
+func retrieveDeploymentHistory(ctx context.Context, applicationID string, dbQueries db.ApplicationScopedQueries) ([]managedgitopsv1alpha1.RevisionHistory, error) {
+
+	var deploymentHistory []db.DeploymentHistory
+	if err := dbQueries.ListDeploymentHistoryByApplicationId(ctx, applicationID, &deploymentHistory); err != nil {
+		return nil, err
+	}
+
+	var res []managedgitopsv1alpha1.RevisionHistory
+
+	for _, entry := range deploymentHistory {
+
+		deployedSource := fauxargocd.FauxDeploymentHistorySource{}
+		if err := json.Unmarshal([]byte(entry.Source), &deployedSource); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal source of deployment history '%s': %v", entry.Deploymenthistory_id, err)
+		}
+
+		revisionHistory := managedgitopsv1alpha1.RevisionHistory{
+			ID: entry.History_id,
+			Source: managedgitopsv1alpha1.GitOpsDeploymentSource{
+				Path:    deployedSource.Source.Path,
+				RepoURL: deployedSource.Source.RepoURL,
+				Branch:  deployedSource.Source.TargetRevision,
+			},
+			// The time is converted to the same form that it has after being read from the GitOpsDeployment, so that
+			// an unchanged history is not seen as a change to the status field.
+			DeployedAt:  metav1.NewTime(time.Unix(entry.Deployed_at.Unix(), 0)),
+			InitiatedBy: entry.Initiated_by,
+			SyncRunName: entry.Sync_run_name,
+		}
+
+		// For multi-source Applications, the revision field contains the revision of each source
+		if len(deployedSource.Sources) > 0 {
+			revisionHistory.Revisions = strings.Split(entry.Revision, ",")
+			for _, source := range deployedSource.Sources {
+				revisionHistory.Sources = append(revisionHistory.Sources, managedgitopsv1alpha1.GitOpsDeploymentSource{
+					Path:    source.Path,
+					RepoURL: source.RepoURL,
+					Branch:  source.TargetRevision,
+				})
+			}
+		} else {
+			revisionHistory.Revision = entry.Revision
+		}
+
+		res = append(res, revisionHistory)
+	}
+
+	return res, nil
+}
+
 func retrieveComparedToFieldInApplicationState(reconciledState string) (fauxargocd.FauxComparedTo, error) {
 	comparedTo := fauxargocd.FauxComparedTo{}
 
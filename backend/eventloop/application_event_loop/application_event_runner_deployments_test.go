@@ -11,6 +11,7 @@ import (
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
@@ -113,12 +114,781 @@ var _ = Describe("Application Event Runner Deployments", func() {
 			Expect(application).To(Equal(getValidApplication(false)))
 		})
 
+		It("Input spec with an orphan deletion policy should set the deletion policy annotation", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.deletionPolicy = managedgitopsv1alpha1.GitOpsDeploymentDeletionPolicy_Orphan
+
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			var appArgo fauxargocd.FauxApplication
+			err = yaml.Unmarshal([]byte(application), &appArgo)
+			Expect(err).To(BeNil())
+			Expect(appArgo.FauxObjectMeta.Annotations).To(Equal(map[string]string{
+				argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation: managedgitopsv1alpha1.GitOpsDeploymentDeletionPolicy_Orphan,
+			}))
+
+			By("verifying that the default deletion policy does not change the generated Application")
+			input.deletionPolicy = managedgitopsv1alpha1.GitOpsDeploymentDeletionPolicy_Delete
+			application, err = createSpecField(input)
+			Expect(err).To(BeNil())
+			Expect(application).To(Equal(getValidApplication(false)))
+		})
+
 		It("Input spec with automated enabled should set automated sync policy", func() {
 			input := getFakeArgoCDSpecInput(true, false)
 			application, err := createSpecField(input)
 			Expect(err).To(BeNil())
 			Expect(application).To(Equal(getValidApplication(true)))
 		})
+
+		It("Input spec with a Helm chart source should set the chart and helm fields", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sourcePath = ""
+			input.sourceChart = "my-chart"
+			input.sourceTargetRevision = "1.2.3"
+			input.sourceHelm = &managedgitopsv1alpha1.ApplicationSourceHelm{
+				ValueFiles:  []string{"values-prod.yaml"},
+				ReleaseName: "my-release",
+				Parameters: []managedgitopsv1alpha1.HelmParameter{
+					{Name: "image.tag", Value: "v1&2", ForceString: true},
+				},
+				Values: "replicaCount: 2\nservice:\n  type: ClusterIP\n",
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			fauxApplication := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApplication)).To(Succeed())
+
+			source := fauxApplication.Spec.Source
+			Expect(source.Path).To(BeEmpty())
+			Expect(source.Chart).To(Equal("my-chart"))
+			Expect(source.TargetRevision).To(Equal("1.2.3"))
+			Expect(source.Helm).ToNot(BeNil())
+			Expect(source.Helm.ValueFiles).To(Equal([]string{"values-prod.yaml"}))
+			Expect(source.Helm.ReleaseName).To(Equal("my-release"))
+			Expect(source.Helm.Parameters).To(Equal([]fauxargocd.HelmParameter{
+				{Name: "image.tag", Value: "v1&2", ForceString: true},
+			}), "parameter values should not have their special characters removed")
+			Expect(source.Helm.Values).To(Equal("replicaCount: 2\nservice:\n  type: ClusterIP\n"))
+		})
+
+		It("Sanitize illegal characters from Helm fields", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sourcePath = ""
+			input.sourceChart = "my-chart\n"
+			input.sourceHelm = &managedgitopsv1alpha1.ApplicationSourceHelm{
+				ValueFiles:  []string{"values-`prod`.yaml"},
+				ReleaseName: "my-release;",
+				Parameters: []managedgitopsv1alpha1.HelmParameter{
+					{Name: "image.tag\"", Value: "v1\r\n"},
+				},
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			fauxApplication := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApplication)).To(Succeed())
+
+			source := fauxApplication.Spec.Source
+			Expect(source.Chart).To(Equal("my-chart"))
+			Expect(source.Helm.ValueFiles).To(Equal([]string{"values-prod.yaml"}))
+			Expect(source.Helm.ReleaseName).To(Equal("my-release"))
+			Expect(source.Helm.Parameters).To(Equal([]fauxargocd.HelmParameter{{Name: "image.tag", Value: "v1"}}))
+		})
+
+		It("Input spec without Helm fields should not include them in the generated Application", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+			Expect(application).ToNot(ContainSubstring("helm"))
+			Expect(application).ToNot(ContainSubstring("chart"))
+		})
+
+		It("Input spec with invalid Helm values should return an error", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sourceHelm = &managedgitopsv1alpha1.ApplicationSourceHelm{
+				Values: "not: [valid",
+			}
+			_, err := createSpecField(input)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("Input spec with Kustomize options should set the kustomize field", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sourceKustomize = &managedgitopsv1alpha1.ApplicationSourceKustomize{
+				NamePrefix:        "dev-",
+				NameSuffix:        "-v1",
+				Images:            []string{"quay.io/org/app:v2"},
+				CommonLabels:      map[string]string{"env": "dev"},
+				CommonAnnotations: map[string]string{"note": "owner's & team's"},
+				Version:           "v4.5.7",
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			fauxApplication := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApplication)).To(Succeed())
+
+			Expect(fauxApplication.Spec.Source.Kustomize).To(Equal(&fauxargocd.ApplicationSourceKustomize{
+				NamePrefix:        "dev-",
+				NameSuffix:        "-v1",
+				Images:            []string{"quay.io/org/app:v2"},
+				CommonLabels:      map[string]string{"env": "dev"},
+				CommonAnnotations: map[string]string{"note": "owner's & team's"},
+				Version:           "v4.5.7",
+			}), "annotation values should not have their special characters removed")
+		})
+
+		It("Sanitize illegal characters from Kustomize fields", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sourceKustomize = &managedgitopsv1alpha1.ApplicationSourceKustomize{
+				NamePrefix:        "dev-;",
+				Images:            []string{"quay.io/org/app:v2\""},
+				CommonLabels:      map[string]string{"env`": "dev%"},
+				CommonAnnotations: map[string]string{"note'": "value\r\n"},
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			fauxApplication := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApplication)).To(Succeed())
+
+			kustomize := fauxApplication.Spec.Source.Kustomize
+			Expect(kustomize.NamePrefix).To(Equal("dev-"))
+			Expect(kustomize.Images).To(Equal([]string{"quay.io/org/app:v2"}))
+			Expect(kustomize.CommonLabels).To(Equal(map[string]string{"env": "dev"}))
+			Expect(kustomize.CommonAnnotations).To(Equal(map[string]string{"note": "value"}))
+		})
+
+		It("Input spec without Kustomize fields should not include them in the generated Application", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+			Expect(application).ToNot(ContainSubstring("kustomize"))
+		})
+
+		It("Input spec with multiple sources should set only the sources field", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sourceRepoURL = ""
+			input.sourcePath = ""
+			input.sourceTargetRevision = ""
+			input.sources = []managedgitopsv1alpha1.ApplicationSource{
+				{
+					RepoURL:        "https://charts.example.com",
+					Chart:          "my-chart",
+					TargetRevision: "1.2.3",
+					Helm: &managedgitopsv1alpha1.ApplicationSourceHelm{
+						ValueFiles: []string{"$values/charts/my-chart/values.yaml"},
+					},
+				},
+				{
+					RepoURL:        "https://github.com/redhat-appstudio/managed-gitops;",
+					TargetRevision: "main",
+					Ref:            "values`",
+				},
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+			Expect(application).ToNot(ContainSubstring("source:"))
+
+			fauxApplication := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApplication)).To(Succeed())
+
+			Expect(fauxApplication.Spec.Source).To(Equal(fauxargocd.ApplicationSource{}))
+			Expect(fauxApplication.Spec.Sources).To(Equal(fauxargocd.ApplicationSources{
+				{
+					RepoURL:        "https://charts.example.com",
+					Chart:          "my-chart",
+					TargetRevision: "1.2.3",
+					Helm: &fauxargocd.ApplicationSourceHelm{
+						ValueFiles: []string{"$values/charts/my-chart/values.yaml"},
+					},
+				},
+				{
+					RepoURL:        "https://github.com/redhat-appstudio/managed-gitops",
+					TargetRevision: "main",
+					Ref:            "values",
+				},
+			}))
+		})
+
+		It("Input spec with a single source should not include the sources field", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+			Expect(application).ToNot(ContainSubstring("sources"))
+			Expect(application).ToNot(ContainSubstring("ref:"))
+		})
+
+		It("Input spec with a user-defined automated sync policy and retry strategy should override the defaults", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			prune := false
+			input.automatedSyncPolicy = &managedgitopsv1alpha1.SyncPolicyAutomated{Prune: &prune}
+			input.retry = &managedgitopsv1alpha1.RetryStrategy{
+				Limit: getInt64Pointer(3),
+				Backoff: &managedgitopsv1alpha1.Backoff{
+					Duration:    "10s\n",
+					Factor:      getInt64Pointer(3),
+					MaxDuration: "1m",
+				},
+			}
+
+			specField, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			application := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(specField), &application)).To(Succeed())
+			Expect(application.Spec.SyncPolicy).ToNot(BeNil())
+			Expect(*application.Spec.SyncPolicy.Automated).To(Equal(fauxargocd.SyncPolicyAutomated{
+				Prune:      false,
+				SelfHeal:   true,
+				AllowEmpty: true,
+			}))
+			Expect(*application.Spec.SyncPolicy.Retry).To(Equal(fauxargocd.RetryStrategy{
+				Limit: 3,
+				Backoff: &fauxargocd.Backoff{
+					Duration:    "10s",
+					Factor:      getInt64Pointer(3),
+					MaxDuration: "1m",
+				},
+			}))
+		})
+
+		It("Input spec with a user-defined retry strategy without a limit should retry indefinitely", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			input.retry = &managedgitopsv1alpha1.RetryStrategy{
+				Backoff: &managedgitopsv1alpha1.Backoff{
+					Duration: "10s",
+				},
+			}
+
+			specField, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			application := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(specField), &application)).To(Succeed())
+			Expect(application.Spec.SyncPolicy).ToNot(BeNil())
+			Expect(*application.Spec.SyncPolicy.Retry).To(Equal(fauxargocd.RetryStrategy{
+				Limit: -1,
+				Backoff: &fauxargocd.Backoff{
+					Duration: "10s",
+				},
+			}))
+
+			By("verifying that a limit of 0 disables retries")
+			input.retry.Limit = getInt64Pointer(0)
+			specField, err = createSpecField(input)
+			Expect(err).To(BeNil())
+
+			application = fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(specField), &application)).To(Succeed())
+			Expect(application.Spec.SyncPolicy.Retry.Limit).To(Equal(int64(0)))
+		})
+
+		It("Input spec with ignoreDifferences should set the ignoreDifferences field, preserving JQ path expressions", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.ignoreDifferences = []managedgitopsv1alpha1.ResourceIgnoreDifferences{
+				{
+					Group:             "apps",
+					Kind:              "Deployment",
+					JSONPointers:      []string{"/spec/replicas"},
+					JQPathExpressions: []string{`.spec.template.spec.containers[] | select(.name == "sidecar")` + "\n"},
+				},
+				{
+					Kind:                  "MutatingWebhookConfiguration",
+					Name:                  "my-webhook;",
+					ManagedFieldsManagers: []string{"kube-controller-manager"},
+				},
+			}
+
+			specField, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			application := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(specField), &application)).To(Succeed())
+			Expect(application.Spec.IgnoreDifferences).To(Equal([]fauxargocd.ResourceIgnoreDifferences{
+				{
+					Group:             "apps",
+					Kind:              "Deployment",
+					JSONPointers:      []string{"/spec/replicas"},
+					JQPathExpressions: []string{`.spec.template.spec.containers[] | select(.name == "sidecar")`},
+				},
+				{
+					Kind:                  "MutatingWebhookConfiguration",
+					Name:                  "my-webhook",
+					ManagedFieldsManagers: []string{"kube-controller-manager"},
+				},
+			}))
+		})
+
+		It("Input spec without ignoreDifferences should not include the field in the generated Application", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+			Expect(application).ToNot(ContainSubstring("ignoredifferences"))
+		})
+
+		It("Input spec with a user-defined prune propagation policy should replace the default sync option", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			input.syncOptions = []string{"PrunePropagationPolicy=foreground", "ServerSideApply=true"}
+
+			specField, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			application := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(specField), &application)).To(Succeed())
+			Expect(application.Spec.SyncPolicy.SyncOptions).To(Equal(fauxargocd.SyncOptions{
+				"PrunePropagationPolicy=foreground",
+				"ServerSideApply=true",
+			}))
+		})
+
+		It("Input spec with user-defined sync options should only contain those options when automated is disabled", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.syncOptions = []string{"Validate=false", "PruneLast=true"}
+
+			specField, err := createSpecField(input)
+			Expect(err).To(BeNil())
+
+			application := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(specField), &application)).To(Succeed())
+			Expect(application.Spec.SyncPolicy.Automated).To(BeNil())
+			Expect(application.Spec.SyncPolicy.SyncOptions).To(Equal(fauxargocd.SyncOptions{"Validate=false", "PruneLast=true"}))
+		})
+
+		It("Input spec with a user-defined automated sync policy should be ignored when automated is disabled", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			prune := false
+			input.automatedSyncPolicy = &managedgitopsv1alpha1.SyncPolicyAutomated{Prune: &prune}
+			input.retry = &managedgitopsv1alpha1.RetryStrategy{Limit: getInt64Pointer(3)}
+
+			application, err := createSpecField(input)
+			Expect(err).To(BeNil())
+			Expect(application).To(Equal(getValidApplication(false)))
+		})
+	})
+
+	Context("checkValidGitOpsDeploymentSources should validate the source(s) of a GitOpsDeployment", func() {
+		It("should accept a valid single source", func() {
+			Expect(checkValidGitOpsDeploymentSources(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Source: managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/a/b", Path: "path"},
+			})).To(BeNil())
+		})
+
+		It("should accept valid multiple sources, including a source with only a ref", func() {
+			Expect(checkValidGitOpsDeploymentSources(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Sources: managedgitopsv1alpha1.ApplicationSources{
+					{
+						RepoURL: "https://charts.example.com", Chart: "my-chart",
+						Helm: &managedgitopsv1alpha1.ApplicationSourceHelm{ValueFiles: []string{"$values/values.yaml"}},
+					},
+					{RepoURL: "https://github.com/a/b", Ref: "values"},
+				},
+			})).To(BeNil())
+		})
+
+		It("should return a user error if both source and sources are set", func() {
+			userErr := checkValidGitOpsDeploymentSources(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Source:  managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/a/b", Path: "path"},
+				Sources: managedgitopsv1alpha1.ApplicationSources{{RepoURL: "https://github.com/a/b", Path: "path"}},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_SourceAndSourcesSet))
+		})
+
+		It("should return a user error if a source of multiple sources has neither a path, chart, nor ref", func() {
+			userErr := checkValidGitOpsDeploymentSources(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Sources: managedgitopsv1alpha1.ApplicationSources{{RepoURL: "https://github.com/a/b"}},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_PathIsRequired))
+		})
+
+		It("should return a user error if a Helm value file references an unknown ref", func() {
+			userErr := checkValidGitOpsDeploymentSources(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Sources: managedgitopsv1alpha1.ApplicationSources{
+					{
+						RepoURL: "https://charts.example.com", Chart: "my-chart",
+						Helm: &managedgitopsv1alpha1.ApplicationSourceHelm{ValueFiles: []string{"$other/values.yaml"}},
+					},
+					{RepoURL: "https://github.com/a/b", Ref: "values"},
+				},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_UnknownSourceRef))
+		})
+
+		It("should return the repository URLs of all the sources, without duplicates", func() {
+			Expect(getSourceRepoURLs(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Source: managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/a/b"},
+			})).To(HaveLen(1))
+
+			Expect(getSourceRepoURLs(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Sources: managedgitopsv1alpha1.ApplicationSources{
+					{RepoURL: "https://github.com/a/b"},
+					{RepoURL: "https://charts.example.com"},
+					{RepoURL: "https://github.com/a/b"},
+				},
+			})).To(HaveLen(2))
+		})
+	})
+
+	Context("checkValidIgnoreDifferences should validate the ignoreDifferences field of a GitOpsDeployment", func() {
+		It("should accept a nil or valid ignoreDifferences field", func() {
+			Expect(checkValidIgnoreDifferences(nil)).To(BeNil())
+			Expect(checkValidIgnoreDifferences([]managedgitopsv1alpha1.ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}},
+			})).To(BeNil())
+		})
+
+		It("should return a user error if no fields to ignore are specified", func() {
+			userErr := checkValidIgnoreDifferences([]managedgitopsv1alpha1.ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment"},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_IgnoreDifferencesNoFields))
+		})
+	})
+
+	Context("setSuspendedCondition should reflect whether a GitOpsDeployment is suspended", func() {
+		It("should set the Suspended condition while suspended, and set it to false once resumed", func() {
+			gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{Suspend: false},
+			}
+
+			By("a GitOpsDeployment that has never been suspended should not have the condition")
+			setSuspendedCondition(gitopsDepl)
+			Expect(gitopsDepl.Status.Conditions).To(BeEmpty())
+
+			By("suspending the GitOpsDeployment should set the condition to true")
+			gitopsDepl.Spec.Suspend = true
+			setSuspendedCondition(gitopsDepl)
+			Expect(gitopsDepl.Status.Conditions).To(HaveLen(1))
+			Expect(gitopsDepl.Status.Conditions[0].Type).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentConditionSuspended))
+			Expect(gitopsDepl.Status.Conditions[0].Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+			Expect(gitopsDepl.Status.Conditions[0].Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonSuspended))
+
+			By("the condition should not be modified if the GitOpsDeployment is still suspended")
+			before := *gitopsDepl.Status.Conditions[0].DeepCopy()
+			setSuspendedCondition(gitopsDepl)
+			Expect(gitopsDepl.Status.Conditions[0]).To(Equal(before))
+
+			By("resuming the GitOpsDeployment should set the condition to false")
+			gitopsDepl.Spec.Suspend = false
+			setSuspendedCondition(gitopsDepl)
+			Expect(gitopsDepl.Status.Conditions).To(HaveLen(1))
+			Expect(gitopsDepl.Status.Conditions[0].Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+			Expect(gitopsDepl.Status.Conditions[0].Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonResumed))
+		})
+	})
+
+	Context("setReadinessConditions should derive the Synced, Healthy, Progressing and Ready conditions from the status", func() {
+
+		findCondition := func(gitopsDepl *managedgitopsv1alpha1.GitOpsDeployment,
+			conditionType managedgitopsv1alpha1.GitOpsDeploymentConditionType) managedgitopsv1alpha1.GitOpsDeploymentCondition {
+			for _, cond := range gitopsDepl.Status.Conditions {
+				if cond.Type == conditionType {
+					return cond
+				}
+			}
+			Fail("condition not found: " + string(conditionType))
+			return managedgitopsv1alpha1.GitOpsDeploymentCondition{}
+		}
+
+		It("should set the conditions to unknown/false, if the sync and health status are not yet known", func() {
+			gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{}
+
+			setReadinessConditions(gitopsDepl)
+			Expect(gitopsDepl.Status.Conditions).To(HaveLen(4))
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionSynced).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusUnknown))
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusUnknown))
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionProgressing).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionReady).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+		})
+
+		It("should report a synced and healthy GitOpsDeployment as ready, and only update the conditions when they change", func() {
+			gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: managedgitopsv1alpha1.GitOpsDeploymentStatus{
+					ObservedGeneration: 2,
+					Sync:               managedgitopsv1alpha1.SyncStatus{Status: managedgitopsv1alpha1.SyncStatusCodeSynced},
+					Health:             managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeHealthy},
+				},
+			}
+
+			setReadinessConditions(gitopsDepl)
+			ready := findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionReady)
+			Expect(ready.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+			Expect(ready.Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonReady))
+			Expect(ready.ObservedGeneration).To(Equal(int64(2)))
+			Expect(ready.LastTransitionTime).ToNot(BeNil())
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionSynced).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+
+			By("the conditions should not be modified if the status has not changed")
+			before := gitopsDepl.DeepCopy().Status.Conditions
+			setReadinessConditions(gitopsDepl)
+			Expect(gitopsDepl.Status.Conditions).To(Equal(before))
+
+			By("a running sync operation should mark the GitOpsDeployment as progressing, and not ready")
+			gitopsDepl.Status.OperationState = &managedgitopsv1alpha1.OperationState{Phase: managedgitopsv1alpha1.OperationRunning}
+			setReadinessConditions(gitopsDepl)
+			progressing := findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionProgressing)
+			Expect(progressing.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+			Expect(progressing.Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonSyncRunning))
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionReady).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+
+			By("a degraded, out of sync GitOpsDeployment should be neither synced, healthy nor ready")
+			gitopsDepl.Status.OperationState = nil
+			gitopsDepl.Status.Sync.Status = managedgitopsv1alpha1.SyncStatusCodeOutOfSync
+			gitopsDepl.Status.Health = managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeDegraded, Message: "container is crash looping"}
+			setReadinessConditions(gitopsDepl)
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionSynced).Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonOutOfSync))
+			healthy := findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy)
+			Expect(healthy.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+			Expect(healthy.Message).To(ContainSubstring("container is crash looping"))
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionProgressing).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+			Expect(findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionReady).Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+
+			By("a GitOpsDeployment with a sync error should not be ready, even if synced and healthy")
+			gitopsDepl.Status.Sync.Status = managedgitopsv1alpha1.SyncStatusCodeSynced
+			gitopsDepl.Status.Health = managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeHealthy}
+			gitopsDepl.Status.Conditions = append(gitopsDepl.Status.Conditions, managedgitopsv1alpha1.GitOpsDeploymentCondition{
+				Type: managedgitopsv1alpha1.GitOpsDeploymentConditionSyncError, Status: managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			})
+			setReadinessConditions(gitopsDepl)
+			ready = findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionReady)
+			Expect(ready.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+			Expect(ready.Message).To(ContainSubstring("syncing"))
+		})
+
+		It("should preserve the lastTransitionTime of a condition if only its message changes", func() {
+			gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+				Status: managedgitopsv1alpha1.GitOpsDeploymentStatus{
+					Health: managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeDegraded, Message: "first"},
+				},
+			}
+			setReadinessConditions(gitopsDepl)
+			previous := findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy)
+
+			gitopsDepl.Status.Health.Message = "second"
+			setReadinessConditions(gitopsDepl)
+			healthy := findCondition(gitopsDepl, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy)
+			Expect(healthy.Message).To(ContainSubstring("second"))
+			Expect(healthy.LastTransitionTime).To(Equal(previous.LastTransitionTime))
+		})
+	})
+
+	Context("setDriftStatus should summarize the resources that are OutOfSync", func() {
+		It("should report the drifted resources, and preserve the time drift was first detected until it is resolved", func() {
+			gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+				Status: managedgitopsv1alpha1.GitOpsDeploymentStatus{
+					Resources: []managedgitopsv1alpha1.ResourceStatus{
+						{Kind: "Service", Namespace: "my-namespace", Name: "my-service", Status: managedgitopsv1alpha1.SyncStatusCodeSynced},
+						{Group: "apps", Kind: "Deployment", Namespace: "my-namespace", Name: "b", Status: managedgitopsv1alpha1.SyncStatusCodeOutOfSync},
+						{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config-map", Status: managedgitopsv1alpha1.SyncStatusCodeOutOfSync},
+						{Group: "apps", Kind: "Deployment", Namespace: "my-namespace", Name: "a", Status: managedgitopsv1alpha1.SyncStatusCodeOutOfSync},
+					},
+				},
+			}
+
+			firstDetected := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+			setDriftStatus(gitopsDepl, nil, firstDetected)
+
+			drift := gitopsDepl.Status.Drift
+			Expect(drift).ToNot(BeNil())
+			Expect(drift.DriftedResourceCount).To(Equal(3))
+			Expect(drift.DetectedAt).To(Equal(firstDetected))
+			Expect(drift.Kinds).To(Equal([]managedgitopsv1alpha1.DriftKindCount{
+				{Kind: "ConfigMap", Count: 1},
+				{Group: "apps", Kind: "Deployment", Count: 2},
+			}))
+			Expect(drift.Resources).To(Equal([]managedgitopsv1alpha1.DriftedResource{
+				{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config-map"},
+				{Group: "apps", Kind: "Deployment", Namespace: "my-namespace", Name: "a"},
+				{Group: "apps", Kind: "Deployment", Namespace: "my-namespace", Name: "b"},
+			}))
+			Expect(gitopsDepl.Status.Conditions).To(HaveLen(1))
+			Expect(gitopsDepl.Status.Conditions[0].Type).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentConditionDriftDetected))
+			Expect(gitopsDepl.Status.Conditions[0].Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+			Expect(gitopsDepl.Status.Conditions[0].Message).To(ContainSubstring("3 resource(s)"))
+
+			By("the time drift was first detected should be preserved while the resources are still OutOfSync")
+			setDriftStatus(gitopsDepl, drift, metav1.Now())
+			Expect(gitopsDepl.Status.Drift.DetectedAt).To(Equal(firstDetected))
+
+			By("only the first drifted resources should be listed")
+			gitopsDepl.Status.Resources = nil
+			for i := 0; i < managedgitopsv1alpha1.MaxDriftedResources+5; i++ {
+				gitopsDepl.Status.Resources = append(gitopsDepl.Status.Resources, managedgitopsv1alpha1.ResourceStatus{
+					Kind: "ConfigMap", Namespace: "my-namespace", Name: fmt.Sprintf("config-map-%02d", i), Status: managedgitopsv1alpha1.SyncStatusCodeOutOfSync,
+				})
+			}
+			setDriftStatus(gitopsDepl, gitopsDepl.Status.Drift, metav1.Now())
+			Expect(gitopsDepl.Status.Drift.DriftedResourceCount).To(Equal(managedgitopsv1alpha1.MaxDriftedResources + 5))
+			Expect(gitopsDepl.Status.Drift.Resources).To(HaveLen(managedgitopsv1alpha1.MaxDriftedResources))
+
+			By("the drift should be cleared, once the resources are no longer OutOfSync")
+			gitopsDepl.Status.Resources = nil
+			setDriftStatus(gitopsDepl, gitopsDepl.Status.Drift, metav1.Now())
+			Expect(gitopsDepl.Status.Drift).To(BeNil())
+			Expect(gitopsDepl.Status.Conditions[0].Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+			Expect(gitopsDepl.Status.Conditions[0].Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonNoDrift))
+		})
+	})
+
+	Context("mergeSyncOptions should combine the default and user sync options", func() {
+		It("should append user sync options after the defaults", func() {
+			Expect(mergeSyncOptions([]string{prunePropagationPolicy}, []string{"CreateNamespace=true"})).
+				To(Equal([]string{prunePropagationPolicy, "CreateNamespace=true"}))
+		})
+
+		It("should replace a default sync option with a user sync option of the same name", func() {
+			Expect(mergeSyncOptions([]string{prunePropagationPolicy}, []string{"ApplyOutOfSyncOnly=true", "PrunePropagationPolicy=orphan"})).
+				To(Equal([]string{"ApplyOutOfSyncOnly=true", "PrunePropagationPolicy=orphan"}))
+		})
+
+		It("should not duplicate sync options", func() {
+			Expect(mergeSyncOptions([]string{prunePropagationPolicy}, []string{prunePropagationPolicy, "Replace=true", "Replace=true"})).
+				To(Equal([]string{prunePropagationPolicy, "Replace=true"}))
+			Expect(mergeSyncOptions(nil, nil)).To(BeEmpty())
+		})
+	})
+
+	Context("checkValidSyncOption should validate the sync options of a GitOpsDeployment", func() {
+		It("should accept the supported Argo CD sync options", func() {
+			Expect(checkValidSyncOption([]managedgitopsv1alpha1.SyncOption{
+				managedgitopsv1alpha1.SyncOptions_CreateNamespace_true,
+				managedgitopsv1alpha1.SyncOptions_ServerSideApply_true,
+				managedgitopsv1alpha1.SyncOptions_PruneLast_true,
+				managedgitopsv1alpha1.SyncOptions_ApplyOutOfSyncOnly_true,
+				managedgitopsv1alpha1.SyncOptions_Replace_false,
+				managedgitopsv1alpha1.SyncOptions_Validate_false,
+				managedgitopsv1alpha1.SyncOptions_RespectIgnoreDifferences_true,
+				managedgitopsv1alpha1.SyncOptions_PrunePropagationPolicy_orphan,
+			})).To(BeNil())
+		})
+
+		It("should return a user error for an unsupported sync option", func() {
+			userErr := checkValidSyncOption([]managedgitopsv1alpha1.SyncOption{"FailOnSharedResource=true"})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidSyncOption))
+		})
+
+		It("should return a user error for conflicting sync options", func() {
+			userErr := checkValidSyncOption([]managedgitopsv1alpha1.SyncOption{
+				managedgitopsv1alpha1.SyncOptions_Validate_true,
+				managedgitopsv1alpha1.SyncOptions_Validate_false,
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_ConflictingSyncOption))
+		})
+	})
+
+	Context("checkValidSyncPolicy should validate the automated sync policy and retry strategy of a GitOpsDeployment", func() {
+		It("should accept a nil or valid sync policy", func() {
+			Expect(checkValidSyncPolicy(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Manual,
+			})).To(BeNil())
+
+			selfHeal := false
+			Expect(checkValidSyncPolicy(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+				SyncPolicy: &managedgitopsv1alpha1.SyncPolicy{
+					Automated: &managedgitopsv1alpha1.SyncPolicyAutomated{SelfHeal: &selfHeal},
+					Retry: &managedgitopsv1alpha1.RetryStrategy{
+						Limit:   getInt64Pointer(5),
+						Backoff: &managedgitopsv1alpha1.Backoff{Duration: "5", Factor: getInt64Pointer(2), MaxDuration: "2m"},
+					},
+				},
+			})).To(BeNil())
+		})
+
+		It("should return a user error if a retry strategy is set on a manual GitOpsDeployment", func() {
+			userErr := checkValidSyncPolicy(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Manual,
+				SyncPolicy: &managedgitopsv1alpha1.SyncPolicy{
+					Retry: &managedgitopsv1alpha1.RetryStrategy{Limit: getInt64Pointer(5)},
+				},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_SyncPolicyOnManual))
+		})
+
+		It("should return a user error for an invalid backoff duration", func() {
+			userErr := checkValidSyncPolicy(managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+				SyncPolicy: &managedgitopsv1alpha1.SyncPolicy{
+					Retry: &managedgitopsv1alpha1.RetryStrategy{
+						Backoff: &managedgitopsv1alpha1.Backoff{Duration: "5 seconds"},
+					},
+				},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidRetryBackoffDuration))
+		})
+	})
+
+	Context("checkValidKustomizeSource should validate the Kustomize options of a GitOpsDeployment", func() {
+		It("should accept a nil or valid Kustomize field", func() {
+			Expect(checkValidKustomizeSource(managedgitopsv1alpha1.ApplicationSource{})).To(BeNil())
+			Expect(checkValidKustomizeSource(managedgitopsv1alpha1.ApplicationSource{
+				Kustomize: &managedgitopsv1alpha1.ApplicationSourceKustomize{
+					Images:       []string{"quay.io/org/app=quay.io/other/app:v2"},
+					CommonLabels: map[string]string{"env": "dev"},
+				},
+			})).To(BeNil())
+		})
+
+		It("should return a user error if both Helm and Kustomize options are set", func() {
+			userErr := checkValidKustomizeSource(managedgitopsv1alpha1.ApplicationSource{
+				Helm:      &managedgitopsv1alpha1.ApplicationSourceHelm{},
+				Kustomize: &managedgitopsv1alpha1.ApplicationSourceKustomize{},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_HelmAndKustomizeSet))
+		})
+
+		It("should return a user error for an invalid image", func() {
+			userErr := checkValidKustomizeSource(managedgitopsv1alpha1.ApplicationSource{
+				Kustomize: &managedgitopsv1alpha1.ApplicationSourceKustomize{Images: []string{""}},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidKustomizeImage))
+		})
+	})
+
+	Context("checkValidHelmSource should validate the Helm options of a GitOpsDeployment", func() {
+		It("should accept a nil or valid Helm field", func() {
+			Expect(checkValidHelmSource(nil)).To(BeNil())
+			Expect(checkValidHelmSource(&managedgitopsv1alpha1.ApplicationSourceHelm{
+				Parameters: []managedgitopsv1alpha1.HelmParameter{{Name: "a", Value: "b"}},
+				Values:     "a: b",
+			})).To(BeNil())
+		})
+
+		It("should return a user error for a parameter without a name", func() {
+			userErr := checkValidHelmSource(&managedgitopsv1alpha1.ApplicationSourceHelm{
+				Parameters: []managedgitopsv1alpha1.HelmParameter{{Value: "b"}},
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidHelmParameter))
+		})
+
+		It("should return a user error for values that are not a YAML object", func() {
+			userErr := checkValidHelmSource(&managedgitopsv1alpha1.ApplicationSourceHelm{
+				Values: "- a\n- b",
+			})
+			Expect(userErr).ToNot(BeNil())
+			Expect(userErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidHelmValues))
+		})
 	})
 })
 
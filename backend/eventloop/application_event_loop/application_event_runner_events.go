@@ -0,0 +1,85 @@
+package application_event_loop
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+)
+
+// recordDeploymentLifecycleEvents records Kubernetes Events on the GitOpsDeployment for the sync operation and health
+// transitions between the previous and the updated status field of the GitOpsDeployment.
+func recordDeploymentLifecycleEvents(eventRecorder record.EventRecorder, previous managedgitopsv1alpha1.GitOpsDeploymentStatus,
+	gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment) {
+
+	recordSyncOperationEvents(eventRecorder, gitopsDeployment, previous.OperationState, gitopsDeployment.Status.OperationState)
+
+	previousHealth := previous.Health.Status
+	health := gitopsDeployment.Status.Health.Status
+
+	if health == managedgitopsv1alpha1.HeathStatusCodeDegraded && previousHealth != managedgitopsv1alpha1.HeathStatusCodeDegraded {
+		message := "Health of the deployed resources is Degraded"
+		if gitopsDeployment.Status.Health.Message != "" {
+			message += ": " + gitopsDeployment.Status.Health.Message
+		}
+		eventlooptypes.RecordWarningEvent(eventRecorder, gitopsDeployment, eventlooptypes.EventReasonHealthDegraded, message)
+
+	} else if previousHealth == managedgitopsv1alpha1.HeathStatusCodeDegraded && health == managedgitopsv1alpha1.HeathStatusCodeHealthy {
+		eventlooptypes.RecordNormalEvent(eventRecorder, gitopsDeployment, eventlooptypes.EventReasonHealthRecovered,
+			"Health of the deployed resources has recovered from Degraded to Healthy")
+	}
+}
+
+// recordSyncOperationEvents records Kubernetes Events on the object (a GitOpsDeployment or GitOpsDeploymentSyncRun)
+// when a sync operation starts, and when it completes, based on the previous and current operation state.
+//
+// Since the operation state is polled, an operation may both start and complete between two observations: in that
+// case, both the started and the completed events are recorded.
+func recordSyncOperationEvents(eventRecorder record.EventRecorder, object runtime.Object, previous *managedgitopsv1alpha1.OperationState,
+	current *managedgitopsv1alpha1.OperationState) {
+
+	if current == nil || current.Phase == "" {
+		return
+	}
+
+	// A sync operation is new if it started at a different time than the previously observed operation
+	newOperation := previous == nil || !previous.StartedAt.Equal(&current.StartedAt)
+
+	revision := ""
+	if current.SyncResult != nil && current.SyncResult.Revision != "" {
+		revision = current.SyncResult.Revision
+	} else if current.Operation.Sync != nil {
+		revision = current.Operation.Sync.Revision
+	}
+
+	if newOperation {
+		message := "Sync operation started"
+		if revision != "" {
+			message = fmt.Sprintf("Sync operation started to revision '%s'", revision)
+		}
+		eventlooptypes.RecordNormalEvent(eventRecorder, object, eventlooptypes.EventReasonSyncStarted, message)
+	}
+
+	if !newOperation && previous.Phase == current.Phase {
+		return
+	}
+
+	switch current.Phase {
+	case managedgitopsv1alpha1.OperationSucceeded:
+		message := "Sync operation succeeded"
+		if revision != "" {
+			message = fmt.Sprintf("Sync operation to revision '%s' succeeded", revision)
+		}
+		eventlooptypes.RecordNormalEvent(eventRecorder, object, eventlooptypes.EventReasonSyncSucceeded, message)
+
+	case managedgitopsv1alpha1.OperationFailed, managedgitopsv1alpha1.OperationError:
+		message := "Sync operation failed"
+		if current.Message != "" {
+			message += ": " + current.Message
+		}
+		eventlooptypes.RecordWarningEvent(eventRecorder, object, eventlooptypes.EventReasonSyncFailed, message)
+	}
+}
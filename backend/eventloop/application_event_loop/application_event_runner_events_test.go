@@ -0,0 +1,105 @@
+package application_event_loop
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Application Event Runner Kubernetes Events", func() {
+
+	var (
+		recorder   *record.FakeRecorder
+		gitopsDepl *managedgitopsv1alpha1.GitOpsDeployment
+		startedAt  metav1.Time
+	)
+
+	// recordedEvents returns the events that have been recorded so far, in the format '<type> <reason> <message>'
+	recordedEvents := func() []string {
+		res := []string{}
+		for {
+			select {
+			case event := <-recorder.Events:
+				res = append(res, event)
+			default:
+				return res
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		startedAt = metav1.NewTime(time.Now().Truncate(time.Second))
+
+		gitopsDepl = &managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-gitops-depl",
+				Namespace: "my-namespace",
+			},
+		}
+	})
+
+	It("should record events when a sync operation starts and completes", func() {
+		gitopsDepl.Status.OperationState = &managedgitopsv1alpha1.OperationState{
+			Phase:     managedgitopsv1alpha1.OperationRunning,
+			StartedAt: startedAt,
+			Operation: managedgitopsv1alpha1.ApplicationOperation{
+				Sync: &managedgitopsv1alpha1.SyncOperation{Revision: "abc123"},
+			},
+		}
+		recordDeploymentLifecycleEvents(recorder, managedgitopsv1alpha1.GitOpsDeploymentStatus{}, gitopsDepl)
+		Expect(recordedEvents()).To(Equal([]string{"Normal SyncStarted Sync operation started to revision 'abc123'"}))
+
+		By("not recording the events again, if the operation state has not changed")
+		previous := *gitopsDepl.Status.DeepCopy()
+		recordDeploymentLifecycleEvents(recorder, previous, gitopsDepl)
+		Expect(recordedEvents()).To(BeEmpty())
+
+		By("recording a warning event when the sync operation fails")
+		gitopsDepl.Status.OperationState.Phase = managedgitopsv1alpha1.OperationFailed
+		gitopsDepl.Status.OperationState.Message = "one or more objects failed to apply"
+		recordDeploymentLifecycleEvents(recorder, previous, gitopsDepl)
+		Expect(recordedEvents()).To(Equal([]string{"Warning SyncFailed Sync operation failed: one or more objects failed to apply"}))
+	})
+
+	It("should record both the started and completed events, if the sync operation completed between two observations", func() {
+		gitopsDepl.Status.OperationState = &managedgitopsv1alpha1.OperationState{
+			Phase:      managedgitopsv1alpha1.OperationSucceeded,
+			StartedAt:  startedAt,
+			SyncResult: &managedgitopsv1alpha1.SyncOperationResult{Revision: "abc123"},
+		}
+		recordDeploymentLifecycleEvents(recorder, managedgitopsv1alpha1.GitOpsDeploymentStatus{}, gitopsDepl)
+		Expect(recordedEvents()).To(Equal([]string{
+			"Normal SyncStarted Sync operation started to revision 'abc123'",
+			"Normal SyncSucceeded Sync operation to revision 'abc123' succeeded",
+		}))
+	})
+
+	It("should record events when the health of the deployed resources degrades and recovers", func() {
+		previous := managedgitopsv1alpha1.GitOpsDeploymentStatus{
+			Health: managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeHealthy},
+		}
+		gitopsDepl.Status.Health = managedgitopsv1alpha1.HealthStatus{
+			Status:  managedgitopsv1alpha1.HeathStatusCodeDegraded,
+			Message: "Deployment has timed out progressing",
+		}
+		recordDeploymentLifecycleEvents(recorder, previous, gitopsDepl)
+		Expect(recordedEvents()).To(Equal([]string{"Warning HealthDegraded Health of the deployed resources is Degraded: Deployment has timed out progressing"}))
+
+		previous = *gitopsDepl.Status.DeepCopy()
+		gitopsDepl.Status.Health = managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeHealthy}
+		recordDeploymentLifecycleEvents(recorder, previous, gitopsDepl)
+		Expect(recordedEvents()).To(Equal([]string{"Normal HealthRecovered Health of the deployed resources has recovered from Degraded to Healthy"}))
+	})
+
+	It("should not record events if there is no EventRecorder", func() {
+		gitopsDepl.Status.Health = managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeDegraded}
+		Expect(func() {
+			recordDeploymentLifecycleEvents(nil, managedgitopsv1alpha1.GitOpsDeploymentStatus{}, gitopsDepl)
+		}).ToNot(Panic())
+	})
+})
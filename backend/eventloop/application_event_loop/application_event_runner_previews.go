@@ -0,0 +1,268 @@
+package application_event_loop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/gitopserrors"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// This file is responsible for processing events related to GitOpsDeploymentPreview CR.
+//
+// A preview is computed once for each generation of the GitOpsDeploymentPreview: a Preview row is created in the
+// database, and the cluster-agent is asked (via an Operation of type Preview) to compare the live resources of the
+// Argo CD Application with the resources at the revision of the GitOpsDeploymentPreview. Once the Operation has
+// completed, the result is copied to the status of the GitOpsDeploymentPreview, and the Preview row is deleted.
+
+func (a *applicationEventLoopRunner_Action) applicationEventRunner_handlePreviewModified(ctx context.Context, dbQueries db.ApplicationScopedQueries) error {
+
+	log := a.log
+
+	previewCR := &managedgitopsv1alpha1.GitOpsDeploymentPreview{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      a.eventResourceName,
+			Namespace: a.eventResourceNamespace,
+		},
+	}
+	if err := a.workspaceClient.Get(ctx, client.ObjectKeyFromObject(previewCR), previewCR); err != nil {
+		if apierr.IsNotFound(err) {
+			// The preview only exists for as long as the CR exists, so there is nothing to clean up
+			return nil
+		}
+		return fmt.Errorf("unable to get GitOpsDeploymentPreview: %v", err)
+	}
+
+	// The preview of this generation has already been computed
+	if previewCR.Status.ObservedGeneration == previewCR.Generation &&
+		(previewCR.Status.Phase == managedgitopsv1alpha1.GitOpsDeploymentPreviewPhase_Completed ||
+			previewCR.Status.Phase == managedgitopsv1alpha1.GitOpsDeploymentPreviewPhase_Failed) {
+		return nil
+	}
+
+	if err := updateGitOpsDeploymentPreviewStatus(ctx, a.workspaceClient, previewCR, func(status *managedgitopsv1alpha1.GitOpsDeploymentPreviewStatus) {
+		*status = managedgitopsv1alpha1.GitOpsDeploymentPreviewStatus{
+			Phase:              managedgitopsv1alpha1.GitOpsDeploymentPreviewPhase_Running,
+			ObservedGeneration: previewCR.Generation,
+		}
+	}); err != nil {
+		return fmt.Errorf("failed to update the status of GitOpsDeploymentPreview: %v", err)
+	}
+
+	resolvedRevision, resources, truncated, userErr := a.computeGitOpsDeploymentPreview(ctx, previewCR, dbQueries)
+	if userErr != nil && userErr.UserError() == "" {
+		// An internal error occurred: return it, so that the event is retried
+		return userErr.DevError()
+	}
+
+	now := metav1.Now()
+
+	if err := updateGitOpsDeploymentPreviewStatus(ctx, a.workspaceClient, previewCR, func(status *managedgitopsv1alpha1.GitOpsDeploymentPreviewStatus) {
+		status.CompletedAt = &now
+
+		if userErr != nil {
+			log.Error(userErr.DevError(), "unable to compute the preview of GitOpsDeploymentPreview")
+			status.Phase = managedgitopsv1alpha1.GitOpsDeploymentPreviewPhase_Failed
+			status.Message = userErr.UserError()
+			return
+		}
+
+		status.Phase = managedgitopsv1alpha1.GitOpsDeploymentPreviewPhase_Completed
+		status.ResolvedRevision = resolvedRevision
+		status.Resources = resources
+		status.Truncated = truncated
+	}); err != nil {
+		return fmt.Errorf("failed to update the status of GitOpsDeploymentPreview: %v", err)
+	}
+
+	return nil
+}
+
+// computeGitOpsDeploymentPreview asks the cluster-agent to compute the preview of the GitOpsDeploymentPreview, and waits
+// for the result. The revision that the previewed revision resolved to is returned, along with the resources that would
+// be added, changed, or removed, and whether those resources were truncated by the cluster-agent.
+func (a *applicationEventLoopRunner_Action) computeGitOpsDeploymentPreview(ctx context.Context, previewCR *managedgitopsv1alpha1.GitOpsDeploymentPreview,
+	dbQueries db.ApplicationScopedQueries) (string, []managedgitopsv1alpha1.PreviewResourceDiff, bool, gitopserrors.UserError) {
+
+	log := a.log.WithValues("revision", previewCR.Spec.Revision)
+
+	if previewCR.Spec.Revision == "" {
+		userErr := fmt.Sprintf("invalid GitOpsDeploymentPreview '%s'. spec.revision must be specified", previewCR.Name)
+		return "", nil, false, gitopserrors.NewUserDevError(userErr, fmt.Errorf(userErr))
+	}
+
+	// 1) Retrieve the GitOpsDeployment, and locate the corresponding application and gitopsengineinstance
+	gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      previewCR.Spec.GitopsDeploymentName,
+			Namespace: previewCR.Namespace,
+		},
+	}
+	if err := a.workspaceClient.Get(ctx, client.ObjectKeyFromObject(gitopsDepl), gitopsDepl); err != nil {
+		if apierr.IsNotFound(err) {
+			userErr := fmt.Sprintf("Unable to retrieve GitOpsDeployment '%s' referenced by the GitOpsDeploymentPreview", gitopsDepl.Name)
+			return "", nil, false, gitopserrors.NewUserDevError(userErr, err)
+		}
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	deplToAppMapping := &db.DeploymentToApplicationMapping{Deploymenttoapplicationmapping_uid_id: string(gitopsDepl.UID)}
+	if err := dbQueries.GetDeploymentToApplicationMappingByDeplId(ctx, deplToAppMapping); err != nil {
+		if db.IsResultNotFoundError(err) {
+			userErr := fmt.Sprintf("GitOpsDeployment '%s' referenced by the GitOpsDeploymentPreview has not yet been deployed", gitopsDepl.Name)
+			return "", nil, false, gitopserrors.NewUserDevError(userErr, err)
+		}
+		log.Error(err, "unable to retrieve deployment to application mapping, on preview modified", "uid", string(gitopsDepl.UID))
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	application := &db.Application{Application_id: deplToAppMapping.Application_id}
+	if err := dbQueries.GetApplicationById(ctx, application); err != nil {
+		log.Error(err, "unable to retrieve application, on preview modified", "applicationId", deplToAppMapping.Application_id)
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	namespace := corev1.Namespace{}
+	if err := a.workspaceClient.Get(ctx, types.NamespacedName{Name: a.eventResourceNamespace}, &namespace); err != nil {
+		return "", nil, false, gitopserrors.NewDevOnlyError(fmt.Errorf("unable to retrieve namespace '%s': %v", a.eventResourceNamespace, err))
+	}
+
+	clusterUser, _, err := a.sharedResourceEventLoop.GetOrCreateClusterUserByNamespaceUID(ctx, a.workspaceClient, namespace, log)
+	if err != nil {
+		return "", nil, false, gitopserrors.NewDevOnlyError(fmt.Errorf("unable to retrieve cluster user, on preview modified: %v", err))
+	}
+
+	gitopsEngineInstance, err := a.sharedResourceEventLoop.GetGitopsEngineInstanceById(ctx, application.Engine_instance_inst_id,
+		a.workspaceClient, namespace, log)
+	if err != nil {
+		log.Error(err, "unable to retrieve gitopsengineinstance, on preview modified", "instanceId", application.Engine_instance_inst_id)
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	// 2) Create the Preview row, and the Operation that informs the cluster-agent to compute it
+	preview := db.Preview{
+		Application_id: application.Application_id,
+		Revision:       previewCR.Spec.Revision,
+		State:          db.PreviewState_Waiting,
+	}
+	if err := dbQueries.CreatePreview(ctx, &preview); err != nil {
+		if db.IsMaxLengthError(err) {
+			userErr := fmt.Sprintf("invalid GitOpsDeploymentPreview '%s'. spec.revision is too long", previewCR.Name)
+			return "", nil, false, gitopserrors.NewUserDevError(userErr, err)
+		}
+		log.Error(err, "unable to create preview, on preview modified")
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+	log = log.WithValues("previewID", preview.Preview_id)
+
+	// The Preview row is only needed for as long as we are waiting for the result
+	defer func() {
+		if _, err := dbQueries.DeletePreviewById(ctx, preview.Preview_id); err != nil {
+			log.Error(err, "unable to delete preview")
+		}
+	}()
+
+	operationClient, err := a.k8sClientFactory.GetK8sClientForGitOpsEngineInstance(ctx, gitopsEngineInstance)
+	if err != nil {
+		log.Error(err, "unable to retrieve gitopsengine instance client, on preview modified")
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	dbOperationInput := db.Operation{
+		Instance_id:   gitopsEngineInstance.Gitopsengineinstance_id,
+		Resource_id:   preview.Preview_id,
+		Resource_type: db.OperationResourceType_Preview,
+	}
+
+	waitForOperation := !a.testOnlySkipCreateOperation // if it's for a unit test, we don't wait for the operation
+	k8sOperation, dbOperation, err := operations.CreateOperation(ctx, waitForOperation, dbOperationInput, clusterUser.Clusteruser_id,
+		gitopsEngineInstance.Namespace_name, dbQueries, operationClient, log)
+	if err != nil {
+		log.Error(err, "could not create operation, on preview modified", "namespace", gitopsEngineInstance.Namespace_name)
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	if err := operations.CleanupOperation(ctx, *dbOperation, *k8sOperation, dbQueries, operationClient, !a.testOnlySkipCreateOperation, log); err != nil {
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	// 3) Retrieve the result of the preview
+	if err := dbQueries.GetPreviewById(ctx, &preview); err != nil {
+		log.Error(err, "unable to retrieve preview, on preview modified")
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+
+	switch preview.State {
+	case db.PreviewState_Completed:
+		resources, err := decompressPreviewResources(preview.Resources)
+		if err != nil {
+			return "", nil, false, gitopserrors.NewDevOnlyError(err)
+		}
+		return preview.Resolved_revision, resources, preview.Resources_truncated, nil
+
+	case db.PreviewState_Failed:
+		userErr := "unable to compute the preview: " + preview.Message
+		return "", nil, false, gitopserrors.NewUserDevError(userErr, fmt.Errorf(userErr))
+
+	default:
+		err := fmt.Errorf("preview was not computed by the cluster-agent, operation state: %s, %s", dbOperation.State, dbOperation.Human_readable_state)
+		return "", nil, false, gitopserrors.NewDevOnlyError(err)
+	}
+}
+
+// Decompress byte array received from table and then convert it into PreviewResourceDiff Array.
+func decompressPreviewResources(resourceData []byte) ([]managedgitopsv1alpha1.PreviewResourceDiff, error) {
+	var resources []managedgitopsv1alpha1.PreviewResourceDiff
+
+	if len(resourceData) == 0 {
+		return resources, nil
+	}
+
+	objBytes, err := sharedutil.DecompressObject(resourceData)
+	if err != nil {
+		return resources, fmt.Errorf("failed to decompress preview resource data: %v", err)
+	}
+
+	if err := json.Unmarshal(objBytes, &resources); err != nil {
+		return resources, fmt.Errorf("unable to unmarshal preview resource data: %v", err)
+	}
+
+	return resources, nil
+}
+
+func updateGitOpsDeploymentPreviewStatus(ctx context.Context, k8sClient client.Client, previewCRParam *managedgitopsv1alpha1.GitOpsDeploymentPreview,
+	updateStatus func(*managedgitopsv1alpha1.GitOpsDeploymentPreviewStatus)) error {
+
+	previewCR := previewCRParam.DeepCopy()
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(previewCR), previewCR); err != nil {
+		if apierr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// The Preview was deleted and recreated: the status is no longer ours to update
+	if previewCR.UID != previewCRParam.UID {
+		return nil
+	}
+
+	originalStatus := previewCR.Status.DeepCopy()
+
+	updateStatus(&previewCR.Status)
+
+	if reflect.DeepEqual(*originalStatus, previewCR.Status) {
+		return nil
+	}
+
+	return k8sClient.Status().Update(ctx, previewCR)
+}
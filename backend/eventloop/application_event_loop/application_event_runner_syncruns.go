@@ -2,13 +2,17 @@ package application_event_loop
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	dbutil "github.com/redhat-appstudio/managed-gitops/backend-shared/db/util"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/gitopserrors"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
@@ -17,6 +21,7 @@ import (
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -289,8 +294,42 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleSyncRun
 		}
 
 		// return an error if 'Automated' sync policy is enabled. Argo CD doesn't allow syncing an Application with automated sync policy.
-		if gitopsDepl.Spec.Type != managedgitopsv1alpha1.GitOpsDeploymentSpecType_Manual {
+		// - The only exception is a rollback that disables the automated sync policy, for as long as the SyncRun exists.
+		if gitopsDepl.Spec.Type != managedgitopsv1alpha1.GitOpsDeploymentSpecType_Manual && !syncRunCR.Spec.DisableAutomatedSync {
 			userErr := fmt.Sprintf("invalid GitOpsDeploymentSyncRun '%s'. Syncing a GitOpsDeployment with Automated sync policy is not allowed", syncRunCR.Name)
+			if syncRunCR.Spec.IsRollback() {
+				userErr = fmt.Sprintf("invalid GitOpsDeploymentSyncRun '%s'. Rolling back a GitOpsDeployment with Automated sync policy requires spec.disableAutomatedSync to be set", syncRunCR.Name)
+			}
+			devErr := fmt.Errorf(userErr)
+			log.Error(devErr, "failed to process GitOpsDeploymentSyncRun")
+			return gitopserrors.NewUserDevError(userErr, devErr)
+		}
+
+		// return an error if a multi-source GitOpsDeployment is rolled back: Argo CD does not support rolling back
+		// Applications with multiple sources.
+		if syncRunCR.Spec.IsRollback() && len(gitopsDepl.Spec.Sources) > 0 {
+			userErr := fmt.Sprintf("invalid GitOpsDeploymentSyncRun '%s'. Rolling back a GitOpsDeployment with multiple sources is not supported", syncRunCR.Name)
+			devErr := fmt.Errorf(userErr)
+			log.Error(devErr, "failed to process GitOpsDeploymentSyncRun")
+			return gitopserrors.NewUserDevError(userErr, devErr)
+		}
+
+		// return an error if the GitOpsDeployment is suspended: no syncs should occur until it is resumed.
+		if gitopsDepl.Spec.Suspend {
+			userErr := fmt.Sprintf("invalid GitOpsDeploymentSyncRun '%s'. Syncing a suspended GitOpsDeployment is not allowed", syncRunCR.Name)
+			devErr := fmt.Errorf(userErr)
+			log.Error(devErr, "failed to process GitOpsDeploymentSyncRun")
+			return gitopserrors.NewUserDevError(userErr, devErr)
+		}
+
+		// return an error if the GitOpsDeployment is waiting for its dependencies: it should not be synced until they are synced and healthy.
+		waitingForDependencies, err := isWaitingForDependencies(ctx, gitopsDepl, dbQueries)
+		if err != nil {
+			log.Error(err, "unable to retrieve deployment dependency gate, on sync run modified", "uid", string(gitopsDepl.UID))
+			return gitopserrors.NewDevOnlyError(err)
+		}
+		if waitingForDependencies {
+			userErr := fmt.Sprintf("invalid GitOpsDeploymentSyncRun '%s'. Syncing a GitOpsDeployment that is waiting for its dependencies is not allowed", syncRunCR.Name)
 			devErr := fmt.Errorf(userErr)
 			log.Error(devErr, "failed to process GitOpsDeploymentSyncRun")
 			return gitopserrors.NewUserDevError(userErr, devErr)
@@ -321,14 +360,34 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleSyncRun
 			// Handle update:
 			// If both GitOpsDeploymentSyncRun CR and the DB entry exists, then the CR is being updated.
 			// Validate and return an error if the immutable fields are updated.
-			return a.handleUpdatedGitOpsDeplSyncRunEvent(ctx, syncRunCR, dbQueries, syncOperation)
+			return a.handleUpdatedGitOpsDeplSyncRunEvent(ctx, syncRunCR, dbQueries, syncOperation, gitopsEngineInstance, *clusterUser)
 		} else {
 			// Handle create:
 			// If the gitopsdeplsyncrun CR exists, but the database entry doesn't, then this is the first time we
 			// have seen the GitOpsDeplSyncRun CR.
 			// Create it in the DB and create the operation.
 
-			return a.handleNewGitOpsDeplSyncRunEvent(ctx, syncRunCR, dbQueries, application, gitopsEngineInstance, namespace, *clusterUser)
+			var rollbackTarget *db.DeploymentHistory
+			if syncRunCR.Spec.IsRollback() {
+
+				var userErr gitopserrors.UserError
+				if rollbackTarget, userErr = resolveRollbackTarget(ctx, syncRunCR.Spec, application.Application_id, dbQueries); userErr != nil {
+					log.Error(userErr.DevError(), "unable to resolve the rollback target of GitOpsDeploymentSyncRun")
+					return userErr
+				}
+
+				// Argo CD does not allow rolling back an Application with an automated sync policy: so we reconcile
+				// the GitOpsDeployment, which disables the automated sync policy of the Argo CD Application while this
+				// SyncRun exists.
+				if gitopsDepl.Spec.Type == managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated {
+					if userErr := a.reconcileGitOpsDeploymentOfSyncRun(ctx, gitopsDepl.Name, dbQueries); userErr != nil {
+						log.Error(userErr.DevError(), "unable to disable the automated sync policy of the GitOpsDeployment, before rollback")
+						return userErr
+					}
+				}
+			}
+
+			return a.handleNewGitOpsDeplSyncRunEvent(ctx, syncRunCR, dbQueries, application, gitopsEngineInstance, namespace, *clusterUser, rollbackTarget)
 		}
 
 	}
@@ -473,6 +532,29 @@ func (a *applicationEventLoopRunner_Action) handleDeletedGitOpsDeplSyncRunEvent(
 		return gitopserrors.NewDevOnlyError(allErrors)
 	}
 
+	// 4) If the SyncRun was a rollback, it may have disabled the automated sync policy of the GitOpsDeployment: reconcile
+	// the GitOpsDeployment, so that the automated sync policy is restored now that the SyncRun no longer exists.
+	if syncOperation.RollbackHistoryID != nil {
+
+		gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      syncOperation.DeploymentNameField,
+				Namespace: namespace.Name,
+			},
+		}
+		if err := a.workspaceClient.Get(ctx, client.ObjectKeyFromObject(gitopsDepl), gitopsDepl); err != nil {
+			if !apierr.IsNotFound(err) {
+				log.Error(err, "unable to retrieve the GitOpsDeployment of a deleted rollback GitOpsDeploymentSyncRun")
+				return gitopserrors.NewDevOnlyError(err)
+			}
+			// The GitOpsDeployment no longer exists, so there is nothing to restore.
+
+		} else if userErr := a.reconcileGitOpsDeploymentOfSyncRun(ctx, gitopsDepl.Name, dbQueries); userErr != nil {
+			log.Error(userErr.DevError(), "unable to restore the automated sync policy of the GitOpsDeployment, after rollback")
+			return userErr
+		}
+	}
+
 	// Success: the CR no longer exists, and we have completed cleanup.
 	return nil
 
@@ -482,10 +564,12 @@ func (a *applicationEventLoopRunner_Action) handleDeletedGitOpsDeplSyncRunEvent(
 // In this case, we need to create SyncOperation and APICRToDBMapping rows in the database.
 //
 // Finally, we need to inform the cluster-agent component (via Operation), so that it can sync the Argo CD Application.
+// If 'rollbackTarget' is non-nil, the cluster-agent rolls back the Argo CD Application to that deployment history entry,
+// rather than syncing it.
 //
 // Returns:
 // - error is non-nil, if an error occurred
-func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplSyncRunEvent(ctx context.Context, syncRunCRParam *managedgitopsv1alpha1.GitOpsDeploymentSyncRun, dbQueries db.ApplicationScopedQueries, application *db.Application, gitopsEngineInstance *db.GitopsEngineInstance, namespace corev1.Namespace, clusterUser db.ClusterUser) gitopserrors.UserError {
+func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplSyncRunEvent(ctx context.Context, syncRunCRParam *managedgitopsv1alpha1.GitOpsDeploymentSyncRun, dbQueries db.ApplicationScopedQueries, application *db.Application, gitopsEngineInstance *db.GitopsEngineInstance, namespace corev1.Namespace, clusterUser db.ClusterUser, rollbackTarget *db.DeploymentHistory) gitopserrors.UserError {
 
 	log := a.log
 	log.Info("Received GitOpsDeploymentSyncRun event for a new GitOpsDeploymentSyncRun resource")
@@ -504,16 +588,32 @@ func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplSyncRunEvent(ctx
 	// in reverse order.
 	var createdResources []db.AppScopedDisposableResource
 
+	syncOptions, err := convertSyncRunOptionsToString(syncRunCRParam.Spec)
+	if err != nil {
+		log.Error(err, "unable to convert the options of GitOpsDeploymentSyncRun to JSON")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
 	// Create sync operation
 	syncOperation := &db.SyncOperation{
 		Application_id:      application.Application_id,
 		DeploymentNameField: syncRunCRParam.Spec.GitopsDeploymentName,
 		Revision:            syncRunCRParam.Spec.RevisionID,
 		DesiredState:        db.SyncOperation_DesiredState_Running,
+		Options:             syncOptions,
+	}
+	if rollbackTarget != nil {
+		syncOperation.Revision = rollbackTarget.Revision
+		syncOperation.RollbackHistoryID = rollbackTarget.History_id
 	}
 	if err := dbQueries.CreateSyncOperation(ctx, syncOperation); err != nil {
 		log.Error(err, "unable to create sync operation in database")
 
+		if db.IsMaxLengthError(err) {
+			userError := "the GitOpsDeploymentSyncRun could not be processed: the revision or the sync options (for example, the list of resources) are too long"
+			return gitopserrors.NewUserDevError(userError, err)
+		}
+
 		return gitopserrors.NewDevOnlyError(err)
 	}
 	createdResources = append(createdResources, syncOperation)
@@ -568,8 +668,25 @@ func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplSyncRunEvent(ctx
 		return gitopserrors.NewDevOnlyError(err)
 	}
 
+	if rollbackTarget != nil {
+		if err := setGitOpsDeploymentSyncRunRollbackStatus(ctx, a.workspaceClient, syncRunCRParam, rollbackTarget, dbOperation); err != nil {
+			log.Error(err, "unable to update the rollback status of GitOpsDeploymentSyncRun")
+		}
+	}
+
+	// Report that the sync has started: the status is updated with the details of the Argo CD sync operation by the deployment status tick
+	if err := updateGitOpsDeploymentSyncRunStatus(ctx, a.workspaceClient, syncRunCRParam, func(status *managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus) {
+		status.Phase = managedgitopsv1alpha1.OperationRunning
+		status.StartedAt = &metav1.Time{Time: time.Now()}
+	}); err != nil {
+		log.Error(err, "unable to update the phase of GitOpsDeploymentSyncRun")
+	}
+
 	backoff := sharedutil.ExponentialBackoff{Factor: 1.3, Min: time.Millisecond * 1000, Max: time.Second * 10, Jitter: true}
 
+	// terminateRequested is true if the user requested that the sync be terminated, while we were waiting for it
+	terminateRequested := false
+
 outer_for:
 
 	for {
@@ -608,12 +725,54 @@ outer_for:
 				log.Info("The SyncRun CR UID has changed, versus the SyncRun CR that we began with, exiting the sync process")
 				break outer_for
 			}
+
+			if currentSyncRunCR.Spec.Terminate {
+				// The user requested that the sync be terminated: we stop waiting for it, and terminate it below.
+				log.Info("The SyncRun CR requested that the sync be terminated, exiting the sync process")
+				terminateRequested = true
+				break outer_for
+			}
 		}
 
 		backoff.DelayOnFail(ctx)
 
 	}
 
+	if rollbackTarget != nil {
+		// The state of dbOperation was refreshed by the loop above, and thus reflects the result of the rollback
+		if err := setGitOpsDeploymentSyncRunRollbackStatus(ctx, a.workspaceClient, syncRunCRParam, rollbackTarget, dbOperation); err != nil {
+			log.Error(err, "unable to update the rollback status of GitOpsDeploymentSyncRun")
+		}
+	}
+
+	if terminateRequested {
+
+		if err := operations.CleanupOperation(ctx, *dbOperation, *k8sOperation, dbQueries, operationClient, !a.testOnlySkipCreateOperation, log); err != nil {
+			return gitopserrors.NewDevOnlyError(err)
+		}
+
+		return a.terminateGitOpsDeplSyncRun(ctx, syncRunCRParam, *syncOperation, dbQueries, gitopsEngineInstance, clusterUser)
+	}
+
+	// If the status has not (yet) been updated with the result of the Argo CD sync operation, for example because the
+	// sync failed before Argo CD started it, report the result based on the state of the Operation.
+	if dbOperation.State == db.OperationState_Completed || dbOperation.State == db.OperationState_Failed {
+		if err := updateGitOpsDeploymentSyncRunStatus(ctx, a.workspaceClient, syncRunCRParam, func(status *managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus) {
+			if status.Phase != managedgitopsv1alpha1.OperationRunning {
+				return
+			}
+			if dbOperation.State == db.OperationState_Completed {
+				status.Phase = managedgitopsv1alpha1.OperationSucceeded
+			} else {
+				status.Phase = managedgitopsv1alpha1.OperationFailed
+				status.Message = dbOperation.Human_readable_state
+			}
+			status.FinishedAt = &metav1.Time{Time: time.Now()}
+		}); err != nil {
+			log.Error(err, "unable to update the phase of GitOpsDeploymentSyncRun")
+		}
+	}
+
 	if err := operations.CleanupOperation(ctx, *dbOperation, *k8sOperation, dbQueries, operationClient, !a.testOnlySkipCreateOperation, log); err != nil {
 		return gitopserrors.NewDevOnlyError(err)
 	}
@@ -621,12 +780,337 @@ outer_for:
 	return nil
 }
 
+// resolveRollbackTarget returns the DeploymentHistory entry of an Application that a rollback GitOpsDeploymentSyncRun
+// targets: either the entry with the given history ID, or the most recent entry that deployed the given revision.
+func resolveRollbackTarget(ctx context.Context, syncRunSpec managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec, applicationID string,
+	dbQueries db.ApplicationScopedQueries) (*db.DeploymentHistory, gitopserrors.UserError) {
+
+	var deploymentHistory []db.DeploymentHistory
+	if err := dbQueries.ListDeploymentHistoryByApplicationId(ctx, applicationID, &deploymentHistory); err != nil {
+		return nil, gitopserrors.NewDevOnlyError(err)
+	}
+
+	// The entries are ordered from the most recent deployment to the oldest
+	for idx := range deploymentHistory {
+		entry := deploymentHistory[idx]
+
+		// Entries without an Argo CD history ID cannot be rolled back to
+		if entry.History_id == nil {
+			continue
+		}
+
+		if syncRunSpec.RollbackToHistoryID != nil && *entry.History_id == *syncRunSpec.RollbackToHistoryID {
+			return &entry, nil
+		}
+
+		if syncRunSpec.RollbackToRevision != "" && entry.Revision == syncRunSpec.RollbackToRevision {
+			return &entry, nil
+		}
+	}
+
+	var userErr string
+	if syncRunSpec.RollbackToHistoryID != nil {
+		userErr = fmt.Sprintf("unable to roll back: the deployment history of GitOpsDeployment '%s' does not contain an entry with ID %d",
+			syncRunSpec.GitopsDeploymentName, *syncRunSpec.RollbackToHistoryID)
+	} else {
+		userErr = fmt.Sprintf("unable to roll back: the deployment history of GitOpsDeployment '%s' does not contain revision '%s'",
+			syncRunSpec.GitopsDeploymentName, syncRunSpec.RollbackToRevision)
+	}
+
+	return nil, gitopserrors.NewUserDevError(userErr, fmt.Errorf(userErr))
+}
+
+// setGitOpsDeploymentSyncRunRollbackStatus updates the .status.rollback field of a GitOpsDeploymentSyncRun, based on the
+// rollback target, and on the state of the Operation that requested the rollback.
+func setGitOpsDeploymentSyncRunRollbackStatus(ctx context.Context, k8sClient client.Client, syncRunCRParam *managedgitopsv1alpha1.GitOpsDeploymentSyncRun,
+	rollbackTarget *db.DeploymentHistory, dbOperation *db.Operation) error {
+
+	rollbackStatus := &managedgitopsv1alpha1.GitOpsDeploymentSyncRunRollbackStatus{
+		Revision: rollbackTarget.Revision,
+		Phase:    managedgitopsv1alpha1.RollbackPhaseRunning,
+	}
+	if rollbackTarget.History_id != nil {
+		rollbackStatus.HistoryID = *rollbackTarget.History_id
+	}
+
+	switch dbOperation.State {
+	case db.OperationState_Completed:
+		rollbackStatus.Phase = managedgitopsv1alpha1.RollbackPhaseSucceeded
+	case db.OperationState_Failed:
+		rollbackStatus.Phase = managedgitopsv1alpha1.RollbackPhaseFailed
+		rollbackStatus.Message = dbOperation.Human_readable_state
+	}
+
+	return updateGitOpsDeploymentSyncRunStatus(ctx, k8sClient, syncRunCRParam, func(status *managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus) {
+		status.Rollback = rollbackStatus
+	})
+}
+
+// terminateGitOpsDeplSyncRun terminates the sync operation requested by a GitOpsDeploymentSyncRun, if it is still running,
+// by informing the cluster-agent (via an Operation of type TerminateSyncOperation). Once the sync operation is
+// terminated, the phase of the GitOpsDeploymentSyncRun is set to Terminated.
+func (a *applicationEventLoopRunner_Action) terminateGitOpsDeplSyncRun(ctx context.Context, syncRunCR *managedgitopsv1alpha1.GitOpsDeploymentSyncRun,
+	syncOperation db.SyncOperation, dbQueries db.ApplicationScopedQueries, gitopsEngineInstance *db.GitopsEngineInstance, clusterUser db.ClusterUser) gitopserrors.UserError {
+
+	log := a.log
+	log.Info("Terminating the sync operation of GitOpsDeploymentSyncRun", "syncOperationID", syncOperation.SyncOperation_id)
+
+	if gitopsEngineInstance == nil || gitopsEngineInstance.Namespace_name == "" {
+		err := fmt.Errorf("gitopsengineinstance was nil, or had an empty namespace, on terminate of GitOpsDeploymentSyncRun: %v", gitopsEngineInstance)
+		log.Error(err, "unexpected nil value of required objects")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	// 1) Update the state of the SyncOperation DB table to say that we want to terminate it: the cluster-agent thus
+	// stops waiting for the sync to complete.
+	syncOperation.DesiredState = db.SyncOperation_DesiredState_Terminated
+	if err := dbQueries.UpdateSyncOperation(ctx, &syncOperation); err != nil {
+		log.Error(err, "unable to update the sync operation as terminated", "syncOperationID", syncOperation.SyncOperation_id)
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	// 2) Create the operation, in order to inform the cluster agent it needs to terminate the sync operation
+	operationClient, err := a.k8sClientFactory.GetK8sClientForGitOpsEngineInstance(ctx, gitopsEngineInstance)
+	if err != nil {
+		log.Error(err, "unable to retrieve gitopsengine instance client, on terminate of GitOpsDeploymentSyncRun")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	dbOperationInput := db.Operation{
+		Instance_id:   gitopsEngineInstance.Gitopsengineinstance_id,
+		Resource_id:   syncOperation.SyncOperation_id,
+		Resource_type: db.OperationResourceType_TerminateSyncOperation,
+	}
+
+	waitForOperation := !a.testOnlySkipCreateOperation // if it's for a unit test, we don't wait for the operation
+	k8sOperation, dbOperation, err := operations.CreateOperation(ctx, waitForOperation, dbOperationInput, clusterUser.Clusteruser_id,
+		gitopsEngineInstance.Namespace_name, dbQueries, operationClient, log)
+	if err != nil {
+		log.Error(err, "could not create operation, on terminate of GitOpsDeploymentSyncRun", "namespace", gitopsEngineInstance.Namespace_name)
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	terminateFailed := dbOperation.State == db.OperationState_Failed
+	terminateMessage := dbOperation.Human_readable_state
+
+	// 3) Clean up the operation
+	if err := operations.CleanupOperation(ctx, *dbOperation, *k8sOperation, dbQueries, operationClient, !a.testOnlySkipCreateOperation, log); err != nil {
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	if terminateFailed {
+		userError := "unable to terminate the sync operation of the GitOpsDeploymentSyncRun: " + terminateMessage
+		return gitopserrors.NewUserDevError(userError, fmt.Errorf(userError))
+	}
+
+	// 4) Report that the sync was terminated, unless it had already completed
+	if err := updateGitOpsDeploymentSyncRunStatus(ctx, a.workspaceClient, syncRunCR, func(status *managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus) {
+		if status.Phase != "" && status.Phase != managedgitopsv1alpha1.OperationRunning && status.Phase != managedgitopsv1alpha1.OperationTerminating {
+			return
+		}
+		status.Phase = managedgitopsv1alpha1.SyncRunPhaseTerminated
+		status.Message = "the sync operation was terminated, as requested by spec.terminate"
+		status.FinishedAt = &metav1.Time{Time: time.Now()}
+
+		if status.Rollback != nil && status.Rollback.Phase == managedgitopsv1alpha1.RollbackPhaseRunning {
+			status.Rollback.Phase = managedgitopsv1alpha1.RollbackPhaseFailed
+			status.Rollback.Message = "the rollback was terminated, as requested by spec.terminate"
+		}
+	}); err != nil {
+		log.Error(err, "unable to update the phase of GitOpsDeploymentSyncRun, on terminate")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	return nil
+}
+
+// convertSyncRunOptionsToString returns the sync options of a GitOpsDeploymentSyncRun (prune, dryRun, force,
+// resources, syncOptions and strategy) as JSON, for storage in the SyncOperation row. If no options are specified,
+// an empty string is returned.
+func convertSyncRunOptionsToString(syncRunSpec managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec) (string, error) {
+
+	options := fauxargocd.FauxSyncOperationOptions{
+		Prune:       syncRunSpec.Prune,
+		DryRun:      syncRunSpec.DryRun,
+		Force:       syncRunSpec.Force,
+		Strategy:    string(syncRunSpec.Strategy),
+		SyncOptions: managedgitopsv1alpha1.SyncOptionToStringSlice(syncRunSpec.SyncOptions),
+	}
+	for _, resource := range syncRunSpec.Resources {
+		options.Resources = append(options.Resources, fauxargocd.FauxSyncOperationResource{
+			Group:     resource.Group,
+			Kind:      resource.Kind,
+			Name:      resource.Name,
+			Namespace: resource.Namespace,
+		})
+	}
+
+	if reflect.DeepEqual(options, fauxargocd.FauxSyncOperationOptions{}) {
+		return "", nil
+	}
+
+	optionsBytes, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+
+	return string(optionsBytes), nil
+}
+
+// updateGitOpsDeploymentSyncRunStatus retrieves the latest version of the GitOpsDeploymentSyncRun, calls updateStatus
+// to modify its status, and then updates the status if it was modified.
+func updateGitOpsDeploymentSyncRunStatus(ctx context.Context, k8sClient client.Client, syncRunCRParam *managedgitopsv1alpha1.GitOpsDeploymentSyncRun,
+	updateStatus func(*managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus)) error {
+
+	syncRunCR := syncRunCRParam.DeepCopy()
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(syncRunCR), syncRunCR); err != nil {
+		if apierr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// The SyncRun was deleted and recreated: the status is no longer ours to update
+	if syncRunCR.UID != syncRunCRParam.UID {
+		return nil
+	}
+
+	originalStatus := syncRunCR.Status.DeepCopy()
+
+	updateStatus(&syncRunCR.Status)
+
+	if reflect.DeepEqual(*originalStatus, syncRunCR.Status) {
+		return nil
+	}
+
+	return k8sClient.Status().Update(ctx, syncRunCR)
+}
+
+// updateSyncRunStatusFromOperationState updates the status of the GitOpsDeploymentSyncRun that requested the current
+// (or most recent) sync operation of an Argo CD Application, if any, with the phase and the result of that operation.
+// Kubernetes Events are recorded on the GitOpsDeploymentSyncRun when the sync operation starts and completes.
+func updateSyncRunStatusFromOperationState(ctx context.Context, operationState *managedgitopsv1alpha1.OperationState,
+	dbQueries db.ApplicationScopedQueries, k8sClient client.Client, eventRecorder record.EventRecorder) error {
+
+	if operationState == nil {
+		return nil
+	}
+
+	// Sync operations that were started on behalf of a SyncOperation reference it via an info item
+	syncOperationID := ""
+	for _, info := range operationState.Operation.Info {
+		if info != nil && info.Name == argosharedutil.ArgoCDOperationInfoSyncOperationIDKey {
+			syncOperationID = info.Value
+		}
+	}
+	if syncOperationID == "" {
+		return nil
+	}
+
+	apiCRToDBMapping := db.APICRToDatabaseMapping{
+		APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+		DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+		DBRelationKey:   syncOperationID,
+	}
+	if err := dbQueries.GetAPICRForDatabaseUID(ctx, &apiCRToDBMapping); err != nil {
+		if db.IsResultNotFoundError(err) {
+			// The GitOpsDeploymentSyncRun that requested the sync no longer exists
+			return nil
+		}
+		return err
+	}
+
+	syncRunCR := &managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      apiCRToDBMapping.APIResourceName,
+			Namespace: apiCRToDBMapping.APIResourceNamespace,
+			UID:       types.UID(apiCRToDBMapping.APIResourceUID),
+		},
+	}
+
+	var previousOperationState *managedgitopsv1alpha1.OperationState
+	syncRunFound := false
+
+	err := updateGitOpsDeploymentSyncRunStatus(ctx, k8sClient, syncRunCR, func(status *managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus) {
+		syncRunFound = true
+		if status.StartedAt != nil {
+			previousOperationState = &managedgitopsv1alpha1.OperationState{Phase: status.Phase, StartedAt: *status.StartedAt}
+		}
+
+		// A sync that was terminated by the user remains in the Terminated phase, rather than the phase reported by Argo CD
+		if status.Phase != managedgitopsv1alpha1.SyncRunPhaseTerminated {
+			status.Phase = operationState.Phase
+			status.Message = operationState.Message
+		}
+
+		startedAt := operationState.StartedAt
+		status.StartedAt = &startedAt
+		status.FinishedAt = operationState.FinishedAt.DeepCopy()
+
+		status.SyncedRevision = ""
+		status.Resources = nil
+		if operationState.SyncResult != nil {
+			status.SyncedRevision = operationState.SyncResult.Revision
+			status.Resources = operationState.SyncResult.Resources
+		}
+	})
+	if err != nil || !syncRunFound {
+		return err
+	}
+
+	// A sync that was terminated by the user keeps its phase, so no further sync events are recorded for it
+	if previousOperationState == nil || previousOperationState.Phase != managedgitopsv1alpha1.SyncRunPhaseTerminated {
+		recordSyncOperationEvents(eventRecorder, syncRunCR, previousOperationState, operationState)
+	}
+
+	return nil
+}
+
+// reconcileGitOpsDeploymentOfSyncRun reconciles the GitOpsDeployment with the given name, in the namespace of the
+// GitOpsDeploymentSyncRun. This ensures that the automated sync policy of the Argo CD Application reflects whether
+// a rollback GitOpsDeploymentSyncRun has disabled it.
+func (a *applicationEventLoopRunner_Action) reconcileGitOpsDeploymentOfSyncRun(ctx context.Context, gitopsDeploymentName string,
+	dbQueries db.ApplicationScopedQueries) gitopserrors.UserError {
+
+	deploymentAction := *a
+	deploymentAction.eventResourceName = gitopsDeploymentName
+
+	_, _, _, _, userErr := deploymentAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+
+	return userErr
+}
+
+// isAutomatedSyncDisabledBySyncRun returns true if a rollback GitOpsDeploymentSyncRun that targets the GitOpsDeployment
+// has disabled the automated sync policy of the GitOpsDeployment.
+//
+// The GitOpsDeploymentSyncRuns are listed from the field index of the cache of the manager (see the
+// GitOpsDeploymentSyncRun controller), so only the GitOpsDeploymentSyncRuns that target the GitOpsDeployment are returned.
+func isAutomatedSyncDisabledBySyncRun(ctx context.Context, gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment, k8sClient client.Client) (bool, error) {
+
+	var syncRunList managedgitopsv1alpha1.GitOpsDeploymentSyncRunList
+	if err := k8sClient.List(ctx, &syncRunList, client.InNamespace(gitopsDeployment.Namespace),
+		client.MatchingFields{eventlooptypes.SyncRunGitOpsDeploymentNameIndexKey: gitopsDeployment.Name}); err != nil {
+		return false, fmt.Errorf("unable to list GitOpsDeploymentSyncRuns of GitOpsDeployment '%s' in namespace '%s': %v",
+			gitopsDeployment.Name, gitopsDeployment.Namespace, err)
+	}
+
+	for _, syncRun := range syncRunList.Items {
+		if syncRun.DeletionTimestamp == nil && syncRun.Spec.GitopsDeploymentName == gitopsDeployment.Name &&
+			syncRun.Spec.IsRollback() && syncRun.Spec.DisableAutomatedSync {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // handleUpdatedGitOpsDeplSyncRunEvent handles GitOpsDeploymentSyncRun events where the user has just updated an existing GitOpsDeploymentSyncRun resource.
-// In this case, we need to ensure that the immutable fields GitOpsDeploymentName and RevisionID are not updated.
+// In this case, we need to ensure that the immutable fields GitOpsDeploymentName, RevisionID, the rollback fields and the
+// sync options are not updated. If the user has set spec.terminate, the sync operation is terminated.
 //
 // Returns:
 // - error is non-nil, if an error occurred
-func (a *applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplSyncRunEvent(ctx context.Context, syncRunCR *managedgitopsv1alpha1.GitOpsDeploymentSyncRun, dbQueries db.ApplicationScopedQueries, syncOperation db.SyncOperation) gitopserrors.UserError {
+func (a *applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplSyncRunEvent(ctx context.Context, syncRunCR *managedgitopsv1alpha1.GitOpsDeploymentSyncRun, dbQueries db.ApplicationScopedQueries, syncOperation db.SyncOperation, gitopsEngineInstance *db.GitopsEngineInstance, clusterUser db.ClusterUser) gitopserrors.UserError {
 	log := a.log
 	log.Info("Received GitOpsDeploymentSyncRun event for an existing GitOpsDeploymentSyncRun resource")
 
@@ -636,12 +1120,38 @@ func (a *applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplSyncRunEvent(
 		return gitopserrors.NewUserDevError(ErrDeploymentNameIsImmutable, err)
 	}
 
-	if syncOperation.Revision != syncRunCR.Spec.RevisionID {
+	// The revision of a rollback is resolved from the deployment history, rather than specified by the user
+	isRollback := syncOperation.RollbackHistoryID != nil
+
+	if !isRollback && syncOperation.Revision != syncRunCR.Spec.RevisionID {
 		err := fmt.Errorf(ErrRevisionIsImmutable)
 		log.Error(err, ErrRevisionIsImmutable)
 		return gitopserrors.NewUserDevError(ErrRevisionIsImmutable, err)
 	}
 
+	if isRollback != syncRunCR.Spec.IsRollback() || (isRollback && syncRunCR.Spec.RollbackToHistoryID != nil &&
+		*syncRunCR.Spec.RollbackToHistoryID != *syncOperation.RollbackHistoryID) {
+		err := fmt.Errorf(managedgitopsv1alpha1.GitOpsDeploymentSyncRunUserError_RollbackIsImmutable)
+		log.Error(err, managedgitopsv1alpha1.GitOpsDeploymentSyncRunUserError_RollbackIsImmutable)
+		return gitopserrors.NewUserDevError(managedgitopsv1alpha1.GitOpsDeploymentSyncRunUserError_RollbackIsImmutable, err)
+	}
+
+	syncOptions, err := convertSyncRunOptionsToString(syncRunCR.Spec)
+	if err != nil {
+		log.Error(err, "unable to convert the options of GitOpsDeploymentSyncRun to JSON")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+	if syncOperation.Options != syncOptions {
+		err := fmt.Errorf(managedgitopsv1alpha1.GitOpsDeploymentSyncRunUserError_SyncOptionsImmutable)
+		log.Error(err, managedgitopsv1alpha1.GitOpsDeploymentSyncRunUserError_SyncOptionsImmutable)
+		return gitopserrors.NewUserDevError(managedgitopsv1alpha1.GitOpsDeploymentSyncRunUserError_SyncOptionsImmutable, err)
+	}
+
+	// The user requested that the sync be terminated, and it has not already been
+	if syncRunCR.Spec.Terminate && syncOperation.DesiredState == db.SyncOperation_DesiredState_Running {
+		return a.terminateGitOpsDeplSyncRun(ctx, syncRunCR, syncOperation, dbQueries, gitopsEngineInstance, clusterUser)
+	}
+
 	return nil
 }
 
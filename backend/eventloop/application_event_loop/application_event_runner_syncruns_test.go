@@ -2,7 +2,9 @@ package application_event_loop
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -10,6 +12,8 @@ import (
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -351,6 +355,392 @@ var _ = Describe("Application Event Runner SyncRuns", func() {
 			Expect(userDevErr.UserError()).Should(Equal(expectedErr))
 		})
 
+		It("should return an error for a suspended GitOpsDeployment", func() {
+
+			By("create a suspended GitOpsDeployment with Manual sync policy")
+			gitopsDeplSuspended := managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-depl-suspended",
+					Namespace: gitopsDepl.Namespace,
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+					Type:    managedgitopsv1alpha1.GitOpsDeploymentSpecType_Manual,
+					Suspend: true,
+				},
+			}
+
+			err := k8sClient.Create(ctx, &gitopsDeplSuspended)
+			Expect(err).To(BeNil())
+
+			By("create a SyncRun CR pointing to the above GitOpsDeployment")
+			syncRun := managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "syncrun",
+					Namespace: gitopsDeplSuspended.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+					GitopsDeploymentName: gitopsDeplSuspended.Name,
+				},
+			}
+
+			err = k8sClient.Create(ctx, &syncRun)
+			Expect(err).To(BeNil())
+
+			By("check if an error is returned")
+			expectedErr := fmt.Sprintf("invalid GitOpsDeploymentSyncRun '%s'. Syncing a suspended GitOpsDeployment is not allowed", syncRun.Name)
+
+			applicationAction.eventResourceName = "syncrun"
+			userDevErr := applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr.DevError().Error()).Should(Equal(expectedErr))
+			Expect(userDevErr.UserError()).Should(Equal(expectedErr))
+		})
+
+		It("should roll back a GitOpsDeployment to an entry of its deployment history", func() {
+
+			By("create a DeploymentHistory entry for the Application of the GitOpsDeployment")
+			deplToAppMapping := db.DeploymentToApplicationMapping{Deploymenttoapplicationmapping_uid_id: string(gitopsDepl.UID)}
+			err := dbQueries.GetDeploymentToApplicationMappingByDeplId(ctx, &deplToAppMapping)
+			Expect(err).To(BeNil())
+
+			historyID := int64(3)
+			deploymentHistory := db.DeploymentHistory{
+				Application_id: deplToAppMapping.Application_id,
+				Revision:       "abc123",
+				Source:         "{}",
+				Deployed_at:    time.Now(),
+				History_id:     &historyID,
+			}
+			err = dbQueries.CreateDeploymentHistory(ctx, &deploymentHistory)
+			Expect(err).To(BeNil())
+
+			By("create a SyncRun CR that rolls back to the history ID")
+			rollbackSyncRun := managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rollback-syncrun",
+					Namespace: gitopsDepl.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+					GitopsDeploymentName: gitopsDepl.Name,
+					RollbackToHistoryID:  &historyID,
+				},
+			}
+			err = k8sClient.Create(ctx, &rollbackSyncRun)
+			Expect(err).To(BeNil())
+
+			applicationAction.eventResourceName = rollbackSyncRun.Name
+			userDevErr := applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			By("verify that the SyncOperation rolls back to the revision of the history entry")
+			mapping := db.APICRToDatabaseMapping{
+				APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+				APIResourceUID:  string(rollbackSyncRun.UID),
+				DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+			}
+			err = dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping)
+			Expect(err).To(BeNil())
+
+			syncOperation := db.SyncOperation{SyncOperation_id: mapping.DBRelationKey}
+			err = dbQueries.GetSyncOperationById(ctx, &syncOperation)
+			Expect(err).To(BeNil())
+			Expect(syncOperation.Revision).To(Equal(deploymentHistory.Revision))
+			Expect(syncOperation.RollbackHistoryID).ToNot(BeNil())
+			Expect(*syncOperation.RollbackHistoryID).To(Equal(historyID))
+
+			By("verify that the rollback target is reported in the status of the SyncRun")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&rollbackSyncRun), &rollbackSyncRun)
+			Expect(err).To(BeNil())
+			Expect(rollbackSyncRun.Status.Rollback).To(Equal(&managedgitopsv1alpha1.GitOpsDeploymentSyncRunRollbackStatus{
+				HistoryID: historyID,
+				Revision:  deploymentHistory.Revision,
+				Phase:     managedgitopsv1alpha1.RollbackPhaseRunning,
+			}))
+
+			By("verify that a SyncRun that rolls back to an unknown revision returns an error")
+			unknownRevisionSyncRun := managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unknown-revision-syncrun",
+					Namespace: gitopsDepl.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+					GitopsDeploymentName: gitopsDepl.Name,
+					RollbackToRevision:   "unknown",
+				},
+			}
+			err = k8sClient.Create(ctx, &unknownRevisionSyncRun)
+			Expect(err).To(BeNil())
+
+			applicationAction.eventResourceName = unknownRevisionSyncRun.Name
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).ToNot(BeNil())
+			Expect(userDevErr.UserError()).To(Equal(fmt.Sprintf("unable to roll back: the deployment history of GitOpsDeployment '%s' does not contain revision 'unknown'", gitopsDepl.Name)))
+		})
+
+		It("should pass the sync options of a GitOpsDeploymentSyncRun to the SyncOperation, and report the result of the sync in its status", func() {
+
+			By("create a SyncRun CR with sync options")
+			optionsSyncRun := managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "options-syncrun",
+					Namespace: gitopsDepl.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+					GitopsDeploymentName: gitopsDepl.Name,
+					RevisionID:           "HEAD",
+					Prune:                true,
+					Force:                true,
+					Strategy:             managedgitopsv1alpha1.SyncRunStrategy_Apply,
+					SyncOptions:          managedgitopsv1alpha1.SyncOptions{managedgitopsv1alpha1.SyncOptions_Validate_false},
+					Resources: []managedgitopsv1alpha1.SyncOperationResource{
+						{Kind: "ConfigMap", Name: "my-config-map"},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, &optionsSyncRun)
+			Expect(err).To(BeNil())
+
+			applicationAction.eventResourceName = optionsSyncRun.Name
+			userDevErr := applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			By("verify that the options are stored in the SyncOperation")
+			mapping := db.APICRToDatabaseMapping{
+				APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+				APIResourceUID:  string(optionsSyncRun.UID),
+				DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+			}
+			err = dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping)
+			Expect(err).To(BeNil())
+
+			syncOperation := db.SyncOperation{SyncOperation_id: mapping.DBRelationKey}
+			err = dbQueries.GetSyncOperationById(ctx, &syncOperation)
+			Expect(err).To(BeNil())
+
+			var options fauxargocd.FauxSyncOperationOptions
+			err = json.Unmarshal([]byte(syncOperation.Options), &options)
+			Expect(err).To(BeNil())
+			Expect(options).To(Equal(fauxargocd.FauxSyncOperationOptions{
+				Prune:       true,
+				Force:       true,
+				Strategy:    "apply",
+				SyncOptions: fauxargocd.SyncOptions{"Validate=false"},
+				Resources:   []fauxargocd.FauxSyncOperationResource{{Kind: "ConfigMap", Name: "my-config-map"}},
+			}))
+
+			By("verify that the SyncRun reports that the sync has started")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&optionsSyncRun), &optionsSyncRun)
+			Expect(err).To(BeNil())
+			Expect(optionsSyncRun.Status.Phase).To(Equal(managedgitopsv1alpha1.OperationRunning))
+			Expect(optionsSyncRun.Status.StartedAt).ToNot(BeNil())
+
+			By("verify that the result of the Argo CD sync operation is copied to the status of the SyncRun")
+			finishedAt := metav1.Now()
+			operationState := &managedgitopsv1alpha1.OperationState{
+				Operation: managedgitopsv1alpha1.ApplicationOperation{
+					Info: []*managedgitopsv1alpha1.Info{{Name: argosharedutil.ArgoCDOperationInfoSyncOperationIDKey, Value: syncOperation.SyncOperation_id}},
+				},
+				Phase:      managedgitopsv1alpha1.OperationSucceeded,
+				Message:    "successfully synced (all tasks run)",
+				StartedAt:  metav1.Now(),
+				FinishedAt: &finishedAt,
+				SyncResult: &managedgitopsv1alpha1.SyncOperationResult{
+					Revision: "abc123",
+					Resources: managedgitopsv1alpha1.ResourceResults{
+						{Kind: "ConfigMap", Name: "my-config-map", Status: "Synced", Message: "configmap/my-config-map configured"},
+					},
+				},
+			}
+			err = updateSyncRunStatusFromOperationState(ctx, operationState, dbQueries, k8sClient, nil)
+			Expect(err).To(BeNil())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&optionsSyncRun), &optionsSyncRun)
+			Expect(err).To(BeNil())
+			Expect(optionsSyncRun.Status.Phase).To(Equal(managedgitopsv1alpha1.OperationSucceeded))
+			Expect(optionsSyncRun.Status.Message).To(Equal(operationState.Message))
+			Expect(optionsSyncRun.Status.StartedAt.Equal(&operationState.StartedAt)).To(BeTrue())
+			Expect(optionsSyncRun.Status.FinishedAt.Equal(&finishedAt)).To(BeTrue())
+			Expect(optionsSyncRun.Status.SyncedRevision).To(Equal("abc123"))
+			Expect(optionsSyncRun.Status.Resources).To(Equal(operationState.SyncResult.Resources))
+
+			By("verify that the sync options are immutable")
+			optionsSyncRun.Spec.Prune = false
+			err = k8sClient.Update(ctx, &optionsSyncRun)
+			Expect(err).To(BeNil())
+
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).ToNot(BeNil())
+			Expect(userDevErr.UserError()).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentSyncRunUserError_SyncOptionsImmutable))
+		})
+
+		It("should terminate the sync operation of a GitOpsDeploymentSyncRun when spec.terminate is set, and report the Terminated phase", func() {
+
+			mapping := db.APICRToDatabaseMapping{
+				APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+				APIResourceUID:  string(gitopsDeplSyncRun.UID),
+				DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+			}
+			err := dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping)
+			Expect(err).To(BeNil())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplSyncRun), gitopsDeplSyncRun)
+			Expect(err).To(BeNil())
+			Expect(gitopsDeplSyncRun.Status.Phase).To(Equal(managedgitopsv1alpha1.OperationRunning))
+
+			By("request that the sync be terminated")
+			informer.Events = nil
+			gitopsDeplSyncRun.Spec.Terminate = true
+			err = k8sClient.Update(ctx, gitopsDeplSyncRun)
+			Expect(err).To(BeNil())
+
+			userDevErr := applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			By("verify that the SyncOperation is marked as terminated")
+			syncOperation := db.SyncOperation{SyncOperation_id: mapping.DBRelationKey}
+			err = dbQueries.GetSyncOperationById(ctx, &syncOperation)
+			Expect(err).To(BeNil())
+			Expect(syncOperation.DesiredState).To(Equal(db.SyncOperation_DesiredState_Terminated))
+
+			By("verify that an Operation was created to terminate the sync operation")
+			operationCreated := false
+			for _, event := range informer.Events {
+				if event.Action == sharedutil.Create && event.ObjectTypeOf() == "Operation" {
+					k8sOperation, ok := (*event.Obj).(*managedgitopsv1alpha1.Operation)
+					Expect(ok).To(BeTrue())
+
+					dbOperation := db.Operation{Operation_id: k8sOperation.Spec.OperationID}
+					err = dbQueries.GetOperationById(ctx, &dbOperation)
+					Expect(err).To(BeNil())
+					Expect(dbOperation.Resource_type).To(Equal(db.OperationResourceType_TerminateSyncOperation))
+					Expect(dbOperation.Resource_id).To(Equal(syncOperation.SyncOperation_id))
+					operationCreated = true
+				}
+			}
+			Expect(operationCreated).To(BeTrue())
+
+			By("verify that the SyncRun reports the Terminated phase")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplSyncRun), gitopsDeplSyncRun)
+			Expect(err).To(BeNil())
+			Expect(gitopsDeplSyncRun.Status.Phase).To(Equal(managedgitopsv1alpha1.SyncRunPhaseTerminated))
+			Expect(gitopsDeplSyncRun.Status.FinishedAt).ToNot(BeNil())
+
+			By("verify that the Terminated phase is not replaced by the phase reported by Argo CD")
+			operationState := &managedgitopsv1alpha1.OperationState{
+				Operation: managedgitopsv1alpha1.ApplicationOperation{
+					Info: []*managedgitopsv1alpha1.Info{{Name: argosharedutil.ArgoCDOperationInfoSyncOperationIDKey, Value: syncOperation.SyncOperation_id}},
+				},
+				Phase:   managedgitopsv1alpha1.OperationFailed,
+				Message: "Operation terminated",
+			}
+			err = updateSyncRunStatusFromOperationState(ctx, operationState, dbQueries, k8sClient, nil)
+			Expect(err).To(BeNil())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplSyncRun), gitopsDeplSyncRun)
+			Expect(err).To(BeNil())
+			Expect(gitopsDeplSyncRun.Status.Phase).To(Equal(managedgitopsv1alpha1.SyncRunPhaseTerminated))
+		})
+
+		It("should disable the automated sync policy while a rollback SyncRun exists, and restore it once the SyncRun is deleted", func() {
+
+			By("create a GitOpsDeployment with Automated sync policy")
+			gitopsDeplAutomated := managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-depl-automated",
+					Namespace: gitopsDepl.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+					Type:   managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+					Source: gitopsDepl.Spec.Source,
+				},
+			}
+			err := k8sClient.Create(ctx, &gitopsDeplAutomated)
+			Expect(err).To(BeNil())
+
+			applicationAction.eventResourceName = gitopsDeplAutomated.Name
+			_, application, _, _, userDevErr := applicationAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+			Expect(application.Spec_field).To(ContainSubstring("automated:"))
+
+			historyID := int64(0)
+			deploymentHistory := db.DeploymentHistory{
+				Application_id: application.Application_id,
+				Revision:       "abc123",
+				Source:         "{}",
+				Deployed_at:    time.Now(),
+				History_id:     &historyID,
+			}
+			err = dbQueries.CreateDeploymentHistory(ctx, &deploymentHistory)
+			Expect(err).To(BeNil())
+
+			By("verify that a rollback SyncRun which doesn't disable the automated sync policy returns an error")
+			rollbackSyncRun := managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rollback-syncrun",
+					Namespace: gitopsDeplAutomated.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+					GitopsDeploymentName: gitopsDeplAutomated.Name,
+					RollbackToRevision:   deploymentHistory.Revision,
+				},
+			}
+			err = k8sClient.Create(ctx, &rollbackSyncRun)
+			Expect(err).To(BeNil())
+
+			applicationAction.eventResourceName = rollbackSyncRun.Name
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).ToNot(BeNil())
+			Expect(userDevErr.UserError()).To(Equal(fmt.Sprintf("invalid GitOpsDeploymentSyncRun '%s'. Rolling back a GitOpsDeployment with Automated sync policy requires spec.disableAutomatedSync to be set", rollbackSyncRun.Name)))
+
+			By("recreate the rollback SyncRun, disabling the automated sync policy")
+			err = k8sClient.Delete(ctx, &rollbackSyncRun)
+			Expect(err).To(BeNil())
+
+			rollbackSyncRun.ResourceVersion = ""
+			rollbackSyncRun.UID = uuid.NewUUID()
+			rollbackSyncRun.Spec.DisableAutomatedSync = true
+			err = k8sClient.Create(ctx, &rollbackSyncRun)
+			Expect(err).To(BeNil())
+
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			err = dbQueries.GetApplicationById(ctx, application)
+			Expect(err).To(BeNil())
+			Expect(application.Spec_field).ToNot(ContainSubstring("automated:"))
+
+			By("verify that the SyncOperation rolls back to the history ID of the revision")
+			mapping := db.APICRToDatabaseMapping{
+				APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+				APIResourceUID:  string(rollbackSyncRun.UID),
+				DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+			}
+			err = dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping)
+			Expect(err).To(BeNil())
+
+			syncOperation := db.SyncOperation{SyncOperation_id: mapping.DBRelationKey}
+			err = dbQueries.GetSyncOperationById(ctx, &syncOperation)
+			Expect(err).To(BeNil())
+			Expect(syncOperation.RollbackHistoryID).ToNot(BeNil())
+			Expect(*syncOperation.RollbackHistoryID).To(Equal(historyID))
+
+			By("delete the rollback SyncRun, and verify that the automated sync policy is restored")
+			err = k8sClient.Delete(ctx, &rollbackSyncRun)
+			Expect(err).To(BeNil())
+
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			err = dbQueries.GetApplicationById(ctx, application)
+			Expect(err).To(BeNil())
+			Expect(application.Spec_field).To(ContainSubstring("automated:"))
+		})
+
 		It("should return true shutdown signal if neither CR nor DB entry exists", func() {
 			By("delete the SyncRun CR and the relevant DB details")
 			err := k8sClient.Delete(ctx, gitopsDeplSyncRun)
@@ -376,6 +766,44 @@ var _ = Describe("Application Event Runner SyncRuns", func() {
 		})
 	})
 
+	Context("isAutomatedSyncDisabledBySyncRun should only consider the rollback GitOpsDeploymentSyncRuns of the GitOpsDeployment", func() {
+
+		It("should return true only if a rollback GitOpsDeploymentSyncRun of the GitOpsDeployment disables the automated sync", func() {
+			ctx := context.Background()
+
+			scheme, _, _, _, err := tests.GenericTestSetup()
+			Expect(err).To(BeNil())
+
+			historyID := int64(1)
+			newSyncRun := func(name string, gitopsDeplName string, disableAutomatedSync bool) *managedgitopsv1alpha1.GitOpsDeploymentSyncRun {
+				return &managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "jane"},
+					Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+						GitopsDeploymentName: gitopsDeplName,
+						RollbackToHistoryID:  &historyID,
+						DisableAutomatedSync: disableAutomatedSync,
+					},
+				}
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newSyncRun("rollback-other", "other-depl", true),
+				newSyncRun("rollback-enabled", "my-depl", false)).Build()
+
+			gitopsDepl := managedgitopsv1alpha1.GitOpsDeployment{ObjectMeta: metav1.ObjectMeta{Name: "my-depl", Namespace: "jane"}}
+
+			disabled, err := isAutomatedSyncDisabledBySyncRun(ctx, gitopsDepl, k8sClient)
+			Expect(err).To(BeNil())
+			Expect(disabled).To(BeFalse())
+
+			Expect(k8sClient.Create(ctx, newSyncRun("rollback-disabled", "my-depl", true))).To(Succeed())
+
+			disabled, err = isAutomatedSyncDisabledBySyncRun(ctx, gitopsDepl, k8sClient)
+			Expect(err).To(BeNil())
+			Expect(disabled).To(BeTrue())
+		})
+	})
+
 	Context("Set GitOpsDeploymentSyncRun conditions", func() {
 
 		var (
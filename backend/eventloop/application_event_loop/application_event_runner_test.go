@@ -317,7 +317,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 						Automated: true,
 					},
 					Retry: managedgitopsv1alpha1.RetryStrategy{
-						Limit: -1,
+						Limit: getInt64Pointer(-1),
 					},
 				},
 				SyncResult: &managedgitopsv1alpha1.SyncOperationResult{
@@ -355,6 +355,17 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 			err = dbQueries.CreateApplicationState(ctx, applicationState)
 			Expect(err).To(BeNil())
 
+			By("add a DeploymentHistory row for the Application")
+			deploymentHistory := &db.DeploymentHistory{
+				Application_id: deplToAppMapping.Application_id,
+				Revision:       "abcdefg",
+				Source:         `{"source":{"repoURL":"https://github.com/abc-org/abc-repo","path":"abc-path","targetRevision":"main"}}`,
+				Deployed_at:    time.Now().Add(-time.Hour),
+				Initiated_by:   db.DeploymentHistory_InitiatedBy_Automated,
+			}
+			err = dbQueries.CreateDeploymentHistory(ctx, deploymentHistory)
+			Expect(err).To(BeNil())
+
 			// ----------------------------------------------------------------------------
 			By("Retrieve latest version of GitOpsDeployment and check Health/Sync before calling applicationEventRunner_handleUpdateDeploymentStatusTick function.")
 			// ----------------------------------------------------------------------------
@@ -405,12 +416,31 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 			Expect(gitopsDeployment.Status.OperationState.Operation).To(Equal(operationState.Operation))
 			Expect(gitopsDeployment.Status.OperationState.SyncResult).To(Equal(operationState.SyncResult))
 
+			Expect(gitopsDeployment.Status.History).To(HaveLen(1))
+			Expect(gitopsDeployment.Status.History[0].Revision).To(Equal(deploymentHistory.Revision))
+			Expect(gitopsDeployment.Status.History[0].Source).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentSource{
+				RepoURL: "https://github.com/abc-org/abc-repo",
+				Path:    "abc-path",
+				Branch:  "main",
+			}))
+			Expect(gitopsDeployment.Status.History[0].DeployedAt.Unix()).To(Equal(deploymentHistory.Deployed_at.Unix()))
+			Expect(gitopsDeployment.Status.History[0].InitiatedBy).To(Equal(db.DeploymentHistory_InitiatedBy_Automated))
+			Expect(gitopsDeployment.Status.History[0].SyncRunName).To(BeEmpty())
+
 			matchingCondition, _ := conditions.NewConditionManager().FindCondition(&gitopsDeployment.Status.Conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionSyncError)
 			Expect(matchingCondition).ToNot(BeNil())
 			Expect(matchingCondition.Message).To(Equal(applicationState.SyncError))
 			Expect(matchingCondition.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
 			Expect(matchingCondition.Type).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentConditionSyncError))
 
+			By("Verify that the GitOpsDeployment is synced and healthy, but is not ready, due to the sync error")
+			matchingCondition, _ = conditions.NewConditionManager().FindCondition(&gitopsDeployment.Status.Conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionSynced)
+			Expect(matchingCondition.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+			matchingCondition, _ = conditions.NewConditionManager().FindCondition(&gitopsDeployment.Status.Conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionHealthy)
+			Expect(matchingCondition.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+			matchingCondition, _ = conditions.NewConditionManager().FindCondition(&gitopsDeployment.Status.Conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionReady)
+			Expect(matchingCondition.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+
 			By("Update SyncError in ApplicationState to be empty")
 			applicationState = &db.ApplicationState{
 				Applicationstate_application_id: deplToAppMapping.Application_id,
@@ -441,6 +471,10 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 			Expect(matchingCondition).ToNot(BeNil())
 			Expect(matchingCondition.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
 
+			By("Verify that the GitOpsDeployment is ready, now that the sync error is resolved")
+			matchingCondition, _ = conditions.NewConditionManager().FindCondition(&gitopsDeployment.Status.Conditions, managedgitopsv1alpha1.GitOpsDeploymentConditionReady)
+			Expect(matchingCondition.Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+
 			By("attempting to update the deployment status tick, even though nothing has changed.")
 			updated, err = a.applicationEventRunner_handleUpdateDeploymentStatusTick(ctx, gitopsDepl.Name, gitopsDepl.Namespace, dbQueries)
 			Expect(err).To(BeNil())
@@ -1106,7 +1140,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 						Automated: true,
 					},
 					Retry: managedgitopsv1alpha1.RetryStrategy{
-						Limit: -1,
+						Limit: getInt64Pointer(-1),
 					},
 				},
 				SyncResult: &managedgitopsv1alpha1.SyncOperationResult{
@@ -1142,7 +1176,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 
 			Expect(opStateOut).NotTo(BeNil())
 			Expect(opStateOut.Operation.InitiatedBy.Automated).To(BeTrue())
-			Expect(opStateOut.Operation.Retry.Limit).To(Equal(int64(-1)))
+			Expect(*opStateOut.Operation.Retry.Limit).To(Equal(int64(-1)))
 			Expect(opStateOut.SyncResult.Resources[0].Group).To(Equal(""))
 			Expect(opStateOut.SyncResult.Resources[0].HookPhase).To(Equal(managedgitopsv1alpha1.OperationRunning))
 			Expect(opStateOut.SyncResult.Resources[0].Namespace).To(Equal("jane"))
@@ -1347,6 +1381,25 @@ var _ = Describe("GitOpsDeployment Conditions", func() {
 		})
 	})
 
+	Context("setObservedGeneration()", func() {
+		It("should update the CR, if the generation has not yet been observed", func() {
+			gitopsDeployment.Generation = 2
+			gitopsDeployment.Status.ObservedGeneration = 1
+			matcher := testStructs.NewGitopsDeploymentMatcher()
+			mockClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(gomock.Any(), matcher, gomock.Any())
+			err := adapter.setObservedGeneration()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gitopsDeployment.Status.ObservedGeneration).To(Equal(int64(2)))
+		})
+		It("should not update the CR, if the generation has already been observed", func() {
+			gitopsDeployment.Generation = 2
+			gitopsDeployment.Status.ObservedGeneration = 2
+			err := adapter.setObservedGeneration()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 })
 
 type OperationCheck struct {
@@ -5,6 +5,7 @@ import (
 
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -26,10 +27,10 @@ type ControllerEventLoop struct {
 	EventLoopInputChannel chan eventlooptypes.EventLoopEvent
 }
 
-func NewControllerEventLoop() *ControllerEventLoop {
+func NewControllerEventLoop(eventRecorder record.EventRecorder) *ControllerEventLoop {
 
 	channel := make(chan eventlooptypes.EventLoopEvent)
-	go controllerEventLoopRouter(channel, defaultWorkspaceEventLoopRouterFactory{})
+	go controllerEventLoopRouter(channel, defaultWorkspaceEventLoopRouterFactory{eventRecorder: eventRecorder})
 
 	res := &ControllerEventLoop{
 		EventLoopInputChannel: channel,
@@ -113,12 +114,14 @@ type workspaceEventLoopRouterFactory interface {
 }
 
 type defaultWorkspaceEventLoopRouterFactory struct {
+	// eventRecorder is used to record Kubernetes Events on GitOps Service API resources
+	eventRecorder record.EventRecorder
 }
 
 var _ workspaceEventLoopRouterFactory = defaultWorkspaceEventLoopRouterFactory{}
 
 func (d defaultWorkspaceEventLoopRouterFactory) startWorkspaceEventLoopRouter(workspaceID string) WorkspaceEventLoopRouterStruct {
 
-	return newWorkspaceEventLoopRouter(workspaceID)
+	return newWorkspaceEventLoopRouter(workspaceID, d.eventRecorder)
 
 }
@@ -209,7 +209,17 @@ func cleanOrphanedEntriesfromTable_DTAM_DeleteEntry(ctx context.Context, deplToA
 		return err
 	}
 
-	// 2) Set the application field of SyncOperations to nil, for all SyncOperations that point to this Application
+	// 2) Remove the DeploymentHistory rows of the Application from the database
+	if err := deleteDbEntry(ctx, dbQueries, deplToAppMapping.Application_id, dbType_DeploymentHistory, log, deplToAppMapping); err != nil {
+		return err
+	}
+
+	// Remove any Preview rows of the Application from the database
+	if err := deleteDbEntry(ctx, dbQueries, deplToAppMapping.Application_id, dbType_Preview, log, deplToAppMapping); err != nil {
+		return err
+	}
+
+	// 3) Set the application field of SyncOperations to nil, for all SyncOperations that point to this Application
 	// - this ensures that the foreign key constraint of SyncOperation doesn't prevent us from deletion the Application
 	rowsUpdated, err := dbQueries.UpdateSyncOperationRemoveApplicationField(ctx, deplToAppMapping.Application_id)
 	if err != nil {
@@ -221,7 +231,7 @@ func cleanOrphanedEntriesfromTable_DTAM_DeleteEntry(ctx context.Context, deplToA
 		log.Info("Removed references to Application from all SyncOperations that reference it")
 	}
 
-	// 3) Delete DeplToAppMapping row that points to this Application
+	// 4) Delete DeplToAppMapping row that points to this Application
 	if err := deleteDbEntry(ctx, dbQueries, deplToAppMapping.Deploymenttoapplicationmapping_uid_id, dbType_DeploymentToApplicationMapping, log, deplToAppMapping); err != nil {
 		return err
 	}
@@ -234,7 +244,7 @@ func cleanOrphanedEntriesfromTable_DTAM_DeleteEntry(ctx context.Context, deplToA
 
 	// If the Application table entry still exists, finish the cleanup...
 
-	// 4) Remove the Application from the database
+	// 5) Remove the Application from the database
 	log.Info("GitOpsDeployment was deleted, so deleting Application row from database")
 	if err := deleteDbEntry(ctx, dbQueries, deplToAppMapping.Application_id, dbType_Application, log, deplToAppMapping); err != nil {
 		return err
@@ -471,6 +481,8 @@ const (
 	dbType_GitopsEngineCluster            dbTableName = "GitopsEngineCluster"
 	dbType_ClusterCredentials             dbTableName = "ClusterCredentials"
 	dbType_ApplicationOwner               dbTableName = "ApplicationOwner"
+	dbType_DeploymentHistory              dbTableName = "DeploymentHistory"
+	dbType_Preview                        dbTableName = "Preview"
 )
 
 // deleteDbEntry deletes database entry of a given CR
@@ -493,6 +505,10 @@ func deleteDbEntry(ctx context.Context, dbQueries db.DatabaseQueries, id string,
 		rowsDeleted, err = dbQueries.DeleteDeploymentToApplicationMappingByDeplId(ctx, id)
 	case dbType_ApplicationOwner:
 		rowsDeleted, err = dbQueries.DeleteApplicationOwner(ctx, id)
+	case dbType_DeploymentHistory:
+		rowsDeleted, err = dbQueries.DeleteDeploymentHistoryByApplicationId(ctx, id)
+	case dbType_Preview:
+		rowsDeleted, err = dbQueries.DeletePreviewsByApplicationId(ctx, id)
 	case dbType_Application:
 		rowsDeleted, err = dbQueries.DeleteApplicationById(ctx, id)
 	case dbType_SyncOperation:
@@ -821,6 +837,14 @@ func cleanOrphanedEntriesfromTable_Application(ctx context.Context, dbQueries db
 					log.Error(err, "Error occurred in cleanOrphanedEntriesfromTable_Application while deleting ApplicationState entry : "+applicationState.Applicationstate_application_id+" from DB.")
 				}
 
+				if err := deleteDbEntry(ctx, dbQueries, appDB.Application_id, dbType_DeploymentHistory, log, appDB); err != nil {
+					log.Error(err, "Error occurred in cleanOrphanedEntriesfromTable_Application while deleting DeploymentHistory entries : "+appDB.Application_id+" from DB.")
+				}
+
+				if err := deleteDbEntry(ctx, dbQueries, appDB.Application_id, dbType_Preview, log, appDB); err != nil {
+					log.Error(err, "Error occurred in cleanOrphanedEntriesfromTable_Application while deleting Preview entries : "+appDB.Application_id+" from DB.")
+				}
+
 				if err := deleteDbEntry(ctx, dbQueries, appDB.Application_id, dbType_Application, log, appDB); err != nil {
 					log.Error(err, "Error occurred in cleanOrphanedEntriesfromTable_Application while deleting Application entry : "+appDB.Application_id+" from DB.")
 				}
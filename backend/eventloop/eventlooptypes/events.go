@@ -0,0 +1,60 @@
+package eventlooptypes
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventRecorderName is the name of the component that records Kubernetes Events on GitOps Service API resources,
+// as shown in the 'From' column of 'kubectl describe'.
+const EventRecorderName = "managed-gitops-backend"
+
+// Reasons of the Kubernetes Events that are recorded on GitOps Service API resources, on deployment lifecycle transitions.
+const (
+	// EventReasonApplicationCreated is recorded on a GitOpsDeployment when its Argo CD Application is first created
+	EventReasonApplicationCreated = "ApplicationCreated"
+
+	// EventReasonSyncStarted is recorded on a GitOpsDeployment (and GitOpsDeploymentSyncRun) when a sync operation starts
+	EventReasonSyncStarted = "SyncStarted"
+
+	// EventReasonSyncSucceeded is recorded on a GitOpsDeployment (and GitOpsDeploymentSyncRun) when a sync operation succeeds
+	EventReasonSyncSucceeded = "SyncSucceeded"
+
+	// EventReasonSyncFailed is recorded on a GitOpsDeployment (and GitOpsDeploymentSyncRun) when a sync operation fails
+	EventReasonSyncFailed = "SyncFailed"
+
+	// EventReasonHealthDegraded is recorded on a GitOpsDeployment when the health of its resources becomes Degraded
+	EventReasonHealthDegraded = "HealthDegraded"
+
+	// EventReasonHealthRecovered is recorded on a GitOpsDeployment when the health of its resources recovers from Degraded
+	EventReasonHealthRecovered = "HealthRecovered"
+
+	// EventReasonEnvironmentConnectionFailed is recorded on a GitOpsDeploymentManagedEnvironment when the GitOps
+	// Service is unable to connect to the cluster
+	EventReasonEnvironmentConnectionFailed = "EnvironmentConnectionFailed"
+
+	// EventReasonCredentialsInvalid is recorded on a GitOpsDeploymentRepositoryCredential when the repository
+	// credentials are not valid
+	EventReasonCredentialsInvalid = "CredentialsInvalid"
+)
+
+// RecordEvent records a Kubernetes Event on the object. The event is not recorded if the recorder is nil: this
+// allows the event loops to be started by unit tests without an EventRecorder.
+func RecordEvent(recorder record.EventRecorder, object runtime.Object, eventType string, reason string, message string) {
+	if recorder == nil {
+		return
+	}
+
+	recorder.Event(object, eventType, reason, message)
+}
+
+// RecordWarningEvent records a Kubernetes Event of type Warning on the object. See RecordEvent.
+func RecordWarningEvent(recorder record.EventRecorder, object runtime.Object, reason string, message string) {
+	RecordEvent(recorder, object, corev1.EventTypeWarning, reason, message)
+}
+
+// RecordNormalEvent records a Kubernetes Event of type Normal on the object. See RecordEvent.
+func RecordNormalEvent(recorder record.EventRecorder, object runtime.Object, reason string, message string) {
+	RecordEvent(recorder, object, corev1.EventTypeNormal, reason, message)
+}
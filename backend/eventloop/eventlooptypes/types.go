@@ -20,11 +20,20 @@ const (
 	RepositoryCredentialModified EventLoopEventType = "RepositoryCredentialModified"
 	ManagedEnvironmentModified   EventLoopEventType = "ManagedEnvironmentModified"
 	SyncRunModified              EventLoopEventType = "SyncRunModified"
+	PreviewModified              EventLoopEventType = "PreviewModified"
 	UpdateDeploymentStatusTick   EventLoopEventType = "UpdateDeploymentStatusTick"
 )
 
 const KubeSystemNamespace = "kube-system"
 
+// GitOpsDeploymentRepoURLIndexKey is the field index of GitOpsDeployments, in the cache of the manager, by the
+// (normalized) repository URLs of their sources.
+const GitOpsDeploymentRepoURLIndexKey = "spec.sources.repoURL"
+
+// SyncRunGitOpsDeploymentNameIndexKey is the field index of GitOpsDeploymentSyncRuns, in the cache of the manager, by the
+// name of the GitOpsDeployment that they target (.spec.gitopsDeploymentName).
+const SyncRunGitOpsDeploymentNameIndexKey = "spec.gitopsDeploymentName"
+
 // EventLoopEvent tracks an event received from the controllers in the apis/managed-gitops/v1alpha1 package.
 // For example, when a GitOpsDeployment is created/modified/deleted, an EventLoopEvent is created and
 // is then processed by the event loops.
@@ -90,6 +99,7 @@ const (
 	GitOpsDeploymentSyncRunTypeName              GitOpsResourceType = "GitOpsDeploymentSyncRun"
 	GitOpsDeploymentRepositoryCredentialTypeName GitOpsResourceType = "GitOpsDeploymentRepositoryCredential"
 	GitOpsDeploymentManagedEnvironmentTypeName   GitOpsResourceType = "GitOpsDeploymentManagedEnvironmentTypeName"
+	GitOpsDeploymentPreviewTypeName              GitOpsResourceType = "GitOpsDeploymentPreview"
 )
 
 func GetWorkspaceIDFromNamespaceID(namespace corev1.Namespace) string {
@@ -0,0 +1,416 @@
+package notification_loop
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+)
+
+const (
+	// maxDeliveryAttempts is the number of attempts made to deliver a notification, before the delivery is marked as failed
+	maxDeliveryAttempts = 5
+
+	// deliveryTimeout is the timeout of a single attempt to deliver a notification
+	deliveryTimeout = 10 * time.Second
+
+	// defaultNotificationTemplate is the message template used when the GitOpsDeploymentNotification does not specify one
+	defaultNotificationTemplate = "GitOpsDeployment '{{.GitOpsDeployment}}' in namespace '{{.Namespace}}': {{.Message}}"
+
+	// cloudEventTypePrefix is the prefix of the type of the CloudEvents that are sent, followed by the trigger
+	cloudEventTypePrefix = "com.redhat.managed-gitops."
+)
+
+// notificationTemplateData contains the fields that are available to the message template of a GitOpsDeploymentNotification
+type notificationTemplateData struct {
+	Trigger          string
+	GitOpsDeployment string
+	Namespace        string
+	Environment      string
+	SyncStatus       string
+	HealthStatus     string
+	Revision         string
+	// Message is the message of the Kubernetes Event that caused the notification
+	Message string
+}
+
+func newNotificationTemplateData(trigger managedgitopsv1alpha1.NotificationTrigger, gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment,
+	message string) notificationTemplateData {
+
+	return notificationTemplateData{
+		Trigger:          string(trigger),
+		GitOpsDeployment: gitopsDeployment.Name,
+		Namespace:        gitopsDeployment.Namespace,
+		Environment:      gitopsDeployment.Spec.Destination.Environment,
+		SyncStatus:       string(gitopsDeployment.Status.Sync.Status),
+		HealthStatus:     string(gitopsDeployment.Status.Health.Status),
+		Revision:         gitopsDeployment.Status.Sync.Revision,
+		Message:          message,
+	}
+}
+
+// webhookPayload is the JSON object that is sent to sinks of type 'Webhook', and the data of the CloudEvents sent to sinks of type 'CloudEvents'
+type webhookPayload struct {
+	Trigger          string `json:"trigger"`
+	GitOpsDeployment string `json:"gitopsDeployment"`
+	Namespace        string `json:"namespace"`
+	Environment      string `json:"environment,omitempty"`
+	SyncStatus       string `json:"syncStatus,omitempty"`
+	HealthStatus     string `json:"healthStatus,omitempty"`
+	Revision         string `json:"revision,omitempty"`
+	Message          string `json:"message"`
+}
+
+// slackPayload is the JSON object that is sent to sinks of type 'Slack'
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// cloudEvent is a CloudEvent (v1.0) in structured content mode, that is sent to sinks of type 'CloudEvents'
+type cloudEvent struct {
+	SpecVersion     string         `json:"specversion"`
+	ID              string         `json:"id"`
+	Source          string         `json:"source"`
+	Type            string         `json:"type"`
+	Subject         string         `json:"subject"`
+	Time            string         `json:"time"`
+	DataContentType string         `json:"datacontenttype"`
+	Data            webhookPayload `json:"data"`
+}
+
+// notificationDeliveryTask sends a single notification to the sink of a GitOpsDeploymentNotification, and records
+// the result in its status.
+type notificationDeliveryTask struct {
+	k8sClient  client.Client
+	httpClient *http.Client
+
+	notification    types.NamespacedName
+	notificationUID types.UID
+
+	trigger managedgitopsv1alpha1.NotificationTrigger
+	key     string
+	data    notificationTemplateData
+
+	// attempts is the number of attempts made so far to deliver the notification
+	attempts int
+
+	log logr.Logger
+}
+
+func newNotificationDeliveryTask(k8sClient client.Client, notification managedgitopsv1alpha1.GitOpsDeploymentNotification,
+	trigger managedgitopsv1alpha1.NotificationTrigger, key string, data notificationTemplateData, log logr.Logger) *notificationDeliveryTask {
+
+	return &notificationDeliveryTask{
+		k8sClient:       k8sClient,
+		httpClient:      newSinkHTTPClient(),
+		notification:    client.ObjectKeyFromObject(&notification),
+		notificationUID: notification.UID,
+		trigger:         trigger,
+		key:             key,
+		data:            data,
+		log:             log.WithValues("notification", notification.Name, "key", key),
+	}
+}
+
+// taskName returns the name of the task in the task retry loop, which ensures a notification is only queued once at a time
+func (t *notificationDeliveryTask) taskName() string {
+	return fmt.Sprintf("deliver/%s/%s/%s", t.notification.Namespace, t.notification.Name, t.key)
+}
+
+// Returns true if the task should be retried, false otherwise, plus an error
+func (t *notificationDeliveryTask) PerformTask(taskContext context.Context) (bool, error) {
+	const retry, noRetry = true, false
+
+	notification := &managedgitopsv1alpha1.GitOpsDeploymentNotification{}
+	if err := t.k8sClient.Get(taskContext, t.notification, notification); err != nil {
+		if apierr.IsNotFound(err) {
+			// The GitOpsDeploymentNotification was deleted: there is no longer a need to send the notification
+			return noRetry, nil
+		}
+		return retry, err
+	}
+
+	if notification.UID != t.notificationUID {
+		// The GitOpsDeploymentNotification was deleted and recreated
+		return noRetry, nil
+	}
+
+	// Skip notifications that were already delivered (or that we already gave up on)
+	if delivery := findNotificationDelivery(notification.Status.Deliveries, t.key); delivery != nil &&
+		delivery.Phase != managedgitopsv1alpha1.NotificationDeliveryPhase_Retrying {
+		return noRetry, nil
+	}
+
+	t.attempts++
+
+	deliveryErr := t.deliver(taskContext, notification)
+
+	delivery := managedgitopsv1alpha1.NotificationDelivery{
+		Key:              t.key,
+		Trigger:          t.trigger,
+		GitOpsDeployment: t.data.GitOpsDeployment,
+		Phase:            managedgitopsv1alpha1.NotificationDeliveryPhase_Succeeded,
+		Attempts:         t.attempts,
+		LastAttemptTime:  metav1.Now(),
+	}
+
+	shouldRetry := noRetry
+	if deliveryErr != nil {
+		delivery.Message = deliveryErr.Error()
+		if t.attempts < maxDeliveryAttempts {
+			delivery.Phase = managedgitopsv1alpha1.NotificationDeliveryPhase_Retrying
+			shouldRetry = retry
+		} else {
+			delivery.Phase = managedgitopsv1alpha1.NotificationDeliveryPhase_Failed
+		}
+	}
+
+	if err := updateNotificationDeliveryStatus(taskContext, t.k8sClient, notification, delivery); err != nil {
+		// The notification is not sent again if only the status update failed, as that would send a duplicate
+		t.log.Error(err, "unable to update the delivery status of GitOpsDeploymentNotification")
+	}
+
+	if deliveryErr != nil {
+		return shouldRetry, fmt.Errorf("unable to deliver notification (attempt %d of %d): %v", t.attempts, maxDeliveryAttempts, deliveryErr)
+	}
+
+	t.log.Info("Delivered notification", "trigger", t.trigger)
+
+	return noRetry, nil
+}
+
+// deliver sends the notification to the sink of the GitOpsDeploymentNotification
+func (t *notificationDeliveryTask) deliver(ctx context.Context, notification *managedgitopsv1alpha1.GitOpsDeploymentNotification) error {
+
+	sinkURL, err := getNotificationSinkURL(ctx, t.k8sClient, notification)
+	if err != nil {
+		return err
+	}
+	if err := validateNotificationSinkURL(ctx, sinkURL); err != nil {
+		return err
+	}
+
+	message, err := renderNotificationMessage(notification.Spec.Template, t.data)
+	if err != nil {
+		return err
+	}
+
+	body, contentType, err := generateNotificationBody(notification.Spec.Sink.Type, t.key, t.data, message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sinkURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request to sink: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Only the status code is reported: the response body is not recorded in the status of the GitOpsDeploymentNotification,
+	// so that the sink cannot be used to read responses from other services.
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned HTTP status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// validateNotificationSinkURL returns an error if the URL is not an absolute http(s) URL, or if its host resolves to
+// an address that notifications may not be sent to (see isAllowedSinkIP).
+func validateNotificationSinkURL(ctx context.Context, sinkURL string) error {
+
+	if sinkURL == "" {
+		return fmt.Errorf("the sink does not specify a url or a secret")
+	}
+
+	parsedURL, err := url.Parse(sinkURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return fmt.Errorf("the url of the sink must be an absolute http or https URL")
+	}
+
+	ipAddrs, err := net.DefaultResolver.LookupIPAddr(ctx, parsedURL.Hostname())
+	if err != nil {
+		return fmt.Errorf("unable to resolve the host of the sink: %v", err)
+	}
+	for _, ipAddr := range ipAddrs {
+		if !isAllowedSinkIP(ipAddr.IP) {
+			return fmt.Errorf("the host of the sink resolves to a loopback, private, link-local or unspecified address, which notifications may not be sent to")
+		}
+	}
+
+	return nil
+}
+
+// isAllowedSinkIP returns true if notifications may be sent to the IP address: that is, if it is not a loopback,
+// private, link-local or unspecified address, so that a GitOpsDeploymentNotification cannot be used to send requests
+// to the services of the cluster, or of the cloud provider (for example, the instance metadata service).
+// It is a variable so that it may be replaced by unit tests, which send notifications to a local server.
+var isAllowedSinkIP = func(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified())
+}
+
+// newSinkHTTPClient returns the HTTP client that notifications are sent with:
+// - The address of every connection is checked with isAllowedSinkIP, after the host was resolved: this ensures
+// that a host that resolves to a different address after validateNotificationSinkURL (DNS rebinding) is still rejected.
+// - Proxies are not used, as the address of the sink could then not be checked.
+// - Redirects are not followed: a redirect response is treated as a failed delivery.
+func newSinkHTTPClient() *http.Client {
+
+	dialer := &net.Dialer{
+		Timeout: deliveryTimeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isAllowedSinkIP(ip) {
+				return fmt.Errorf("connections to address '%s' are not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// renderNotificationMessage renders the message template of a GitOpsDeploymentNotification, or the default template if it is empty
+func renderNotificationMessage(messageTemplate string, data notificationTemplateData) (string, error) {
+
+	if messageTemplate == "" {
+		messageTemplate = defaultNotificationTemplate
+	}
+
+	tmpl, err := template.New("notification").Option("missingkey=error").Parse(messageTemplate)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse the template: %v", err)
+	}
+
+	var res bytes.Buffer
+	if err := tmpl.Execute(&res, data); err != nil {
+		return "", fmt.Errorf("unable to render the template: %v", err)
+	}
+
+	return res.String(), nil
+}
+
+// generateNotificationBody returns the body, and content type, of the request sent to a sink of the given type
+func generateNotificationBody(sinkType managedgitopsv1alpha1.NotificationSinkType, key string, data notificationTemplateData,
+	message string) ([]byte, string, error) {
+
+	payload := webhookPayload{
+		Trigger:          data.Trigger,
+		GitOpsDeployment: data.GitOpsDeployment,
+		Namespace:        data.Namespace,
+		Environment:      data.Environment,
+		SyncStatus:       data.SyncStatus,
+		HealthStatus:     data.HealthStatus,
+		Revision:         data.Revision,
+		Message:          message,
+	}
+
+	var body any
+	contentType := "application/json"
+
+	switch sinkType {
+	case managedgitopsv1alpha1.NotificationSinkType_Webhook:
+		body = payload
+
+	case managedgitopsv1alpha1.NotificationSinkType_Slack:
+		body = slackPayload{Text: message}
+
+	case managedgitopsv1alpha1.NotificationSinkType_CloudEvents:
+		body = cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              data.Namespace + "/" + key,
+			Source:          fmt.Sprintf("/apis/managed-gitops.redhat.com/v1alpha1/namespaces/%s/gitopsdeployments/%s", data.Namespace, data.GitOpsDeployment),
+			Type:            cloudEventTypePrefix + data.Trigger,
+			Subject:         data.GitOpsDeployment,
+			Time:            time.Now().UTC().Format(time.RFC3339),
+			DataContentType: "application/json",
+			Data:            payload,
+		}
+		contentType = "application/cloudevents+json"
+
+	default:
+		return nil, "", fmt.Errorf("unsupported sink type '%s'", sinkType)
+	}
+
+	res, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to marshal the notification: %v", err)
+	}
+
+	return res, contentType, nil
+}
+
+// findNotificationDelivery returns the delivery with the given key, or nil if there is none
+func findNotificationDelivery(deliveries []managedgitopsv1alpha1.NotificationDelivery, key string) *managedgitopsv1alpha1.NotificationDelivery {
+	for idx := range deliveries {
+		if deliveries[idx].Key == key {
+			return &deliveries[idx]
+		}
+	}
+	return nil
+}
+
+// updateNotificationDeliveryStatus adds (or replaces) the delivery in the status of the GitOpsDeploymentNotification,
+// keeping only the most recent MaxNotificationDeliveries deliveries. Deliveries of other notifications may update the
+// status concurrently, so the update is retried on conflict.
+func updateNotificationDeliveryStatus(ctx context.Context, k8sClient client.Client, notification *managedgitopsv1alpha1.GitOpsDeploymentNotification,
+	delivery managedgitopsv1alpha1.NotificationDelivery) error {
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(notification), notification); err != nil {
+			return err
+		}
+
+		deliveries := []managedgitopsv1alpha1.NotificationDelivery{}
+		for _, existing := range notification.Status.Deliveries {
+			if existing.Key != delivery.Key {
+				deliveries = append(deliveries, existing)
+			}
+		}
+		deliveries = append(deliveries, delivery)
+
+		if len(deliveries) > managedgitopsv1alpha1.MaxNotificationDeliveries {
+			deliveries = deliveries[len(deliveries)-managedgitopsv1alpha1.MaxNotificationDeliveries:]
+		}
+
+		notification.Status.Deliveries = deliveries
+
+		return k8sClient.Status().Update(ctx, notification)
+	})
+}
@@ -0,0 +1,297 @@
+package notification_loop
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+)
+
+//+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=gitopsdeploymentnotifications,verbs=get;list;watch
+//+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=gitopsdeploymentnotifications/status,verbs=get;update;patch
+
+// The notification loop is responsible for sending notifications to the sinks of the GitOpsDeploymentNotifications
+// that subscribe to a deployment lifecycle transition (a trigger).
+//
+// The triggers are derived from the Kubernetes Events that the event loops record on GitOps Service API resources:
+// the EventRecorder returned by NewNotifyingEventRecorder records the event as usual, and then passes it to the
+// notification loop. For example, a 'SyncFailed' event on a GitOpsDeployment corresponds to the 'on-sync-failed' trigger.
+//
+// Notifications are sent via a TaskRetryLoop:
+//   - A dispatch task looks up the GitOpsDeploymentNotifications that subscribe to the trigger, for the GitOpsDeployment.
+//   - A delivery task is then started for each of those, which sends the notification to the sink, and records the
+//     result in the status of the GitOpsDeploymentNotification. Failed deliveries are retried with exponential backoff.
+//
+// Each notification has a key that identifies the transition it was sent for: the delivery tasks are named after
+// that key, and deliveries that are already recorded as complete in the status are not sent again.
+type NotificationLoop struct {
+	k8sClient     client.Client
+	taskRetryLoop *sharedutil.TaskRetryLoop
+	log           logr.Logger
+}
+
+// NewNotificationLoop returns a NotificationLoop that uses the given client to retrieve and update the
+// GitOpsDeploymentNotifications, and the GitOpsDeployments they subscribe to.
+func NewNotificationLoop(k8sClient client.Client) *NotificationLoop {
+	return &NotificationLoop{
+		k8sClient:     k8sClient,
+		taskRetryLoop: sharedutil.NewTaskRetryLoop("notification-retry-loop"),
+		log:           log.FromContext(context.Background()).WithName("notification-loop"),
+	}
+}
+
+// triggerForEvent returns the notification trigger corresponding to a Kubernetes Event with the given reason,
+// recorded on the given object, if any.
+func triggerForEvent(object runtime.Object, reason string) (managedgitopsv1alpha1.NotificationTrigger, bool) {
+
+	switch object.(type) {
+	case *managedgitopsv1alpha1.GitOpsDeployment:
+		switch reason {
+		case eventlooptypes.EventReasonSyncFailed:
+			return managedgitopsv1alpha1.NotificationTrigger_SyncFailed, true
+		case eventlooptypes.EventReasonHealthDegraded:
+			return managedgitopsv1alpha1.NotificationTrigger_HealthDegraded, true
+		case eventlooptypes.EventReasonSyncSucceeded:
+			return managedgitopsv1alpha1.NotificationTrigger_Deployed, true
+		}
+
+	case *managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment:
+		if reason == eventlooptypes.EventReasonEnvironmentConnectionFailed {
+			return managedgitopsv1alpha1.NotificationTrigger_EnvDisconnected, true
+		}
+	}
+
+	return "", false
+}
+
+// Notify queues the notifications for the Kubernetes Event with the given reason and message, recorded on the
+// given object. Events that do not correspond to a notification trigger are ignored.
+//
+// This function is async: the GitOpsDeploymentNotifications are retrieved, and the notifications sent, by the task retry loop.
+func (nl *NotificationLoop) Notify(object runtime.Object, reason string, message string) {
+
+	trigger, ok := triggerForEvent(object, reason)
+	if !ok {
+		return
+	}
+
+	clientObj, ok := object.DeepCopyObject().(client.Object)
+	if !ok {
+		return
+	}
+
+	task := &notificationDispatchTask{
+		k8sClient:     nl.k8sClient,
+		taskRetryLoop: nl.taskRetryLoop,
+		object:        clientObj,
+		trigger:       trigger,
+		message:       message,
+		log:           nl.log.WithValues("trigger", trigger, "namespace", clientObj.GetNamespace(), "name", clientObj.GetName()),
+	}
+
+	// The resource version identifies the status update that caused the event to be recorded
+	taskName := fmt.Sprintf("dispatch/%s/%s/%s/%s", trigger, clientObj.GetNamespace(), clientObj.GetName(), clientObj.GetResourceVersion())
+
+	nl.taskRetryLoop.AddTaskIfNotPresent(taskName, task, sharedutil.ExponentialBackoff{Factor: 2, Min: time.Millisecond * 200, Max: time.Second * 10, Jitter: true})
+}
+
+// notifyingEventRecorder records Kubernetes Events with the wrapped EventRecorder, and then passes them to the notification loop.
+type notifyingEventRecorder struct {
+	record.EventRecorder
+	notificationLoop *NotificationLoop
+}
+
+// NewNotifyingEventRecorder returns an EventRecorder that records events with the given EventRecorder, and sends
+// notifications to the subscribed GitOpsDeploymentNotifications for events that correspond to a notification trigger.
+func NewNotifyingEventRecorder(eventRecorder record.EventRecorder, notificationLoop *NotificationLoop) record.EventRecorder {
+	return &notifyingEventRecorder{
+		EventRecorder:    eventRecorder,
+		notificationLoop: notificationLoop,
+	}
+}
+
+func (r *notifyingEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.notificationLoop.Notify(object, reason, message)
+}
+
+func (r *notifyingEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// notificationDispatchTask retrieves the GitOpsDeploymentNotifications that subscribe to a trigger, and queues a
+// delivery task for each of them.
+type notificationDispatchTask struct {
+	k8sClient     client.Client
+	taskRetryLoop *sharedutil.TaskRetryLoop
+
+	// object is the GitOpsDeployment, or GitOpsDeploymentManagedEnvironment, that the event was recorded on
+	object  client.Object
+	trigger managedgitopsv1alpha1.NotificationTrigger
+	message string
+
+	log logr.Logger
+}
+
+// Returns true if the task should be retried, false otherwise, plus an error
+func (t *notificationDispatchTask) PerformTask(taskContext context.Context) (bool, error) {
+	const retry, noRetry = true, false
+
+	var notificationList managedgitopsv1alpha1.GitOpsDeploymentNotificationList
+	if err := t.k8sClient.List(taskContext, &notificationList, &client.ListOptions{Namespace: t.object.GetNamespace()}); err != nil {
+		return retry, fmt.Errorf("unable to list GitOpsDeploymentNotifications: %v", err)
+	}
+
+	if len(notificationList.Items) == 0 {
+		return noRetry, nil
+	}
+
+	gitopsDeployments, err := t.getGitOpsDeploymentsOfEvent(taskContext)
+	if err != nil {
+		return retry, err
+	}
+
+	for idx := range notificationList.Items {
+		notification := notificationList.Items[idx]
+
+		if !subscribesToTrigger(notification, t.trigger) {
+			continue
+		}
+
+		for _, gitopsDeployment := range gitopsDeployments {
+
+			selected, err := selectsGitOpsDeployment(notification, gitopsDeployment)
+			if err != nil {
+				t.log.Error(err, "unable to determine whether the GitOpsDeploymentNotification selects the GitOpsDeployment", "notification", notification.Name)
+				continue
+			}
+			if !selected {
+				continue
+			}
+
+			deliveryTask := newNotificationDeliveryTask(t.k8sClient, notification, t.trigger, t.dedupeKey(gitopsDeployment),
+				newNotificationTemplateData(t.trigger, gitopsDeployment, t.message), t.log)
+
+			t.taskRetryLoop.AddTaskIfNotPresent(deliveryTask.taskName(), deliveryTask, sharedutil.ExponentialBackoff{Factor: 2, Min: time.Second, Max: time.Minute, Jitter: true})
+
+			t.log.V(logutil.LogLevel_Debug).Info("Queued notification", "notification", notification.Name, "gitopsDeployment", gitopsDeployment.Name)
+		}
+	}
+
+	return noRetry, nil
+}
+
+// getGitOpsDeploymentsOfEvent returns the GitOpsDeployments affected by the event: either the GitOpsDeployment that
+// the event was recorded on, or the GitOpsDeployments that are deployed to the GitOpsDeploymentManagedEnvironment that
+// the event was recorded on.
+func (t *notificationDispatchTask) getGitOpsDeploymentsOfEvent(ctx context.Context) ([]managedgitopsv1alpha1.GitOpsDeployment, error) {
+
+	switch object := t.object.(type) {
+	case *managedgitopsv1alpha1.GitOpsDeployment:
+		return []managedgitopsv1alpha1.GitOpsDeployment{*object}, nil
+
+	case *managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment:
+		var gitopsDeploymentList managedgitopsv1alpha1.GitOpsDeploymentList
+		if err := t.k8sClient.List(ctx, &gitopsDeploymentList, &client.ListOptions{Namespace: object.Namespace}); err != nil {
+			return nil, fmt.Errorf("unable to list GitOpsDeployments: %v", err)
+		}
+
+		res := []managedgitopsv1alpha1.GitOpsDeployment{}
+		for _, gitopsDeployment := range gitopsDeploymentList.Items {
+			if gitopsDeployment.Spec.Destination.Environment == object.Name {
+				res = append(res, gitopsDeployment)
+			}
+		}
+		return res, nil
+	}
+
+	return nil, nil
+}
+
+// dedupeKey returns the key that identifies the notification of the transition for the given GitOpsDeployment.
+// The resource version of the object that the event was recorded on identifies the status update that caused the transition.
+func (t *notificationDispatchTask) dedupeKey(gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment) string {
+
+	if _, isManagedEnv := t.object.(*managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment); isManagedEnv {
+		return fmt.Sprintf("%s/%s/%s/%s", t.trigger, gitopsDeployment.Name, t.object.GetName(), t.object.GetResourceVersion())
+	}
+
+	return fmt.Sprintf("%s/%s/%s", t.trigger, gitopsDeployment.Name, t.object.GetResourceVersion())
+}
+
+// subscribesToTrigger returns true if the GitOpsDeploymentNotification subscribes to the trigger, false otherwise.
+func subscribesToTrigger(notification managedgitopsv1alpha1.GitOpsDeploymentNotification, trigger managedgitopsv1alpha1.NotificationTrigger) bool {
+	for _, notificationTrigger := range notification.Spec.Triggers {
+		if notificationTrigger == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsGitOpsDeployment returns true if the GitOpsDeploymentNotification selects the GitOpsDeployment, via its
+// list of GitOpsDeployment names and/or its label selector, false otherwise.
+func selectsGitOpsDeployment(notification managedgitopsv1alpha1.GitOpsDeploymentNotification, gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment) (bool, error) {
+
+	if len(notification.Spec.GitOpsDeployments) > 0 {
+		found := false
+		for _, name := range notification.Spec.GitOpsDeployments {
+			if name == gitopsDeployment.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	if notification.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(notification.Spec.Selector)
+		if err != nil {
+			return false, fmt.Errorf("invalid selector: %v", err)
+		}
+		if !selector.Matches(labels.Set(gitopsDeployment.Labels)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// getNotificationSinkURL returns the URL of the sink of the GitOpsDeploymentNotification, either from its spec, or from the referenced Secret.
+func getNotificationSinkURL(ctx context.Context, k8sClient client.Client, notification *managedgitopsv1alpha1.GitOpsDeploymentNotification) (string, error) {
+
+	if notification.Spec.Sink.Secret == "" {
+		return notification.Spec.Sink.URL, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: notification.Namespace, Name: notification.Spec.Sink.Secret}, secret); err != nil {
+		if apierr.IsNotFound(err) {
+			return "", fmt.Errorf("secret '%s' of the sink was not found", notification.Spec.Sink.Secret)
+		}
+		return "", fmt.Errorf("unable to retrieve Secret '%s' of the sink: %v", notification.Spec.Sink.Secret, err)
+	}
+
+	url := string(secret.Data["url"])
+	if url == "" {
+		return "", fmt.Errorf("secret '%s' of the sink does not contain a 'url' value", notification.Spec.Sink.Secret)
+	}
+
+	return url, nil
+}
@@ -0,0 +1,13 @@
+package notification_loop_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestNotificationLoop(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Notification Loop Suite")
+}
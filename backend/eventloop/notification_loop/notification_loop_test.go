@@ -0,0 +1,296 @@
+package notification_loop
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// receivedNotification is a request received by the local sink
+type receivedNotification struct {
+	contentType string
+	body        map[string]any
+}
+
+var _ = Describe("Notification Loop", func() {
+
+	var (
+		ctx          context.Context
+		k8sClient    client.Client
+		sink         *httptest.Server
+		sinkStatus   int
+		received     []receivedNotification
+		receivedLock sync.Mutex
+		gitopsDepl   *managedgitopsv1alpha1.GitOpsDeployment
+		notification *managedgitopsv1alpha1.GitOpsDeploymentNotification
+
+		originalIsAllowedSinkIP = isAllowedSinkIP
+	)
+
+	receivedNotifications := func() []receivedNotification {
+		receivedLock.Lock()
+		defer receivedLock.Unlock()
+		return append([]receivedNotification{}, received...)
+	}
+
+	getNotification := func() *managedgitopsv1alpha1.GitOpsDeploymentNotification {
+		res := &managedgitopsv1alpha1.GitOpsDeploymentNotification{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(notification), res)).To(Succeed())
+		return res
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		// The sink is a local server, which notifications may not otherwise be sent to
+		isAllowedSinkIP = func(ip net.IP) bool { return true }
+
+		receivedLock.Lock()
+		received = nil
+		sinkStatus = http.StatusOK
+		receivedLock.Unlock()
+
+		sink = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedLock.Lock()
+			defer receivedLock.Unlock()
+
+			bodyBytes, err := io.ReadAll(r.Body)
+			Expect(err).To(BeNil())
+
+			body := map[string]any{}
+			Expect(json.Unmarshal(bodyBytes, &body)).To(Succeed())
+
+			received = append(received, receivedNotification{contentType: r.Header.Get("Content-Type"), body: body})
+			w.WriteHeader(sinkStatus)
+		}))
+
+		scheme, _, _, workspace, err := tests.GenericTestSetup()
+		Expect(err).To(BeNil())
+
+		gitopsDepl = &managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "my-gitops-depl",
+				Namespace:       workspace.Name,
+				ResourceVersion: "100",
+				Labels:          map[string]string{"team": "a"},
+			},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Destination: managedgitopsv1alpha1.ApplicationDestination{Environment: "staging"},
+			},
+			Status: managedgitopsv1alpha1.GitOpsDeploymentStatus{
+				Sync:   managedgitopsv1alpha1.SyncStatus{Status: managedgitopsv1alpha1.SyncStatusCodeOutOfSync, Revision: "abc123"},
+				Health: managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeMissing},
+			},
+		}
+
+		notification = &managedgitopsv1alpha1.GitOpsDeploymentNotification{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-notification",
+				Namespace: workspace.Name,
+				UID:       "notification-uid",
+			},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentNotificationSpec{
+				Triggers: []managedgitopsv1alpha1.NotificationTrigger{managedgitopsv1alpha1.NotificationTrigger_SyncFailed,
+					managedgitopsv1alpha1.NotificationTrigger_EnvDisconnected},
+				Sink: managedgitopsv1alpha1.NotificationSink{
+					Type: managedgitopsv1alpha1.NotificationSinkType_Webhook,
+					URL:  sink.URL,
+				},
+			},
+		}
+
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace, notification).Build()
+	})
+
+	AfterEach(func() {
+		sink.Close()
+		isAllowedSinkIP = originalIsAllowedSinkIP
+	})
+
+	It("should send a notification when an event corresponding to a subscribed trigger is recorded, and record the delivery", func() {
+
+		eventRecorder := NewNotifyingEventRecorder(record.NewFakeRecorder(10), NewNotificationLoop(k8sClient))
+
+		By("ignoring events that correspond to a trigger that the notification does not subscribe to")
+		eventlooptypes.RecordNormalEvent(eventRecorder, gitopsDepl, eventlooptypes.EventReasonSyncSucceeded, "Sync operation succeeded")
+
+		eventlooptypes.RecordWarningEvent(eventRecorder, gitopsDepl, eventlooptypes.EventReasonSyncFailed, "Sync operation failed: boom")
+
+		Eventually(receivedNotifications, "10s", "100ms").Should(HaveLen(1))
+		Consistently(receivedNotifications, "1s", "100ms").Should(HaveLen(1))
+
+		notif := receivedNotifications()[0]
+		Expect(notif.contentType).To(Equal("application/json"))
+		Expect(notif.body["trigger"]).To(Equal("on-sync-failed"))
+		Expect(notif.body["gitopsDeployment"]).To(Equal(gitopsDepl.Name))
+		Expect(notif.body["environment"]).To(Equal("staging"))
+		Expect(notif.body["message"]).To(Equal("GitOpsDeployment 'my-gitops-depl' in namespace '" + gitopsDepl.Namespace + "': Sync operation failed: boom"))
+
+		Eventually(func() []managedgitopsv1alpha1.NotificationDelivery {
+			return getNotification().Status.Deliveries
+		}, "10s", "100ms").Should(HaveLen(1))
+
+		delivery := getNotification().Status.Deliveries[0]
+		Expect(delivery.Key).To(Equal("on-sync-failed/my-gitops-depl/100"))
+		Expect(delivery.Phase).To(Equal(managedgitopsv1alpha1.NotificationDeliveryPhase_Succeeded))
+		Expect(delivery.Attempts).To(Equal(1))
+	})
+
+	It("should send a notification for each selected GitOpsDeployment deployed to a disconnected environment", func() {
+
+		gitopsDepl.ResourceVersion = ""
+		otherDepl := gitopsDepl.DeepCopy()
+		otherDepl.Name = "other-gitops-depl"
+		otherDepl.Labels = map[string]string{"team": "b"}
+		Expect(k8sClient.Create(ctx, gitopsDepl)).To(Succeed())
+		Expect(k8sClient.Create(ctx, otherDepl)).To(Succeed())
+
+		notification.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+		Expect(k8sClient.Update(ctx, notification)).To(Succeed())
+
+		managedEnv := &managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: gitopsDepl.Namespace, ResourceVersion: "7"},
+		}
+
+		NewNotificationLoop(k8sClient).Notify(managedEnv, eventlooptypes.EventReasonEnvironmentConnectionFailed, "Unable to connect to the cluster")
+
+		Eventually(receivedNotifications, "10s", "100ms").Should(HaveLen(1))
+		Consistently(receivedNotifications, "1s", "100ms").Should(HaveLen(1))
+		Expect(receivedNotifications()[0].body["trigger"]).To(Equal("on-env-disconnected"))
+		Expect(receivedNotifications()[0].body["gitopsDeployment"]).To(Equal(gitopsDepl.Name))
+	})
+
+	It("should retry a failed delivery, and mark it as failed after the maximum number of attempts", func() {
+		receivedLock.Lock()
+		sinkStatus = http.StatusInternalServerError
+		receivedLock.Unlock()
+
+		task := newNotificationDeliveryTask(k8sClient, *notification, managedgitopsv1alpha1.NotificationTrigger_SyncFailed, "my-key",
+			newNotificationTemplateData(managedgitopsv1alpha1.NotificationTrigger_SyncFailed, *gitopsDepl, "failed"), log.FromContext(ctx))
+
+		for attempt := 1; attempt < maxDeliveryAttempts; attempt++ {
+			shouldRetry, err := task.PerformTask(ctx)
+			Expect(err).ToNot(BeNil())
+			Expect(shouldRetry).To(BeTrue())
+			Expect(getNotification().Status.Deliveries[0].Phase).To(Equal(managedgitopsv1alpha1.NotificationDeliveryPhase_Retrying))
+		}
+
+		shouldRetry, err := task.PerformTask(ctx)
+		Expect(err).ToNot(BeNil())
+		Expect(shouldRetry).To(BeFalse())
+
+		delivery := getNotification().Status.Deliveries[0]
+		Expect(delivery.Phase).To(Equal(managedgitopsv1alpha1.NotificationDeliveryPhase_Failed))
+		Expect(delivery.Attempts).To(Equal(maxDeliveryAttempts))
+		Expect(delivery.Message).To(ContainSubstring("sink returned HTTP status 500"))
+		Expect(receivedNotifications()).To(HaveLen(maxDeliveryAttempts))
+
+		By("not sending the notification again, once the delivery has completed")
+		shouldRetry, err = task.PerformTask(ctx)
+		Expect(err).To(BeNil())
+		Expect(shouldRetry).To(BeFalse())
+		Expect(receivedNotifications()).To(HaveLen(maxDeliveryAttempts))
+	})
+
+	It("should send Slack and CloudEvents notifications, using the template and the URL from the sink Secret", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sink-secret", Namespace: notification.Namespace},
+			Data:       map[string][]byte{"url": []byte(sink.URL)},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+		notification.Spec.Sink = managedgitopsv1alpha1.NotificationSink{Type: managedgitopsv1alpha1.NotificationSinkType_Slack, Secret: secret.Name}
+		notification.Spec.Template = "{{.GitOpsDeployment}} is {{.SyncStatus}} at {{.Revision}}"
+		Expect(k8sClient.Update(ctx, notification)).To(Succeed())
+
+		data := newNotificationTemplateData(managedgitopsv1alpha1.NotificationTrigger_SyncFailed, *gitopsDepl, "failed")
+
+		task := newNotificationDeliveryTask(k8sClient, *notification, managedgitopsv1alpha1.NotificationTrigger_SyncFailed, "slack-key", data, log.FromContext(ctx))
+		shouldRetry, err := task.PerformTask(ctx)
+		Expect(err).To(BeNil())
+		Expect(shouldRetry).To(BeFalse())
+		Expect(receivedNotifications()[0].body).To(Equal(map[string]any{"text": "my-gitops-depl is OutOfSync at abc123"}))
+
+		notification = getNotification()
+		notification.Spec.Sink.Type = managedgitopsv1alpha1.NotificationSinkType_CloudEvents
+		Expect(k8sClient.Update(ctx, notification)).To(Succeed())
+
+		task = newNotificationDeliveryTask(k8sClient, *notification, managedgitopsv1alpha1.NotificationTrigger_SyncFailed, "cloudevents-key", data, log.FromContext(ctx))
+		_, err = task.PerformTask(ctx)
+		Expect(err).To(BeNil())
+
+		cloudEvent := receivedNotifications()[1]
+		Expect(cloudEvent.contentType).To(Equal("application/cloudevents+json"))
+		Expect(cloudEvent.body["specversion"]).To(Equal("1.0"))
+		Expect(cloudEvent.body["type"]).To(Equal("com.redhat.managed-gitops.on-sync-failed"))
+		Expect(cloudEvent.body["id"]).To(Equal(notification.Namespace + "/cloudevents-key"))
+		Expect(cloudEvent.body["data"]).To(HaveKeyWithValue("message", "my-gitops-depl is OutOfSync at abc123"))
+	})
+
+	It("should not send a notification with an invalid template or sink URL", func() {
+		_, err := renderNotificationMessage("{{.DoesNotExist}}", notificationTemplateData{})
+		Expect(err).ToNot(BeNil())
+
+		Expect(validateNotificationSinkURL(ctx, "")).ToNot(Succeed())
+		Expect(validateNotificationSinkURL(ctx, "ftp://example.com")).ToNot(Succeed())
+		Expect(validateNotificationSinkURL(ctx, "https://203.0.113.10/services/abc")).To(Succeed())
+	})
+
+	It("should not send notifications to loopback, private, link-local or unspecified addresses", func() {
+		isAllowedSinkIP = originalIsAllowedSinkIP
+
+		for _, sinkURL := range []string{"http://127.0.0.1:8080/", "http://localhost/", "http://[::1]/", "http://10.0.0.1/",
+			"http://192.168.1.1/", "http://172.16.0.1/", "http://169.254.169.254/latest/meta-data/", "http://[fe80::1]/",
+			"http://0.0.0.0/"} {
+			Expect(validateNotificationSinkURL(ctx, sinkURL)).ToNot(Succeed(), sinkURL)
+		}
+
+		By("verifying that the address is also checked when connecting, in case the host resolves to a different address")
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, strings.NewReader("{}"))
+		Expect(err).To(BeNil())
+		_, err = newSinkHTTPClient().Do(req)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("are not allowed"))
+		Expect(receivedNotifications()).To(BeEmpty())
+	})
+
+	It("should not follow redirects, or record the response body of the sink", func() {
+		redirectTarget := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Fail("the redirect should not be followed")
+		}))
+		defer redirectTarget.Close()
+
+		redirectingSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, redirectTarget.URL, http.StatusTemporaryRedirect)
+		}))
+		defer redirectingSink.Close()
+
+		notification.Spec.Sink.URL = redirectingSink.URL
+		Expect(k8sClient.Update(ctx, notification)).To(Succeed())
+
+		task := newNotificationDeliveryTask(k8sClient, *notification, managedgitopsv1alpha1.NotificationTrigger_SyncFailed, "redirect-key",
+			newNotificationTemplateData(managedgitopsv1alpha1.NotificationTrigger_SyncFailed, *gitopsDepl, "failed"), log.FromContext(ctx))
+		_, err := task.PerformTask(ctx)
+		Expect(err).ToNot(BeNil())
+
+		delivery := getNotification().Status.Deliveries[0]
+		Expect(delivery.Message).To(Equal("sink returned HTTP status 307"))
+	})
+})
@@ -8,6 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -29,6 +30,9 @@ const (
 type RepoCredReconciler struct {
 	client.Client
 	DB db.DatabaseQueries
+
+	// EventRecorder is used to record Kubernetes Events on the GitOpsDeploymentRepositoryCredentials. May be nil.
+	EventRecorder record.EventRecorder
 }
 
 // This function iterates through each entry of RepositoryCredential table in DB and updates the status of the CR.
@@ -50,7 +54,7 @@ func (r *RepoCredReconciler) startTimerForNextCycle() {
 		_, _ = sharedutil.CatchPanic(func() error {
 
 			// Reconcile RepositoryCredentials here
-			reconcileRepositoryCredentials(ctx, r.DB, r.Client, log)
+			reconcileRepositoryCredentials(ctx, r.DB, r.Client, r.EventRecorder, log)
 
 			return nil
 		})
@@ -66,7 +70,7 @@ func (r *RepoCredReconciler) startTimerForNextCycle() {
 // Reconcile logic for API CR To Database Mapping table and utility functions.
 // This will reconcile repository credential entries from ACTDM table and RepoistoryCredential table
 // /////////////
-func reconcileRepositoryCredentials(ctx context.Context, dbQueries db.DatabaseQueries, client client.Client, l logr.Logger) {
+func reconcileRepositoryCredentials(ctx context.Context, dbQueries db.DatabaseQueries, client client.Client, eventRecorder record.EventRecorder, l logr.Logger) {
 
 	offSet := 0
 	log := l.WithValues("job", "reconcileRepositoryCredentials")
@@ -103,7 +107,7 @@ func reconcileRepositoryCredentials(ctx context.Context, dbQueries db.DatabaseQu
 			if db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentRepositoryCredential == apiCrToDbMappingFromDB.APIResourceType {
 
 				// Process if CR is of GitOpsDeploymentRepositoryCredential type.
-				reconcileRepositoryCredentialStatus(ctx, client, dbQueries, apiCrToDbMappingFromDB, objectMeta, log)
+				reconcileRepositoryCredentialStatus(ctx, client, eventRecorder, dbQueries, apiCrToDbMappingFromDB, objectMeta, log)
 			}
 
 			log.Info("RepositoryCredential ACTDM Reconcile processed APICRToDatabaseMapping entry: " + apiCrToDbMappingFromDB.APIResourceUID)
@@ -114,7 +118,7 @@ func reconcileRepositoryCredentials(ctx context.Context, dbQueries db.DatabaseQu
 	}
 }
 
-func reconcileRepositoryCredentialStatus(ctx context.Context, apiNamespaceClient client.Client, dbQueries db.DatabaseQueries, apiCrToDbMappingFromDB db.APICRToDatabaseMapping, objectMeta metav1.ObjectMeta, l logr.Logger) {
+func reconcileRepositoryCredentialStatus(ctx context.Context, apiNamespaceClient client.Client, eventRecorder record.EventRecorder, dbQueries db.DatabaseQueries, apiCrToDbMappingFromDB db.APICRToDatabaseMapping, objectMeta metav1.ObjectMeta, l logr.Logger) {
 
 	gitopsDeploymentRepositoryCredentialCR := managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential{ObjectMeta: objectMeta}
 	log := l.WithValues("job", "reconcileRepositoryCredentialStatus")
@@ -127,7 +131,7 @@ func reconcileRepositoryCredentialStatus(ctx context.Context, apiNamespaceClient
 
 	// Sanity test for gitopsDeploymentRepositoryCredentialCR.Spec.Secret to be non-empty value
 	if gitopsDeploymentRepositoryCredentialCR.Spec.Secret == "" {
-		if err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, nil, log); err != nil {
+		if err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, eventRecorder, nil, log); err != nil {
 			log.Error(err, fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR))
 		}
 		return
@@ -146,14 +150,14 @@ func reconcileRepositoryCredentialStatus(ctx context.Context, apiNamespaceClient
 	// Fetch the secret from the cluster
 	if err := apiNamespaceClient.Get(ctx, client.ObjectKey{Name: secret.Name, Namespace: secret.Namespace}, secret); err != nil {
 		log.Error(err, "Secret not found")
-		if err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, nil, log); err != nil {
+		if err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, eventRecorder, nil, log); err != nil {
 			log.Error(err, fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR))
 		}
 		return
 	}
 
 	// Update the status of GitopsDeploymentRepositoryCredential
-	if err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, secret, log); err != nil {
+	if err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, eventRecorder, secret, log); err != nil {
 		log.Error(err, fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR))
 	}
 
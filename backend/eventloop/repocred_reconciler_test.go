@@ -109,7 +109,7 @@ var _ = Describe("RepoCred Reconcile Function Tests", func() {
 
 			By("Call Reconcile function.")
 
-			reconcileRepositoryCredentials(ctx, dbq, k8sClient, log)
+			reconcileRepositoryCredentials(ctx, dbq, k8sClient, nil, log)
 
 			By("Verify that status is updated for GitopsDeploymentRepositoryCredentialCR.")
 			objectMeta := metav1.ObjectMeta{
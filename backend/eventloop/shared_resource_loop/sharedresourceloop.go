@@ -15,6 +15,7 @@ import (
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -44,6 +45,10 @@ import (
 //     concurrently create API-namespace-scoped database resources at the same time.
 type SharedResourceEventLoop struct {
 	inputChannel chan sharedResourceLoopMessage
+
+	// eventRecorder is used to record Kubernetes Events on the API resources reconciled by the shared resource loop.
+	// May be nil, in which case no events are recorded.
+	eventRecorder record.EventRecorder
 }
 
 // The bool return value is 'true' if ClusterUser is created; 'false' if it already exists in DB or in case of failure.
@@ -143,6 +148,7 @@ func (srEventLoop *SharedResourceEventLoop) ReconcileSharedManagedEnv(ctx contex
 		log:                l,
 		ctx:                ctx,
 		workspaceClient:    workspaceClient,
+		eventRecorder:      srEventLoop.eventRecorder,
 		workspaceNamespace: workspaceNamespace,
 		messageType:        sharedResourceLoopMessage_getOrCreateSharedManagedEnv,
 		responseChannel:    responseChannel,
@@ -189,6 +195,7 @@ func (srEventLoop *SharedResourceEventLoop) ReconcileRepositoryCredential(ctx co
 		log:                l,
 		ctx:                ctx,
 		workspaceClient:    workspaceClient,
+		eventRecorder:      srEventLoop.eventRecorder,
 		workspaceNamespace: workspaceNamespace,
 		messageType:        sharedResourceLoopMessage_reconcileRepositoryCredential,
 		responseChannel:    responseChannel,
@@ -214,9 +221,16 @@ func (srEventLoop *SharedResourceEventLoop) ReconcileRepositoryCredential(ctx co
 }
 
 func NewSharedResourceLoop() *SharedResourceEventLoop {
+	return NewSharedResourceLoopWithEventRecorder(nil)
+}
+
+// NewSharedResourceLoopWithEventRecorder starts a shared resource loop that records Kubernetes Events (for example,
+// when a managed environment cannot be connected to) using the given EventRecorder.
+func NewSharedResourceLoopWithEventRecorder(eventRecorder record.EventRecorder) *SharedResourceEventLoop {
 
 	sharedResourceEventLoop := &SharedResourceEventLoop{
-		inputChannel: make(chan sharedResourceLoopMessage),
+		inputChannel:  make(chan sharedResourceLoopMessage),
+		eventRecorder: eventRecorder,
 	}
 
 	go internalSharedResourceEventLoop(sharedResourceEventLoop.inputChannel)
@@ -237,6 +251,7 @@ type sharedResourceLoopMessage struct {
 	log                logr.Logger
 	ctx                context.Context
 	workspaceClient    client.Client
+	eventRecorder      record.EventRecorder
 	workspaceNamespace corev1.Namespace
 
 	messageType     sharedResourceLoopMessageType
@@ -357,7 +372,7 @@ func processSharedResourceMessage(ctx context.Context, msg sharedResourceLoopMes
 			return
 		}
 
-		res, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, msg.workspaceClient, payload.managedEnvironmentCRName,
+		res, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, msg.workspaceClient, msg.eventRecorder, payload.managedEnvironmentCRName,
 			payload.managedEnvironmentCRNamespace, payload.isWorkspaceTarget, msg.workspaceNamespace,
 			payload.k8sClientFactory, dbQueries, l)
 
@@ -417,7 +432,7 @@ func processSharedResourceMessage(ctx context.Context, msg sharedResourceLoopMes
 		if ok {
 
 			repositoryCredential, err = internalProcessMessage_ReconcileRepositoryCredential(ctx,
-				payload.repositoryCredentialCRName, msg.workspaceNamespace, msg.workspaceClient, payload.k8sClientFactory, dbQueries, true, l)
+				payload.repositoryCredentialCRName, msg.workspaceNamespace, msg.workspaceClient, msg.eventRecorder, payload.k8sClientFactory, dbQueries, true, l)
 
 		} else {
 			err = fmt.Errorf("SEVERE - unexpected cast in internalSharedResourceEventLoop")
@@ -2,6 +2,7 @@ package shared_resource_loop
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"sort"
@@ -24,6 +25,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	controllerLog "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -33,6 +35,7 @@ const (
 )
 
 func internalProcessMessage_ReconcileSharedManagedEnv(ctx context.Context, workspaceClient client.Client,
+	eventRecorder record.EventRecorder,
 	managedEnvironmentCRName string,
 	managedEnvironmentCRNamespace string,
 	isWorkspaceTarget bool,
@@ -47,7 +50,7 @@ func internalProcessMessage_ReconcileSharedManagedEnv(ctx context.Context, works
 	if condition.reason != "" && condition.managedEnvCR.Name != "" {
 
 		// If a metav1.Condition{} needs to be set, set it here.
-		updateManagedEnvironmentConnectionStatus(ctx, condition.managedEnvCR, workspaceClient, condition, log)
+		updateManagedEnvironmentConnectionStatus(ctx, condition.managedEnvCR, workspaceClient, eventRecorder, condition, log)
 
 	}
 
@@ -194,11 +197,15 @@ func internalProcessMessage_internalReconcileSharedManagedEnv(ctx context.Contex
 			}, errors.New(msg)
 	}
 
+	// If the CA of the cluster could not be determined, the error is reported when the cluster credentials are replaced, below
+	expectedCertificateAuthorityData, caErr := getExpectedCertificateAuthorityData(ctx, workspaceClient, managedEnvironmentCR, secretCR)
+
 	// We found the managed env, now verify that the ManagedEnv's .spec values match the corresponding fields in the ClusterCredentials row
 	if clusterCreds.Host != managedEnvironmentCR.Spec.APIURL ||
 		clusterCreds.AllowInsecureSkipTLSVerify != managedEnvironmentCR.Spec.AllowInsecureSkipTLSVerify ||
 		clusterCreds.ClusterResources != managedEnvironmentCR.Spec.ClusterResources ||
-		clusterCreds.Namespaces != managedEnvNamespaceSliceList {
+		clusterCreds.Namespaces != managedEnvNamespaceSliceList ||
+		caErr != nil || clusterCreds.Certificate_authority_data != expectedCertificateAuthorityData {
 		// C) If at least one of the fields in the managed env CR has changed, then replace the cluster credentials of the managed environment
 		return replaceExistingManagedEnv(ctx, gitopsEngineClient, workspaceClient, *clusterUser, isNewUser, managedEnvironmentCR, secretCR, *managedEnv,
 			workspaceNamespace, k8sClientFactory, dbQueries, log)
@@ -852,6 +859,19 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 			err
 	}
 
+	// The user must be verified before the client config is built, as building it would otherwise run the exec plugin
+	// (or read the files) that the kubeconfig specifies, within the backend.
+	if authInfo, exists := config.AuthInfos[matchingContext.AuthInfo]; exists {
+		if err := verifyKubeconfigAuthInfoIsSupported(authInfo, matchingContextName); err != nil {
+			return db.ClusterCredentials{}, connectionInitializedCondition{
+				managedEnvCR: managedEnvironment,
+				status:       metav1.ConditionFalse,
+				reason:       managedgitopsv1alpha1.ConditionReasonUnableToParseKubeconfigData,
+				message:      err.Error(),
+			}, err
+		}
+	}
+
 	clientConfig := clientcmd.NewNonInteractiveClientConfig(*config, matchingContextName, &clientcmd.ConfigOverrides{}, nil)
 
 	restConfig, err := clientConfig.ClientConfig()
@@ -863,9 +883,23 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 			err
 	}
 
-	// Ignore the self-signed certificate
+	// The CA of the cluster, if specified, is used by Argo CD to verify the certificate of the cluster
+	certificateAuthorityData, err := resolveCertificateAuthorityData(ctx, workspaceClient, managedEnvironment,
+		config.Clusters[matchingContext.Cluster])
+	if err != nil {
+		return db.ClusterCredentials{},
+			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonInvalidCABundle, err, managedEnvironment),
+			err
+	}
+
+	// Ignore the self-signed certificate (client-go does not allow a CA to be specified as well)
 	if managedEnvironment.Spec.AllowInsecureSkipTLSVerify {
 		restConfig.Insecure = true
+		restConfig.CAData = nil
+		restConfig.CAFile = ""
+	} else if managedEnvironment.Spec.CABundleSecretRef != nil {
+		restConfig.CAData = []byte(certificateAuthorityData)
+		restConfig.CAFile = ""
 	}
 
 	k8sClient, err := k8sClientFactory.BuildK8sClient(restConfig)
@@ -877,7 +911,7 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 			err
 	}
 
-	var saBearerToken string
+	var saBearerToken, clientCertificateData, clientKeyData string
 	log.Info("createNewServiceAccount is ", "CreateNewServiceAccount", managedEnvironment.Spec.CreateNewServiceAccount)
 	if managedEnvironment.Spec.CreateNewServiceAccount {
 		// This is the original behaviour, where we create a new service account
@@ -905,8 +939,8 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 			}, fmt.Errorf("%s", msg)
 		}
 
-		if val.Token == "" {
-			msg := fmt.Sprintf("kubeconfig must have a service account token for the user in context \"%s\". client-certificate is not supported at this time.", matchingContextName)
+		if val.Token == "" && (len(val.ClientCertificateData) == 0 || len(val.ClientKeyData) == 0) {
+			msg := fmt.Sprintf("kubeconfig must have a service account token, or a client-certificate-data and client-key-data, for the user in context \"%s\"", matchingContextName)
 			return db.ClusterCredentials{}, connectionInitializedCondition{
 				managedEnvCR: managedEnvironment,
 				status:       metav1.ConditionFalse,
@@ -915,7 +949,13 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 			}, fmt.Errorf("%s", msg)
 		}
 
-		saBearerToken = val.Token
+		// A token is preferred over a client certificate, if the user has both
+		if val.Token != "" {
+			saBearerToken = val.Token
+		} else {
+			clientCertificateData = string(val.ClientCertificateData)
+			clientKeyData = string(val.ClientKeyData)
+		}
 	}
 
 	// Convert the .spec.namespaces field to a comma-separated list of namespaces
@@ -944,6 +984,9 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 		Kube_config_context:         "",
 		Serviceaccount_bearer_token: saBearerToken,
 		Serviceaccount_ns:           serviceAccountNamespaceKubeSystem,
+		Client_certificate_data:     clientCertificateData,
+		Client_key_data:             clientKeyData,
+		Certificate_authority_data:  certificateAuthorityData,
 		AllowInsecureSkipTLSVerify:  insecureVerifyTLS,
 		Namespaces:                  namespacesField,
 		ClusterResources:            managedEnvironment.Spec.ClusterResources,
@@ -956,11 +999,13 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 
 			log.Error(err, "Unable to verify ClusterCredentials using provided token", clusterCredentials.GetAsLogKeyValues()...)
 
-			message := "Unable to validate the credentials provided in the ManagedEnvironment Secret. Verify the API URL, and service account token are correct."
+			message := "Unable to validate the credentials provided in the ManagedEnvironment Secret. Verify the API URL, and service account token (or client certificate) are correct."
 			if apierr.IsForbidden(err) {
 				message = "Provided service account does not have permission to access resources in the cluster. Verify that the service account has the correct Role and RoleBinding."
 			} else if isCertificateSignedByUnknownAuthority(err) {
-				message = "Certificate signed by unknown authority. Note that the '.spec.allowInsecureSkipTLSVerify' field can be used to ignore this error."
+				message = "Certificate signed by unknown authority. Verify that the 'certificate-authority-data' of the kubeconfig, or the CA bundle referenced by " +
+					"the '.spec.caBundleSecretRef' field, contains the CA that signed the certificate of the cluster. Note that the " +
+					"'.spec.allowInsecureSkipTLSVerify' field can be used to ignore this error."
 			}
 			return db.ClusterCredentials{}, connectionInitializedCondition{
 				managedEnvCR: managedEnvironment,
@@ -989,6 +1034,109 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 
 }
 
+// resolveCertificateAuthorityData returns the PEM-encoded CA bundle that should be used to verify the certificate of the
+// cluster of a ManagedEnvironment:
+//   - the CA bundle of the Secret referenced by .spec.caBundleSecretRef, if set
+//   - otherwise, the 'certificate-authority-data' of the kubeconfig cluster, if any.
+func resolveCertificateAuthorityData(ctx context.Context, workspaceClient client.Client,
+	managedEnvironment managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment, kubeconfigCluster *clientcmdapi.Cluster) (string, error) {
+
+	caBundleSecretRef := managedEnvironment.Spec.CABundleSecretRef
+	if caBundleSecretRef == nil {
+		if kubeconfigCluster == nil {
+			return "", nil
+		}
+		return string(kubeconfigCluster.CertificateAuthorityData), nil
+	}
+
+	if caBundleSecretRef.Name == "" {
+		return "", fmt.Errorf("the '.spec.caBundleSecretRef' field must specify the name of a Secret")
+	}
+
+	key := caBundleSecretRef.Key
+	if key == "" {
+		key = managedgitopsv1alpha1.DefaultCABundleSecretKey
+	}
+
+	caBundleSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      caBundleSecretRef.Name,
+			Namespace: managedEnvironment.Namespace,
+		},
+	}
+	if err := workspaceClient.Get(ctx, client.ObjectKeyFromObject(&caBundleSecret), &caBundleSecret); err != nil {
+		if apierr.IsNotFound(err) {
+			return "", fmt.Errorf("the CA bundle Secret '%s' referenced by '.spec.caBundleSecretRef' does not exist", caBundleSecret.Name)
+		}
+		return "", fmt.Errorf("unable to retrieve the CA bundle Secret '%s' referenced by '.spec.caBundleSecretRef': %w", caBundleSecret.Name, err)
+	}
+
+	if caBundleSecret.Type != sharedutil.ManagedEnvironmentSecretType {
+		return "", fmt.Errorf("the CA bundle Secret '%s' referenced by '.spec.caBundleSecretRef' must be of type '%s'",
+			caBundleSecret.Name, sharedutil.ManagedEnvironmentSecretType)
+	}
+
+	caBundle, exists := caBundleSecret.Data[key]
+	if !exists || len(caBundle) == 0 {
+		return "", fmt.Errorf("the CA bundle Secret '%s' referenced by '.spec.caBundleSecretRef' is missing the '%s' key", caBundleSecret.Name, key)
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM(caBundle) {
+		return "", fmt.Errorf("the '%s' key of the CA bundle Secret '%s' referenced by '.spec.caBundleSecretRef' does not contain a PEM-encoded certificate",
+			key, caBundleSecret.Name)
+	}
+
+	return string(caBundle), nil
+}
+
+// getExpectedCertificateAuthorityData returns the CA bundle that the ClusterCredentials of a ManagedEnvironment are
+// expected to contain, based on the kubeconfig of the ManagedEnvironment Secret and the .spec.caBundleSecretRef field.
+func getExpectedCertificateAuthorityData(ctx context.Context, workspaceClient client.Client,
+	managedEnvironment managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment, secret corev1.Secret) (string, error) {
+
+	config, err := clientcmd.Load(secret.Data[KubeconfigKey])
+	if err != nil {
+		return "", fmt.Errorf("unable to parse kubeconfig data: %w", err)
+	}
+
+	_, matchingContext, err := locateContextThatMatchesAPIURL(config, managedEnvironment.Spec.APIURL)
+	if err != nil {
+		return "", err
+	}
+
+	return resolveCertificateAuthorityData(ctx, workspaceClient, managedEnvironment, config.Clusters[matchingContext.Cluster])
+}
+
+// verifyKubeconfigAuthInfoIsSupported returns an error if the user of a kubeconfig authenticates in a way that the
+// GitOps Service does not support:
+//   - exec plugins and auth providers, as these would run commands (or contact identity providers) from within the
+//     backend and Argo CD.
+//   - client certificates and keys that are referenced by file path, rather than embedded in the kubeconfig, as these
+//     files would be read from the filesystem of the backend.
+func verifyKubeconfigAuthInfoIsSupported(authInfo *clientcmdapi.AuthInfo, contextName string) error {
+
+	if authInfo == nil {
+		return nil
+	}
+
+	if authInfo.Exec != nil {
+		return fmt.Errorf("the user in context \"%s\" of the kubeconfig uses an exec plugin, which is not supported. "+
+			"Use a service account token, or an embedded client-certificate-data and client-key-data, instead", contextName)
+	}
+
+	if authInfo.AuthProvider != nil {
+		return fmt.Errorf("the user in context \"%s\" of the kubeconfig uses an auth provider, which is not supported. "+
+			"Use a service account token, or an embedded client-certificate-data and client-key-data, instead", contextName)
+	}
+
+	if authInfo.ClientCertificate != "" || authInfo.ClientKey != "" {
+		return fmt.Errorf("the user in context \"%s\" of the kubeconfig references a client-certificate or client-key file, "+
+			"which is not supported. Use client-certificate-data and client-key-data instead", contextName)
+	}
+
+	return nil
+}
+
 // locateContextThatMatchesAPIURL examines a kubeconfig (Config struct), and looks for the context that
 // matches the cluster with the given API URL.
 // See 'sharedresourceloop_managedend_test.go' for an example of a kubeconfig.
@@ -1046,17 +1194,31 @@ func sanityTestCredentials(clusterCreds db.ClusterCredentials) (*rest.Config, bo
 	if clusterCreds.Host == "" {
 		return nil, false, fmt.Errorf("cluster credentials is missing host")
 	}
-	if clusterCreds.Serviceaccount_bearer_token == "" {
+	hasClientCertificate := clusterCreds.Client_certificate_data != "" || clusterCreds.Client_key_data != ""
+	if clusterCreds.Serviceaccount_bearer_token == "" && !hasClientCertificate {
 		return nil, false, fmt.Errorf("cluster credentials is missing service account bearer token")
 	}
+	if hasClientCertificate && (clusterCreds.Client_certificate_data == "" || clusterCreds.Client_key_data == "") {
+		return nil, false, fmt.Errorf("cluster credentials must have both a client certificate and a client key")
+	}
 
 	configParam := &rest.Config{
 		Host:        clusterCreds.Host,
 		BearerToken: clusterCreds.Serviceaccount_bearer_token,
 	}
 
+	if hasClientCertificate {
+		configParam.CertData = []byte(clusterCreds.Client_certificate_data)
+		configParam.KeyData = []byte(clusterCreds.Client_key_data)
+	}
+
 	configParam.Insecure = clusterCreds.AllowInsecureSkipTLSVerify
 
+	// client-go does not allow a CA to be specified when TLS verification is skipped
+	if !configParam.Insecure && clusterCreds.Certificate_authority_data != "" {
+		configParam.CAData = []byte(clusterCreds.Certificate_authority_data)
+	}
+
 	configParam.ServerName = ""
 
 	return configParam, true, nil
@@ -1144,7 +1306,7 @@ func createUnknownErrorEnvInitCondition() connectionInitializedCondition {
 // to preserve the LastTransitionTime (see https://pkg.go.dev/k8s.io/apimachinery/pkg/apis/meta/v1#Condition.LastTransitionTime )
 func updateManagedEnvironmentConnectionStatus(ctx context.Context,
 	managedEnvironment managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment,
-	client client.Client, connInitCondition connectionInitializedCondition, log logr.Logger) {
+	client client.Client, eventRecorder record.EventRecorder, connInitCondition connectionInitializedCondition, log logr.Logger) {
 
 	const conditionType = managedgitopsv1alpha1.ManagedEnvironmentStatusConnectionInitializationSucceeded
 	var condition *metav1.Condition = nil
@@ -1168,6 +1330,12 @@ func updateManagedEnvironmentConnectionStatus(ctx context.Context,
 		if err := client.Status().Update(ctx, &managedEnvironment); err != nil {
 			log.Error(err, "updating managed environment status condition")
 		}
+
+		// Only record an event when the connection status changes, so that the event is not repeated on every reconcile
+		if connInitCondition.status == metav1.ConditionFalse {
+			eventlooptypes.RecordWarningEvent(eventRecorder, &managedEnvironment, eventlooptypes.EventReasonEnvironmentConnectionFailed,
+				connInitCondition.message)
+		}
 	}
 }
 
@@ -1180,9 +1348,10 @@ func verifyClusterCredentialsWithNamespaceList(ctx context.Context, clusterCreds
 		return false, err
 	}
 
-	// Ignore the self-signed certificate
+	// Ignore the self-signed certificate (client-go does not allow a CA to be specified as well)
 	if managedEnvCR.Spec.AllowInsecureSkipTLSVerify {
 		configParam.Insecure = true
+		configParam.CAData = nil
 	}
 
 	clientObj, err := k8sClientFactory.BuildK8sClient(configParam)
@@ -2,8 +2,15 @@ package shared_resource_loop
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
@@ -25,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -134,7 +142,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 
 				By("calling reconcileSharedManagedEnv for the first time, and verifying the database rows are created")
 
-				src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+				src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 					false, *namespace, mockFactory, dbQueries, log)
 				Expect(err).To(BeNil())
 				Expect(src.ManagedEnv).To(Not(BeNil()))
@@ -147,7 +155,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				err = k8sClient.List(ctx, &saList)
 				Expect(err).To(BeNil())
 
-				src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+				src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 					false, *namespace, mockFactory, dbQueries, log)
 				Expect(err).To(BeNil())
 				Expect(src.ManagedEnv).To(Not(BeNil()))
@@ -167,7 +175,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				err = dbQueries.CreateAPICRToDatabaseMapping(ctx, oldAPICRToDBMapping)
 				Expect(err).To(BeNil())
 
-				src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+				src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 					false, *namespace, mockFactory, dbQueries, log)
 				Expect(err).To(BeNil())
 
@@ -187,7 +195,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				managedEnv.Spec.APIURL = "https://api2.fake-unit-test-data.origin-ci-int-gce.dev.rhcloud.com:6443"
 				err = k8sClient.Update(ctx, &managedEnv)
 				Expect(err).To(BeNil())
-				src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+				src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 					false, *namespace, mockFactory, dbQueries, log)
 				Expect(err).To(BeNil())
 
@@ -214,7 +222,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 
 				oldManagedEnv := src.ManagedEnv
 
-				src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+				src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 					false, *namespace, mockFactory, dbQueries, log)
 				Expect(err).To(BeNil())
 
@@ -262,7 +270,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			err = dbQueries.CreateAPICRToDatabaseMapping(ctx, apiCR)
 			Expect(err).To(BeNil())
 
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).ToNot(BeNil())
@@ -290,7 +298,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("calling ReconcileSharedManagedEnv")
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).To(Not(BeNil()))
@@ -305,7 +313,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 
 			By("ensuring the LastTransitionTime is not updated if nothing has changed")
 			lastTransitionTime := managedEnv.Status.Conditions[0].LastTransitionTime
-			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).To(Not(BeNil()))
@@ -331,7 +339,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("calling ReconcileSharedManagedEnv")
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).To(Not(BeNil()))
@@ -348,7 +356,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			managedEnv.Status.Conditions = []metav1.Condition{}
 			err = k8sClient.Update(ctx, &managedEnv)
 			Expect(err).To(BeNil())
-			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 
 			By("ensuring the status condition is recreated")
@@ -365,7 +373,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			managedEnv.Status.Conditions[0].Status = metav1.ConditionFalse
 			err = k8sClient.Update(ctx, &managedEnv)
 			Expect(err).To(BeNil())
-			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 
 			By("ensuring the status condition is recreated")
@@ -405,7 +413,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			}
 
 			By("calling reconcile to create  new managed env")
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(src.ManagedEnv).To(BeNil())
 			Expect(err).ToNot(BeNil())
@@ -433,7 +441,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("first calling reconcile to create database entries for new managed env")
-			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(firstSrc.ManagedEnv).ToNot(BeNil())
@@ -451,7 +459,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				failingClient:  mockClient,
 				realFakeClient: k8sClient,
 			}
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(src.ManagedEnv).To(BeNil())
 			Expect(err).ToNot(BeNil())
@@ -479,7 +487,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("first calling reconcile to create database entries for new managed env")
-			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(firstSrc.ManagedEnv).ToNot(BeNil())
@@ -498,7 +506,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				failingClient:  mockClient,
 				realFakeClient: k8sClient,
 			}
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(src.ManagedEnv).To(BeNil())
 			Expect(err).ToNot(BeNil())
@@ -527,7 +535,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("first calling reconcile to create database entries for new managed env")
-			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(firstSrc.ManagedEnv).ToNot(BeNil())
@@ -546,7 +554,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				failingClient:  mockClient,
 				realFakeClient: k8sClient,
 			}
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(src.ManagedEnv).To(BeNil())
 			Expect(err).ToNot(BeNil())
@@ -559,7 +567,9 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(managedEnv.Status.Conditions[0].Type).To(Equal(managedgitopsv1alpha1.ManagedEnvironmentStatusConnectionInitializationSucceeded))
 			Expect(managedEnv.Status.Conditions[0].Status).To(Equal(metav1.ConditionUnknown))
 			Expect(managedEnv.Status.Conditions[0].Reason).To(Equal(string(managedgitopsv1alpha1.ConditionReasonUnableToValidateClusterCredentials)))
-			Expect(managedEnv.Status.Conditions[0].Message).To(Equal("Certificate signed by unknown authority. Note that the '.spec.allowInsecureSkipTLSVerify' field can be used to ignore this error."))
+			Expect(managedEnv.Status.Conditions[0].Message).To(Equal("Certificate signed by unknown authority. Verify that the 'certificate-authority-data' " +
+				"of the kubeconfig, or the CA bundle referenced by the '.spec.caBundleSecretRef' field, contains the CA that signed the certificate " +
+				"of the cluster. Note that the '.spec.allowInsecureSkipTLSVerify' field can be used to ignore this error."))
 		})
 
 		It("should set the condition ConnectionInitializationSucceeded appropriately when the connection fails because of insufficient permissions to get a particular namespaces", func() {
@@ -576,7 +586,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("first calling reconcile to create database entries for new managed env")
-			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(firstSrc.ManagedEnv).ToNot(BeNil())
@@ -595,7 +605,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				failingClient:  mockClient,
 				realFakeClient: k8sClient,
 			}
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(src.ManagedEnv).To(BeNil())
 			Expect(err).ToNot(BeNil())
@@ -624,7 +634,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("first calling reconcile to create database entries for new managed env")
-			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			firstSrc, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(firstSrc.ManagedEnv).ToNot(BeNil())
@@ -643,7 +653,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				failingClient:  mockClient,
 				realFakeClient: k8sClient,
 			}
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).ToNot(BeNil())
@@ -691,7 +701,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("first calling reconcile to create database entries for new managed env")
-			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(createRC.ManagedEnv).ToNot(BeNil())
@@ -727,7 +737,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				"There should be no operations for this ManagedEnvironment in the database before Reconcile is called.")
 
 			By("calling reconcile, after deleting the CR, to ensure the database entries are reconciled")
-			deleteRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			deleteRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(deleteRC.ManagedEnv).To(BeNil())
@@ -775,7 +785,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("calling reconcile on the managed env, which is missing a secret")
-			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).ToNot(BeNil())
 			Expect(createRC.ManagedEnv).To(BeNil())
@@ -799,7 +809,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("first calling reconcile to create database entries for new managed env")
-			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(createRC.ManagedEnv).ToNot(BeNil())
@@ -808,7 +818,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("call reconcile again, but without the cluster secret existing")
-			createRC, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			createRC, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).ToNot(BeNil())
 			Expect(createRC.ManagedEnv).To(BeNil())
@@ -833,7 +843,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("calling reconcile to create database entries for new managed env")
-			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(createRC.ManagedEnv).ToNot(BeNil())
@@ -860,7 +870,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("call the reconcile function again")
-			createRC, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			createRC, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(createRC.ManagedEnv).ToNot(BeNil())
@@ -877,10 +887,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 
 		})
 
-		It("should produce a useful error message if the user in the kubeconfig doesn't have a token", func() {
-			By("creating ManagedEnvironment/Secret, without creating a new ServiceAccount")
-
-			kubeConfigContents := generateFakeKubeConfigWithoutToken()
+		buildManagedEnvironmentWithKubeConfigUser := func(kubeConfigUser string) (*managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment, *corev1.Secret) {
 			secret := &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-my-managed-env-secret",
@@ -888,7 +895,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				},
 				Type: sharedutil.ManagedEnvironmentSecretType,
 				Data: map[string][]byte{
-					KubeconfigKey: ([]byte)(kubeConfigContents),
+					KubeconfigKey: ([]byte)(generateFakeKubeConfigWithUser(kubeConfigUser)),
 				},
 			}
 			managedEnv := &managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
@@ -905,6 +912,59 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 
 			managedEnv.UID = "test-" + uuid.NewUUID()
 			secret.UID = "test-" + uuid.NewUUID()
+
+			return managedEnv, secret
+		}
+
+		DescribeTable("should produce a useful error message if the user in the kubeconfig doesn't have a token or an embedded client certificate",
+			func(kubeConfigUser string, expectedErrorPrefix string) {
+				By("creating ManagedEnvironment/Secret, without creating a new ServiceAccount")
+
+				managedEnv, secret := buildManagedEnvironmentWithKubeConfigUser(kubeConfigUser)
+				eventloop_test_util.StartServiceAccountListenerOnFakeClient(ctx, string(managedEnv.UID), k8sClient)
+
+				err := k8sClient.Create(ctx, managedEnv)
+				Expect(err).To(BeNil())
+
+				err = k8sClient.Create(ctx, secret)
+				Expect(err).To(BeNil())
+
+				By("calling reconcileSharedManagedEnv, which should produce the error")
+
+				src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
+					false, *namespace, mockFactory, dbQueries, log)
+				Expect(src.ManagedEnv).To(BeNil())
+				Expect(err).To(Not(BeNil()))
+				// Find the root error
+				for tmp := err; tmp != nil; tmp = errors.Unwrap(tmp) {
+					err = tmp
+				}
+				Expect(err.Error()).To(HavePrefix(expectedErrorPrefix))
+			},
+			Entry("user with a username and password", `
+      username: user
+      password: password`,
+				"kubeconfig must have a service account token, or a client-certificate-data and client-key-data, for the user in context"),
+			Entry("user with a client certificate, but no client key", `
+      client-certificate-data: Rk9PCg==`,
+				"kubeconfig must have a service account token, or a client-certificate-data and client-key-data, for the user in context"),
+			Entry("user with a client certificate and key that are referenced by file path", `
+      client-certificate: /etc/passwd
+      client-key: /etc/passwd`,
+				"the user in context \"default/api-fake-unit-test-data-origin-ci-int-gce-dev-rhcloud-com:6443/kube:admin\" of the kubeconfig references a client-certificate or client-key file"),
+			Entry("user with an exec plugin", `
+      exec:
+        apiVersion: client.authentication.k8s.io/v1beta1
+        command: /bin/sh
+        args: ["-c", "echo"]`,
+				"the user in context \"default/api-fake-unit-test-data-origin-ci-int-gce-dev-rhcloud-com:6443/kube:admin\" of the kubeconfig uses an exec plugin"),
+		)
+
+		It("should reconcile a ManagedEnvironment whose kubeconfig user has a client certificate, rather than a token", func() {
+
+			managedEnv, secret := buildManagedEnvironmentWithKubeConfigUser(`
+      client-certificate-data: Rk9PCg==
+      client-key-data: QkFSCg==`)
 			eventloop_test_util.StartServiceAccountListenerOnFakeClient(ctx, string(managedEnv.UID), k8sClient)
 
 			err := k8sClient.Create(ctx, managedEnv)
@@ -913,18 +973,22 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			err = k8sClient.Create(ctx, secret)
 			Expect(err).To(BeNil())
 
-			By("calling reconcileSharedManagedEnv, which should produce the error")
-
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			By("calling reconcile to create database entries for new managed env")
+			createRC, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
-			Expect(src.ManagedEnv).To(BeNil())
-			Expect(err).To(Not(BeNil()))
-			// Find the root error
-			for tmp := err; tmp != nil; tmp = errors.Unwrap(tmp) {
-				err = tmp
+			Expect(err).To(BeNil())
+			Expect(createRC.ManagedEnv).ToNot(BeNil())
+
+			By("ensuring the cluster credentials contain the client certificate and key from the kubeconfig")
+			clusterCredentials := db.ClusterCredentials{
+				Clustercredentials_cred_id: createRC.ManagedEnv.Clustercredentials_id,
 			}
-			Expect(err.Error()).To(HavePrefix("kubeconfig must have a service account token for the user in context"))
-			Expect(err.Error()).To(HaveSuffix("client-certificate is not supported at this time."))
+
+			err = dbQueries.GetClusterCredentialsById(ctx, &clusterCredentials)
+			Expect(err).To(BeNil())
+			Expect(clusterCredentials.Serviceaccount_bearer_token).To(BeEmpty())
+			Expect(clusterCredentials.Client_certificate_data).To(Equal("FOO\n"))
+			Expect(clusterCredentials.Client_key_data).To(Equal("BAR\n"))
 		})
 
 		It("should test whether appProjectEnvironment is created and deleted based on managedEnv, and also verifies that a AppProjectManagedEnvironment that is owned by the same user is not deleted.", func() {
@@ -941,7 +1005,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			err = k8sClient.Create(ctx, &secret)
 			Expect(err).To(BeNil())
 
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).ToNot(BeNil())
@@ -989,7 +1053,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			err = k8sClient.Delete(ctx, &managedEnv)
 			Expect(err).To(BeNil())
 
-			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).To(BeNil())
@@ -1019,7 +1083,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("calling ReconcileSharedManagedEnvironment")
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).ToNot(BeNil())
@@ -1058,7 +1122,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("calling ReconcileSharedManagedEnv")
-			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 			Expect(src.ManagedEnv).ToNot(BeNil())
@@ -1085,7 +1149,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Expect(err).To(BeNil())
 
 			By("calling ReconcileSharedManagedEnv and verifying that the ManagedEnvironment row was created")
-			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+			src, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 				false, *namespace, mockFactory, dbQueries, log)
 			Expect(err).To(BeNil())
 
@@ -1118,7 +1182,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				Expect(err).To(BeNil())
 
 				By("first calling reconcile to create database entries for new managed env")
-				reconcileRes, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+				reconcileRes, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 					false, *namespace, mockFactory, dbQueries, log)
 				Expect(err).To(BeNil())
 				Expect(reconcileRes.ManagedEnv).ToNot(BeNil())
@@ -1139,7 +1203,7 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 				Expect(err).To(BeNil())
 
 				By("calling reconcile again to ensure the managed environment db entry is updated with the new value")
-				reconcileRes, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, managedEnv.Name, managedEnv.Namespace,
+				reconcileRes, err = internalProcessMessage_ReconcileSharedManagedEnv(ctx, k8sClient, nil, managedEnv.Name, managedEnv.Namespace,
 					false, *namespace, mockFactory, dbQueries, log)
 				Expect(err).To(BeNil())
 				Expect(reconcileRes.ManagedEnv).ToNot(BeNil())
@@ -1175,6 +1239,127 @@ var _ = Describe("SharedResourceEventLoop ManagedEnvironment-related Test", func
 			Entry("other characters are invalid", "invalid_characters", false),
 		)
 
+		DescribeTable("Verify that sanityTestCredentials authenticates with either a bearer token or a client certificate",
+			func(clusterCreds db.ClusterCredentials, expectedConfig *rest.Config, expectError bool) {
+				clusterCreds.Host = "https://api.example.com:6443"
+
+				res, valid, err := sanityTestCredentials(clusterCreds)
+				Expect(err != nil).To(Equal(expectError))
+				Expect(valid).To(Equal(!expectError))
+				if expectedConfig != nil {
+					expectedConfig.Host = clusterCreds.Host
+				}
+				Expect(res).To(Equal(expectedConfig))
+			},
+			Entry("bearer token", db.ClusterCredentials{Serviceaccount_bearer_token: "token"},
+				&rest.Config{BearerToken: "token"}, false),
+			Entry("client certificate and key", db.ClusterCredentials{Client_certificate_data: "cert", Client_key_data: "key"},
+				&rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: []byte("cert"), KeyData: []byte("key")}}, false),
+			Entry("client certificate and key, with the CA of the cluster",
+				db.ClusterCredentials{Client_certificate_data: "cert", Client_key_data: "key", Certificate_authority_data: "ca"},
+				&rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: []byte("cert"), KeyData: []byte("key"), CAData: []byte("ca")}}, false),
+			Entry("the CA of the cluster is not used if TLS verification is skipped",
+				db.ClusterCredentials{Serviceaccount_bearer_token: "token", Certificate_authority_data: "ca", AllowInsecureSkipTLSVerify: true},
+				&rest.Config{BearerToken: "token", TLSClientConfig: rest.TLSClientConfig{Insecure: true}}, false),
+			Entry("neither a bearer token nor a client certificate", db.ClusterCredentials{}, nil, true),
+			Entry("a client certificate without a key", db.ClusterCredentials{Client_certificate_data: "cert"}, nil, true),
+		)
+
+		Context("Verify that resolveCertificateAuthorityData returns the CA of the cluster from the kubeconfig, or the CA bundle Secret", func() {
+
+			var (
+				ctx             context.Context
+				workspaceClient client.Client
+				managedEnv      managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment
+				caBundle        string
+			)
+
+			kubeconfigCluster := &clientcmdapi.Cluster{CertificateAuthorityData: []byte("kubeconfig-ca")}
+
+			BeforeEach(func() {
+				ctx = context.Background()
+
+				scheme, _, _, _, err := tests.GenericTestSetup()
+				Expect(err).To(BeNil())
+				workspaceClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+				managedEnv = managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-managed-env", Namespace: "my-namespace"},
+				}
+
+				caBundle = generateTestCABundle()
+			})
+
+			createCABundleSecret := func(secretType corev1.SecretType, data map[string][]byte) {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-ca-bundle", Namespace: managedEnv.Namespace},
+					Type:       secretType,
+					Data:       data,
+				}
+				Expect(workspaceClient.Create(ctx, secret)).To(Succeed())
+			}
+
+			It("should return the certificate-authority-data of the kubeconfig cluster, if .spec.caBundleSecretRef is not set", func() {
+				res, err := resolveCertificateAuthorityData(ctx, workspaceClient, managedEnv, kubeconfigCluster)
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal("kubeconfig-ca"))
+
+				res, err = resolveCertificateAuthorityData(ctx, workspaceClient, managedEnv, nil)
+				Expect(err).To(BeNil())
+				Expect(res).To(BeEmpty())
+			})
+
+			It("should return the CA bundle of the Secret referenced by .spec.caBundleSecretRef, instead of that of the kubeconfig", func() {
+				createCABundleSecret(sharedutil.ManagedEnvironmentSecretType, map[string][]byte{
+					managedgitopsv1alpha1.DefaultCABundleSecretKey: []byte(caBundle),
+					"other-ca.crt": []byte(caBundle + caBundle),
+				})
+
+				managedEnv.Spec.CABundleSecretRef = &managedgitopsv1alpha1.CABundleSecretReference{Name: "my-ca-bundle"}
+				res, err := resolveCertificateAuthorityData(ctx, workspaceClient, managedEnv, kubeconfigCluster)
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal(caBundle))
+
+				By("verifying a key other than the default can be referenced")
+				managedEnv.Spec.CABundleSecretRef.Key = "other-ca.crt"
+				res, err = resolveCertificateAuthorityData(ctx, workspaceClient, managedEnv, kubeconfigCluster)
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal(caBundle + caBundle))
+			})
+
+			It("should return an error if the CA bundle Secret does not exist, is of the wrong type, or does not contain a valid CA bundle", func() {
+				managedEnv.Spec.CABundleSecretRef = &managedgitopsv1alpha1.CABundleSecretReference{Name: "my-ca-bundle"}
+
+				_, err := resolveCertificateAuthorityData(ctx, workspaceClient, managedEnv, kubeconfigCluster)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring("does not exist"))
+
+				createCABundleSecret(corev1.SecretTypeOpaque, map[string][]byte{
+					managedgitopsv1alpha1.DefaultCABundleSecretKey: []byte(caBundle),
+				})
+				_, err = resolveCertificateAuthorityData(ctx, workspaceClient, managedEnv, kubeconfigCluster)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring("must be of type"))
+
+				secret := &corev1.Secret{}
+				Expect(workspaceClient.Get(ctx, client.ObjectKey{Name: "my-ca-bundle", Namespace: managedEnv.Namespace}, secret)).To(Succeed())
+				secret.Type = sharedutil.ManagedEnvironmentSecretType
+				secret.Data = map[string][]byte{managedgitopsv1alpha1.DefaultCABundleSecretKey: []byte("not a certificate")}
+				Expect(workspaceClient.Delete(ctx, secret)).To(Succeed())
+				secret.ResourceVersion = ""
+				Expect(workspaceClient.Create(ctx, secret)).To(Succeed())
+
+				_, err = resolveCertificateAuthorityData(ctx, workspaceClient, managedEnv, kubeconfigCluster)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring("does not contain a PEM-encoded certificate"))
+
+				managedEnv.Spec.CABundleSecretRef.Key = "missing-key"
+				_, err = resolveCertificateAuthorityData(ctx, workspaceClient, managedEnv, kubeconfigCluster)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring("is missing the 'missing-key' key"))
+			})
+		})
+
 		DescribeTable("Verify that convertManagedEnvNamespacesFieldToCommaSeparatedList correctly converts a string slice to comma-separated list, rejecting invalid namespaces",
 			func(namespaceSlice []string, expectedResult string, expectError bool) {
 				res, err := convertManagedEnvNamespacesFieldToCommaSeparatedList(namespaceSlice)
@@ -1342,7 +1527,8 @@ users:
 `
 }
 
-func generateFakeKubeConfigWithoutToken() string {
+// generateFakeKubeConfigWithUser returns a kubeconfig with a single context, whose user has the given (YAML) fields
+func generateFakeKubeConfigWithUser(user string) string {
 	// This config has been sanitized of any real credentials.
 	return `
 apiVersion: v1
@@ -1362,9 +1548,27 @@ current-context: default/api-fake-unit-test-data-origin-ci-int-gce-dev-rhcloud-c
 preferences: {}
 users:
   - name: kube:admin/api-fake-unit-test-data-origin-ci-int-gce-dev-rhcloud-com:6443
-    user:
-      client-certificate-data: Rk9PCg==
-      client-key-data: QkFSCg==
-`
+    user:` + user + "\n"
+
+}
+
+// generateTestCABundle returns a PEM-encoded, self-signed CA certificate
+func generateTestCABundle() string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).To(BeNil())
 
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
 }
@@ -8,7 +8,10 @@ import (
 	"strings"
 
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/go-git/go-git/v5/utils/ioutil"
 
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
@@ -19,10 +22,12 @@ import (
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -38,6 +43,7 @@ func internalProcessMessage_ReconcileRepositoryCredential(ctx context.Context,
 	repositoryCredentialCRName string,
 	repositoryCredentialCRNamespace corev1.Namespace,
 	apiNamespaceClient client.Client,
+	eventRecorder record.EventRecorder,
 	k8sClientFactory SRLK8sClientFactory,
 	dbQueries db.DatabaseQueries, shouldWait bool, l logr.Logger) (*db.RepositoryCredentials, error) {
 
@@ -176,7 +182,7 @@ func internalProcessMessage_ReconcileRepositoryCredential(ctx context.Context,
 
 	// Sanity test for gitopsDeploymentRepositoryCredentialCR.Spec.Secret to be non-empty value
 	if gitopsDeploymentRepositoryCredentialCR.Spec.Secret == "" {
-		if err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, nil, l); err != nil {
+		if err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, eventRecorder, nil, l); err != nil {
 			l.Error(err, fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR))
 		}
 		return nil, fmt.Errorf("secret cannot be empty")
@@ -204,7 +210,7 @@ func internalProcessMessage_ReconcileRepositoryCredential(ctx context.Context,
 			// Something went wrong, retry
 			errMessage = fmt.Errorf("error retrieving secret: %v", err)
 		}
-		if err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, secret, l); err != nil {
+		if err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, eventRecorder, secret, l); err != nil {
 			l.Error(err, fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR))
 		}
 
@@ -218,7 +224,7 @@ func internalProcessMessage_ReconcileRepositoryCredential(ctx context.Context,
 	}
 
 	// Before updating the records in DB, we need to set the Conditions of the CR
-	if err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, secret, l); err != nil {
+	if err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, apiNamespaceClient, eventRecorder, secret, l); err != nil {
 		l.Error(err, fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR))
 	}
 
@@ -436,7 +442,9 @@ func createRepoCredOperation(ctx context.Context, dbRepoCred db.RepositoryCreden
 // Updates the given repository credential CR's status condition to match the given condition and additional checks.
 // If there is an existing status condition with the exact same status, reason and message, no update is made in order
 // to preserve the LastTransitionTime (see https://pkg.go.dev/k8s.io/apimachinery/pkg/apis/meta/v1#Condition.LastTransitionTime )
-func UpdateGitopsDeploymentRepositoryCredentialStatus(ctx context.Context, repositoryCredential *managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential, client client.Client, secret *corev1.Secret, log logr.Logger) error {
+// A CredentialsInvalid event is recorded when the credentials become invalid.
+func UpdateGitopsDeploymentRepositoryCredentialStatus(ctx context.Context, repositoryCredential *managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential, client client.Client,
+	eventRecorder record.EventRecorder, secret *corev1.Secret, log logr.Logger) error {
 
 	// if the condition was sent along with the function call, we don't need to perform additional checks
 	newConditions := generateValidRepositoryCredentialsConditions(repositoryCredential, ctx, secret)
@@ -465,16 +473,40 @@ func UpdateGitopsDeploymentRepositoryCredentialStatus(ctx context.Context, repos
 			log.Error(err, vErr.Error(), "DebugErr", errGenericCR, "CR Name", repositoryCredential, "Namespace", repositoryCredential.Namespace)
 			return vErr
 		}
+		previousValidCredCondition := findRepositoryCredentialCondition(repositoryCredential.Status.Conditions,
+			managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredentialConditionValidRepositoryCredential)
+
 		repositoryCredential.Status.SetConditions(newConditions)
 		// Update the GitOpsDeploymentRepositoryCredential CR
 		if err := client.Status().Update(ctx, repositoryCredential); err != nil {
 			log.Error(err, "updating repository credential CR's status condition")
+			return nil
+		}
+
+		// Record an event when the credentials become invalid (or become invalid for a different reason)
+		validCredCondition := findRepositoryCredentialCondition(newConditions,
+			managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredentialConditionValidRepositoryCredential)
+		if validCredCondition != nil && validCredCondition.Status == metav1.ConditionFalse &&
+			(previousValidCredCondition == nil || previousValidCredCondition.Status != metav1.ConditionFalse || previousValidCredCondition.Reason != validCredCondition.Reason) {
+
+			eventlooptypes.RecordWarningEvent(eventRecorder, repositoryCredential, eventlooptypes.EventReasonCredentialsInvalid, validCredCondition.Message)
 		}
 	}
 
 	return nil
 }
 
+// findRepositoryCredentialCondition returns a copy of the condition of the given type, or nil if it does not exist.
+func findRepositoryCredentialCondition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			condition := conditions[i]
+			return &condition
+		}
+	}
+	return nil
+}
+
 func generateValidRepositoryCredentialsConditions(repositoryCredential *managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential, ctx context.Context, secret *corev1.Secret) []metav1.Condition {
 
 	var validRepoUrlCondition, validRepoCredCondition metav1.Condition
@@ -585,33 +617,87 @@ func generateValidRepositoryCredentialsConditions(repositoryCredential *managedg
 func validateRepositoryCredentials(rawRepoURL string, secret *corev1.Secret) error {
 
 	normalizedRepoUrl := NormalizeGitURL(rawRepoURL)
+
+	_, err := ListRemoteReferences(context.Background(), normalizedRepoUrl, secret)
+	return err
+}
+
+// ListRemoteReferences lists the references (for example, branches and tags) of a remote Git repository, as
+// 'git ls-remote' does. If secret is non-nil, it should contain the credentials of the repository, in the format of
+// the Secret of a GitOpsDeploymentRepositoryCredential.
+func ListRemoteReferences(ctx context.Context, repoURL string, secret *corev1.Secret) ([]*plumbing.Reference, error) {
+
 	rem := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
 		Name: "origin",
-		URLs: []string{normalizedRepoUrl},
+		URLs: []string{repoURL},
 	})
 
+	auth, err := getRepositoryAuth(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return rem.ListContext(ctx, &git.ListOptions{Auth: auth})
+}
+
+// ListRemoteReferencesWithTransport lists the references of a remote Git repository, as ListRemoteReferences does,
+// but connects to the endpoint with the given transport, rather than with the default transport of its protocol.
+func ListRemoteReferencesWithTransport(ctx context.Context, gitTransport transport.Transport, endpoint *transport.Endpoint,
+	secret *corev1.Secret) (resultRefs []*plumbing.Reference, err error) {
+
+	auth, err := getRepositoryAuth(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := gitTransport.NewUploadPackSession(endpoint, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer ioutil.CheckClose(session, &err)
+
+	advertisedRefs, err := session.AdvertisedReferencesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allRefs, err := advertisedRefs.AllReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range allRefs {
+		resultRefs = append(resultRefs, ref)
+	}
+
+	return resultRefs, nil
+}
+
+// getRepositoryAuth returns the credentials of the Secret of a GitOpsDeploymentRepositoryCredential, or nil if the
+// Secret is nil.
+func getRepositoryAuth(secret *corev1.Secret) (transport.AuthMethod, error) {
+
+	if secret == nil {
+		return nil, nil
+	}
+
 	// Secret exists, so get its data
 	authUsername := string(secret.Data["username"])
 	authPassword := string(secret.Data["password"])
 	authSSHKey := string(secret.Data["sshPrivateKey"])
 
-	listOptions := &git.ListOptions{}
-
 	if authSSHKey != "" {
 		privateKey, err := ssh.NewPublicKeys("git", []byte(authSSHKey), "")
 		if err != nil {
-			return err
-		}
-		listOptions.Auth = privateKey
-	} else {
-		listOptions.Auth = &http.BasicAuth{
-			Username: authUsername,
-			Password: authPassword,
+			return nil, err
 		}
+		return privateKey, nil
 	}
 
-	_, err := rem.List(listOptions)
-	return err
+	return &http.BasicAuth{
+		Username: authUsername,
+		Password: authPassword,
+	}, nil
 }
 
 // EnsurePrefix idempotently ensures that a base string has a given prefix.
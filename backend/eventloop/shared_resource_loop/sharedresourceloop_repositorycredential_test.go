@@ -173,7 +173,7 @@ var _ = Describe("SharedResourceEventLoop Repository Credential Tests", func() {
 				},
 			}
 
-			err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, k8sClient, &corev1.Secret{}, log.FromContext(ctx))
+			err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, k8sClient, nil, &corev1.Secret{}, log.FromContext(ctx))
 			Expect(err).To(BeNil())
 
 			Expect(gitopsDeploymentRepositoryCredentialCR).Should(SatisfyAll(haveErrOccurredConditionSet(expectedRepoCredStatus, false)))
@@ -211,7 +211,7 @@ var _ = Describe("SharedResourceEventLoop Repository Credential Tests", func() {
 			gitopsDeploymentRepositoryCredentialCR.Status = expectedRepoCredStatus
 			Expect(k8sClient.Status().Update(ctx, gitopsDeploymentRepositoryCredentialCR)).To(BeNil())
 
-			err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, k8sClient, &corev1.Secret{}, log.FromContext(ctx))
+			err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, k8sClient, nil, &corev1.Secret{}, log.FromContext(ctx))
 			Expect(err).To(BeNil())
 
 			Expect(gitopsDeploymentRepositoryCredentialCR).Should(SatisfyAll(haveErrOccurredConditionSet(expectedRepoCredStatus, true)))
@@ -240,7 +240,7 @@ var _ = Describe("SharedResourceEventLoop Repository Credential Tests", func() {
 				},
 			}
 
-			err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, k8sClient, nil, log.FromContext(ctx))
+			err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, k8sClient, nil, nil, log.FromContext(ctx))
 			Expect(err).To(BeNil())
 
 			Expect(gitopsDeploymentRepositoryCredentialCR).Should(SatisfyAll(haveErrOccurredConditionSet(expectedRepoCredStatus, false)))
@@ -270,7 +270,7 @@ var _ = Describe("SharedResourceEventLoop Repository Credential Tests", func() {
 				},
 			}
 
-			err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, k8sClient, nil, log.FromContext(ctx))
+			err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, k8sClient, nil, nil, log.FromContext(ctx))
 			Expect(err).To(BeNil())
 
 			Expect(gitopsDeploymentRepositoryCredentialCR).Should(SatisfyAll(haveErrOccurredConditionSet(expectedRepoCredStatus, false)))
@@ -403,7 +403,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 			var k8sClientFactory SRLK8sClientFactory
 
-			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 
 			// Negative test (there is no Secret)
 			Expect(err).NotTo(BeNil())
@@ -425,7 +425,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 			// Create again the CR
 			// Expected: Since there's no DB entry for the CR, it will create an operation
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).NotTo(BeNil())
 
@@ -503,7 +503,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 			// Re-running should not error
 			fmt.Println("Re-running the internalProcessMessage_ReconcileRepositoryCredential()")
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).NotTo(BeNil())
 
@@ -527,7 +527,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			err = dbq.UpdateRepositoryCredentials(ctx, dbRepoCred)
 			Expect(err).To(BeNil())
 
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).ToNot(BeNil())
 
@@ -569,7 +569,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			Expect(err).ToNot(BeNil()) // err unexpected number of rows affected:
 			// Expect(err).To(BeNil())
 
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).ToNot(BeNil())
 
@@ -596,7 +596,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			// Expected: Since there is no GitOpsDeploymentRepositoryCredential CR, it will delete the DB entry
 			err = k8sClient.Delete(ctx, cr)
 			Expect(err).To(BeNil())
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).To(BeNil())
 
@@ -619,7 +619,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 			// Negative test: Try again to reconcile the RepositoryCredential
 			// Expected: It should not error (both db row and CR should be deleted). Nothing we can do.
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).To(BeNil())
 
@@ -764,7 +764,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			err = k8sClient.Create(ctx, secret)
 			Expect(err).To(BeNil())
 
-			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).NotTo(BeNil())
 
@@ -903,7 +903,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			err = k8sClient.Create(ctx, secret)
 			Expect(err).To(BeNil())
 
-			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).NotTo(BeNil())
 
@@ -931,7 +931,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			Expect(err).To(BeNil())
 			Expect(appProjectRepositoryDB).NotTo(BeNil())
 
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, k8sClientFactory, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, k8sClient, nil, k8sClientFactory, dbq, false, l)
 			Expect(err).To(BeNil())
 			Expect(dbRepoCred).NotTo(BeNil())
 			Expect(dbRepoCred.PrivateURL).To(Equal("http://github.com/jgwest/my-repo"))
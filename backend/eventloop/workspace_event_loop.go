@@ -17,6 +17,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -38,13 +39,13 @@ import (
 
 // Start a workspace event loop router go routine, which is responsible for handling API namespace events and
 // then passing them to the controller loop.
-func newWorkspaceEventLoopRouter(workspaceID string) WorkspaceEventLoopRouterStruct {
+func newWorkspaceEventLoopRouter(workspaceID string, eventRecorder record.EventRecorder) WorkspaceEventLoopRouterStruct {
 
 	res := WorkspaceEventLoopRouterStruct{
 		channel: make(chan workspaceEventLoopMessage),
 	}
 
-	internalStartWorkspaceEventLoopRouter(res.channel, workspaceID, defaultApplicationEventLoopFactory{})
+	internalStartWorkspaceEventLoopRouter(res.channel, workspaceID, defaultApplicationEventLoopFactory{}, eventRecorder)
 
 	return res
 }
@@ -55,7 +56,7 @@ func newWorkspaceEventLoopRouterWithFactory(workspaceID string, applEventLoopFac
 		channel: make(chan workspaceEventLoopMessage),
 	}
 
-	internalStartWorkspaceEventLoopRouter(res.channel, workspaceID, applEventLoopFactory)
+	internalStartWorkspaceEventLoopRouter(res.channel, workspaceID, applEventLoopFactory, nil)
 
 	return res
 }
@@ -89,7 +90,7 @@ type workspaceEventLoopMessage struct {
 // internalStartWorkspaceEventLoopRouter has the primary goal of catching panics from the workspaceEventLoopRouter, and
 // recovering from them.
 func internalStartWorkspaceEventLoopRouter(input chan workspaceEventLoopMessage, workspaceID string,
-	applEventLoopFactory applicationEventQueueLoopFactory) {
+	applEventLoopFactory applicationEventQueueLoopFactory, eventRecorder record.EventRecorder) {
 
 	go func() {
 
@@ -102,7 +103,7 @@ func internalStartWorkspaceEventLoopRouter(input chan workspaceEventLoopMessage,
 
 		for {
 			isPanic, _ := sharedutil.CatchPanic(func() error {
-				workspaceEventLoopRouter(input, workspaceID, applEventLoopFactory)
+				workspaceEventLoopRouter(input, workspaceID, applEventLoopFactory, eventRecorder)
 				return nil
 			})
 
@@ -186,12 +187,15 @@ type workspaceEventLoopInternalState struct {
 
 	// applEventLoopFactory is the factory function to use, to create the application event loop
 	applEventLoopFactory applicationEventQueueLoopFactory
+
+	// eventRecorder is used to record Kubernetes Events on GitOps Service API resources
+	eventRecorder record.EventRecorder
 }
 
 // workspaceEventLoopRouter receives all events for the namespace, and passes them to specific goroutine responsible
 // for handling events for individual applications.
 func workspaceEventLoopRouter(input chan workspaceEventLoopMessage, namespaceID string,
-	applEventLoopFactory applicationEventQueueLoopFactory) {
+	applEventLoopFactory applicationEventQueueLoopFactory, eventRecorder record.EventRecorder) {
 
 	ctx := context.Background()
 
@@ -202,13 +206,14 @@ func workspaceEventLoopRouter(input chan workspaceEventLoopMessage, namespaceID
 	log.Info("workspaceEventLoopRouter started")
 	defer log.Info("workspaceEventLoopRouter ended.")
 
-	sharedResourceEventLoop := shared_resource_loop.NewSharedResourceLoop()
+	sharedResourceEventLoop := shared_resource_loop.NewSharedResourceLoopWithEventRecorder(eventRecorder)
 
 	state := workspaceEventLoopInternalState{
 		sharedResourceEventLoop: sharedResourceEventLoop,
 		orphanedResources:       map[string]map[string]eventlooptypes.EventLoopEvent{},
 		applicationMap:          map[string]workspaceEventLoop_applicationEventLoopEntry{},
 		applEventLoopFactory:    applEventLoopFactory,
+		eventRecorder:           eventRecorder,
 		workspaceResourceLoop:   newWorkspaceResourceLoop(sharedResourceEventLoop, input),
 
 		log:         log,
@@ -306,6 +311,15 @@ func handleWorkspaceEventLoopMessage(ctx context.Context, event eventlooptypes.E
 			// The SyncRun no longer exists, or an unrecoverable error occurred, so just continue
 			return
 		}
+
+	} else if event.Event.ReqResource == eventlooptypes.GitOpsDeploymentPreviewTypeName {
+
+		associatedGitOpsDeploymentName = getGitOpsDeploymentNameOfPreview(ctx, event, log)
+
+		if associatedGitOpsDeploymentName == "" {
+			// The Preview no longer exists, or an unrecoverable error occurred, so just continue
+			return
+		}
 	}
 
 	if associatedGitOpsDeploymentName == "" {
@@ -321,7 +335,7 @@ func handleWorkspaceEventLoopMessage(ctx context.Context, event eventlooptypes.E
 
 		var err error
 		applicationEntryVal, err = startApplicationEventQueueLoop(ctx, event.Event.Client, associatedGitOpsDeploymentName, event,
-			state.sharedResourceEventLoop, state.applEventLoopFactory, log)
+			state.sharedResourceEventLoop, state.applEventLoopFactory, state.eventRecorder, log)
 		if err != nil {
 			// We already logged the error in startApplicationEventLoop, no need to log here
 			return
@@ -427,7 +441,7 @@ func handleStatusTickerMessage(state workspaceEventLoopInternalState) {
 
 func startApplicationEventQueueLoop(ctx context.Context, k8sClient client.Client, associatedGitOpsDeploymentName string, event eventlooptypes.EventLoopMessage,
 	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
-	applEventLoopFactory applicationEventQueueLoopFactory, log logr.Logger) (workspaceEventLoop_applicationEventLoopEntry, error) {
+	applEventLoopFactory applicationEventQueueLoopFactory, eventRecorder record.EventRecorder, log logr.Logger) (workspaceEventLoop_applicationEventLoopEntry, error) {
 
 	// Start the application event queue go-routine
 
@@ -438,6 +452,7 @@ func startApplicationEventQueueLoop(ctx context.Context, k8sClient client.Client
 		SharedResourceEventLoop:   sharedResourceEventLoop,
 		InputChan:                 make(chan application_event_loop.RequestMessage),
 		Client:                    k8sClient,
+		EventRecorder:             eventRecorder,
 	}
 
 	// Start the application event loop's goroutine
@@ -480,6 +495,29 @@ func (d defaultApplicationEventLoopFactory) startApplicationEventQueueLoop(ctx c
 
 var _ applicationEventQueueLoopFactory = defaultApplicationEventLoopFactory{}
 
+// getGitOpsDeploymentNameOfPreview returns the name of the GitOpsDeployment that a GitOpsDeploymentPreview refers to.
+// A GitOpsDeploymentPreview has no database state that outlives the computation of the preview, so if the
+// GitOpsDeploymentPreview no longer exists (or an error occurred), "" is returned and the event is ignored.
+func getGitOpsDeploymentNameOfPreview(ctx context.Context, event eventlooptypes.EventLoopMessage, log logr.Logger) string {
+
+	previewCR := &v1alpha1.GitOpsDeploymentPreview{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      event.Event.Request.Name,
+			Namespace: event.Event.Request.Namespace,
+		},
+	}
+	if err := event.Event.Client.Get(ctx, client.ObjectKeyFromObject(previewCR), previewCR); err != nil {
+		if apierr.IsNotFound(err) {
+			log.V(logutil.LogLevel_Debug).Info("skipping preview resource that could no longer be found:", "resource", previewCR.ObjectMeta)
+		} else {
+			log.Error(err, "unexpected client error on retrieving preview object", "resource", previewCR.ObjectMeta)
+		}
+		return ""
+	}
+
+	return previewCR.Spec.GitopsDeploymentName
+}
+
 // Processes events related to GitOpsDeploymentSyncRuns: determines whether the referenced GitOpsDeployment exists.
 // - If the GitOpsDepl exists, return the name
 // - Otherwise, add the SyncRun resource to orphaned list (why? because it is a gitopsdeplsyncrun that refers to a gitopsdepl that doesn't exist)
@@ -38,13 +38,17 @@ import (
 
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	dbutil "github.com/redhat-appstudio/managed-gitops/backend-shared/db/util"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	managedgitopscontrollers "github.com/redhat-appstudio/managed-gitops/backend/controllers/managed-gitops"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/notification_loop"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/preprocess_event_loop"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
 	"github.com/redhat-appstudio/managed-gitops/backend/routes"
+	backendutil "github.com/redhat-appstudio/managed-gitops/backend/util"
 	crzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	//+kubebuilder:scaffold:imports
 )
@@ -126,7 +130,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	preprocessEventLoop := preprocess_event_loop.NewPreprocessEventLoop()
+	// Kubernetes Events recorded by the event loops also trigger the notifications of GitOpsDeploymentNotifications
+	notificationLoop := notification_loop.NewNotificationLoop(mgr.GetClient())
+	eventRecorder := notification_loop.NewNotifyingEventRecorder(mgr.GetEventRecorderFor(eventlooptypes.EventRecorderName), notificationLoop)
+
+	preprocessEventLoop := preprocess_event_loop.NewPreprocessEventLoop(eventRecorder)
 
 	if err = (&managedgitopscontrollers.GitOpsDeploymentReconciler{
 		PreprocessEventLoop: preprocessEventLoop,
@@ -144,6 +152,14 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "GitOpsDeploymentSyncRun")
 		os.Exit(1)
 	}
+	if err = (&managedgitopscontrollers.GitOpsDeploymentPreviewReconciler{
+		PreprocessEventLoop: preprocessEventLoop,
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GitOpsDeploymentPreview")
+		os.Exit(1)
+	}
 	if err = (&managedgitopscontrollers.GitOpsDeploymentRepositoryCredentialReconciler{
 		Client:              mgr.GetClient(),
 		Scheme:              mgr.GetScheme(),
@@ -174,7 +190,14 @@ func main() {
 
 		setupLog.Info("setting up webhooks")
 
-		if err = (&managedgitopsv1alpha1.GitOpsDeployment{}).SetupWebhookWithManager(mgr); err != nil {
+		// If enabled, the GitOpsDeployment webhook also verifies that the repository and revision of its source exist
+		if sourceValidator := backendutil.NewSourceValidatorFromEnv(mgr.GetClient(), setupLog); sourceValidator != nil {
+			setupLog.Info("enabling source validation in the GitOpsDeployment webhook", "timeout", sourceValidator.Timeout, "failOpen", sourceValidator.FailOpen)
+			if err = (&managedgitopsv1alpha1.GitOpsDeployment{}).SetupWebhookWithSourceValidator(mgr, sourceValidator); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "GitOpsDeployment")
+				os.Exit(1)
+			}
+		} else if err = (&managedgitopsv1alpha1.GitOpsDeployment{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "GitOpsDeployment")
 			os.Exit(1)
 		}
@@ -198,6 +221,7 @@ func main() {
 	startDBReconciler(mgr)
 	startRepoCredReconciler(mgr)
 	startDBMetricsReconciler(mgr)
+	initializeRoutes(mgr)
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -247,8 +271,9 @@ func startRepoCredReconciler(mgr ctrl.Manager) {
 	}
 
 	repoCredReconciler := eventloop.RepoCredReconciler{
-		DB:     dbQueries,
-		Client: mgr.GetClient(),
+		DB:            dbQueries,
+		Client:        mgr.GetClient(),
+		EventRecorder: mgr.GetEventRecorderFor(eventlooptypes.EventRecorderName),
 	}
 
 	// Start goroutine for Repository Credential reconciler
@@ -272,14 +297,24 @@ func startDBMetricsReconciler(mgr ctrl.Manager) {
 	databaseReconciler.StartDBMetricsReconcilerForMetrics()
 }
 
-// nolint:unused
-func initializeRoutes() {
+func initializeRoutes(mgr ctrl.Manager) {
 
-	// Intializing the server for routing endpoints
-	router := routes.RouteInit()
-	err := router.ListenAndServe()
-	if err != http.ErrServerClosed {
-		log.Println("Error on ListenAndServe:", err)
+	dbQueries, err := db.NewSharedProductionPostgresDBQueries(false)
+	if err != nil {
+		setupLog.Error(err, "never able to connect to database")
+		os.Exit(1)
 	}
 
+	// Intializing the server for routing endpoints: the webhook secrets of repositories are held in the namespace
+	// of the GitOps engine instance.
+	router := routes.RouteInit(dbQueries, mgr.GetClient(), dbutil.GetGitOpsEngineSingleInstanceNamespace())
+
+	// Start goroutine for the webhook event server
+	go func() {
+		err := router.ListenAndServe()
+		if err != http.ErrServerClosed {
+			log.Println("Error on ListenAndServe:", err)
+		}
+	}()
+
 }
@@ -26,6 +26,17 @@ var (
 		},
 	)
 
+	// GitopsdeplDriftedResources is the number of resources of each GitOpsDeployment that are OutOfSync with the GitOps
+	// repository. GitOpsDeployments without drift are not reported, so that long-lived drift can be alerted on
+	// with, for example, 'gitopsDeployment_drifted_resources > 0' for a given duration.
+	GitopsdeplDriftedResources = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gitopsDeployment_drifted_resources",
+			Help: "Number of resources of a GitOpsDeployment that are OutOfSync with the GitOps repository",
+		},
+		[]string{"name", "namespace"},
+	)
+
 	activeGitOpsDeployments = activeGitOpsDeploymentSet{
 		mutex:             sync.Mutex{},
 		gitOpsDeployments: map[string]bool{},
@@ -89,6 +100,8 @@ func RemoveGitOpsDeployment(resourceName string, resourceNamespace string, resou
 
 	delete(activeGitOpsDeployments.gitOpsDeployments, mapKey)
 
+	GitopsdeplDriftedResources.DeleteLabelValues(resourceName, resourceNamespace)
+
 	// Update the total number of GitOpsDeployments, now that it has changed.
 	Gitopsdepl.Set((float64)(len(activeGitOpsDeployments.gitOpsDeployments)))
 
@@ -119,9 +132,20 @@ func SetErrorState(resourceName string, resourceNamespace string, resourceNamesp
 
 }
 
+// SetDriftedResources sets the number of resources of a GitOpsDeployment that are OutOfSync with the GitOps repository.
+// The GitOpsDeployment is no longer reported once it has no drifted resources.
+func SetDriftedResources(resourceName string, resourceNamespace string, driftedResources int) {
+	if driftedResources > 0 {
+		GitopsdeplDriftedResources.WithLabelValues(resourceName, resourceNamespace).Set(float64(driftedResources))
+	} else {
+		GitopsdeplDriftedResources.DeleteLabelValues(resourceName, resourceNamespace)
+	}
+}
+
 func ClearMetrics() {
 	Gitopsdepl.Set(0)
 	GitopsdeplFailures.Set(0)
+	GitopsdeplDriftedResources.Reset()
 	activeGitOpsDeployments.mutex.Lock()
 	defer activeGitOpsDeployments.mutex.Unlock()
 
@@ -129,6 +153,6 @@ func ClearMetrics() {
 }
 
 func init() {
-	metric.Registry.MustRegister(Gitopsdepl, GitopsdeplFailures, OperationDBRows, OperationDBRowsInWaitingState, OperationDBRowsIn_InProgressState,
+	metric.Registry.MustRegister(Gitopsdepl, GitopsdeplFailures, GitopsdeplDriftedResources, OperationDBRows, OperationDBRowsInWaitingState, OperationDBRowsIn_InProgressState,
 		OperationDBRowsInCompletedState, OperationDBRowsInErrorState, TotalOperationDBRowsInCompletedState, TotalOperationDBRowsInNonCompleteState)
 }
@@ -85,4 +85,35 @@ var _ = Describe("Test for Gitopsdeployment metrics counter", func() {
 			Expect(activeGitOpsDeployments.gitOpsDeployments[key]).To(BeTrue())
 		})
 	})
+
+	Context("Prometheus metrics report the number of drifted resources of each GitOpsDeployment", func() {
+		It("tests SetDriftedResources and RemoveGitOpsDeployment on a drifted gitops deployment", func() {
+
+			ClearMetrics()
+
+			gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-gitops-depl",
+					Namespace: "gitops-depl-namespace",
+					UID:       uuid.NewUUID(),
+				},
+			}
+
+			AddOrUpdateGitOpsDeployment(gitopsDepl.Name, gitopsDepl.Namespace, string(gitopsDepl.UID))
+
+			By("setting the number of drifted resources of the GitOpsDeployment")
+			SetDriftedResources(gitopsDepl.Name, gitopsDepl.Namespace, 3)
+			Expect(testutil.ToFloat64(GitopsdeplDriftedResources.WithLabelValues(gitopsDepl.Name, gitopsDepl.Namespace))).To(Equal(float64(3)))
+
+			By("verifying the GitOpsDeployment is no longer reported once it has no drift")
+			SetDriftedResources(gitopsDepl.Name, gitopsDepl.Namespace, 0)
+			Expect(testutil.CollectAndCount(GitopsdeplDriftedResources)).To(Equal(0))
+
+			By("verifying the GitOpsDeployment is no longer reported once it is removed")
+			SetDriftedResources(gitopsDepl.Name, gitopsDepl.Namespace, 2)
+			Expect(testutil.CollectAndCount(GitopsdeplDriftedResources)).To(Equal(1))
+			RemoveGitOpsDeployment(gitopsDepl.Name, gitopsDepl.Namespace, string(gitopsDepl.UID))
+			Expect(testutil.CollectAndCount(GitopsdeplDriftedResources)).To(Equal(0))
+		})
+	})
 })
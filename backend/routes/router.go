@@ -6,19 +6,23 @@ import (
 	"time"
 
 	restful "github.com/emicklei/go-restful/v3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	webhooks "github.com/redhat-appstudio/managed-gitops/backend/routes/webhooks"
 )
 
-func RouteInit() *http.Server {
+func RouteInit(dbQueries db.DatabaseQueries, k8sClient client.Client, webhookSecretNamespace string) *http.Server {
 	wsContainer := restful.NewContainer()
 	wsContainer.Router(restful.CurlyRouter{})
 
+	webhookHandler := webhooks.NewWebhookEventHandler(dbQueries, k8sClient, webhookSecretNamespace)
+
 	webhookR := new(restful.WebService)
 	webhookR.
 		Path("/api/v1/webhookevent").
 		Consumes(restful.MIME_JSON)
-	webhookR.Route(webhookR.POST("").To(webhooks.ParseWebhookInfo))
+	webhookR.Route(webhookR.POST("").To(webhookHandler.ParseWebhookInfo))
 	wsContainer.Add(webhookR)
 
 	log.Print("Main: the server is up, and listening to port 8090 on your host.")
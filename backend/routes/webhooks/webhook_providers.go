@@ -0,0 +1,508 @@
+package routes
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/github"
+)
+
+// gitPushEvent is the provider-independent representation of a push to a Git repository: the webhook push events of
+// each Git provider are normalized into a gitPushEvent.
+type gitPushEvent struct {
+	// RepositoryURLs are the URLs of the pushed repository, for example, the HTTPS and SSH clone URLs.
+	RepositoryURLs []string
+
+	// Ref is the fully qualified ref that was pushed, for example 'refs/heads/main' or 'refs/tags/v1.0.0'.
+	Ref string
+
+	// Commit is the commit SHA that the ref was updated to.
+	Commit string
+
+	// DefaultBranch is the default branch of the repository, or empty if the provider does not send it.
+	DefaultBranch string
+}
+
+// webhookProvider validates and parses the webhook events of a particular Git provider (GitHub, GitLab, etc).
+type webhookProvider interface {
+	// name returns the name of the Git provider, for example 'github'
+	name() string
+
+	// isProviderOfRequest returns true if the webhook request was sent by the Git provider, based on its headers.
+	isProviderOfRequest(header http.Header) bool
+
+	// getEventType returns the type of the webhook event, or empty if the header is missing.
+	getEventType(header http.Header) string
+
+	// getDeliveryID returns the unique ID of the webhook event delivery, or empty if the header is missing.
+	getDeliveryID(header http.Header) string
+
+	// getSignature returns the signature (or token) that is used to validate the webhook event, or empty if the header
+	// is missing.
+	getSignature(header http.Header) string
+
+	// validateSignature validates the signature (or token) of the webhook event, using the webhook secret of the
+	// repository.
+	validateSignature(signature string, payload []byte, webhookSecret []byte) error
+
+	// getRepositoryURLs returns the URLs of the repository that the webhook event was sent for.
+	getRepositoryURLs(payload []byte) ([]string, error)
+
+	// parsePushEvents returns the push events contained in the webhook event, or nil if the webhook event is not a
+	// push event.
+	parsePushEvents(eventType string, payload []byte) ([]gitPushEvent, error)
+}
+
+// webhookProviders is the list of supported Git providers, in the order in which they are matched against requests.
+// - Gitea also sends the GitHub headers, so it must be matched before GitHub.
+var webhookProviders = []webhookProvider{
+	giteaWebhookProvider{},
+	gitlabWebhookProvider{},
+	bitbucketWebhookProvider{},
+	githubWebhookProvider{},
+}
+
+// getWebhookProvider returns the Git provider that sent the webhook request, or nil if it is not recognized.
+func getWebhookProvider(header http.Header) webhookProvider {
+	for _, provider := range webhookProviders {
+		if provider.isProviderOfRequest(header) {
+			return provider
+		}
+	}
+	return nil
+}
+
+// uniqueNonEmptyStrings returns the values that are not empty, without duplicates, in their original order.
+func uniqueNonEmptyStrings(values ...string) []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			res = append(res, value)
+		}
+	}
+	return res
+}
+
+// ----------------------------------------------------------------------------
+// GitHub
+// ----------------------------------------------------------------------------
+
+// githubWebhookProvider handles the webhook events of GitHub: the payload is signed with HMAC SHA-256 in the
+// 'X-Hub-Signature-256' header.
+type githubWebhookProvider struct{}
+
+func (githubWebhookProvider) name() string {
+	return "github"
+}
+
+func (githubWebhookProvider) isProviderOfRequest(header http.Header) bool {
+	return header.Get("X-GitHub-Event") != ""
+}
+
+func (githubWebhookProvider) getEventType(header http.Header) string {
+	return header.Get("X-GitHub-Event")
+}
+
+func (githubWebhookProvider) getDeliveryID(header http.Header) string {
+	return header.Get("X-GitHub-Delivery")
+}
+
+func (githubWebhookProvider) getSignature(header http.Header) string {
+	return header.Get("X-Hub-Signature-256")
+}
+
+func (githubWebhookProvider) validateSignature(signature string, payload []byte, webhookSecret []byte) error {
+	return validateSignature256(signature, payload, webhookSecret)
+}
+
+// githubRepositoryPayload is the subset of the fields of the repository of a GitHub (or Gitea) webhook event
+type githubRepositoryPayload struct {
+	Repository struct {
+		HTMLURL       string `json:"html_url"`
+		CloneURL      string `json:"clone_url"`
+		SSHURL        string `json:"ssh_url"`
+		GitURL        string `json:"git_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+}
+
+func (githubWebhookProvider) getRepositoryURLs(payload []byte) ([]string, error) {
+	var repoPayload githubRepositoryPayload
+	if err := json.Unmarshal(payload, &repoPayload); err != nil {
+		return nil, err
+	}
+
+	repo := repoPayload.Repository
+	return uniqueNonEmptyStrings(repo.HTMLURL, repo.CloneURL, repo.SSHURL, repo.GitURL), nil
+}
+
+func (githubWebhookProvider) parsePushEvents(eventType string, payload []byte) ([]gitPushEvent, error) {
+
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	pushEvent, isPushEvent := event.(*github.PushEvent)
+	if !isPushEvent {
+		return nil, nil
+	}
+
+	repo := pushEvent.GetRepo()
+
+	return []gitPushEvent{{
+		RepositoryURLs: uniqueNonEmptyStrings(repo.GetHTMLURL(), repo.GetCloneURL(), repo.GetSSHURL(), repo.GetGitURL()),
+		Ref:            pushEvent.GetRef(),
+		Commit:         pushEvent.GetAfter(),
+		DefaultBranch:  repo.GetDefaultBranch(),
+	}}, nil
+}
+
+// ----------------------------------------------------------------------------
+// Gitea
+// ----------------------------------------------------------------------------
+
+// giteaWebhookProvider handles the webhook events of Gitea: the payload is signed with HMAC SHA-256 in the
+// 'X-Gitea-Signature' header, which (unlike GitHub) is not prefixed with 'sha256='.
+type giteaWebhookProvider struct{}
+
+func (giteaWebhookProvider) name() string {
+	return "gitea"
+}
+
+func (giteaWebhookProvider) isProviderOfRequest(header http.Header) bool {
+	return header.Get("X-Gitea-Event") != ""
+}
+
+func (giteaWebhookProvider) getEventType(header http.Header) string {
+	return header.Get("X-Gitea-Event")
+}
+
+func (giteaWebhookProvider) getDeliveryID(header http.Header) string {
+	return header.Get("X-Gitea-Delivery")
+}
+
+func (giteaWebhookProvider) getSignature(header http.Header) string {
+	return header.Get("X-Gitea-Signature")
+}
+
+func (giteaWebhookProvider) validateSignature(signature string, payload []byte, webhookSecret []byte) error {
+	return validateHMACSHA256(signature, payload, webhookSecret)
+}
+
+func (giteaWebhookProvider) getRepositoryURLs(payload []byte) ([]string, error) {
+	// The repository of a Gitea webhook event has the same fields as GitHub
+	return githubWebhookProvider{}.getRepositoryURLs(payload)
+}
+
+// giteaPushPayload is the subset of the fields of a Gitea push event
+type giteaPushPayload struct {
+	githubRepositoryPayload
+	Ref   string `json:"ref"`
+	After string `json:"after"`
+}
+
+func (giteaWebhookProvider) parsePushEvents(eventType string, payload []byte) ([]gitPushEvent, error) {
+
+	if eventType != "push" {
+		return nil, nil
+	}
+
+	var pushPayload giteaPushPayload
+	if err := json.Unmarshal(payload, &pushPayload); err != nil {
+		return nil, err
+	}
+
+	repo := pushPayload.Repository
+
+	return []gitPushEvent{{
+		RepositoryURLs: uniqueNonEmptyStrings(repo.HTMLURL, repo.CloneURL, repo.SSHURL),
+		Ref:            pushPayload.Ref,
+		Commit:         pushPayload.After,
+		DefaultBranch:  repo.DefaultBranch,
+	}}, nil
+}
+
+// ----------------------------------------------------------------------------
+// GitLab
+// ----------------------------------------------------------------------------
+
+const (
+	gitlabEventPushHook    = "Push Hook"
+	gitlabEventTagPushHook = "Tag Push Hook"
+)
+
+// gitlabWebhookProvider handles the webhook events of GitLab: GitLab does not sign the payload, but instead sends the
+// webhook secret token as-is in the 'X-Gitlab-Token' header.
+type gitlabWebhookProvider struct{}
+
+func (gitlabWebhookProvider) name() string {
+	return "gitlab"
+}
+
+func (gitlabWebhookProvider) isProviderOfRequest(header http.Header) bool {
+	return header.Get("X-Gitlab-Event") != ""
+}
+
+func (gitlabWebhookProvider) getEventType(header http.Header) string {
+	return header.Get("X-Gitlab-Event")
+}
+
+func (gitlabWebhookProvider) getDeliveryID(header http.Header) string {
+	return header.Get("X-Gitlab-Event-UUID")
+}
+
+func (gitlabWebhookProvider) getSignature(header http.Header) string {
+	return header.Get("X-Gitlab-Token")
+}
+
+func (gitlabWebhookProvider) validateSignature(signature string, payload []byte, webhookSecret []byte) error {
+	if subtle.ConstantTimeCompare([]byte(signature), webhookSecret) != 1 {
+		return fmt.Errorf("token does not match the webhook secret")
+	}
+	return nil
+}
+
+// gitlabPushPayload is the subset of the fields of a GitLab push (or tag push) event
+type gitlabPushPayload struct {
+	ObjectKind  string `json:"object_kind"`
+	Ref         string `json:"ref"`
+	After       string `json:"after"`
+	CheckoutSHA string `json:"checkout_sha"`
+	Project     struct {
+		WebURL        string `json:"web_url"`
+		GitHTTPURL    string `json:"git_http_url"`
+		GitSSHURL     string `json:"git_ssh_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"project"`
+	Repository struct {
+		Homepage   string `json:"homepage"`
+		GitHTTPURL string `json:"git_http_url"`
+		GitSSHURL  string `json:"git_ssh_url"`
+	} `json:"repository"`
+}
+
+func (gitlabWebhookProvider) getRepositoryURLs(payload []byte) ([]string, error) {
+	var pushPayload gitlabPushPayload
+	if err := json.Unmarshal(payload, &pushPayload); err != nil {
+		return nil, err
+	}
+
+	return uniqueNonEmptyStrings(pushPayload.Project.WebURL, pushPayload.Project.GitHTTPURL, pushPayload.Project.GitSSHURL,
+		pushPayload.Repository.Homepage, pushPayload.Repository.GitHTTPURL, pushPayload.Repository.GitSSHURL), nil
+}
+
+func (p gitlabWebhookProvider) parsePushEvents(eventType string, payload []byte) ([]gitPushEvent, error) {
+
+	if eventType != gitlabEventPushHook && eventType != gitlabEventTagPushHook {
+		return nil, nil
+	}
+
+	var pushPayload gitlabPushPayload
+	if err := json.Unmarshal(payload, &pushPayload); err != nil {
+		return nil, err
+	}
+
+	repoURLs, err := p.getRepositoryURLs(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	commit := pushPayload.CheckoutSHA
+	if commit == "" {
+		commit = pushPayload.After
+	}
+
+	return []gitPushEvent{{
+		RepositoryURLs: repoURLs,
+		Ref:            pushPayload.Ref,
+		Commit:         commit,
+		DefaultBranch:  pushPayload.Project.DefaultBranch,
+	}}, nil
+}
+
+// ----------------------------------------------------------------------------
+// Bitbucket
+// ----------------------------------------------------------------------------
+
+const (
+	bitbucketCloudEventPush        = "repo:push"
+	bitbucketCloudChangeTypeBranch = "branch"
+	bitbucketCloudChangeTypeTag    = "tag"
+	bitbucketCloudSSHURLPrefix     = "git@bitbucket.org:"
+
+	bitbucketServerEventRefsChanged  = "repo:refs_changed"
+	bitbucketServerChangeTypeDelete  = "DELETE"
+	bitbucketServerRefTypeBranch     = "BRANCH"
+	bitbucketServerRefTypeTag        = "TAG"
+	bitbucketServerCloneLinkNameHTTP = "http"
+	bitbucketServerCloneLinkNameSSH  = "ssh"
+)
+
+// bitbucketWebhookProvider handles the webhook events of both Bitbucket Cloud and Bitbucket Server (Data Center):
+// the payload is signed with HMAC SHA-256 in the 'X-Hub-Signature' header, prefixed with 'sha256='.
+//
+// Bitbucket does not include the default branch of the repository in its push events.
+type bitbucketWebhookProvider struct{}
+
+func (bitbucketWebhookProvider) name() string {
+	return "bitbucket"
+}
+
+func (bitbucketWebhookProvider) isProviderOfRequest(header http.Header) bool {
+	return header.Get("X-Event-Key") != ""
+}
+
+func (bitbucketWebhookProvider) getEventType(header http.Header) string {
+	return header.Get("X-Event-Key")
+}
+
+func (bitbucketWebhookProvider) getDeliveryID(header http.Header) string {
+	// Bitbucket Cloud sends 'X-Request-UUID', while Bitbucket Server sends 'X-Request-Id'
+	if deliveryID := header.Get("X-Request-UUID"); deliveryID != "" {
+		return deliveryID
+	}
+	return header.Get("X-Request-Id")
+}
+
+func (bitbucketWebhookProvider) getSignature(header http.Header) string {
+	return header.Get("X-Hub-Signature")
+}
+
+func (bitbucketWebhookProvider) validateSignature(signature string, payload []byte, webhookSecret []byte) error {
+	return validateSignature256(signature, payload, webhookSecret)
+}
+
+// bitbucketPushPayload is the subset of the fields of a Bitbucket Cloud 'repo:push' event, and a Bitbucket Server
+// 'repo:refs_changed' event.
+type bitbucketPushPayload struct {
+	Repository struct {
+		// FullName is the '(workspace)/(repository)' name of a Bitbucket Cloud repository
+		FullName string `json:"full_name"`
+		Links    struct {
+			// HTML is the web URL of a Bitbucket Cloud repository
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+			// Clone are the clone URLs of a Bitbucket Server repository
+			Clone []struct {
+				Href string `json:"href"`
+				Name string `json:"name"`
+			} `json:"clone"`
+		} `json:"links"`
+	} `json:"repository"`
+
+	// Push contains the changes of a Bitbucket Cloud event
+	Push struct {
+		Changes []struct {
+			// New is the new state of the branch or tag, or nil if it was deleted
+			New *struct {
+				Type   string `json:"type"`
+				Name   string `json:"name"`
+				Target struct {
+					Hash string `json:"hash"`
+				} `json:"target"`
+			} `json:"new"`
+		} `json:"changes"`
+	} `json:"push"`
+
+	// Changes contains the changes of a Bitbucket Server event
+	Changes []struct {
+		Ref struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"ref"`
+		ToHash string `json:"toHash"`
+		Type   string `json:"type"`
+	} `json:"changes"`
+}
+
+func (bitbucketWebhookProvider) getRepositoryURLs(payload []byte) ([]string, error) {
+	var pushPayload bitbucketPushPayload
+	if err := json.Unmarshal(payload, &pushPayload); err != nil {
+		return nil, err
+	}
+
+	repo := pushPayload.Repository
+
+	res := []string{repo.Links.HTML.Href}
+	for _, cloneLink := range repo.Links.Clone {
+		if cloneLink.Name == bitbucketServerCloneLinkNameHTTP || cloneLink.Name == bitbucketServerCloneLinkNameSSH {
+			res = append(res, cloneLink.Href)
+		}
+	}
+
+	// Bitbucket Cloud does not send the SSH clone URL: but it can be derived from the repository name.
+	if repo.FullName != "" && len(repo.Links.Clone) == 0 {
+		res = append(res, bitbucketCloudSSHURLPrefix+repo.FullName+".git")
+	}
+
+	return uniqueNonEmptyStrings(res...), nil
+}
+
+func (p bitbucketWebhookProvider) parsePushEvents(eventType string, payload []byte) ([]gitPushEvent, error) {
+
+	if eventType != bitbucketCloudEventPush && eventType != bitbucketServerEventRefsChanged {
+		return nil, nil
+	}
+
+	var pushPayload bitbucketPushPayload
+	if err := json.Unmarshal(payload, &pushPayload); err != nil {
+		return nil, err
+	}
+
+	repoURLs, err := p.getRepositoryURLs(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []gitPushEvent
+
+	// A single Bitbucket event may contain changes to multiple branches and tags: each is a separate push event.
+
+	// Bitbucket Cloud
+	for _, change := range pushPayload.Push.Changes {
+		if change.New == nil {
+			// The branch or tag was deleted, so there is nothing to refresh
+			continue
+		}
+
+		var ref string
+		if change.New.Type == bitbucketCloudChangeTypeBranch {
+			ref = gitRefBranchPrefix + change.New.Name
+		} else if change.New.Type == bitbucketCloudChangeTypeTag {
+			ref = gitRefTagPrefix + change.New.Name
+		} else {
+			continue
+		}
+
+		res = append(res, gitPushEvent{
+			RepositoryURLs: repoURLs,
+			Ref:            ref,
+			Commit:         change.New.Target.Hash,
+		})
+	}
+
+	// Bitbucket Server
+	for _, change := range pushPayload.Changes {
+		if change.Type == bitbucketServerChangeTypeDelete {
+			// The branch or tag was deleted, so there is nothing to refresh
+			continue
+		}
+		if change.Ref.Type != bitbucketServerRefTypeBranch && change.Ref.Type != bitbucketServerRefTypeTag {
+			continue
+		}
+
+		res = append(res, gitPushEvent{
+			RepositoryURLs: repoURLs,
+			Ref:            change.Ref.ID,
+			Commit:         change.ToHash,
+		})
+	}
+
+	return res, nil
+}
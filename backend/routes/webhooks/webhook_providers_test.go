@@ -0,0 +1,273 @@
+package routes
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+)
+
+// providerFixture is a webhook push event that was recorded from a Git provider, along with the request headers that
+// the provider sends with it.
+type providerFixture struct {
+	file     string
+	provider string
+	header   map[string]string
+
+	// sign returns the signature (or token) header value that the Git provider would send for the payload
+	sign func(payload []byte, webhookSecret string) string
+
+	// signatureHeader is the name of the header containing the signature (or token)
+	signatureHeader string
+
+	expectedRepositoryURL string
+	expectedPushEvents    []gitPushEvent
+}
+
+func hmacSHA256Hex(payload []byte, webhookSecret string) string {
+	mac := hmac.New(sha256.New, []byte(webhookSecret))
+	_, err := mac.Write(payload)
+	Expect(err).To(BeNil())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func signWithSHA256Prefix(payload []byte, webhookSecret string) string {
+	return "sha256=" + hmacSHA256Hex(payload, webhookSecret)
+}
+
+func signWithToken(payload []byte, webhookSecret string) string {
+	return webhookSecret
+}
+
+var providerFixtures = []providerFixture{
+	{
+		file:     "github-push.json",
+		provider: "github",
+		header: map[string]string{
+			"X-GitHub-Event":    "push",
+			"X-GitHub-Delivery": "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+		},
+		sign:                  signWithSHA256Prefix,
+		signatureHeader:       "X-Hub-Signature-256",
+		expectedRepositoryURL: "https://github.com/redhat-appstudio/managed-gitops.git",
+		expectedPushEvents: []gitPushEvent{{
+			RepositoryURLs: []string{
+				"https://github.com/redhat-appstudio/managed-gitops",
+				"https://github.com/redhat-appstudio/managed-gitops.git",
+				"git@github.com:redhat-appstudio/managed-gitops.git",
+				"git://github.com/redhat-appstudio/managed-gitops.git",
+			},
+			Ref:           "refs/heads/main",
+			Commit:        "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+			DefaultBranch: "main",
+		}},
+	},
+	{
+		file:     "gitlab-push.json",
+		provider: "gitlab",
+		header: map[string]string{
+			"X-Gitlab-Event":      "Push Hook",
+			"X-Gitlab-Event-UUID": "13792a34-cac6-4fda-95a8-c58e00a3954e",
+		},
+		sign:                  signWithToken,
+		signatureHeader:       "X-Gitlab-Token",
+		expectedRepositoryURL: "git@gitlab.example.com:team/app-gitops.git",
+		expectedPushEvents: []gitPushEvent{{
+			RepositoryURLs: []string{
+				"https://gitlab.example.com/team/app-gitops",
+				"https://gitlab.example.com/team/app-gitops.git",
+				"git@gitlab.example.com:team/app-gitops.git",
+			},
+			Ref:           "refs/heads/main",
+			Commit:        "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+			DefaultBranch: "main",
+		}},
+	},
+	{
+		file:     "gitea-push.json",
+		provider: "gitea",
+		header: map[string]string{
+			// Gitea also sends the GitHub headers
+			"X-Gitea-Event":     "push",
+			"X-Gitea-Delivery":  "f6266f16-1bf3-46a5-9ea4-602e06ead473",
+			"X-GitHub-Event":    "push",
+			"X-GitHub-Delivery": "f6266f16-1bf3-46a5-9ea4-602e06ead473",
+		},
+		sign:                  hmacSHA256Hex,
+		signatureHeader:       "X-Gitea-Signature",
+		expectedRepositoryURL: "https://gitea.example.com/team/app-gitops",
+		expectedPushEvents: []gitPushEvent{{
+			RepositoryURLs: []string{
+				"https://gitea.example.com/team/app-gitops",
+				"https://gitea.example.com/team/app-gitops.git",
+				"git@gitea.example.com:team/app-gitops.git",
+			},
+			Ref:           "refs/heads/develop",
+			Commit:        "bffeb74224043ba2feb48d137756c8a9331c449a",
+			DefaultBranch: "main",
+		}},
+	},
+	{
+		file:     "bitbucket-cloud-push.json",
+		provider: "bitbucket",
+		header: map[string]string{
+			"X-Event-Key":    "repo:push",
+			"X-Request-UUID": "2f4a9f0e-5b7c-4d3e-8a1b-6c9d0e1f2a3b",
+			"X-Hook-UUID":    "a3d7b5e1-8c2f-4e6a-9b0d-1f3e5a7c9b2d",
+		},
+		sign:                  signWithSHA256Prefix,
+		signatureHeader:       "X-Hub-Signature",
+		expectedRepositoryURL: "git@bitbucket.org:team/app-gitops.git",
+		expectedPushEvents: []gitPushEvent{
+			{
+				RepositoryURLs: []string{"https://bitbucket.org/team/app-gitops", "git@bitbucket.org:team/app-gitops.git"},
+				Ref:            "refs/heads/main",
+				Commit:         "7c3f6b1a9e2d4c8b0f1e3a5d7c9b2e4f6a8c0d2e",
+			},
+			// The deleted branch is skipped
+			{
+				RepositoryURLs: []string{"https://bitbucket.org/team/app-gitops", "git@bitbucket.org:team/app-gitops.git"},
+				Ref:            "refs/tags/v1.2.0",
+				Commit:         "7c3f6b1a9e2d4c8b0f1e3a5d7c9b2e4f6a8c0d2e",
+			},
+		},
+	},
+	{
+		file:     "bitbucket-server-refs-changed.json",
+		provider: "bitbucket",
+		header: map[string]string{
+			"X-Event-Key":  "repo:refs_changed",
+			"X-Request-Id": "d4b8c6a2-0e1f-4a3b-9c5d-7e8f9a0b1c2d",
+		},
+		sign:                  signWithSHA256Prefix,
+		signatureHeader:       "X-Hub-Signature",
+		expectedRepositoryURL: "https://bitbucket.example.com/scm/team/app-gitops.git",
+		expectedPushEvents: []gitPushEvent{
+			// The deleted branch is skipped
+			{
+				RepositoryURLs: []string{
+					"ssh://git@bitbucket.example.com:7999/team/app-gitops.git",
+					"https://bitbucket.example.com/scm/team/app-gitops.git",
+				},
+				Ref:    "refs/heads/main",
+				Commit: "178864a7d521b6f5e720b386b2c2b0ef8563e0dc",
+			},
+		},
+	},
+}
+
+func readFixture(fixture providerFixture) []byte {
+	payload, err := os.ReadFile(filepath.Join("testdata", fixture.file))
+	Expect(err).To(BeNil())
+	return payload
+}
+
+func fixtureHeader(fixture providerFixture) http.Header {
+	header := http.Header{}
+	for key, value := range fixture.header {
+		header.Set(key, value)
+	}
+	return header
+}
+
+var _ = Describe("Webhook provider tests", func() {
+
+	const webhookSecret = "my-webhook-secret"
+
+	for idx := range providerFixtures {
+
+		fixture := providerFixtures[idx]
+
+		Context("Test the recorded push event fixture "+fixture.file, func() {
+
+			It("should recognize the Git provider, and normalize the push events", func() {
+				payload := readFixture(fixture)
+				header := fixtureHeader(fixture)
+
+				provider := getWebhookProvider(header)
+				Expect(provider).ToNot(BeNil())
+				Expect(provider.name()).To(Equal(fixture.provider))
+				Expect(provider.getEventType(header)).ToNot(BeEmpty())
+				Expect(provider.getDeliveryID(header)).ToNot(BeEmpty())
+
+				repoURLs, err := provider.getRepositoryURLs(payload)
+				Expect(err).To(BeNil())
+				Expect(repoURLs).To(ContainElement(fixture.expectedRepositoryURL))
+
+				pushEvents, err := provider.parsePushEvents(provider.getEventType(header), payload)
+				Expect(err).To(BeNil())
+				Expect(pushEvents).To(Equal(fixture.expectedPushEvents))
+			})
+
+			It("should validate the signature (or token) of the webhook event", func() {
+				payload := readFixture(fixture)
+				provider := getWebhookProvider(fixtureHeader(fixture))
+				Expect(provider).ToNot(BeNil())
+
+				Expect(provider.validateSignature(fixture.sign(payload, webhookSecret), payload, []byte(webhookSecret))).To(Succeed())
+
+				By("using a different secret")
+				Expect(provider.validateSignature(fixture.sign(payload, "another-secret"), payload, []byte(webhookSecret))).ToNot(Succeed())
+
+				By("using an empty signature")
+				Expect(provider.validateSignature("", payload, []byte(webhookSecret))).ToNot(Succeed())
+			})
+
+			It("should only accept the webhook event when it is signed with the webhook secret of the repository", func() {
+				payload := readFixture(fixture)
+
+				scheme, _, _, _, err := tests.GenericTestSetup()
+				Expect(err).To(BeNil())
+
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "managed-gitops-webhook",
+						Namespace: "gitops-service-argocd",
+					},
+					Type: sharedutil.WebhookSecretType,
+					Data: map[string][]byte{
+						sharedutil.WebhookSecretRepositoryURLKey: []byte(fixture.expectedRepositoryURL),
+						sharedutil.WebhookSecretSecretKey:        []byte(webhookSecret),
+					},
+				}
+				k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+				handler := NewWebhookEventHandler(nil, k8sClient, secret.Namespace)
+
+				handleRequest := func(signature string) int {
+					httpRequest := httptest.NewRequest(http.MethodPost, "/api/v1/webhookevent", strings.NewReader(string(payload)))
+					for key, value := range fixture.header {
+						httpRequest.Header.Set(key, value)
+					}
+					httpRequest.Header.Set(fixture.signatureHeader, signature)
+
+					recorder := httptest.NewRecorder()
+					handler.ParseWebhookInfo(restful.NewRequest(httpRequest), restful.NewResponse(recorder))
+					return recorder.Code
+				}
+
+				Expect(handleRequest(fixture.sign(payload, "another-secret"))).To(Equal(http.StatusUnauthorized))
+
+				By("removing the webhook secret of the repository")
+				err = k8sClient.Delete(context.Background(), secret)
+				Expect(err).To(BeNil())
+				Expect(handleRequest(fixture.sign(payload, webhookSecret))).To(Equal(http.StatusForbidden))
+			})
+		})
+	}
+})
@@ -0,0 +1,170 @@
+package routes
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+)
+
+const (
+	// signature256Prefix is the prefix of the value of the 'X-Hub-Signature-256' header: the value is the hex-encoded
+	// HMAC SHA-256 digest of the payload, prefixed with 'sha256='.
+	signature256Prefix = "sha256="
+
+	// deliveryIDExpiry is how long a webhook event is remembered for, after which a webhook event with the same
+	// delivery ID or payload would no longer be detected as a replay.
+	deliveryIDExpiry = 24 * time.Hour
+)
+
+// getWebhookSecretForRepository returns the webhook secret of the Secret (of type WebhookSecretType) in the given
+// namespace, whose repository URL matches any of the given repository URLs.
+// returns nil, if no webhook secret is configured for the repository.
+func getWebhookSecretForRepository(ctx context.Context, k8sClient client.Client, namespace string, repoURLs []string) ([]byte, error) {
+
+	var secretList corev1.SecretList
+	if err := k8sClient.List(ctx, &secretList, &client.ListOptions{Namespace: namespace}); err != nil {
+		return nil, fmt.Errorf("unable to list webhook secrets in namespace '%s': %v", namespace, err)
+	}
+
+	normalizedRepoURLs := map[string]bool{}
+	for _, repoURL := range repoURLs {
+		if normalizedRepoURL := shared_resource_loop.NormalizeGitURL(repoURL); normalizedRepoURL != "" {
+			normalizedRepoURLs[normalizedRepoURL] = true
+		}
+	}
+
+	for idx := range secretList.Items {
+		secret := secretList.Items[idx]
+
+		if secret.Type != sharedutil.WebhookSecretType {
+			continue
+		}
+
+		secretRepoURL := shared_resource_loop.NormalizeGitURL(string(secret.Data[sharedutil.WebhookSecretRepositoryURLKey]))
+		if secretRepoURL == "" || !normalizedRepoURLs[secretRepoURL] {
+			continue
+		}
+
+		webhookSecret := secret.Data[sharedutil.WebhookSecretSecretKey]
+		if len(webhookSecret) == 0 {
+			return nil, fmt.Errorf("webhook secret '%s' does not contain a '%s' value", secret.Name, sharedutil.WebhookSecretSecretKey)
+		}
+
+		return webhookSecret, nil
+	}
+
+	return nil, nil
+}
+
+// validateSignature256 verifies that the signature header value (for example, 'X-Hub-Signature-256' of GitHub) is
+// the HMAC SHA-256 digest of the payload prefixed with 'sha256=', using the webhook secret as the key.
+func validateSignature256(signature string, payload []byte, webhookSecret []byte) error {
+
+	if !strings.HasPrefix(signature, signature256Prefix) {
+		return fmt.Errorf("signature does not have the '%s' prefix", signature256Prefix)
+	}
+
+	return validateHMACSHA256(strings.TrimPrefix(signature, signature256Prefix), payload, webhookSecret)
+}
+
+// validateHMACSHA256 verifies that the hex encoded signature is the HMAC SHA-256 digest of the payload, using the
+// webhook secret as the key.
+func validateHMACSHA256(hexSignature string, payload []byte, webhookSecret []byte) error {
+
+	signatureMAC, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return fmt.Errorf("signature is not hex encoded: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, webhookSecret)
+	if _, err := mac.Write(payload); err != nil {
+		return err
+	}
+
+	if !hmac.Equal(signatureMAC, mac.Sum(nil)) {
+		return fmt.Errorf("signature does not match the payload")
+	}
+
+	return nil
+}
+
+// deliveryIDCache remembers the webhook events that have been processed, so that a webhook event that is sent again
+// (replayed) can be rejected.
+//
+// The delivery ID header of an event is not covered by the signature of the payload, and so could be changed by
+// whoever replays the event: events are thus remembered both by their delivery ID, and by the SHA-256 digest of
+// their payload (see deliveryKeys).
+//
+// Events are only remembered for deliveryIDExpiry, and are not persisted across restarts of the backend: since
+// the events only trigger a refresh of the affected Applications, a replay that is not detected is harmless.
+type deliveryIDCache struct {
+	mutex sync.Mutex
+
+	// deliveryIDs is a map from delivery key (see deliveryKeys) to the time at which it was first seen
+	deliveryIDs map[string]time.Time
+}
+
+func newDeliveryIDCache() *deliveryIDCache {
+	return &deliveryIDCache{
+		deliveryIDs: map[string]time.Time{},
+	}
+}
+
+// deliveryKeys returns the keys that a webhook event is remembered by: the delivery ID of the event, and the
+// SHA-256 digest of its payload.
+func deliveryKeys(providerName string, webhook *WebHookInfo) []string {
+	payloadDigest := sha256.Sum256(webhook.Payload)
+
+	return []string{
+		providerName + "/id/" + webhook.Id,
+		providerName + "/payload/" + hex.EncodeToString(payloadDigest[:]),
+	}
+}
+
+// addIfNotSeen records the delivery keys, and returns true, if none of the keys have been seen (within the expiry
+// window) before. Otherwise, returns false, and none of the keys are recorded.
+func (c *deliveryIDCache) addIfNotSeen(deliveryKeys []string, now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Remove the delivery keys that have expired
+	for key, seen := range c.deliveryIDs {
+		if now.Sub(seen) > deliveryIDExpiry {
+			delete(c.deliveryIDs, key)
+		}
+	}
+
+	for _, key := range deliveryKeys {
+		if _, exists := c.deliveryIDs[key]; exists {
+			return false
+		}
+	}
+
+	for _, key := range deliveryKeys {
+		c.deliveryIDs[key] = now
+	}
+
+	return true
+}
+
+// remove forgets the delivery keys, so that an event that could not be processed is accepted when it is delivered
+// again.
+func (c *deliveryIDCache) remove(deliveryKeys []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, key := range deliveryKeys {
+		delete(c.deliveryIDs, key)
+	}
+}
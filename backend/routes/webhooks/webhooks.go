@@ -1,86 +1,424 @@
 package routes
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
 	"io"
-	"log"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/emicklei/go-restful/v3"
-	"github.com/google/go-github/github"
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
+	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+)
+
+const (
+	// refreshOperationGCExpirationTime is the time (in seconds) after which a completed or failed RefreshApplication
+	// Operation is garbage collected by the cluster-agent: these Operations are not waited on, and so are not otherwise
+	// cleaned up.
+	refreshOperationGCExpirationTime = 10 * 60
+
+	// maxPayloadSize is the maximum size of a webhook payload: GitHub caps payloads at 25 MB.
+	maxPayloadSize = 25 * 1024 * 1024
+
+	gitRefBranchPrefix = "refs/heads/"
+	gitRefTagPrefix    = "refs/tags/"
 )
 
 type WebHookInfo struct {
-	Id        string // Id for the github webhook request
+	Id        string // Id for the webhook request
 	Event     string // indicates which event took place (push, starred, pull request etc)
-	Signature string // signature of the github webhook request
+	Signature string // signature (or token) of the webhook request
 	Payload   []byte // consists of all the contents within the webhook
 }
 
-func ParseWebhookInfo(request *restful.Request, response *restful.Response) {
+// WebhookEventHandler handles the webhook events that are sent by GitHub, GitLab, Bitbucket (Cloud and Server) and
+// Gitea. The push events of each Git provider are normalized into a single gitPushEvent.
+//
+// The signature (or token) of each webhook event is validated using the webhook secret of its repository: webhook
+// secrets are held in Secrets of type WebhookSecretType, in the webhook secret namespace. Webhook events are rejected
+// if no webhook secret is configured for the repository, or if the event has previously been delivered.
+//
+// On a push event, an Operation is created for every Application that is deployed from the pushed repository and
+// revision, which asks the cluster-agent to refresh the corresponding Argo CD Application. This allows the change to
+// be picked up within seconds, rather than waiting for the next Argo CD repository poll. The affected Applications are
+// found via the GitOpsDeployments that reference the repository, using a field index of the cache of K8sClient.
+type WebhookEventHandler struct {
+	DBQueries db.DatabaseQueries
+	K8sClient client.Client
+
+	// K8sClientFactory returns the client of the cluster of a GitOpsEngineInstance, which Operations are created on
+	K8sClientFactory shared_resource_loop.SRLK8sClientFactory
+
+	// WebhookSecretNamespace is the namespace containing the webhook secrets of the repositories
+	WebhookSecretNamespace string
+
+	deliveryIDs *deliveryIDCache
+}
+
+func NewWebhookEventHandler(dbQueries db.DatabaseQueries, k8sClient client.Client, webhookSecretNamespace string) *WebhookEventHandler {
+	return &WebhookEventHandler{
+		DBQueries:              dbQueries,
+		K8sClient:              k8sClient,
+		K8sClientFactory:       shared_resource_loop.DefaultK8sClientFactory{},
+		WebhookSecretNamespace: webhookSecretNamespace,
+		deliveryIDs:            newDeliveryIDCache(),
+	}
+}
+
+func (h *WebhookEventHandler) ParseWebhookInfo(request *restful.Request, response *restful.Response) {
+
+	ctx := request.Request.Context()
+
+	log := log.FromContext(ctx).
+		WithName(logutil.LogLogger_managed_gitops).
+		WithValues("component", "webhook-event-handler")
+
 	webhook := new(WebHookInfo)
 	if !strings.EqualFold(request.Request.Method, "POST") {
-		log.Fatalln(nil, errors.New("POST method not found, unknown method occurred!"))
+		writeErrorResponse(response, http.StatusMethodNotAllowed, "POST method not found, unknown method occurred", log)
+		return
 	}
-	if webhook.Event = request.Request.Header.Get("x-github-event"); len(webhook.Event) == 0 {
-		log.Fatalln(nil, errors.New("No event!"))
+
+	provider := getWebhookProvider(request.Request.Header)
+	if provider == nil {
+		writeErrorResponse(response, http.StatusBadRequest, "unrecognized webhook provider", log)
+		return
 	}
-	if webhook.Id = request.Request.Header.Get("x-github-delivery"); len(webhook.Id) == 0 {
-		log.Fatalln(nil, errors.New("No event Id!"))
+
+	if webhook.Event = provider.getEventType(request.Request.Header); len(webhook.Event) == 0 {
+		writeErrorResponse(response, http.StatusBadRequest, "no event", log)
+		return
 	}
+	if webhook.Id = provider.getDeliveryID(request.Request.Header); len(webhook.Id) == 0 {
+		writeErrorResponse(response, http.StatusBadRequest, "no event id", log)
+		return
+	}
+	if webhook.Signature = provider.getSignature(request.Request.Header); len(webhook.Signature) == 0 {
+		writeErrorResponse(response, http.StatusUnauthorized, "no signature", log)
+		return
+	}
+
+	log = log.WithValues("provider", provider.name(), "event", webhook.Event, "deliveryID", webhook.Id)
+
 	// assigning payload data
-	payload, err := io.ReadAll(request.Request.Body)
+	payload, err := io.ReadAll(http.MaxBytesReader(response, request.Request.Body, maxPayloadSize))
 	if err != nil {
-		log.Printf("error reading request body: err=%s\n", err)
+		log.Error(err, "error reading request body")
+		writeErrorResponse(response, http.StatusBadRequest, "unable to read request body", log)
 		return
 	}
 	webhook.Payload = payload
 	defer func() {
 		err = request.Request.Body.Close()
 		if err != nil {
-			log.Printf("error closing request body: err=%v\n", err)
+			log.Error(err, "error closing request body")
 		}
 	}()
 
-	event, err := github.ParseWebHook(github.WebHookType(request.Request), webhook.Payload)
-	if err != nil {
-		log.Printf("could not parse webhook: err=%s\n", err)
+	// Validate the signature of the payload, using the webhook secret of the repository
+	if status, err := h.validateWebhookSignature(ctx, provider, webhook); err != nil {
+		log.Error(err, "unable to validate webhook signature")
+		writeErrorResponse(response, status, "unable to validate webhook signature", log)
 		return
 	}
 
-	// printing the payload data in structured format
-	webhookData, err := json.MarshalIndent(event, "", "  ")
-	if err != nil {
-		log.Printf("error, %v", err)
+	// Reject webhook events that have previously been delivered: only events with a valid signature are recorded, so
+	// that the delivery IDs of future events cannot be used up by a sender without the webhook secret.
+	deliveryKeys := deliveryKeys(provider.name(), webhook)
+	if !h.deliveryIDs.addIfNotSeen(deliveryKeys, time.Now()) {
+		log.Info("Rejecting webhook event, as an event with the same delivery ID or payload was previously processed")
+		writeErrorResponse(response, http.StatusConflict, "webhook event was previously delivered", log)
 		return
 	}
 
-	errWrite := response.WriteEntity(event)
-	if errWrite != nil {
-		log.Fatal(errWrite)
-	}
-
-	// classifying the type of event
-	switch e := event.(type) {
-	case *github.PushEvent:
-		// this is a commit push
-		fmt.Println("A Commit event occurred")
-		fmt.Println(string(webhookData))
-	case *github.PullRequestEvent:
-		// this is a pull request
-		fmt.Println("A pull request has been created")
-		fmt.Println(string(webhookData))
-	case *github.WatchEvent:
-		// if someone starred our repository
-		if e.Action != nil && *e.Action == "starred" {
-			fmt.Printf("%s starred repository %s\n",
-				*e.Sender.Login, *e.Repo.FullName)
-		}
-		fmt.Println(string(webhookData))
-	default:
-		log.Printf("unknown event type %s\n", github.WebHookType(request.Request))
+	// If the event could not be processed, forget it, so that it is accepted when the provider delivers it again.
+	processed := false
+	defer func() {
+		if !processed {
+			h.deliveryIDs.remove(deliveryKeys)
+		}
+	}()
+
+	pushEvents, err := provider.parsePushEvents(webhook.Event, webhook.Payload)
+	if err != nil {
+		log.Error(err, "could not parse webhook")
+		writeErrorResponse(response, http.StatusBadRequest, "unable to parse webhook payload", log)
 		return
 	}
+
+	if len(pushEvents) == 0 {
+		// Other events do not affect the deployed Applications, and so are acknowledged but otherwise ignored.
+		log.V(logutil.LogLevel_Debug).Info("Ignoring webhook event")
+	}
+
+	for _, pushEvent := range pushEvents {
+		refreshed, err := refreshApplicationsForPushEvent(ctx, pushEvent, h.DBQueries, h.K8sClient, h.K8sClientFactory, log)
+		if err != nil {
+			log.Error(err, "unable to refresh the Applications of push event")
+			writeErrorResponse(response, http.StatusInternalServerError, "unable to process push event", log)
+			return
+		}
+		log.Info("Processed push event", "repository", pushEvent.RepositoryURLs[0], "ref", pushEvent.Ref,
+			"commit", pushEvent.Commit, "refreshedApplications", refreshed)
+	}
+
+	processed = true
+	response.WriteHeader(http.StatusOK)
+}
+
+// validateWebhookSignature validates the signature of the webhook event, using the webhook secret of the repository
+// that the event was sent for.
+// returns the HTTP status to respond with, and an error, if the signature could not be validated.
+func (h *WebhookEventHandler) validateWebhookSignature(ctx context.Context, provider webhookProvider, webhook *WebHookInfo) (int, error) {
+
+	repoURLs, err := provider.getRepositoryURLs(webhook.Payload)
+	if err != nil {
+		return http.StatusBadRequest, fmt.Errorf("unable to parse repository of webhook payload: %v", err)
+	}
+	if len(repoURLs) == 0 {
+		return http.StatusBadRequest, fmt.Errorf("webhook payload does not contain a repository")
+	}
+
+	webhookSecret, err := getWebhookSecretForRepository(ctx, h.K8sClient, h.WebhookSecretNamespace, repoURLs)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if webhookSecret == nil {
+		return http.StatusForbidden, fmt.Errorf("no webhook secret is configured for repository '%s'", repoURLs[0])
+	}
+
+	if err := provider.validateSignature(webhook.Signature, webhook.Payload, webhookSecret); err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// refreshApplicationsForPushEvent creates an Operation of type RefreshApplication for every Application row that is
+// deployed from the repository and revision of the push event. The Applications are found from the GitOpsDeployments
+// whose sources reference the repository, which are listed from the GitOpsDeploymentRepoURLIndexKey field index.
+// returns the number of Applications that were refreshed
+func refreshApplicationsForPushEvent(ctx context.Context, pushEvent gitPushEvent, dbQueries db.DatabaseQueries,
+	k8sClient client.Client, k8sClientFactory shared_resource_loop.SRLK8sClientFactory, log logr.Logger) (int, error) {
+
+	if len(pushEvent.RepositoryURLs) == 0 || pushEvent.Ref == "" {
+		log.Info("Push event did not contain a repository and ref, so no Applications will be refreshed")
+		return 0, nil
+	}
+
+	gitopsDeployments, err := listGitOpsDeploymentsOfRepository(ctx, k8sClient, pushEvent.RepositoryURLs)
+	if err != nil {
+		return 0, err
+	}
+	if len(gitopsDeployments) == 0 {
+		return 0, nil
+	}
+
+	// Get the special cluster user created for internal use, because we need a ClusterUser for creating Operations,
+	// and webhook events do not have one.
+	var specialClusterUser db.ClusterUser
+	if err := dbQueries.GetOrCreateSpecialClusterUser(ctx, &specialClusterUser); err != nil {
+		return 0, fmt.Errorf("unable to fetch special cluster user: %v", err)
+	}
+
+	refreshed := 0
+
+	for idx := range gitopsDeployments {
+		gitopsDeployment := gitopsDeployments[idx]
+
+		deplToAppMapping := db.DeploymentToApplicationMapping{Deploymenttoapplicationmapping_uid_id: string(gitopsDeployment.UID)}
+		if err := dbQueries.GetDeploymentToApplicationMappingByDeplId(ctx, &deplToAppMapping); err != nil {
+			if !db.IsResultNotFoundError(err) {
+				return refreshed, fmt.Errorf("unable to retrieve the DeploymentToApplicationMapping of GitOpsDeployment '%s': %v", gitopsDeployment.Name, err)
+			}
+			// The GitOpsDeployment has not (yet) been deployed, so there is no Application to refresh
+			continue
+		}
+
+		application := db.Application{Application_id: deplToAppMapping.Application_id}
+		if err := dbQueries.GetApplicationById(ctx, &application); err != nil {
+			if !db.IsResultNotFoundError(err) {
+				return refreshed, fmt.Errorf("unable to retrieve Application '%s': %v", application.Application_id, err)
+			}
+			continue
+		}
+
+		var appArgo fauxargocd.FauxApplication
+		if err := yaml.Unmarshal([]byte(application.Spec_field), &appArgo); err != nil {
+			log.Error(err, "unable to unmarshal spec field of Application", "applicationID", application.Application_id)
+			continue
+		}
+
+		if !isApplicationAffectedByPushEvent(appArgo, pushEvent) {
+			continue
+		}
+
+		if err := createRefreshApplicationOperation(ctx, application, specialClusterUser, dbQueries, k8sClientFactory, log); err != nil {
+			log.Error(err, "unable to create refresh operation for Application", "applicationID", application.Application_id)
+			continue
+		}
+
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+// listGitOpsDeploymentsOfRepository returns the GitOpsDeployments, in any namespace, with a source that references one
+// of the given repository URLs.
+func listGitOpsDeploymentsOfRepository(ctx context.Context, k8sClient client.Client, repoURLs []string) ([]managedgitopsv1alpha1.GitOpsDeployment, error) {
+
+	var res []managedgitopsv1alpha1.GitOpsDeployment
+	seen := map[types.UID]bool{}
+
+	for _, repoURL := range repoURLs {
+		normalizedRepoURL := shared_resource_loop.NormalizeGitURL(repoURL)
+		if normalizedRepoURL == "" {
+			continue
+		}
+
+		var gitopsDeploymentList managedgitopsv1alpha1.GitOpsDeploymentList
+		if err := k8sClient.List(ctx, &gitopsDeploymentList,
+			client.MatchingFields{eventlooptypes.GitOpsDeploymentRepoURLIndexKey: normalizedRepoURL}); err != nil {
+			return nil, fmt.Errorf("unable to list the GitOpsDeployments of repository '%s': %v", repoURL, err)
+		}
+
+		for idx := range gitopsDeploymentList.Items {
+			gitopsDeployment := gitopsDeploymentList.Items[idx]
+			if seen[gitopsDeployment.UID] || !isGitOpsDeploymentOfRepository(gitopsDeployment, normalizedRepoURL) {
+				continue
+			}
+			seen[gitopsDeployment.UID] = true
+			res = append(res, gitopsDeployment)
+		}
+	}
+
+	return res, nil
+}
+
+// isGitOpsDeploymentOfRepository returns true if any of the sources of the GitOpsDeployment reference the (normalized)
+// repository URL. This is the same check as the field index, for clients that do not support field selectors.
+func isGitOpsDeploymentOfRepository(gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment, normalizedRepoURL string) bool {
+
+	sources := append([]managedgitopsv1alpha1.ApplicationSource{gitopsDeployment.Spec.Source}, gitopsDeployment.Spec.Sources...)
+	for _, source := range sources {
+		if shared_resource_loop.NormalizeGitURL(source.RepoURL) == normalizedRepoURL {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createRefreshApplicationOperation creates an Operation that asks the cluster-agent to refresh the Argo CD Application
+// of the given Application row. The Operation is created in the namespace of the GitOpsEngineInstance of the Application,
+// using the client of its cluster. The Operation is not waited on: it is garbage collected by the cluster-agent, once it
+// has completed.
+func createRefreshApplicationOperation(ctx context.Context, application db.Application, specialClusterUser db.ClusterUser,
+	dbQueries db.DatabaseQueries, k8sClientFactory shared_resource_loop.SRLK8sClientFactory, log logr.Logger) error {
+
+	gitopsEngineInstance := db.GitopsEngineInstance{Gitopsengineinstance_id: application.Engine_instance_inst_id}
+	if err := dbQueries.GetGitopsEngineInstanceById(ctx, &gitopsEngineInstance); err != nil {
+		return fmt.Errorf("unable to retrieve GitOpsEngineInstance '%s': %v", application.Engine_instance_inst_id, err)
+	}
+
+	gitopsEngineClient, err := k8sClientFactory.GetK8sClientForGitOpsEngineInstance(ctx, &gitopsEngineInstance)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the client of GitOpsEngineInstance '%s': %v", gitopsEngineInstance.Gitopsengineinstance_id, err)
+	}
+
+	dbOperationInput := db.Operation{
+		Instance_id:        application.Engine_instance_inst_id,
+		Resource_id:        application.Application_id,
+		Resource_type:      db.OperationResourceType_RefreshApplication,
+		GC_expiration_time: refreshOperationGCExpirationTime,
+	}
+
+	if _, _, err := operations.CreateOperation(ctx, false, dbOperationInput, specialClusterUser.Clusteruser_id,
+		gitopsEngineInstance.Namespace_name, dbQueries, gitopsEngineClient, log); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isApplicationAffectedByPushEvent returns true if any of the sources of the Argo CD Application reference the
+// repository and revision that were pushed to.
+func isApplicationAffectedByPushEvent(appArgo fauxargocd.FauxApplication, pushEvent gitPushEvent) bool {
+
+	sources := []fauxargocd.ApplicationSource{appArgo.Spec.Source}
+	sources = append(sources, appArgo.Spec.Sources...)
+
+	for _, source := range sources {
+		if source.RepoURL == "" {
+			continue
+		}
+		if isRepositoryOfPushEvent(source.RepoURL, pushEvent.RepositoryURLs) &&
+			isRevisionOfPushEvent(source.TargetRevision, pushEvent.Ref, pushEvent.DefaultBranch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRepositoryOfPushEvent returns true if the repository URL refers to the pushed repository, regardless of whether
+// the HTTPS or SSH form of the URL is used.
+func isRepositoryOfPushEvent(repoURL string, pushedRepoURLs []string) bool {
+
+	normalizedRepoURL := shared_resource_loop.NormalizeGitURL(repoURL)
+	if normalizedRepoURL == "" {
+		return false
+	}
+
+	for _, pushedRepoURL := range pushedRepoURLs {
+		if shared_resource_loop.NormalizeGitURL(pushedRepoURL) == normalizedRepoURL {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRevisionOfPushEvent returns true if the target revision refers to the pushed ref: either the branch or tag of the
+// same name, or the default branch if the target revision is empty or 'HEAD'.
+//
+// If the default branch is not known (not all Git providers send it), then an empty or 'HEAD' target revision is
+// assumed to refer to any pushed branch: refreshing an Application unnecessarily is harmless.
+func isRevisionOfPushEvent(targetRevision string, ref string, defaultBranch string) bool {
+
+	var pushedRevision string
+	if strings.HasPrefix(ref, gitRefBranchPrefix) {
+		pushedRevision = strings.TrimPrefix(ref, gitRefBranchPrefix)
+
+		if (targetRevision == "" || targetRevision == "HEAD") && (defaultBranch == "" || pushedRevision == defaultBranch) {
+			return true
+		}
+
+	} else if strings.HasPrefix(ref, gitRefTagPrefix) {
+		pushedRevision = strings.TrimPrefix(ref, gitRefTagPrefix)
+	} else {
+		return false
+	}
+
+	return targetRevision == pushedRevision || targetRevision == ref
+}
+
+func writeErrorResponse(response *restful.Response, status int, message string, log logr.Logger) {
+	if err := response.WriteErrorString(status, message); err != nil {
+		log.Error(err, "unable to write webhook error response")
+	}
 }
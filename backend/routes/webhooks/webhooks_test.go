@@ -0,0 +1,463 @@
+package routes
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+)
+
+const sampleWatchEvent = `{
+	"action": "started",
+	"repository": {
+		"full_name": "redhat-appstudio/managed-gitops",
+		"html_url": "https://github.com/redhat-appstudio/managed-gitops",
+		"clone_url": "https://github.com/redhat-appstudio/managed-gitops.git",
+		"ssh_url": "git@github.com:redhat-appstudio/managed-gitops.git",
+		"default_branch": "main"
+	}
+}`
+
+func newPushEvent(ref string) gitPushEvent {
+	return gitPushEvent{
+		RepositoryURLs: []string{
+			"https://github.com/redhat-appstudio/managed-gitops",
+			"https://github.com/redhat-appstudio/managed-gitops.git",
+			"git@github.com:redhat-appstudio/managed-gitops.git",
+		},
+		Ref:           ref,
+		DefaultBranch: "main",
+	}
+}
+
+func newFauxApplication(repoURL, targetRevision string) fauxargocd.FauxApplication {
+	return fauxargocd.FauxApplication{
+		FauxObjectMeta: fauxargocd.FauxObjectMeta{
+			Name:      "my-application",
+			Namespace: "argocd",
+		},
+		Spec: fauxargocd.FauxApplicationSpec{
+			Source: fauxargocd.ApplicationSource{
+				RepoURL:        repoURL,
+				Path:           "resources/test-data/sample-gitops-repository/environments/overlays/dev",
+				TargetRevision: targetRevision,
+			},
+		},
+	}
+}
+
+// failingSpecialClusterUserDBQueries fails to fetch the special cluster user the given number of times, and otherwise
+// returns no DeploymentToApplicationMappings. Other queries are not expected to be called.
+type failingSpecialClusterUserDBQueries struct {
+	db.DatabaseQueries
+	failuresRemaining int
+}
+
+func (f *failingSpecialClusterUserDBQueries) GetOrCreateSpecialClusterUser(ctx context.Context, clusterUser *db.ClusterUser) error {
+	if f.failuresRemaining > 0 {
+		f.failuresRemaining--
+		return fmt.Errorf("simulated database error")
+	}
+	return nil
+}
+
+func (f *failingSpecialClusterUserDBQueries) GetDeploymentToApplicationMappingByDeplId(ctx context.Context, deplToAppMapping *db.DeploymentToApplicationMapping) error {
+	return db.NewResultNotFoundError("GetDeploymentToApplicationMappingByDeplId")
+}
+
+// mockK8sClientFactory returns the same client for every GitOpsEngineInstance
+type mockK8sClientFactory struct {
+	shared_resource_loop.SRLK8sClientFactory
+	k8sClient client.Client
+}
+
+func (m mockK8sClientFactory) GetK8sClientForGitOpsEngineInstance(ctx context.Context, gitopsEngineInstance *db.GitopsEngineInstance) (client.Client, error) {
+	return m.k8sClient, nil
+}
+
+func newGitOpsDeployment(name string, namespace string, repoURL string) *managedgitopsv1alpha1.GitOpsDeployment {
+	return &managedgitopsv1alpha1.GitOpsDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID("uid-" + name),
+		},
+		Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+			Source: managedgitopsv1alpha1.ApplicationSource{
+				RepoURL: repoURL,
+				Path:    "environments/overlays/dev",
+			},
+			Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+		},
+	}
+}
+
+var _ = Describe("Webhook event handler tests", func() {
+
+	Context("Test isRevisionOfPushEvent", func() {
+
+		DescribeTable("should match the target revision of an Application against the pushed ref",
+			func(targetRevision string, ref string, expected bool) {
+				Expect(isRevisionOfPushEvent(targetRevision, ref, "main")).To(Equal(expected))
+			},
+			Entry("branch of the same name", "main", "refs/heads/main", true),
+			Entry("fully qualified branch of the same name", "refs/heads/main", "refs/heads/main", true),
+			Entry("empty revision, and the default branch", "", "refs/heads/main", true),
+			Entry("HEAD, and the default branch", "HEAD", "refs/heads/main", true),
+			Entry("HEAD, and a branch that is not the default branch", "HEAD", "refs/heads/feature", false),
+			Entry("branch of a different name", "feature", "refs/heads/main", false),
+			Entry("tag of the same name", "v1.0.0", "refs/tags/v1.0.0", true),
+			Entry("empty revision, and a tag", "", "refs/tags/main", false),
+			Entry("unrecognized ref", "main", "refs/pull/1/head", false),
+		)
+
+		It("should match an empty or HEAD target revision against any branch, if the default branch is not known", func() {
+			Expect(isRevisionOfPushEvent("", "refs/heads/feature", "")).To(BeTrue())
+			Expect(isRevisionOfPushEvent("HEAD", "refs/heads/feature", "")).To(BeTrue())
+			Expect(isRevisionOfPushEvent("HEAD", "refs/tags/v1.0.0", "")).To(BeFalse())
+		})
+	})
+
+	Context("Test isApplicationAffectedByPushEvent", func() {
+
+		It("should match the HTTPS and SSH forms of the repository URL", func() {
+			pushEvent := newPushEvent("refs/heads/main")
+
+			for _, repoURL := range []string{
+				"https://github.com/redhat-appstudio/managed-gitops",
+				"https://github.com/redhat-appstudio/managed-gitops.git",
+				"https://github.com/Redhat-Appstudio/Managed-GitOps",
+				"git@github.com:redhat-appstudio/managed-gitops.git",
+			} {
+				Expect(isApplicationAffectedByPushEvent(newFauxApplication(repoURL, "main"), pushEvent)).To(BeTrue(), repoURL)
+			}
+		})
+
+		It("should not match a different repository, or a different revision", func() {
+			pushEvent := newPushEvent("refs/heads/main")
+
+			Expect(isApplicationAffectedByPushEvent(newFauxApplication("https://github.com/redhat-appstudio/managed-gitops-fork", "main"), pushEvent)).To(BeFalse())
+			Expect(isApplicationAffectedByPushEvent(newFauxApplication("https://github.com/redhat-appstudio/managed-gitops", "staging"), pushEvent)).To(BeFalse())
+		})
+
+		It("should match any of the sources of a multi-source Application", func() {
+			pushEvent := newPushEvent("refs/heads/main")
+
+			appArgo := newFauxApplication("", "")
+			appArgo.Spec.Sources = fauxargocd.ApplicationSources{
+				{RepoURL: "https://github.com/redhat-appstudio/other-repository", TargetRevision: "main"},
+				{RepoURL: "https://github.com/redhat-appstudio/managed-gitops", TargetRevision: "main"},
+			}
+
+			Expect(isApplicationAffectedByPushEvent(appArgo, pushEvent)).To(BeTrue())
+		})
+	})
+
+	Context("Test ParseWebhookInfo", func() {
+
+		const webhookSecretNamespace = "gitops-service-argocd"
+		const webhookSecret = "my-webhook-secret"
+
+		var handler *WebhookEventHandler
+
+		BeforeEach(func() {
+			scheme, _, _, _, err := tests.GenericTestSetup()
+			Expect(err).To(BeNil())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "managed-gitops-webhook",
+					Namespace: webhookSecretNamespace,
+				},
+				Type: sharedutil.WebhookSecretType,
+				Data: map[string][]byte{
+					sharedutil.WebhookSecretRepositoryURLKey: []byte("https://github.com/redhat-appstudio/managed-gitops.git"),
+					sharedutil.WebhookSecretSecretKey:        []byte(webhookSecret),
+				},
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+			// The handler has no database: so the tests below would fail if any Applications were refreshed.
+			handler = NewWebhookEventHandler(nil, k8sClient, webhookSecretNamespace)
+		})
+
+		sign := func(body string, secret string) string {
+			mac := hmac.New(sha256.New, []byte(secret))
+			_, err := mac.Write([]byte(body))
+			Expect(err).To(BeNil())
+			return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		}
+
+		handleRequest := func(event string, deliveryID string, signature string, body string) *httptest.ResponseRecorder {
+			httpRequest := httptest.NewRequest(http.MethodPost, "/api/v1/webhookevent", strings.NewReader(body))
+			httpRequest.Header.Set("Content-Type", "application/json")
+			if event != "" {
+				httpRequest.Header.Set("X-GitHub-Event", event)
+			}
+			if deliveryID != "" {
+				httpRequest.Header.Set("X-GitHub-Delivery", deliveryID)
+			}
+			if signature != "" {
+				httpRequest.Header.Set("X-Hub-Signature-256", signature)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ParseWebhookInfo(restful.NewRequest(httpRequest), restful.NewResponse(recorder))
+
+			return recorder
+		}
+
+		It("should return a bad request error, rather than exiting, if the event headers are missing", func() {
+			signature := sign(sampleWatchEvent, webhookSecret)
+
+			Expect(handleRequest("", "my-delivery-id", signature, sampleWatchEvent).Code).To(Equal(http.StatusBadRequest))
+			Expect(handleRequest("watch", "", signature, sampleWatchEvent).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return an unauthorized error if the signature is missing or invalid", func() {
+			Expect(handleRequest("watch", "my-delivery-id", "", sampleWatchEvent).Code).To(Equal(http.StatusUnauthorized))
+
+			By("signing the payload with a different secret")
+			Expect(handleRequest("watch", "my-delivery-id", sign(sampleWatchEvent, "another-secret"), sampleWatchEvent).Code).
+				To(Equal(http.StatusUnauthorized))
+
+			By("modifying the payload after it was signed")
+			modifiedPayload := strings.Replace(sampleWatchEvent, "started", "deleted", 1)
+			Expect(handleRequest("watch", "my-delivery-id", sign(sampleWatchEvent, webhookSecret), modifiedPayload).Code).
+				To(Equal(http.StatusUnauthorized))
+
+			By("using a signature without the sha256 prefix")
+			Expect(handleRequest("watch", "my-delivery-id", strings.TrimPrefix(sign(sampleWatchEvent, webhookSecret), "sha256="), sampleWatchEvent).Code).
+				To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should return a forbidden error if no webhook secret is configured for the repository", func() {
+			payload := strings.ReplaceAll(sampleWatchEvent, "managed-gitops", "another-repository")
+
+			Expect(handleRequest("watch", "my-delivery-id", sign(payload, webhookSecret), payload).Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return a bad request error if the payload cannot be parsed", func() {
+			Expect(handleRequest("watch", "my-delivery-id", sign("not-json", webhookSecret), "not-json").Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should acknowledge events other than push events, and reject events that are delivered again", func() {
+			signature := sign(sampleWatchEvent, webhookSecret)
+
+			Expect(handleRequest("watch", "my-delivery-id", signature, sampleWatchEvent).Code).To(Equal(http.StatusOK))
+
+			By("replaying the same event")
+			Expect(handleRequest("watch", "my-delivery-id", signature, sampleWatchEvent).Code).To(Equal(http.StatusConflict))
+
+			By("replaying the same event with a different delivery ID, as the delivery ID is not signed")
+			Expect(handleRequest("watch", "another-delivery-id", signature, sampleWatchEvent).Code).To(Equal(http.StatusConflict))
+
+			By("sending a different event with a different delivery ID")
+			anotherEvent := strings.Replace(sampleWatchEvent, "started", "deleted", 1)
+			Expect(handleRequest("watch", "another-delivery-id", sign(anotherEvent, webhookSecret), anotherEvent).Code).
+				To(Equal(http.StatusOK))
+		})
+
+		It("should accept an event that is delivered again, if it previously could not be processed", func() {
+			dbQueries := &failingSpecialClusterUserDBQueries{failuresRemaining: 1}
+			handler.DBQueries = dbQueries
+
+			gitopsDepl := newGitOpsDeployment("my-gitops-depl", "jane", "https://github.com/redhat-appstudio/managed-gitops")
+			Expect(handler.K8sClient.Create(context.Background(), gitopsDepl)).To(Succeed())
+
+			pushEvent := strings.Replace(sampleWatchEvent, `"action": "started",`, `"ref": "refs/heads/main",`, 1)
+			signature := sign(pushEvent, webhookSecret)
+
+			Expect(handleRequest("push", "my-delivery-id", signature, pushEvent).Code).To(Equal(http.StatusInternalServerError))
+
+			By("redelivering the event, which is now processed")
+			Expect(handleRequest("push", "my-delivery-id", signature, pushEvent).Code).To(Equal(http.StatusOK))
+			Expect(dbQueries.failuresRemaining).To(Equal(0))
+
+			By("replaying the event, once it has been processed")
+			Expect(handleRequest("push", "my-delivery-id", signature, pushEvent).Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should not record the delivery ID of an event with an invalid signature", func() {
+			Expect(handleRequest("watch", "my-delivery-id", sign(sampleWatchEvent, "another-secret"), sampleWatchEvent).Code).
+				To(Equal(http.StatusUnauthorized))
+
+			Expect(handleRequest("watch", "my-delivery-id", sign(sampleWatchEvent, webhookSecret), sampleWatchEvent).Code).
+				To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("Test deliveryIDCache", func() {
+
+		It("should only detect a replay of a delivery key within the expiry window", func() {
+			cache := newDeliveryIDCache()
+			now := time.Now()
+
+			Expect(cache.addIfNotSeen([]string{"my-delivery-id", "my-payload"}, now)).To(BeTrue())
+			Expect(cache.addIfNotSeen([]string{"my-delivery-id", "another-payload"}, now.Add(time.Hour))).To(BeFalse())
+			Expect(cache.addIfNotSeen([]string{"another-delivery-id", "my-payload"}, now.Add(time.Hour))).To(BeFalse())
+
+			By("verifying that no keys are recorded when a replay is detected")
+			Expect(cache.deliveryIDs).To(HaveLen(2))
+
+			Expect(cache.addIfNotSeen([]string{"another-delivery-id", "another-payload"}, now.Add(time.Hour))).To(BeTrue())
+
+			By("verifying the delivery keys are forgotten once they expire")
+			Expect(cache.addIfNotSeen([]string{"my-delivery-id", "my-payload"}, now.Add(deliveryIDExpiry+time.Minute))).To(BeTrue())
+			Expect(cache.deliveryIDs).To(HaveLen(4))
+		})
+
+		It("should forget delivery keys that are removed", func() {
+			cache := newDeliveryIDCache()
+			now := time.Now()
+
+			Expect(cache.addIfNotSeen([]string{"my-delivery-id", "my-payload"}, now)).To(BeTrue())
+			cache.remove([]string{"my-delivery-id", "my-payload"})
+			Expect(cache.deliveryIDs).To(BeEmpty())
+
+			Expect(cache.addIfNotSeen([]string{"my-delivery-id", "my-payload"}, now)).To(BeTrue())
+		})
+
+		It("should key a webhook event by both its delivery ID and its payload", func() {
+			keys := deliveryKeys("github", &WebHookInfo{Id: "my-delivery-id", Payload: []byte(sampleWatchEvent)})
+			Expect(keys).To(HaveLen(2))
+			Expect(keys[0]).To(Equal("github/id/my-delivery-id"))
+
+			otherKeys := deliveryKeys("github", &WebHookInfo{Id: "another-delivery-id", Payload: []byte(sampleWatchEvent)})
+			Expect(otherKeys[1]).To(Equal(keys[1]))
+		})
+	})
+
+	Context("Test refreshApplicationsForPushEvent", func() {
+
+		var ctx context.Context
+		var dbq db.AllDatabaseQueries
+		var k8sClient client.Client
+		var gitopsEngineInstance *db.GitopsEngineInstance
+		var managedEnvironment *db.ManagedEnvironment
+
+		// createApplication creates an Application row, deployed from the given GitOpsDeployment
+		createApplication := func(id string, appArgo fauxargocd.FauxApplication, gitopsDepl *managedgitopsv1alpha1.GitOpsDeployment) db.Application {
+			specField, err := yaml.Marshal(&appArgo)
+			Expect(err).To(BeNil())
+
+			application := db.Application{
+				Application_id:          id,
+				Name:                    appArgo.Name,
+				Spec_field:              string(specField),
+				Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+				Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+			}
+			err = dbq.CreateApplication(ctx, &application)
+			Expect(err).To(BeNil())
+
+			Expect(k8sClient.Create(ctx, gitopsDepl)).To(Succeed())
+
+			err = dbq.CreateDeploymentToApplicationMapping(ctx, &db.DeploymentToApplicationMapping{
+				Deploymenttoapplicationmapping_uid_id: string(gitopsDepl.UID),
+				DeploymentName:                        gitopsDepl.Name,
+				DeploymentNamespace:                   gitopsDepl.Namespace,
+				NamespaceUID:                          "test-namespace-uid",
+				Application_id:                        application.Application_id,
+			})
+			Expect(err).To(BeNil())
+
+			return application
+		}
+
+		BeforeEach(func() {
+			scheme, argocdNamespace, kubesystemNamespace, apiNamespace, err := tests.GenericTestSetup()
+			Expect(err).To(BeNil())
+
+			k8sClient = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(apiNamespace, argocdNamespace, kubesystemNamespace).
+				Build()
+
+			err = db.SetupForTestingDBGinkgo()
+			Expect(err).To(BeNil())
+
+			ctx = context.Background()
+
+			dbq, err = db.NewUnsafePostgresDBQueries(true, true)
+			Expect(err).To(BeNil())
+
+			_, managedEnvironment, _, gitopsEngineInstance, _, err = db.CreateSampleData(dbq)
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			dbq.CloseDatabase()
+		})
+
+		It("should create a RefreshApplication Operation for only the Applications that reference the pushed repository and branch", func() {
+
+			affectedApplication := createApplication("test-affected-application",
+				newFauxApplication("https://github.com/redhat-appstudio/managed-gitops.git", "main"),
+				newGitOpsDeployment("affected", "jane", "https://github.com/redhat-appstudio/managed-gitops.git"))
+			unaffectedApplication := createApplication("test-unaffected-application",
+				newFauxApplication("https://github.com/redhat-appstudio/managed-gitops", "staging"),
+				newGitOpsDeployment("unaffected", "jane", "https://github.com/redhat-appstudio/managed-gitops"))
+			otherRepositoryApplication := createApplication("test-other-repository-application",
+				newFauxApplication("https://github.com/redhat-appstudio/other-repository", "main"),
+				newGitOpsDeployment("other-repository", "jane", "https://github.com/redhat-appstudio/other-repository"))
+
+			refreshed, err := refreshApplicationsForPushEvent(ctx, newPushEvent("refs/heads/main"), dbq, k8sClient,
+				mockK8sClientFactory{k8sClient: k8sClient}, log.FromContext(ctx))
+			Expect(err).To(BeNil())
+			Expect(refreshed).To(Equal(1))
+
+			By("verifying an Operation was created for the affected Application")
+			var operationsDB []db.Operation
+			err = dbq.ListOperationsByResourceIdAndTypeAndOwnerId(ctx, affectedApplication.Application_id,
+				db.OperationResourceType_RefreshApplication, &operationsDB, db.SpecialClusterUserName)
+			Expect(err).To(BeNil())
+			Expect(operationsDB).To(HaveLen(1))
+			Expect(operationsDB[0].GC_expiration_time).To(Equal(refreshOperationGCExpirationTime))
+
+			operationCR := &managedgitopsv1alpha1.Operation{}
+			operationCR.Name = operations.GenerateOperationCRName(operationsDB[0])
+			operationCR.Namespace = gitopsEngineInstance.Namespace_name
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(operationCR), operationCR)
+			Expect(err).To(BeNil())
+			Expect(operationCR.Spec.OperationID).To(Equal(operationsDB[0].Operation_id))
+
+			By("verifying no Operation was created for the unaffected Application")
+			var unaffectedOperationsDB []db.Operation
+			err = dbq.ListOperationsByResourceIdAndTypeAndOwnerId(ctx, unaffectedApplication.Application_id,
+				db.OperationResourceType_RefreshApplication, &unaffectedOperationsDB, db.SpecialClusterUserName)
+			Expect(err).To(BeNil())
+			Expect(unaffectedOperationsDB).To(BeEmpty())
+
+			err = dbq.ListOperationsByResourceIdAndTypeAndOwnerId(ctx, otherRepositoryApplication.Application_id,
+				db.OperationResourceType_RefreshApplication, &unaffectedOperationsDB, db.SpecialClusterUserName)
+			Expect(err).To(BeNil())
+			Expect(unaffectedOperationsDB).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,341 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+	"golang.org/x/oauth2"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+)
+
+// CommitState is the state of a commit status, as reported to the Git provider
+type CommitState string
+
+const (
+	CommitState_Pending CommitState = "pending"
+	CommitState_Success CommitState = "success"
+	CommitState_Failure CommitState = "failure"
+)
+
+const (
+	defaultGitLabAPIURL = "https://gitlab.com/api/v4/"
+
+	// defaultGitHubHost and defaultGitLabHost are the hosts of the repositories that may use the default API URLs
+	defaultGitHubHost = "github.com"
+	defaultGitLabHost = "gitlab.com"
+
+	// commitStatusRequestTimeout is the maximum time to wait for the Git provider to respond to a request
+	commitStatusRequestTimeout = 30 * time.Second
+)
+
+// CommitStatus is a status that is posted to a commit of a Git repository
+type CommitStatus struct {
+	State CommitState
+
+	// Context is a label that differentiates this status from the statuses of other systems, for example 'gitops/staging'
+	Context string
+
+	// Description is a short summary of the status
+	Description string
+
+	// TargetURL is an optional link to the details of the status
+	TargetURL string
+}
+
+// CommitStatusReporter posts commit statuses to the API of a Git provider.
+type CommitStatusReporter interface {
+
+	// CreateCommitStatus posts the status to the commit 'sha' of the repository 'repoURL'
+	CreateCommitStatus(ctx context.Context, repoURL string, sha string, status CommitStatus) error
+}
+
+// NewCommitStatusReporter returns a CommitStatusReporter for the given Git provider (GitHub or GitLab).
+//
+// Since the token is sent to the API URL, the API URL must be on the same host as the repository (or, for GitHub,
+// on its 'api.' subdomain): otherwise, an error is returned.
+//
+// Parameters:
+//
+//	provider : The Git provider of the repository: "GitHub" or "GitLab"
+//	apiURL   : The API URL of the Git provider. If empty, the API URL of github.com, or gitlab.com, is used.
+//	repoURL  : The URL of the repository that statuses will be posted to
+//	token    : The personal access token (PAT) that is used to post the status
+func NewCommitStatusReporter(ctx context.Context, provider managedgitopsv1alpha1.CommitStatusProvider, apiURL string, repoURL string,
+	token string) (CommitStatusReporter, error) {
+
+	if err := validateCommitStatusAPIURL(provider, apiURL, repoURL); err != nil {
+		return nil, err
+	}
+
+	switch provider {
+	case managedgitopsv1alpha1.CommitStatusProvider_GitHub:
+		oauthClient := oauth2.NewClient(ctx, &TokenSource{AccessToken: token})
+		oauthClient.Timeout = commitStatusRequestTimeout
+
+		if apiURL == "" {
+			return &gitHubCommitStatusReporter{client: github.NewClient(oauthClient)}, nil
+		}
+
+		client, err := github.NewEnterpriseClient(apiURL, apiURL, oauthClient)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create GitHub client for '%s': %v", apiURL, err)
+		}
+		return &gitHubCommitStatusReporter{client: client}, nil
+
+	case managedgitopsv1alpha1.CommitStatusProvider_GitLab:
+		if apiURL == "" {
+			apiURL = defaultGitLabAPIURL
+		}
+		if !strings.HasSuffix(apiURL, "/") {
+			apiURL += "/"
+		}
+		return &gitLabCommitStatusReporter{apiURL: apiURL, token: token, httpClient: &http.Client{Timeout: commitStatusRequestTimeout}}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported commit status provider: '%s'", provider)
+	}
+}
+
+// validateCommitStatusAPIURL returns an error if the token of the repository could be sent to a host other than the
+// host of the repository: that is, if the API URL is not on the host of the repository (or, for GitHub, on its 'api.'
+// subdomain), or, if the API URL is empty, if the repository is not hosted on github.com, or gitlab.com.
+func validateCommitStatusAPIURL(provider managedgitopsv1alpha1.CommitStatusProvider, apiURL string, repoURL string) error {
+
+	repoHost, err := getRepositoryHost(repoURL)
+	if err != nil {
+		return err
+	}
+
+	if apiURL == "" {
+		defaultHost := ""
+		switch provider {
+		case managedgitopsv1alpha1.CommitStatusProvider_GitHub:
+			defaultHost = defaultGitHubHost
+		case managedgitopsv1alpha1.CommitStatusProvider_GitLab:
+			defaultHost = defaultGitLabHost
+		default:
+			return fmt.Errorf("unsupported commit status provider: '%s'", provider)
+		}
+
+		if repoHost != defaultHost {
+			return fmt.Errorf("an API URL is required to report commit statuses for repository '%s', as it is not hosted on '%s'", repoURL, defaultHost)
+		}
+		return nil
+	}
+
+	parsedAPIURL, err := url.Parse(apiURL)
+	if err != nil {
+		return fmt.Errorf("unable to parse API URL '%s': %v", apiURL, err)
+	}
+	if parsedAPIURL.Scheme != "https" && parsedAPIURL.Scheme != "http" {
+		return fmt.Errorf("API URL '%s' must be an HTTP(S) URL", apiURL)
+	}
+
+	apiHost := strings.ToLower(parsedAPIURL.Hostname())
+	if apiHost != repoHost && !(provider == managedgitopsv1alpha1.CommitStatusProvider_GitHub && apiHost == "api."+repoHost) {
+		return fmt.Errorf("the host of API URL '%s' does not match the host of repository '%s'", apiURL, repoURL)
+	}
+
+	return nil
+}
+
+// gitHubCommitStatusReporter posts commit statuses using the GitHub statuses API
+type gitHubCommitStatusReporter struct {
+	client *github.Client
+}
+
+func (r *gitHubCommitStatusReporter) CreateCommitStatus(ctx context.Context, repoURL string, sha string, status CommitStatus) error {
+
+	repoPath, err := getRepositoryPath(repoURL)
+	if err != nil {
+		return err
+	}
+
+	owner, repo, found := strings.Cut(repoPath, "/")
+	if !found || strings.Contains(repo, "/") {
+		return fmt.Errorf("unable to determine the GitHub owner and repository of '%s'", repoURL)
+	}
+
+	repoStatus := &github.RepoStatus{
+		State:       github.String(string(status.State)),
+		Context:     github.String(status.Context),
+		Description: github.String(status.Description),
+	}
+	if status.TargetURL != "" {
+		repoStatus.TargetURL = github.String(status.TargetURL)
+	}
+
+	if _, _, err := r.client.Repositories.CreateStatus(ctx, owner, repo, sha, repoStatus); err != nil {
+		return fmt.Errorf("unable to create GitHub commit status for '%s' at '%s': %w", repoURL, sha, err)
+	}
+
+	return nil
+}
+
+// gitLabCommitStatusReporter posts commit statuses using the GitLab commit statuses API
+type gitLabCommitStatusReporter struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+type gitLabCommitStatus struct {
+	State       string `json:"state"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+func (r *gitLabCommitStatusReporter) CreateCommitStatus(ctx context.Context, repoURL string, sha string, status CommitStatus) error {
+
+	repoPath, err := getRepositoryPath(repoURL)
+	if err != nil {
+		return err
+	}
+
+	state := string(status.State)
+	if status.State == CommitState_Failure {
+		// GitLab uses 'failed', rather than 'failure'
+		state = "failed"
+	}
+
+	body, err := json.Marshal(gitLabCommitStatus{
+		State:       state,
+		Name:        status.Context,
+		Description: status.Description,
+		TargetURL:   status.TargetURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	statusURL := r.apiURL + "projects/" + url.PathEscape(repoPath) + "/statuses/" + url.PathEscape(sha)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, statusURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", r.token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to create GitLab commit status for '%s' at '%s': %w", repoURL, sha, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unable to create GitLab commit status for '%s' at '%s': %w", repoURL, sha,
+			&commitStatusResponseError{statusCode: resp.StatusCode, status: resp.Status, body: string(respBody)})
+	}
+
+	return nil
+}
+
+// commitStatusResponseError is returned when the Git provider responds to a commit status request with an error status
+type commitStatusResponseError struct {
+	statusCode int
+	status     string
+	body       string
+}
+
+func (e *commitStatusResponseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.status, e.body)
+}
+
+// IsTransientCommitStatusError returns true if the error returned by CreateCommitStatus may not occur when the request is
+// retried: the Git provider could not be reached or did not respond in time, rate limited the request (429), or
+// responded with a server error (5xx). Other errors, such as a rejected token, or an invalid repository URL, are not
+// transient.
+func IsTransientCommitStatusError(err error) bool {
+
+	var rateLimitErr *github.RateLimitError
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+		return true
+	}
+
+	var gitHubErr *github.ErrorResponse
+	if errors.As(err, &gitHubErr) && gitHubErr.Response != nil {
+		return isTransientStatusCode(gitHubErr.Response.StatusCode)
+	}
+
+	var responseErr *commitStatusResponseError
+	if errors.As(err, &responseErr) {
+		return isTransientStatusCode(responseErr.statusCode)
+	}
+
+	// Errors of the HTTP client, for example, if the connection was refused, or the request timed out
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func isTransientStatusCode(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// getRepositoryHost returns the (lowercase) host name of the repository, for example, 'github.com' for
+// 'https://github.com/owner/repo.git' or 'git@github.com:owner/repo.git'.
+func getRepositoryHost(repoURL string) (string, error) {
+
+	var repoHost string
+
+	if strings.Contains(repoURL, "://") {
+		parsedURL, err := url.Parse(repoURL)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse repository URL '%s': %v", repoURL, err)
+		}
+		repoHost = parsedURL.Hostname()
+
+	} else if userAndHost, _, found := strings.Cut(repoURL, ":"); found {
+		// SCP-like SSH syntax, for example, 'git@github.com:owner/repo.git'
+		_, repoHost, _ = strings.Cut(userAndHost, "@")
+		if repoHost == "" {
+			repoHost = userAndHost
+		}
+	}
+
+	if repoHost == "" {
+		return "", fmt.Errorf("unable to determine the host of repository '%s'", repoURL)
+	}
+
+	return strings.ToLower(repoHost), nil
+}
+
+// getRepositoryPath returns the path of the repository on the Git provider, for example, 'owner/repo' for
+// 'https://github.com/owner/repo.git' or 'git@github.com:owner/repo.git'.
+func getRepositoryPath(repoURL string) (string, error) {
+
+	var repoPath string
+
+	if strings.Contains(repoURL, "://") {
+		parsedURL, err := url.Parse(repoURL)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse repository URL '%s': %v", repoURL, err)
+		}
+		repoPath = parsedURL.Path
+
+	} else if _, path, found := strings.Cut(repoURL, ":"); found {
+		// SCP-like SSH syntax, for example, 'git@github.com:owner/repo.git'
+		repoPath = path
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+
+	if !strings.Contains(repoPath, "/") {
+		return "", fmt.Errorf("unable to determine the repository path of '%s'", repoURL)
+	}
+
+	return repoPath, nil
+}
@@ -0,0 +1,189 @@
+package util_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend/util"
+)
+
+var _ = Describe("Commit status reporter tests", func() {
+
+	const (
+		token = "my-token"
+		sha   = "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c"
+	)
+
+	status := util.CommitStatus{
+		State:       util.CommitState_Failure,
+		Context:     "gitops/staging",
+		Description: "Deployed to staging: Degraded",
+	}
+
+	// startServer starts an HTTP server that records the path, headers and JSON body of the last request it received
+	startServer := func(lastRequest *http.Request, lastBody *map[string]any) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*lastRequest = *r
+			Expect(json.NewDecoder(r.Body).Decode(lastBody)).To(Succeed())
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("{}"))
+		}))
+	}
+
+	Context("Test the GitHub commit status reporter", func() {
+
+		It("should post the status to the GitHub statuses API of the commit", func() {
+			var lastRequest http.Request
+			var lastBody map[string]any
+			server := startServer(&lastRequest, &lastBody)
+			defer server.Close()
+
+			repoURL := "git@127.0.0.1:redhat-appstudio/managed-gitops.git"
+
+			reporter, err := util.NewCommitStatusReporter(context.Background(), managedgitopsv1alpha1.CommitStatusProvider_GitHub, server.URL+"/api/v3/", repoURL, token)
+			Expect(err).To(BeNil())
+
+			err = reporter.CreateCommitStatus(context.Background(), repoURL, sha, status)
+			Expect(err).To(BeNil())
+
+			Expect(lastRequest.Method).To(Equal(http.MethodPost))
+			Expect(lastRequest.URL.Path).To(Equal("/api/v3/repos/redhat-appstudio/managed-gitops/statuses/" + sha))
+			Expect(lastRequest.Header.Get("Authorization")).To(Equal("Bearer " + token))
+			Expect(lastBody).To(Equal(map[string]any{
+				"state":       "failure",
+				"context":     "gitops/staging",
+				"description": "Deployed to staging: Degraded",
+			}))
+		})
+
+		It("should return an error for a repository URL without an owner and repository", func() {
+			repoURL := "https://github.com/redhat-appstudio"
+
+			reporter, err := util.NewCommitStatusReporter(context.Background(), managedgitopsv1alpha1.CommitStatusProvider_GitHub, "", repoURL, token)
+			Expect(err).To(BeNil())
+
+			err = reporter.CreateCommitStatus(context.Background(), repoURL, sha, status)
+			Expect(err).ToNot(BeNil())
+			Expect(util.IsTransientCommitStatusError(err)).To(BeFalse())
+		})
+
+		DescribeTable("should return a transient error if the GitLab API is unavailable, or rate limits the request",
+			func(statusCode int) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(statusCode)
+				}))
+				defer server.Close()
+
+				repoURL := "git@127.0.0.1:team/app-gitops.git"
+
+				reporter, err := util.NewCommitStatusReporter(context.Background(), managedgitopsv1alpha1.CommitStatusProvider_GitLab, server.URL, repoURL, token)
+				Expect(err).To(BeNil())
+
+				err = reporter.CreateCommitStatus(context.Background(), repoURL, sha, status)
+				Expect(err).ToNot(BeNil())
+				Expect(util.IsTransientCommitStatusError(err)).To(BeTrue())
+			},
+			Entry("too many requests", http.StatusTooManyRequests),
+			Entry("internal server error", http.StatusInternalServerError),
+			Entry("service unavailable", http.StatusServiceUnavailable),
+		)
+
+		It("should return a transient error if the GitLab API cannot be reached", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			server.Close()
+
+			repoURL := "git@127.0.0.1:team/app-gitops.git"
+
+			reporter, err := util.NewCommitStatusReporter(context.Background(), managedgitopsv1alpha1.CommitStatusProvider_GitLab, server.URL, repoURL, token)
+			Expect(err).To(BeNil())
+
+			err = reporter.CreateCommitStatus(context.Background(), repoURL, sha, status)
+			Expect(err).ToNot(BeNil())
+			Expect(util.IsTransientCommitStatusError(err)).To(BeTrue())
+		})
+	})
+
+	Context("Test the GitLab commit status reporter", func() {
+
+		It("should post the status to the GitLab commit statuses API of the project", func() {
+			var lastRequest http.Request
+			var lastBody map[string]any
+			server := startServer(&lastRequest, &lastBody)
+			defer server.Close()
+
+			repoURL := "http://127.0.0.1/group/subgroup/app-gitops.git"
+
+			reporter, err := util.NewCommitStatusReporter(context.Background(), managedgitopsv1alpha1.CommitStatusProvider_GitLab, server.URL+"/api/v4", repoURL, token)
+			Expect(err).To(BeNil())
+
+			err = reporter.CreateCommitStatus(context.Background(), repoURL, sha, status)
+			Expect(err).To(BeNil())
+
+			Expect(lastRequest.Method).To(Equal(http.MethodPost))
+			Expect(lastRequest.URL.EscapedPath()).To(Equal("/api/v4/projects/group%2Fsubgroup%2Fapp-gitops/statuses/" + sha))
+			Expect(lastRequest.Header.Get("PRIVATE-TOKEN")).To(Equal(token))
+			Expect(lastBody).To(Equal(map[string]any{
+				"state":       "failed",
+				"name":        "gitops/staging",
+				"description": "Deployed to staging: Degraded",
+			}))
+		})
+
+		It("should return an error if the GitLab API rejects the status", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+			defer server.Close()
+
+			repoURL := "git@127.0.0.1:team/app-gitops.git"
+
+			reporter, err := util.NewCommitStatusReporter(context.Background(), managedgitopsv1alpha1.CommitStatusProvider_GitLab, server.URL, repoURL, token)
+			Expect(err).To(BeNil())
+
+			err = reporter.CreateCommitStatus(context.Background(), repoURL, sha, status)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	It("should return an error for an unsupported provider", func() {
+		_, err := util.NewCommitStatusReporter(context.Background(), "Bitbucket", "", "https://bitbucket.org/team/app-gitops", token)
+		Expect(err).ToNot(BeNil())
+	})
+
+	DescribeTable("should only allow an API URL on the host of the repository, so that the token is not sent to other hosts",
+		func(provider managedgitopsv1alpha1.CommitStatusProvider, apiURL string, repoURL string, expectError bool) {
+			_, err := util.NewCommitStatusReporter(context.Background(), provider, apiURL, repoURL, token)
+			if expectError {
+				Expect(err).ToNot(BeNil())
+			} else {
+				Expect(err).To(BeNil())
+			}
+		},
+		Entry("default GitHub API URL, for a repository on github.com", managedgitopsv1alpha1.CommitStatusProvider_GitHub,
+			"", "https://github.com/redhat-appstudio/managed-gitops", false),
+		Entry("default GitLab API URL, for a repository on gitlab.com", managedgitopsv1alpha1.CommitStatusProvider_GitLab,
+			"", "git@gitlab.com:team/app-gitops.git", false),
+		Entry("GitHub Enterprise API URL, on the host of the repository", managedgitopsv1alpha1.CommitStatusProvider_GitHub,
+			"https://github.example.com/api/v3/", "git@github.example.com:team/app-gitops.git", false),
+		Entry("GitHub API URL, on the 'api.' subdomain of the host of the repository", managedgitopsv1alpha1.CommitStatusProvider_GitHub,
+			"https://api.github.com/", "https://github.com/redhat-appstudio/managed-gitops", false),
+		Entry("GitLab API URL, on the host of the repository", managedgitopsv1alpha1.CommitStatusProvider_GitLab,
+			"https://gitlab.example.com/api/v4", "https://GitLab.example.com/team/app-gitops.git", false),
+		Entry("default GitHub API URL, for a repository that is not on github.com", managedgitopsv1alpha1.CommitStatusProvider_GitHub,
+			"", "https://github.example.com/team/app-gitops", true),
+		Entry("default GitLab API URL, for a repository that is not on gitlab.com", managedgitopsv1alpha1.CommitStatusProvider_GitLab,
+			"", "https://gitlab.example.com/team/app-gitops", true),
+		Entry("API URL on another host", managedgitopsv1alpha1.CommitStatusProvider_GitHub,
+			"https://attacker.example.com/api/v3/", "https://github.com/redhat-appstudio/managed-gitops", true),
+		Entry("API URL on a host with the host of the repository as a suffix", managedgitopsv1alpha1.CommitStatusProvider_GitLab,
+			"https://evil-gitlab.example.com/api/v4", "https://gitlab.example.com/team/app-gitops", true),
+		Entry("API URL that is not an HTTP(S) URL", managedgitopsv1alpha1.CommitStatusProvider_GitLab,
+			"file://gitlab.example.com/api/v4", "https://gitlab.example.com/team/app-gitops", true),
+	)
+})
@@ -0,0 +1,371 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitprotocol "github.com/go-git/go-git/v5/plumbing/transport/git"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	sharedloop "github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+)
+
+const (
+	// EnableSourceValidationEnvVar is set to 'true' to verify, when a GitOpsDeployment is created or its source is
+	// modified, that its repository is reachable and that its revision resolves.
+	EnableSourceValidationEnvVar = "ENABLE_SOURCE_VALIDATION"
+
+	// SourceValidationTimeoutEnvVar is the maximum time (in seconds) to wait for the repositories of a GitOpsDeployment
+	// to respond, when verifying its source.
+	SourceValidationTimeoutEnvVar = "SOURCE_VALIDATION_TIMEOUT_SECONDS"
+
+	// SourceValidationFailOpenEnvVar is set to 'false' to reject GitOpsDeployments whose source could not be verified,
+	// for example because the repository did not respond in time. By default, they are admitted.
+	SourceValidationFailOpenEnvVar = "SOURCE_VALIDATION_FAIL_OPEN"
+
+	// DefaultSourceValidationTimeout is used if SOURCE_VALIDATION_TIMEOUT_SECONDS is not set. It should be lower than the
+	// timeout of the validating webhook.
+	DefaultSourceValidationTimeout = 5 * time.Second
+)
+
+// allowedRepositoryProtocols are the protocols of the repositories that are connected to, to verify a source. In
+// particular, local ('file') repositories are not, as they would allow the file system of the backend to be read.
+var allowedRepositoryProtocols = map[string]bool{"https": true, "ssh": true, "git": true}
+
+// commitSHARegex matches (possibly abbreviated) Git commit SHAs, which cannot be resolved without cloning the repository
+var commitSHARegex = regexp.MustCompile("^[0-9a-f]{7,40}$")
+
+// SourceValidator verifies that the repository of each source of a GitOpsDeployment is reachable, and that its
+// revision resolves, by listing the references of the repository (as 'git ls-remote' does). The credentials of a
+// GitOpsDeploymentRepositoryCredential in the namespace of the GitOpsDeployment are used, if one matches the
+// repository.
+//
+// Sources that reference a Helm chart repository, rather than a Git repository, are not verified.
+type SourceValidator struct {
+	Client client.Client
+
+	// Timeout is the maximum time to wait for all of the repositories of a GitOpsDeployment to respond
+	Timeout time.Duration
+
+	// FailOpen is true if a GitOpsDeployment should be admitted when its source could not be verified, for example
+	// because its repository did not respond in time, or the Secret of its repository credentials could not be read.
+	// GitOpsDeployments whose repository or revision do not exist are rejected regardless.
+	FailOpen bool
+
+	// NewTransport returns the transport that the references of a repository are listed with. If nil,
+	// newRepositoryTransport is used, which does not connect to repositories whose host resolves to an address that
+	// is not allowed by isAllowedRepositoryIP. It may be replaced by unit tests, which serve repositories locally.
+	NewTransport func(ctx context.Context, endpoint *transport.Endpoint) (transport.Transport, error)
+
+	Log logr.Logger
+}
+
+var _ managedgitopsv1alpha1.GitOpsDeploymentSourceValidator = &SourceValidator{}
+
+// NewSourceValidatorFromEnv returns a SourceValidator that is configured from the environment variables of the
+// backend, or nil if source validation is not enabled.
+func NewSourceValidatorFromEnv(k8sClient client.Client, log logr.Logger) *SourceValidator {
+
+	if !strings.EqualFold(os.Getenv(EnableSourceValidationEnvVar), "true") {
+		return nil
+	}
+
+	res := &SourceValidator{
+		Client:   k8sClient,
+		Timeout:  DefaultSourceValidationTimeout,
+		FailOpen: !strings.EqualFold(os.Getenv(SourceValidationFailOpenEnvVar), "false"),
+		Log:      log,
+	}
+
+	if timeout := os.Getenv(SourceValidationTimeoutEnvVar); timeout != "" {
+		if seconds, err := strconv.Atoi(timeout); err != nil || seconds <= 0 {
+			log.Error(err, fmt.Sprintf("value of env var %s must be a positive number of seconds, using the default", SourceValidationTimeoutEnvVar))
+		} else {
+			res.Timeout = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return res
+}
+
+// sourceNotVerifiableError is returned when the source of a GitOpsDeployment could not be verified, rather than
+// being found not to exist. The message of err does not describe why the repository could not be accessed, as it is
+// returned to the user: otherwise, it could be used to learn which hosts and ports the backend is able to reach.
+// The cause is only logged.
+type sourceNotVerifiableError struct {
+	err   error
+	cause error
+}
+
+func (e *sourceNotVerifiableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sourceNotVerifiableError) reason() string {
+	if e.cause == nil {
+		return e.err.Error()
+	}
+	return e.err.Error() + ": " + e.cause.Error()
+}
+
+// ValidateSource implements GitOpsDeploymentSourceValidator
+func (v *SourceValidator) ValidateSource(ctx context.Context, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment) error {
+
+	ctx, cancel := context.WithTimeout(ctx, v.Timeout)
+	defer cancel()
+
+	fieldPrefix := "spec.source"
+	sources := []managedgitopsv1alpha1.ApplicationSource{gitopsDeployment.Spec.Source}
+	if len(gitopsDeployment.Spec.Sources) > 0 {
+		fieldPrefix = "spec.sources"
+		sources = gitopsDeployment.Spec.Sources
+	}
+
+	for _, source := range sources {
+
+		// Helm chart repositories are not Git repositories
+		if source.Chart != "" || source.RepoURL == "" {
+			continue
+		}
+
+		err := v.validateGitSource(ctx, gitopsDeployment.Namespace, fieldPrefix, source)
+		if err == nil {
+			continue
+		}
+
+		var notVerifiableErr *sourceNotVerifiableError
+		if errors.As(err, &notVerifiableErr) && v.FailOpen {
+			v.Log.Info("Admitting GitOpsDeployment whose source could not be verified", "name", gitopsDeployment.Name,
+				"namespace", gitopsDeployment.Namespace, "repoURL", source.RepoURL, "reason", notVerifiableErr.reason())
+			continue
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (v *SourceValidator) validateGitSource(ctx context.Context, namespace string, fieldPrefix string, source managedgitopsv1alpha1.ApplicationSource) error {
+
+	secret, err := v.getRepositoryCredentialSecret(ctx, namespace, source.RepoURL)
+	if err != nil {
+		return &sourceNotVerifiableError{err: fmt.Errorf("unable to retrieve the credentials of repository '%s': %v", source.RepoURL, err)}
+	}
+
+	endpoint, err := transport.NewEndpoint(source.RepoURL)
+	if err != nil {
+		return fmt.Errorf("%s.repoURL '%s' is not a valid repository URL: %v", fieldPrefix, source.RepoURL, err)
+	}
+
+	if endpoint.Protocol == "file" {
+		return fmt.Errorf("%s.repoURL '%s' must be a remote repository URL", fieldPrefix, source.RepoURL)
+	}
+
+	if !allowedRepositoryProtocols[endpoint.Protocol] {
+		return &sourceNotVerifiableError{err: fmt.Errorf("repository '%s' could not be verified: only https, ssh and git repositories are verified", source.RepoURL)}
+	}
+
+	newTransport := v.NewTransport
+	if newTransport == nil {
+		newTransport = newRepositoryTransport
+	}
+
+	gitTransport, err := newTransport(ctx, endpoint)
+	if err != nil {
+		return &sourceNotVerifiableError{err: fmt.Errorf("unable to access repository '%s'", source.RepoURL), cause: err}
+	}
+
+	refs, err := sharedloop.ListRemoteReferencesWithTransport(ctx, gitTransport, endpoint, secret)
+	if err != nil {
+
+		if errors.Is(err, transport.ErrRepositoryNotFound) || errors.Is(err, transport.ErrAuthenticationRequired) ||
+			errors.Is(err, transport.ErrAuthorizationFailed) {
+
+			message := fmt.Sprintf("%s.repoURL '%s' could not be accessed: %v", fieldPrefix, source.RepoURL, err)
+			if secret == nil {
+				message += ". If the repository is private, create a GitOpsDeploymentRepositoryCredential for it"
+			}
+			return errors.New(message)
+		}
+
+		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return fmt.Errorf("%s.repoURL '%s' is an empty repository", fieldPrefix, source.RepoURL)
+		}
+
+		return &sourceNotVerifiableError{err: fmt.Errorf("unable to access repository '%s'", source.RepoURL), cause: err}
+	}
+
+	if !isRevisionResolvable(source.TargetRevision, refs) {
+		return fmt.Errorf("%s.targetRevision '%s' was not found in repository '%s'", fieldPrefix, source.TargetRevision, source.RepoURL)
+	}
+
+	return nil
+}
+
+// newRepositoryTransport returns the transport that the references of the repository at the endpoint are listed
+// with. To ensure that a GitOpsDeployment cannot be used to send requests to the services of the cluster, or of the
+// cloud provider, the addresses of the host of the repository are checked with isAllowedRepositoryIP:
+// - For https repositories, the address of every connection is checked by the dialer, after the host was resolved,
+// including the connections of redirects. Proxies are not used, as the address of the repository could then not be
+// checked.
+// - The ssh and git transports dial the host of the repository themselves, so the host is instead resolved, and its
+// addresses checked, beforehand. The git transport then connects to the checked address. The ssh transport must
+// connect to the host by name, as its host key is verified against the known hosts by name.
+func newRepositoryTransport(ctx context.Context, endpoint *transport.Endpoint) (transport.Transport, error) {
+
+	switch endpoint.Protocol {
+	case "https":
+		return githttp.NewClient(newRepositoryHTTPClient()), nil
+
+	case "ssh":
+		if _, err := resolveRepositoryHost(ctx, endpoint.Host); err != nil {
+			return nil, err
+		}
+		return gitssh.DefaultClient, nil
+
+	case "git":
+		ip, err := resolveRepositoryHost(ctx, endpoint.Host)
+		if err != nil {
+			return nil, err
+		}
+		host := ip.String()
+		if ip.To4() == nil {
+			host = "[" + host + "]"
+		}
+		return &fixedHostTransport{Transport: gitprotocol.DefaultClient, host: host}, nil
+	}
+
+	return nil, fmt.Errorf("protocol '%s' is not supported", endpoint.Protocol)
+}
+
+// resolveRepositoryHost resolves the host of a repository, and returns its first address, if all of its addresses
+// are allowed by isAllowedRepositoryIP.
+func resolveRepositoryHost(ctx context.Context, host string) (net.IP, error) {
+
+	ipAddrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ipAddrs) == 0 {
+		return nil, fmt.Errorf("host '%s' did not resolve to an address", host)
+	}
+
+	for _, ipAddr := range ipAddrs {
+		if !isAllowedRepositoryIP(ipAddr.IP) {
+			return nil, fmt.Errorf("connections to address '%s' of host '%s' are not allowed", ipAddr.IP, host)
+		}
+	}
+
+	return ipAddrs[0].IP, nil
+}
+
+// newRepositoryHTTPClient returns the HTTP client that the references of https repositories are listed with: the
+// address of every connection is checked with isAllowedRepositoryIP, and proxies are not used.
+func newRepositoryHTTPClient() *http.Client {
+
+	dialer := &net.Dialer{
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isAllowedRepositoryIP(ip) {
+				return fmt.Errorf("connections to address '%s' are not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	httpTransport.Proxy = nil
+	httpTransport.DialContext = dialer.DialContext
+
+	return &http.Client{Transport: httpTransport}
+}
+
+// fixedHostTransport is a transport that connects to the given host, rather than to the host of the endpoint: it
+// is used to connect to an address of the host of a repository that was checked with isAllowedRepositoryIP, so that
+// the host cannot resolve to a different address by the time it is connected to (DNS rebinding).
+type fixedHostTransport struct {
+	transport.Transport
+	host string
+}
+
+func (t *fixedHostTransport) NewUploadPackSession(endpoint *transport.Endpoint, auth transport.AuthMethod) (transport.UploadPackSession, error) {
+	fixedEndpoint := *endpoint
+	fixedEndpoint.Host = t.host
+	return t.Transport.NewUploadPackSession(&fixedEndpoint, auth)
+}
+
+// isAllowedRepositoryIP returns true if the references of a repository may be listed from the IP address: that is,
+// if it is not a loopback, private, link-local or unspecified address, as with the sinks of notifications.
+func isAllowedRepositoryIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified())
+}
+
+// getRepositoryCredentialSecret returns the Secret of the GitOpsDeploymentRepositoryCredential in the namespace that
+// matches the repository, or nil if none match.
+func (v *SourceValidator) getRepositoryCredentialSecret(ctx context.Context, namespace string, repoURL string) (*corev1.Secret, error) {
+
+	var repoCredList managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredentialList
+	if err := v.Client.List(ctx, &repoCredList, &client.ListOptions{Namespace: namespace}); err != nil {
+		return nil, err
+	}
+
+	normalizedRepoURL := sharedloop.NormalizeGitURL(repoURL)
+
+	for _, repoCred := range repoCredList.Items {
+		if sharedloop.NormalizeGitURL(repoCred.Spec.Repository) != normalizedRepoURL {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		if err := v.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: repoCred.Spec.Secret}, secret); err != nil {
+			return nil, err
+		}
+		return secret, nil
+	}
+
+	return nil, nil
+}
+
+// isRevisionResolvable returns true if the target revision of a source is the HEAD of the repository, a branch or
+// tag of the repository, or a commit SHA.
+func isRevisionResolvable(targetRevision string, refs []*plumbing.Reference) bool {
+
+	if targetRevision == "" || targetRevision == "HEAD" {
+		return true
+	}
+
+	// Commit SHAs cannot be verified without cloning the repository, so they are left to Argo CD to resolve
+	if commitSHARegex.MatchString(targetRevision) {
+		return true
+	}
+
+	for _, ref := range refs {
+		name := ref.Name()
+		if name.String() == targetRevision || ((name.IsBranch() || name.IsTag()) && name.Short() == targetRevision) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,226 @@
+package util_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend/util"
+)
+
+var _ = Describe("Source validator tests", func() {
+
+	const (
+		// The repository is served by an in-memory Git server, which is injected as the transport of the validator
+		repoURL   = "https://git.example.com/my-org/my-repo"
+		namespace = "my-namespace"
+	)
+
+	var (
+		ctx       context.Context
+		validator *util.SourceValidator
+	)
+
+	newGitOpsDeployment := func(source managedgitopsv1alpha1.ApplicationSource) *managedgitopsv1alpha1.GitOpsDeployment {
+		return &managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-gitops-depl", Namespace: namespace},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Source: source,
+				Type:   managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		By("creating a Git repository with a 'master' branch and a 'v1.0.0' tag, served by an in-memory Git server")
+		storage := memory.NewStorage()
+		repo, err := git.Init(storage, memfs.New())
+		Expect(err).To(BeNil())
+
+		worktree, err := repo.Worktree()
+		Expect(err).To(BeNil())
+		file, err := worktree.Filesystem.Create("deployment.yaml")
+		Expect(err).To(BeNil())
+		_, err = file.Write([]byte("kind: Deployment\n"))
+		Expect(err).To(BeNil())
+		Expect(file.Close()).To(Succeed())
+		_, err = worktree.Add("deployment.yaml")
+		Expect(err).To(BeNil())
+		commit, err := worktree.Commit("initial commit", &git.CommitOptions{
+			Author: &object.Signature{Name: "user", Email: "user@example.com", When: time.Now()},
+		})
+		Expect(err).To(BeNil())
+		_, err = repo.CreateTag("v1.0.0", commit, nil)
+		Expect(err).To(BeNil())
+
+		endpoint, err := transport.NewEndpoint(repoURL)
+		Expect(err).To(BeNil())
+		memoryServer := server.NewClient(server.MapLoader{endpoint.String(): storage})
+
+		scheme := runtime.NewScheme()
+		Expect(managedgitopsv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		validator = &util.SourceValidator{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Timeout:  5 * time.Second,
+			FailOpen: true,
+			NewTransport: func(ctx context.Context, endpoint *transport.Endpoint) (transport.Transport, error) {
+				return memoryServer, nil
+			},
+			Log: logr.Discard(),
+		}
+	})
+
+	It("should admit a GitOpsDeployment whose repository exists, and whose revision resolves", func() {
+
+		for _, revision := range []string{"", "HEAD", "master", "refs/heads/master", "v1.0.0", "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c"} {
+			gitopsDepl := newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: repoURL, Path: "environments/dev", TargetRevision: revision})
+			Expect(validator.ValidateSource(ctx, gitopsDepl)).To(Succeed(), "revision: "+revision)
+		}
+	})
+
+	It("should reject a GitOpsDeployment whose repository does not exist, or whose revision does not resolve", func() {
+
+		By("verifying a typo in the repository URL is rejected")
+		gitopsDepl := newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: repoURL + "s", Path: "environments/dev"})
+		err := validator.ValidateSource(ctx, gitopsDepl)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("spec.source.repoURL"))
+		Expect(err.Error()).To(ContainSubstring("repository not found"))
+
+		By("verifying a typo in the revision is rejected")
+		gitopsDepl = newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: repoURL, Path: "environments/dev", TargetRevision: "mastr"})
+		err = validator.ValidateSource(ctx, gitopsDepl)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("spec.source.targetRevision 'mastr' was not found"))
+
+		By("verifying the sources of a multi-source GitOpsDeployment are each verified, other than Helm chart repositories")
+		gitopsDepl = newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{})
+		gitopsDepl.Spec.Sources = []managedgitopsv1alpha1.ApplicationSource{
+			{RepoURL: "https://charts.example.com", Chart: "my-chart", TargetRevision: "1.0.0"},
+			{RepoURL: repoURL, Path: "environments/dev", TargetRevision: "does-not-exist"},
+		}
+		err = validator.ValidateSource(ctx, gitopsDepl)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("spec.sources.targetRevision 'does-not-exist' was not found"))
+	})
+
+	It("should admit a GitOpsDeployment whose source could not be verified only if fail-open is enabled", func() {
+
+		By("starting a Git server that does not respond before the timeout")
+		slowServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}))
+		defer slowServer.Close()
+
+		// The server is local, so the transport of the validator is replaced with one that may connect to it
+		validator.NewTransport = func(ctx context.Context, endpoint *transport.Endpoint) (transport.Transport, error) {
+			return githttp.NewClient(slowServer.Client()), nil
+		}
+		validator.Timeout = 200 * time.Millisecond
+		gitopsDepl := newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: slowServer.URL + "/my-org/my-repo", Path: "environments/dev"})
+
+		start := time.Now()
+		Expect(validator.ValidateSource(ctx, gitopsDepl)).To(Succeed())
+		Expect(time.Since(start)).To(BeNumerically("<", 3*time.Second))
+
+		validator.FailOpen = false
+		err := validator.ValidateSource(ctx, gitopsDepl)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("unable to access repository"))
+	})
+
+	It("should use the GitOpsDeploymentRepositoryCredential that matches the repository", func() {
+
+		repoCred := &managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-repo-cred", Namespace: namespace},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredentialSpec{
+				Repository: repoURL + ".git",
+				Secret:     "my-secret",
+			},
+		}
+		Expect(validator.Client.Create(ctx, repoCred)).To(Succeed())
+
+		gitopsDepl := newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: repoURL, Path: "environments/dev"})
+
+		By("verifying the source cannot be verified if the Secret of the matching credentials does not exist")
+		validator.FailOpen = false
+		err := validator.ValidateSource(ctx, gitopsDepl)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("unable to retrieve the credentials of repository"))
+
+		By("creating the Secret, and verifying the source is then verified")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: namespace},
+			Data:       map[string][]byte{"username": []byte("user"), "password": []byte("password")},
+		}
+		Expect(validator.Client.Create(ctx, secret)).To(Succeed())
+		Expect(validator.ValidateSource(ctx, gitopsDepl)).To(Succeed())
+	})
+
+	It("should not connect to local repositories, or to repositories of internal addresses", func() {
+
+		By("starting a Git server on a loopback address, which records the requests it receives")
+		requests := 0
+		localServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer localServer.Close()
+
+		validator.NewTransport = nil
+
+		By("verifying a local repository is rejected, even if fail-open is enabled")
+		gitopsDepl := newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: "file:///etc/my-repo", Path: "environments/dev"})
+		err := validator.ValidateSource(ctx, gitopsDepl)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("must be a remote repository URL"))
+
+		By("verifying a repository of a loopback address is not connected to, and that the error does not describe why")
+		gitopsDepl = newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: localServer.URL + "/my-org/my-repo", Path: "environments/dev"})
+		Expect(validator.ValidateSource(ctx, gitopsDepl)).To(Succeed())
+
+		validator.FailOpen = false
+		err = validator.ValidateSource(ctx, gitopsDepl)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(Equal("unable to access repository '" + localServer.URL + "/my-org/my-repo'"))
+
+		By("verifying a repository of a protocol other than https, ssh or git is not connected to")
+		gitopsDepl = newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: "http://127.0.0.1/my-org/my-repo", Path: "environments/dev"})
+		err = validator.ValidateSource(ctx, gitopsDepl)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("only https, ssh and git repositories are verified"))
+
+		for _, repoURL := range []string{"ssh://git@127.0.0.1/my-org/my-repo", "git://169.254.169.254/my-org/my-repo"} {
+			gitopsDepl = newGitOpsDeployment(managedgitopsv1alpha1.ApplicationSource{RepoURL: repoURL, Path: "environments/dev"})
+			err = validator.ValidateSource(ctx, gitopsDepl)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("unable to access repository '" + repoURL + "'"))
+		}
+
+		Expect(requests).To(Equal(0))
+	})
+})
@@ -28,6 +28,7 @@ import (
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/go-logr/logr"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
@@ -93,7 +94,7 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	} else {
 		applicationDB.Application_id = databaseID
 	}
-	if _, _, err := r.Cache.GetApplicationById(ctx, applicationDB.Application_id); err != nil {
+	if dbApplication, _, err := r.Cache.GetApplicationById(ctx, applicationDB.Application_id); err != nil {
 		if db.IsResultNotFoundError(err) {
 
 			log.V(logutil.LogLevel_Warn).Info("Application CR '" + req.NamespacedName.String() +
@@ -114,10 +115,29 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			log.Error(err, "Unable to retrieve Application from database: "+applicationDB.Application_id)
 			return ctrl.Result{}, err
 		}
+	} else {
+		applicationDB = &dbApplication
 	}
 
 	log = log.WithValues("applicationID", applicationDB.Application_id)
 
+	// For multi-source Applications, retrieve the fields of the Application that are specific to multi-source Applications
+	multiSourceFields := controllers.MultiSourceApplicationFields{}
+	if dbSources, err := controllers.GetSpecFieldSources(applicationDB.Spec_field); err != nil {
+		log.Error(err, "unable to retrieve sources from Application spec field")
+	} else if len(dbSources) > 0 {
+		if multiSourceFields, err = controllers.GetMultiSourceApplicationFields(ctx, rClient, app); err != nil {
+			log.Error(err, "unable to retrieve multi-source fields of Application")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Record the most recent sync operation of the Application, if it succeeded: a failure to record the history is
+	// logged, but does not prevent the ApplicationState from being updated (the history is recorded on the next reconcile).
+	if err := recordDeploymentHistory(ctx, app, applicationDB.Application_id, multiSourceFields, r.DB, log); err != nil {
+		log.Error(err, "unable to record deployment history of Application")
+	}
+
 	// 3) Does there exist an ApplicationState for this Application, already?
 	applicationState := &db.ApplicationState{
 		Applicationstate_application_id: applicationDB.Application_id,
@@ -153,7 +173,7 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 			// storeInComparedToFieldInApplicationState will read 'comparedTo' field of an Argo CD Application, and write the
 			// correct value into 'applicationState'.
-			reconciledState, err := storeInComparedToFieldInApplicationState(app, *applicationState)
+			reconciledState, err := storeInComparedToFieldInApplicationState(app, multiSourceFields, *applicationState)
 			if err != nil {
 				log.Error(err, "unable to store comparedToField in ApplicationState")
 				return ctrl.Result{}, err
@@ -209,7 +229,7 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// storeInComparedToFieldInApplicationState will read 'comparedTo' field of an Argo CD Application, and write the
 	// correct value into 'applicationState'.
-	reconciledState, err := storeInComparedToFieldInApplicationState(app, *applicationState)
+	reconciledState, err := storeInComparedToFieldInApplicationState(app, multiSourceFields, *applicationState)
 	if err != nil {
 		log.Error(err, "unable to store comparedToField in ApplicationState")
 		return ctrl.Result{}, err
@@ -242,6 +262,128 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 }
 
+// recordDeploymentHistory adds a DeploymentHistory row for the most recent sync operation of the Argo CD Application, if
+// that operation succeeded and has not already been recorded. Older rows are then pruned, so that at most
+// db.MaxDeploymentHistoryEntriesPerApplication rows are retained for the Application.
+func recordDeploymentHistory(ctx context.Context, app appv1.Application, applicationID string,
+	multiSourceFields controllers.MultiSourceApplicationFields, dbQueries db.DatabaseQueries, log logr.Logger) error {
+
+	operationState := app.Status.OperationState
+	if operationState == nil || operationState.Phase != common.OperationSucceeded || operationState.FinishedAt == nil ||
+		operationState.Operation.Sync == nil || operationState.Operation.Sync.DryRun {
+		return nil
+	}
+
+	// 1) Retrieve the revision and source(s) that were deployed by the operation
+	deployedSource := fauxargocd.FauxDeploymentHistorySource{}
+	var revision string
+
+	if len(multiSourceFields.ComparedToSources) > 0 {
+		for _, source := range multiSourceFields.ComparedToSources {
+			deployedSource.Sources = append(deployedSource.Sources, fauxargocd.ApplicationSource{
+				RepoURL:        source.RepoURL,
+				Path:           source.Path,
+				TargetRevision: source.TargetRevision,
+				Chart:          source.Chart,
+				Ref:            source.Ref,
+			})
+		}
+		revision = strings.Join(multiSourceFields.Revisions, ",")
+
+	} else {
+		source := app.Spec.Source
+		revision = operationState.Operation.Sync.Revision
+		if operationState.SyncResult != nil {
+			source = operationState.SyncResult.Source
+			revision = operationState.SyncResult.Revision
+		}
+
+		deployedSource.Source = fauxargocd.ApplicationSource{
+			RepoURL:        source.RepoURL,
+			Path:           source.Path,
+			TargetRevision: source.TargetRevision,
+			Chart:          source.Chart,
+		}
+	}
+
+	if revision == "" {
+		log.V(logutil.LogLevel_Debug).Info("Skipping deployment history of a sync operation that has no revision")
+		return nil
+	}
+
+	// 2) If the operation has already been recorded, there is no more work to do
+	var deploymentHistory []db.DeploymentHistory
+	if err := dbQueries.ListDeploymentHistoryByApplicationId(ctx, applicationID, &deploymentHistory); err != nil {
+		return fmt.Errorf("unable to list deployment history: %v", err)
+	}
+
+	// Argo CD reports the time at which an operation finished to the second, so we compare the times to the second.
+	deployedAt := operationState.FinishedAt.Time.UTC().Truncate(time.Second)
+	if len(deploymentHistory) > 0 && !deploymentHistory[0].Deployed_at.UTC().Truncate(time.Second).Before(deployedAt) {
+		return nil
+	}
+
+	sourceBytes, err := json.Marshal(deployedSource)
+	if err != nil {
+		return fmt.Errorf("SEVERE: unable to convert deployed source to JSON: %v", err)
+	}
+
+	newDeploymentHistory := db.DeploymentHistory{
+		Application_id: applicationID,
+		Revision:       db.TruncateVarchar(revision, db.DeploymentHistoryRevisionLength),
+		Source:         string(sourceBytes),
+		Deployed_at:    deployedAt,
+	}
+
+	// Argo CD records the start time of the operation in its own deployment history: the ID of the matching entry
+	// may be used to roll back to this deployment.
+	for idx := range app.Status.History {
+		argoHistory := app.Status.History[idx]
+		if argoHistory.DeployStartedAt != nil && argoHistory.DeployStartedAt.Equal(&operationState.StartedAt) {
+			historyID := argoHistory.ID
+			newDeploymentHistory.History_id = &historyID
+		}
+	}
+
+	// 3) Determine who initiated the operation, and, for operations started on behalf of a GitOpsDeploymentSyncRun,
+	// the name of that SyncRun.
+	if operationState.Operation.InitiatedBy.Automated {
+		newDeploymentHistory.Initiated_by = db.DeploymentHistory_InitiatedBy_Automated
+	} else {
+		newDeploymentHistory.Initiated_by = db.TruncateVarchar(operationState.Operation.InitiatedBy.Username, db.DeploymentHistoryInitiatedByLength)
+	}
+
+	for _, info := range operationState.Operation.Info {
+		if info == nil || info.Name != argosharedutil.ArgoCDOperationInfoSyncOperationIDKey || info.Value == "" {
+			continue
+		}
+
+		apiCRToDBMapping := db.APICRToDatabaseMapping{
+			APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+			DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+			DBRelationKey:   info.Value,
+		}
+		if err := dbQueries.GetAPICRForDatabaseUID(ctx, &apiCRToDBMapping); err != nil {
+			// The SyncRun may have since been deleted: the history is still recorded, but without the SyncRun name.
+			log.V(logutil.LogLevel_Warn).Info("unable to retrieve the GitOpsDeploymentSyncRun of a sync operation", "syncOperationID", info.Value, "error", err.Error())
+		} else {
+			newDeploymentHistory.Sync_run_name = db.TruncateVarchar(apiCRToDBMapping.APIResourceName, db.DeploymentHistorySyncRunNameLength)
+		}
+	}
+
+	// 4) Record the operation, and prune the older entries
+	if err := dbQueries.CreateDeploymentHistory(ctx, &newDeploymentHistory); err != nil {
+		return fmt.Errorf("unable to create deployment history: %v", err)
+	}
+	log.Info("Recorded deployment history of Application", newDeploymentHistory.GetAsLogKeyValues()...)
+
+	if _, err := dbQueries.PruneDeploymentHistoryByApplicationId(ctx, applicationID, db.MaxDeploymentHistoryEntriesPerApplication); err != nil {
+		return fmt.Errorf("unable to prune deployment history: %v", err)
+	}
+
+	return nil
+}
+
 func sanitizeHealthAndStatus(applicationState *db.ApplicationState) {
 
 	if applicationState.Health == "" {
@@ -281,7 +423,9 @@ func (r *ApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 // storeInComparedToFieldInApplicationState will read 'comparedTo' field of an Argo CD Application, and write the
 // correct value into 'applicationState'.
-func storeInComparedToFieldInApplicationState(argoCDAppParam appv1.Application, applicationState db.ApplicationState) (string, error) {
+// - For multi-source Applications, the sources and revisions are read from multiSourceFields.
+func storeInComparedToFieldInApplicationState(argoCDAppParam appv1.Application, multiSourceFields controllers.MultiSourceApplicationFields,
+	applicationState db.ApplicationState) (string, error) {
 
 	comparedToParam := argoCDAppParam.Status.Sync.ComparedTo
 	// 1) Convert into a non-Argo CD version of this data, so that we can easily store it in the database as JSON
@@ -297,6 +441,17 @@ func storeInComparedToFieldInApplicationState(argoCDAppParam appv1.Application,
 		},
 	}
 
+	for _, source := range multiSourceFields.ComparedToSources {
+		comparedTo.Sources = append(comparedTo.Sources, fauxargocd.ApplicationSource{
+			RepoURL:        source.RepoURL,
+			Path:           source.Path,
+			TargetRevision: source.TargetRevision,
+			Chart:          source.Chart,
+			Ref:            source.Ref,
+		})
+	}
+	comparedTo.Revisions = multiSourceFields.Revisions
+
 	// If the comparedToParam is non-empty, then process it
 	if !reflect.DeepEqual(comparedToParam, appv1.ComparedTo{}) {
 
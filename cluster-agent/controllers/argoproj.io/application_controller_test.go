@@ -10,11 +10,13 @@ import (
 
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers/argoproj.io/application_info_cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -878,6 +880,121 @@ var _ = Describe("Application Controller", func() {
 			By("Verifying that the ApplicationState DB row has been updated to match the Application SyncError")
 			Expect(applicationStateget.SyncError).To(Equal("Failed to sync"))
 		})
+
+		It("should record the deployment history of successful sync operations, once per operation", func() {
+			By("Close database connection")
+			defer dbQueries.CloseDatabase()
+			defer testTeardown()
+
+			ctx = context.Background()
+
+			applicationDB := &db.Application{
+				Application_id:          guestbookApp.Labels[dbID],
+				Name:                    name,
+				Spec_field:              "{}",
+				Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+				Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+			}
+			err = reconciler.DB.CreateApplication(ctx, applicationDB)
+			Expect(err).To(BeNil())
+
+			By("creating a SyncRun mapping for the SyncOperation that requested the sync")
+			apiCRToDBMapping := db.APICRToDatabaseMapping{
+				APIResourceType:      db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+				APIResourceUID:       "test-sync-run-uid",
+				APIResourceName:      "my-sync-run",
+				APIResourceNamespace: "my-namespace",
+				NamespaceUID:         "test-namespace-uid",
+				DBRelationType:       db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+				DBRelationKey:        "test-syncoperation-id",
+			}
+			err = reconciler.DB.CreateAPICRToDatabaseMapping(ctx, &apiCRToDBMapping)
+			Expect(err).To(BeNil())
+
+			By("creating an Application with a successful sync operation that was requested by the SyncRun")
+			startedAt := metav1.NewTime(time.Now().Add(-2 * time.Minute).Truncate(time.Second))
+			finishedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+			guestbookApp.Status.History = appv1.RevisionHistories{
+				{ID: 4, Revision: "abcdef", DeployStartedAt: &startedAt, DeployedAt: finishedAt},
+			}
+			guestbookApp.Status.OperationState = &appv1.OperationState{
+				Operation: appv1.Operation{
+					Sync:        &appv1.SyncOperation{Revision: "main"},
+					InitiatedBy: appv1.OperationInitiator{Username: "admin"},
+					Info:        []*appv1.Info{{Name: argosharedutil.ArgoCDOperationInfoSyncOperationIDKey, Value: apiCRToDBMapping.DBRelationKey}},
+				},
+				Phase:      common.OperationSucceeded,
+				StartedAt:  startedAt,
+				FinishedAt: &finishedAt,
+				SyncResult: &appv1.SyncOperationResult{
+					Revision: "abcdef",
+					Source:   guestbookApp.Spec.Source,
+				},
+			}
+			err = reconciler.Create(ctx, guestbookApp)
+			Expect(err).To(BeNil())
+
+			By("calling Reconcile twice, and verifying that a single DeploymentHistory row is recorded")
+			for i := 0; i < 2; i++ {
+				_, err = reconciler.Reconcile(ctx, newRequest(namespace, name))
+				Expect(err).To(BeNil())
+			}
+
+			var deploymentHistory []db.DeploymentHistory
+			err = reconciler.DB.ListDeploymentHistoryByApplicationId(ctx, applicationDB.Application_id, &deploymentHistory)
+			Expect(err).To(BeNil())
+			Expect(deploymentHistory).To(HaveLen(1))
+			Expect(deploymentHistory[0].Revision).To(Equal("abcdef"))
+			Expect(deploymentHistory[0].Initiated_by).To(Equal("admin"))
+			Expect(deploymentHistory[0].Sync_run_name).To(Equal(apiCRToDBMapping.APIResourceName))
+			Expect(deploymentHistory[0].Deployed_at.Unix()).To(Equal(finishedAt.Unix()))
+			Expect(deploymentHistory[0].History_id).ToNot(BeNil())
+			Expect(*deploymentHistory[0].History_id).To(Equal(int64(4)))
+
+			deployedSource := fauxargocd.FauxDeploymentHistorySource{}
+			err = json.Unmarshal([]byte(deploymentHistory[0].Source), &deployedSource)
+			Expect(err).To(BeNil())
+			Expect(deployedSource.Source.RepoURL).To(Equal(guestbookApp.Spec.Source.RepoURL))
+			Expect(deployedSource.Source.Path).To(Equal(guestbookApp.Spec.Source.Path))
+
+			By("simulating a new automated sync operation that failed, and verifying that it is not recorded")
+			err = reconciler.Get(ctx, client.ObjectKeyFromObject(guestbookApp), guestbookApp)
+			Expect(err).To(BeNil())
+
+			failedFinishedAt := metav1.NewTime(finishedAt.Add(30 * time.Second))
+			guestbookApp.Status.OperationState.Operation = appv1.Operation{
+				Sync:        &appv1.SyncOperation{Revision: "main"},
+				InitiatedBy: appv1.OperationInitiator{Automated: true},
+			}
+			guestbookApp.Status.OperationState.Phase = common.OperationFailed
+			guestbookApp.Status.OperationState.FinishedAt = &failedFinishedAt
+			err = reconciler.Update(ctx, guestbookApp)
+			Expect(err).To(BeNil())
+
+			_, err = reconciler.Reconcile(ctx, newRequest(namespace, name))
+			Expect(err).To(BeNil())
+
+			err = reconciler.DB.ListDeploymentHistoryByApplicationId(ctx, applicationDB.Application_id, &deploymentHistory)
+			Expect(err).To(BeNil())
+			Expect(deploymentHistory).To(HaveLen(1))
+
+			By("simulating a new automated sync operation that succeeded, and verifying that it is recorded")
+			guestbookApp.Status.OperationState.Phase = common.OperationSucceeded
+			guestbookApp.Status.OperationState.SyncResult.Revision = "123456"
+			err = reconciler.Update(ctx, guestbookApp)
+			Expect(err).To(BeNil())
+
+			_, err = reconciler.Reconcile(ctx, newRequest(namespace, name))
+			Expect(err).To(BeNil())
+
+			err = reconciler.DB.ListDeploymentHistoryByApplicationId(ctx, applicationDB.Application_id, &deploymentHistory)
+			Expect(err).To(BeNil())
+			Expect(deploymentHistory).To(HaveLen(2))
+			Expect(deploymentHistory[0].Revision).To(Equal("123456"))
+			Expect(deploymentHistory[0].Initiated_by).To(Equal(db.DeploymentHistory_InitiatedBy_Automated))
+			Expect(deploymentHistory[0].Sync_run_name).To(BeEmpty())
+			Expect(deploymentHistory[1].Revision).To(Equal("abcdef"))
+		})
 	})
 
 	Context("Test compressObject function", func() {
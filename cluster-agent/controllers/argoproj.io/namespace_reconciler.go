@@ -193,7 +193,7 @@ func syncCRsWithDB_Applications(ctx context.Context, dbQueries db.DatabaseQuerie
 
 				// At this point we have the applications from ArgoCD and DB, now compare them to check if they are not in Sync.
 
-				if compare, err := controllers.CompareApplication(applicationFromArgoCD, applicationRowFromDB, log); err != nil {
+				if compare, err := controllers.CompareApplicationWithSources(ctx, client, applicationFromArgoCD, applicationRowFromDB, log); err != nil {
 					log.Error(err, "unable to compare application contents")
 					continue
 				} else if compare != "" {
@@ -15,6 +15,7 @@ import (
 	dbutil "github.com/redhat-appstudio/managed-gitops/backend-shared/db/util"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/metrics"
@@ -22,9 +23,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
-	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -463,6 +464,39 @@ func (task *processOperationEventTask) internalPerformTask(taskContext context.C
 
 		return &dbOperation, shouldRetry, err
 
+	} else if dbOperation.Resource_type == db.OperationResourceType_TerminateSyncOperation {
+
+		// Process a request to terminate the sync operation of a SyncOperation
+		shouldRetry, err := processOperation_TerminateSyncOperation(taskContext, dbOperation, *operationCR, operationConfigParams)
+
+		if err != nil {
+			log.Error(err, "error occurred on processing the terminate sync operation")
+		}
+
+		return &dbOperation, shouldRetry, err
+
+	} else if dbOperation.Resource_type == db.OperationResourceType_RefreshApplication {
+
+		// Process a request to refresh an Argo CD Application
+		shouldRetry, err := processOperation_RefreshApplication(taskContext, dbOperation, *operationCR, operationConfigParams)
+
+		if err != nil {
+			log.Error(err, "error occurred on processing the refresh application operation")
+		}
+
+		return &dbOperation, shouldRetry, err
+
+	} else if dbOperation.Resource_type == db.OperationResourceType_Preview {
+
+		// Process a request to preview the resources of an Argo CD Application at an alternate revision
+		shouldRetry, err := processOperation_Preview(taskContext, dbOperation, *operationCR, operationConfigParams)
+
+		if err != nil {
+			log.Error(err, "error occurred on processing the preview operation")
+		}
+
+		return &dbOperation, shouldRetry, err
+
 	} else if dbOperation.Resource_type == db.OperationResourceType_GitOpsEngineInstance {
 
 		// Process a SyncOperation event
@@ -570,6 +604,252 @@ func processOperation_SyncOperation(ctx context.Context, dbOperation db.Operatio
 	}
 }
 
+// processOperation_TerminateSyncOperation terminates the Argo CD sync operation that was started on behalf of the
+// SyncOperation pointed to by the Operation, if it is still running.
+// returns shouldRetry, error
+func processOperation_TerminateSyncOperation(ctx context.Context, dbOperation db.Operation, crOperation operation.Operation,
+	opConfig operationConfig) (bool, error) {
+
+	log := opConfig.log
+	dbQueries := opConfig.dbQueries
+
+	// Sanity checks
+	if dbOperation.Resource_id == "" {
+		return shouldRetryFalse, fmt.Errorf("resource id was nil while processing operation: " + crOperation.Name)
+	}
+
+	// 1) Retrieve the SyncOperation DB entry pointed to by the Operation DB entry
+	dbSyncOperation := &db.SyncOperation{
+		SyncOperation_id: dbOperation.Resource_id,
+	}
+	if err := dbQueries.GetSyncOperationById(ctx, dbSyncOperation); err != nil {
+
+		if !db.IsResultNotFoundError(err) {
+			log.Error(err, "DB error occurred on retrieving SyncOperation: "+dbSyncOperation.SyncOperation_id)
+			return shouldRetryTrue, err
+		}
+
+		// If the SyncOperation no longer exists, there is nothing to terminate.
+		log.V(logutil.LogLevel_Debug).Info("SyncOperation '" + dbSyncOperation.SyncOperation_id + "' DB entry was no longer available, during terminate.")
+		return shouldRetryFalse, nil
+	}
+
+	// 2) Retrieve the Application DB entry pointed to by the SyncOperation DB entry
+	dbApplication := db.Application{
+		Application_id: dbSyncOperation.Application_id,
+	}
+	if err := dbQueries.GetApplicationById(ctx, &dbApplication); err != nil {
+
+		if db.IsResultNotFoundError(err) {
+			// If the Application no longer exists, neither does the sync operation.
+			log.V(logutil.LogLevel_Debug).Info("Application '" + dbApplication.Application_id + "' of SyncOperation was no longer available, during terminate.")
+			return shouldRetryFalse, nil
+		}
+
+		log.Error(err, "Error occurred on retrieving application ID in SyncOperation table")
+		return shouldRetryTrue, err
+	}
+
+	// 3) Only terminate the operation of the Argo CD Application, if it was started on behalf of the SyncOperation:
+	// the SyncOperation may have already completed, and another sync may be running in its place.
+	isRunning, err := isSyncOperationRunning(ctx, opConfig.eventClient, dbApplication.Name, opConfig.argoCDNamespace.Name, *dbSyncOperation)
+	if err != nil {
+		log.Error(err, "unable to determine if the sync operation of SyncOperation is running for Application: "+dbApplication.Name)
+		return shouldRetryTrue, err
+	}
+	if !isRunning {
+		log.Info("The sync operation of SyncOperation '" + dbSyncOperation.SyncOperation_id + "' is not running, so there is nothing to terminate")
+		return shouldRetryFalse, nil
+	}
+
+	if err := opConfig.syncFuncs.terminateOperation(ctx, dbApplication.Name, opConfig.argoCDNamespace, opConfig.credentialService,
+		opConfig.eventClient, time.Duration(5*time.Minute), log); err != nil {
+
+		log.Error(err, "unable to terminate operation: "+dbApplication.Name)
+		return shouldRetryTrue, err
+	}
+
+	log.Info("Successfully terminated the sync operation of SyncOperation '" + dbSyncOperation.SyncOperation_id + "' for application '" + dbApplication.Name + "'")
+
+	return shouldRetryFalse, nil
+}
+
+// processOperation_RefreshApplication asks Argo CD to refresh the Argo CD Application of the Application DB entry
+// pointed to by the Operation: for example, after new commits were pushed to the repository of the Application.
+// returns shouldRetry, error
+func processOperation_RefreshApplication(ctx context.Context, dbOperation db.Operation, crOperation operation.Operation,
+	opConfig operationConfig) (bool, error) {
+
+	log := opConfig.log
+	dbQueries := opConfig.dbQueries
+
+	// Sanity checks
+	if dbOperation.Resource_id == "" {
+		return shouldRetryFalse, fmt.Errorf("resource id was nil while processing operation: " + crOperation.Name)
+	}
+
+	// 1) Retrieve the Application DB entry pointed to by the Operation DB entry
+	dbApplication := db.Application{
+		Application_id: dbOperation.Resource_id,
+	}
+	if err := dbQueries.GetApplicationById(ctx, &dbApplication); err != nil {
+
+		if db.IsResultNotFoundError(err) {
+			// If the Application no longer exists, there is nothing to refresh.
+			log.V(logutil.LogLevel_Debug).Info("Application '" + dbApplication.Application_id + "' DB entry was no longer available, during refresh.")
+			return shouldRetryFalse, nil
+		}
+
+		log.Error(err, "DB error occurred on retrieving Application: "+dbApplication.Application_id)
+		return shouldRetryTrue, err
+	}
+
+	// 2) Ask Argo CD to refresh the Argo CD Application, and wait for it to do so.
+	if err := opConfig.syncFuncs.refreshApp(ctx, opConfig.eventClient, dbApplication.Name, opConfig.argoCDNamespace.Name); err != nil {
+
+		if apierr.IsNotFound(err) {
+			// The Argo CD Application may not have been created yet, in which case it will be up-to-date once it is.
+			log.Info("Argo CD Application '" + dbApplication.Name + "' was not found, so there is nothing to refresh")
+			return shouldRetryFalse, nil
+		}
+
+		log.Error(err, "unable to refresh application: "+dbApplication.Name)
+		return shouldRetryTrue, err
+	}
+
+	log.Info("Successfully refreshed application '" + dbApplication.Name + "'")
+
+	return shouldRetryFalse, nil
+}
+
+// processOperation_Preview compares the resources of the Argo CD Application of the Preview DB entry, at the revision
+// of the Preview, with the live resources of the Application. The result is stored in the Preview DB entry, for the
+// backend to retrieve.
+//
+// Returns shouldRetry, error
+func processOperation_Preview(ctx context.Context, dbOperation db.Operation, crOperation operation.Operation,
+	opConfig operationConfig) (bool, error) {
+
+	log := opConfig.log
+	dbQueries := opConfig.dbQueries
+
+	// Sanity checks
+	if dbOperation.Resource_id == "" {
+		return shouldRetryFalse, fmt.Errorf("resource id was nil while processing operation: " + crOperation.Name)
+	}
+
+	// 1) Retrieve the Preview DB entry pointed to by the Operation DB entry
+	dbPreview := db.Preview{
+		Preview_id: dbOperation.Resource_id,
+	}
+	if err := dbQueries.GetPreviewById(ctx, &dbPreview); err != nil {
+
+		if db.IsResultNotFoundError(err) {
+			// If the Preview no longer exists, then the backend is no longer waiting for the result.
+			log.V(logutil.LogLevel_Debug).Info("Preview '" + dbPreview.Preview_id + "' DB entry was no longer available.")
+			return shouldRetryFalse, nil
+		}
+
+		log.Error(err, "DB error occurred on retrieving Preview: "+dbPreview.Preview_id)
+		return shouldRetryTrue, err
+	}
+
+	if dbPreview.State != db.PreviewState_Waiting {
+		log.V(logutil.LogLevel_Debug).Info("Preview '" + dbPreview.Preview_id + "' was already processed.")
+		return shouldRetryFalse, nil
+	}
+
+	// 2) Retrieve the Application DB entry pointed to by the Preview
+	dbApplication := db.Application{
+		Application_id: dbPreview.Application_id,
+	}
+	if err := dbQueries.GetApplicationById(ctx, &dbApplication); err != nil {
+
+		if db.IsResultNotFoundError(err) {
+			return updatePreviewAsFailed(ctx, dbPreview, fmt.Errorf("the Application of the Preview no longer exists"), opConfig)
+		}
+
+		log.Error(err, "DB error occurred on retrieving Application: "+dbApplication.Application_id)
+		return shouldRetryTrue, err
+	}
+
+	// 3) Ask Argo CD to render the resources at the revision, and compare them with the live resources
+	resolvedRevision, resourceDiffs, truncated, err := opConfig.syncFuncs.appPreview(ctx, dbApplication.Name, dbPreview.Revision,
+		opConfig.argoCDNamespace, opConfig.credentialService, opConfig.eventClient)
+	if err != nil {
+		log.Error(err, "unable to preview application: "+dbApplication.Name)
+		return updatePreviewAsFailed(ctx, dbPreview, err, opConfig)
+	}
+
+	// 4) Store the result in the Preview DB entry
+	resources, err := sharedutil.CompressObject(resourceDiffs)
+	if err != nil {
+		return updatePreviewAsFailed(ctx, dbPreview, fmt.Errorf("unable to compress the result of the preview: %v", err), opConfig)
+	}
+
+	dbPreview.State = db.PreviewState_Completed
+	dbPreview.Resolved_revision = db.TruncateVarchar(resolvedRevision, db.PreviewResolvedRevisionLength)
+	dbPreview.Resources = resources
+	dbPreview.Resources_truncated = truncated
+
+	if err := dbQueries.UpdatePreview(ctx, &dbPreview); err != nil {
+		log.Error(err, "unable to update Preview: "+dbPreview.Preview_id)
+		return shouldRetryTrue, err
+	}
+
+	log.Info("Successfully previewed application '"+dbApplication.Name+"'", "revision", dbPreview.Revision,
+		"resolvedRevision", resolvedRevision, "resources", len(resourceDiffs), "truncated", truncated)
+
+	return shouldRetryFalse, nil
+}
+
+// updatePreviewAsFailed updates the state of the Preview DB entry to Failed, with the given error as the message. The
+// error is returned, so that the Operation is also Failed.
+func updatePreviewAsFailed(ctx context.Context, dbPreview db.Preview, previewErr error, opConfig operationConfig) (bool, error) {
+
+	dbPreview.State = db.PreviewState_Failed
+	dbPreview.Message = db.TruncateVarchar(previewErr.Error(), db.PreviewMessageLength)
+
+	if err := opConfig.dbQueries.UpdatePreview(ctx, &dbPreview); err != nil {
+		opConfig.log.Error(err, "unable to update Preview as failed: "+dbPreview.Preview_id)
+		return shouldRetryTrue, err
+	}
+
+	return shouldRetryFalse, previewErr
+}
+
+// isSyncOperationRunning returns true if the Argo CD Application has an operation in progress, that was started on
+// behalf of the given SyncOperation.
+func isSyncOperationRunning(ctx context.Context, k8sClient client.Client, appName, appNS string, dbSyncOperation db.SyncOperation) (bool, error) {
+	app := &appv1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      appName,
+			Namespace: appNS,
+		},
+	}
+
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(app), app); err != nil {
+		if apierr.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if app.Operation == nil {
+		return false, nil
+	}
+
+	for _, info := range app.Operation.Info {
+		if info != nil && info.Name == argosharedutil.ArgoCDOperationInfoSyncOperationIDKey {
+			return info.Value == dbSyncOperation.SyncOperation_id, nil
+		}
+	}
+
+	// Argo CD rollback operations cannot reference the SyncOperation: so a running operation that references no
+	// SyncOperation is assumed to be the rollback. Otherwise, it was started by someone else (e.g. an automated sync).
+	return dbSyncOperation.RollbackHistoryID != nil, nil
+}
+
 func refreshApplication(ctx context.Context, k8sClient client.Client, appName, appNS string) error {
 	appCR := &appv1.Application{
 		ObjectMeta: metav1.ObjectMeta{
@@ -578,25 +858,18 @@ func refreshApplication(ctx context.Context, k8sClient client.Client, appName, a
 		},
 	}
 
-	// Update the application with refresh annotation if it is not present already.
-	// RetryOnConflict retries updating the application if there are conflicts.
-	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(appCR), appCR)
-		if err != nil {
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(appCR), appCR); err != nil {
+		return err
+	}
+
+	// Add the refresh annotation to the application, if it is not present already. The annotation is added via a merge
+	// patch (rather than an Update), so that the '.spec.sources' field of multi-source Applications is preserved.
+	if refreshType, ok := appCR.Annotations[appv1.AnnotationKeyRefresh]; !ok || refreshType != string(appv1.RefreshTypeNormal) {
+		if err := controllers.PatchApplicationMetadata(ctx, k8sClient, appCR, map[string]interface{}{
+			"annotations": map[string]interface{}{appv1.AnnotationKeyRefresh: string(appv1.RefreshTypeNormal)},
+		}); err != nil {
 			return err
 		}
-		if appCR.Annotations == nil {
-			appCR.Annotations = map[string]string{}
-		}
-		refreshType, ok := appCR.Annotations[appv1.AnnotationKeyRefresh]
-		if !ok || refreshType != string(appv1.RefreshTypeNormal) {
-			appCR.Annotations[appv1.AnnotationKeyRefresh] = string(appv1.RefreshTypeNormal)
-			return k8sClient.Update(ctx, appCR)
-		}
-		return nil
-	})
-	if err != nil {
-		return err
 	}
 
 	// wait until the refresh annotation is removed from the Argo CD application
@@ -673,17 +946,22 @@ func isOperationRunning(ctx context.Context, k8sClient client.Client, appName, a
 
 // syncFuncs is a wrapper over sync and terminate functions and is used in unit testing different sync scenarios
 type syncFuncs struct {
-	appSync            func(context.Context, string, string, string, client.Client, *utils.CredentialService, bool) error
+	appSync            func(context.Context, string, string, string, client.Client, *utils.CredentialService, bool, []*appv1.Info, fauxargocd.FauxSyncOperationOptions) error
+	appRollback        func(context.Context, string, int64, bool, string, client.Client, *utils.CredentialService, bool) error
 	terminateOperation func(context.Context, string, corev1.Namespace, *utils.CredentialService, client.Client, time.Duration, logr.Logger) error
 
 	refreshApp func(context.Context, client.Client, string, string) error
+
+	appPreview func(context.Context, string, string, corev1.Namespace, *utils.CredentialService, client.Client) (string, []operation.PreviewResourceDiff, bool, error)
 }
 
 func defaultSyncFuncs() *syncFuncs {
 	return &syncFuncs{
 		appSync:            utils.AppSync,
+		appRollback:        utils.AppRollback,
 		terminateOperation: utils.TerminateOperation,
 		refreshApp:         refreshApplication,
+		appPreview:         utils.AppPreview,
 	}
 }
 
@@ -701,10 +979,28 @@ func runAppSync(ctx context.Context, dbOperation db.Operation, dbSyncOperation d
 
 	defer cancelFunc()
 
-	// Start the AppSync operation in a separate thread.
+	// The SyncOperation is referenced from the sync operation of the Argo CD Application, so that the Application
+	// controller can tell which GitOpsDeploymentSyncRun requested the sync.
+	syncInfos := []*appv1.Info{{Name: argosharedutil.ArgoCDOperationInfoSyncOperationIDKey, Value: dbSyncOperation.SyncOperation_id}}
+
+	// The options of the sync (prune, dryRun, force, etc), if any were specified by the GitOpsDeploymentSyncRun
+	syncOptions := fauxargocd.FauxSyncOperationOptions{}
+	if dbSyncOperation.Options != "" {
+		if err := json.Unmarshal([]byte(dbSyncOperation.Options), &syncOptions); err != nil {
+			log.Error(err, "SEVERE: unable to unmarshal the options of SyncOperation '"+dbSyncOperation.SyncOperation_id+"'")
+			return shouldRetryFalse, err
+		}
+	}
+
+	// Start the AppSync (or AppRollback, if the SyncOperation is a rollback) operation in a separate thread.
 	go func() {
-		err = opConfig.syncFuncs.appSync(cancellableCtx, dbApplication.Name, dbSyncOperation.Revision, opConfig.argoCDNamespace.Name, opConfig.eventClient,
-			opConfig.credentialService, false)
+		if dbSyncOperation.RollbackHistoryID != nil {
+			err = opConfig.syncFuncs.appRollback(cancellableCtx, dbApplication.Name, *dbSyncOperation.RollbackHistoryID, syncOptions.Prune,
+				opConfig.argoCDNamespace.Name, opConfig.eventClient, opConfig.credentialService, false)
+		} else {
+			err = opConfig.syncFuncs.appSync(cancellableCtx, dbApplication.Name, dbSyncOperation.Revision, opConfig.argoCDNamespace.Name, opConfig.eventClient,
+				opConfig.credentialService, false, syncInfos, syncOptions)
+		}
 
 		var failed bool
 		if err != nil {
@@ -880,6 +1176,14 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 			// Add databaseID label
 			app.ObjectMeta.Labels = map[string]string{controllers.ArgoCDApplicationDatabaseIDLabel: dbApplication.Application_id}
 
+			// Add the deletion policy annotation, if the resources of the Application should be orphaned on deletion
+			deletionPolicy, err := controllers.GetSpecFieldDeletionPolicy(dbApplication.Spec_field)
+			if err != nil {
+				log.Error(err, "SEVERE: unable to unmarshal application deletion policy on creating Application CR.")
+				return shouldRetryFalse, nil
+			}
+			controllers.SetDeletionPolicyAnnotation(app, deletionPolicy)
+
 			// Before we create the application, make sure that the managed environment exists that the application points to
 			if app.Spec.Destination.Name != argosharedutil.ArgoCDDefaultDestinationInCluster {
 				if err := ensureManagedEnvironmentExists(ctx, *dbApplication, opConfig); err != nil {
@@ -888,12 +1192,30 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 				}
 			}
 
-			if err := opConfig.eventClient.Create(ctx, app, &client.CreateOptions{}); err != nil {
+			// Multi-source Applications are created from unstructured content, as the Argo CD API types do not contain the sources field
+			var appToCreate client.Object = app
+			if dbSources, err := controllers.GetSpecFieldSources(dbApplication.Spec_field); err != nil {
+				log.Error(err, "SEVERE: unable to unmarshal application sources on creating Application CR.")
+				return shouldRetryFalse, nil
+			} else if len(dbSources) > 0 {
+				if appToCreate, err = controllers.ConvertToMultiSourceApplication(*app, dbSources); err != nil {
+					log.Error(err, "unable to convert Application CR to a multi-source Application")
+					return shouldRetryFalse, err
+				}
+			}
+
+			if err := opConfig.eventClient.Create(ctx, appToCreate, &client.CreateOptions{}); err != nil {
 				log.Error(err, "Unable to create Argo CD Application CR")
 				// This may or may not be salvageable depending on the error; ultimately we should figure out which
 				// error messages mean unsalvageable, and not wait for them.
 				return shouldRetryTrue, err
 			}
+			if multiSourceApp, isMultiSource := appToCreate.(*unstructured.Unstructured); isMultiSource {
+				if err := controllers.VerifyMultiSourceApplicationIsSupported(multiSourceApp); err != nil {
+					log.Error(err, "multi-source Application is not supported by Argo CD")
+					return shouldRetryFalse, err
+				}
+			}
 			logutil.LogAPIResourceChangeEvent(app.Namespace, app.Name, app, logutil.ResourceCreated, log)
 
 			// Success
@@ -912,7 +1234,7 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 
 	// Before we create the application, make sure that the managed environment that the application points to exists
 
-	specDiff, err := controllers.CompareApplication(*app, *dbApplication, log)
+	specDiff, err := controllers.CompareApplicationWithSources(ctx, opConfig.eventClient, *app, *dbApplication, log)
 	if err != nil {
 		log.Error(err, "unable to compare Argo CD Application with DB row")
 		return shouldRetryFalse, err
@@ -928,16 +1250,53 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 			return shouldRetryFalse, nil
 		}
 
+		// If automated sync has been (re-)enabled, for example because the GitOpsDeployment was resumed, ask Argo CD to
+		// refresh the Application, so that any changes made while automated sync was disabled are detected promptly.
+		if specFieldApp.Spec.SyncPolicy != nil && specFieldApp.Spec.SyncPolicy.Automated != nil &&
+			(app.Spec.SyncPolicy == nil || app.Spec.SyncPolicy.Automated == nil) {
+
+			if app.Annotations == nil {
+				app.Annotations = map[string]string{}
+			}
+			app.Annotations[appv1.AnnotationKeyRefresh] = string(appv1.RefreshTypeNormal)
+		}
+
 		app.Spec.Destination = specFieldApp.Spec.Destination
 		app.Spec.Source = specFieldApp.Spec.Source
 		app.Spec.Project = specFieldApp.Spec.Project
 		app.Spec.SyncPolicy = specFieldApp.Spec.SyncPolicy
+		app.Spec.IgnoreDifferences = specFieldApp.Spec.IgnoreDifferences
 
-		if err := opConfig.eventClient.Update(ctx, app); err != nil {
+		deletionPolicy, err := controllers.GetSpecFieldDeletionPolicy(dbApplication.Spec_field)
+		if err != nil {
+			log.Error(err, "SEVERE: unable to unmarshal DB application deletion policy, on updating existing Application CR: "+app.Name)
+			return shouldRetryFalse, nil
+		}
+		controllers.SetDeletionPolicyAnnotation(app, deletionPolicy)
+
+		// Multi-source Applications are updated from unstructured content, as the Argo CD API types do not contain the sources field
+		var appToUpdate client.Object = app
+		if dbSources, err := controllers.GetSpecFieldSources(dbApplication.Spec_field); err != nil {
+			log.Error(err, "SEVERE: unable to unmarshal DB application sources, on updating existing Application CR: "+app.Name)
+			return shouldRetryFalse, nil
+		} else if len(dbSources) > 0 {
+			if appToUpdate, err = controllers.ConvertToMultiSourceApplication(*app, dbSources); err != nil {
+				log.Error(err, "unable to convert Application CR to a multi-source Application")
+				return shouldRetryFalse, err
+			}
+		}
+
+		if err := opConfig.eventClient.Update(ctx, appToUpdate); err != nil {
 			log.Error(err, "unable to update application after difference detected.")
 			// Retry if we were unable to update the Application, for example due to a conflict
 			return shouldRetryTrue, err
 		}
+		if multiSourceApp, isMultiSource := appToUpdate.(*unstructured.Unstructured); isMultiSource {
+			if err := controllers.VerifyMultiSourceApplicationIsSupported(multiSourceApp); err != nil {
+				log.Error(err, "multi-source Application is not supported by Argo CD")
+				return shouldRetryFalse, err
+			}
+		}
 		logutil.LogAPIResourceChangeEvent(app.Namespace, app.Name, app, logutil.ResourceModified, log)
 
 		log.Info("Updated Argo CD Application CR", "specDiff", specDiff)
@@ -1291,6 +1650,16 @@ func generateExpectedClusterSecret(ctx context.Context, application db.Applicati
 		},
 	}
 
+	if clusterCredentials.Client_certificate_data != "" {
+		clusterSecretConfigJSON.TLSClientConfig.CertData = []byte(clusterCredentials.Client_certificate_data)
+		clusterSecretConfigJSON.TLSClientConfig.KeyData = []byte(clusterCredentials.Client_key_data)
+	}
+
+	// Argo CD (and client-go) do not allow a CA to be specified when TLS verification is skipped
+	if clusterCredentials.Certificate_authority_data != "" && !insecureVerifyTLS {
+		clusterSecretConfigJSON.TLSClientConfig.CAData = []byte(clusterCredentials.Certificate_authority_data)
+	}
+
 	jsonString, err := json.Marshal(clusterSecretConfigJSON)
 	if err != nil {
 		return corev1.Secret{}, deleteSecret_false, fmt.Errorf("SEVERE: unable to marshal JSON")
@@ -28,7 +28,9 @@ import (
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	dbutil "github.com/redhat-appstudio/managed-gitops/backend-shared/db/util"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	sharedoperations "github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 
@@ -142,6 +144,49 @@ var _ = Describe("Operation Controller", func() {
 
 		})
 
+		It("generateExpectedClusterSecret should include the client certificate and CA of the cluster credentials in the cluster secret", func() {
+
+			clusterCredentials := db.ClusterCredentials{
+				Clustercredentials_cred_id: "test-cluster-creds-test",
+				Host:                       "https://my-cluster-url.com",
+				Serviceaccount_ns:          "Serviceaccount_ns",
+				Client_certificate_data:    "client-certificate-data",
+				Client_key_data:            "client-key-data",
+				Certificate_authority_data: "certificate-authority-data",
+			}
+			err := dbQueries.CreateClusterCredentials(ctx, &clusterCredentials)
+			Expect(err).To(BeNil())
+
+			managedEnvironment := db.ManagedEnvironment{
+				Managedenvironment_id: "test-managed-env",
+				Clustercredentials_id: clusterCredentials.Clustercredentials_cred_id,
+				Name:                  "my env",
+			}
+			err = dbQueries.CreateManagedEnvironment(ctx, &managedEnvironment)
+			Expect(err).To(BeNil())
+
+			applicationDB := &db.Application{
+				Application_id:          "test-my-application",
+				Name:                    name,
+				Spec_field:              "{}",
+				Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+				Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+			}
+			err = dbQueries.CreateApplication(ctx, applicationDB)
+			Expect(err).To(BeNil())
+
+			secret, shouldDelete, err := generateExpectedClusterSecret(ctx, *applicationDB, opConfigVal)
+			Expect(err).To(BeNil())
+			Expect(shouldDelete).To(BeFalse())
+
+			configJSON := argosharedutil.ClusterSecretConfigJSON{}
+			Expect(json.Unmarshal(secret.Data["config"], &configJSON)).To(Succeed())
+			Expect(configJSON.BearerToken).To(BeEmpty())
+			Expect(string(configJSON.TLSClientConfig.CertData)).To(Equal("client-certificate-data"))
+			Expect(string(configJSON.TLSClientConfig.KeyData)).To(Equal("client-key-data"))
+			Expect(string(configJSON.TLSClientConfig.CAData)).To(Equal("certificate-authority-data"))
+		})
+
 		It("generateExpectedClusterSecret should reject an invalid URL containing query parameters", func() {
 
 			clusterCredentials := db.ClusterCredentials{
@@ -2327,9 +2372,11 @@ var _ = Describe("Operation Controller", func() {
 				By("create Operation DB row and CR for the SyncOperation")
 				createOperationDBAndCR(syncOperation.SyncOperation_id, gitopsEngineInstanceID)
 
-				By("verify there is no retry for a successful sync")
+				By("verify there is no retry for a successful sync, and that the SyncOperation is referenced by the sync operation")
+				var syncInfos []*appv1.Info
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, infos []*appv1.Info, options fauxargocd.FauxSyncOperationOptions) error {
+						syncInfos = infos
 						return nil
 					},
 					refreshApp: refreshApplication,
@@ -2339,6 +2386,102 @@ var _ = Describe("Operation Controller", func() {
 				Expect(err).Should(BeNil())
 				Expect(retry).To(BeFalse())
 
+				Expect(syncInfos).To(ConsistOf(&appv1.Info{Name: argosharedutil.ArgoCDOperationInfoSyncOperationIDKey, Value: syncOperation.SyncOperation_id}))
+
+				By("verify if the refresh annotation was added")
+				Expect(<-refreshAnnotationFound).To(Equal(struct{}{}))
+			})
+
+			It("should roll back the Application, rather than sync it, if the SyncOperation is a rollback", func() {
+
+				By("create a SyncOperation in the database, which rolls back to a history ID")
+				rollbackHistoryID := int64(2)
+				syncOperation := db.SyncOperation{
+					SyncOperation_id:    "test-syncoperation",
+					Application_id:      applicationDB.Application_id,
+					DeploymentNameField: "test",
+					Revision:            "main",
+					DesiredState:        db.SyncOperation_DesiredState_Running,
+					RollbackHistoryID:   &rollbackHistoryID,
+				}
+				err = dbQueries.CreateSyncOperation(ctx, &syncOperation)
+				Expect(err).To(BeNil())
+
+				By("create Operation DB row and CR for the SyncOperation")
+				createOperationDBAndCR(syncOperation.SyncOperation_id, gitopsEngineInstanceID)
+
+				By("verify that the Application is rolled back to the history ID, and not synced")
+				appSyncCalled := false
+				var rolledBackAppName string
+				var rolledBackHistoryID int64 = -1
+				task.syncFuncs = &syncFuncs{
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, infos []*appv1.Info, options fauxargocd.FauxSyncOperationOptions) error {
+						appSyncCalled = true
+						return nil
+					},
+					appRollback: func(ctx context.Context, appName string, historyID int64, prune bool, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+						rolledBackAppName = appName
+						rolledBackHistoryID = historyID
+						return nil
+					},
+					refreshApp: refreshApplication,
+				}
+
+				retry, err := task.PerformTask(ctx)
+				Expect(err).Should(BeNil())
+				Expect(retry).To(BeFalse())
+
+				Expect(appSyncCalled).To(BeFalse())
+				Expect(rolledBackAppName).To(Equal(applicationDB.Name))
+				Expect(rolledBackHistoryID).To(Equal(rollbackHistoryID))
+
+				By("verify if the refresh annotation was added")
+				Expect(<-refreshAnnotationFound).To(Equal(struct{}{}))
+			})
+
+			It("should pass the options of the SyncOperation to the sync", func() {
+
+				By("create a SyncOperation in the database, with sync options")
+				expectedOptions := fauxargocd.FauxSyncOperationOptions{
+					Prune:       true,
+					DryRun:      true,
+					Strategy:    "apply",
+					SyncOptions: fauxargocd.SyncOptions{"Validate=false"},
+					Resources:   []fauxargocd.FauxSyncOperationResource{{Kind: "ConfigMap", Name: "my-config-map", Namespace: "my-namespace"}},
+				}
+				optionsJSON, err := json.Marshal(expectedOptions)
+				Expect(err).To(BeNil())
+
+				syncOperation := db.SyncOperation{
+					SyncOperation_id:    "test-syncoperation",
+					Application_id:      applicationDB.Application_id,
+					DeploymentNameField: "test",
+					Revision:            "main",
+					DesiredState:        db.SyncOperation_DesiredState_Running,
+					Options:             string(optionsJSON),
+				}
+				err = dbQueries.CreateSyncOperation(ctx, &syncOperation)
+				Expect(err).To(BeNil())
+
+				By("create Operation DB row and CR for the SyncOperation")
+				createOperationDBAndCR(syncOperation.SyncOperation_id, gitopsEngineInstanceID)
+
+				By("verify that the options are passed to the sync")
+				var syncOptions fauxargocd.FauxSyncOperationOptions
+				task.syncFuncs = &syncFuncs{
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, infos []*appv1.Info, options fauxargocd.FauxSyncOperationOptions) error {
+						syncOptions = options
+						return nil
+					},
+					refreshApp: refreshApplication,
+				}
+
+				retry, err := task.PerformTask(ctx)
+				Expect(err).Should(BeNil())
+				Expect(retry).To(BeFalse())
+
+				Expect(syncOptions).To(Equal(expectedOptions))
+
 				By("verify if the refresh annotation was added")
 				Expect(<-refreshAnnotationFound).To(Equal(struct{}{}))
 			})
@@ -2362,7 +2505,7 @@ var _ = Describe("Operation Controller", func() {
 				By("check if the sync failed error is returned with retry")
 				expectedErr := "sync failed due to xyz reason"
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, infos []*appv1.Info, options fauxargocd.FauxSyncOperationOptions) error {
 						return fmt.Errorf(expectedErr)
 					},
 					refreshApp: refreshApplication,
@@ -2436,7 +2579,7 @@ var _ = Describe("Operation Controller", func() {
 				Expect(apierr.IsConflict(err)).To(BeTrue())
 
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, infos []*appv1.Info, options fauxargocd.FauxSyncOperationOptions) error {
 						return nil
 					},
 					refreshApp: refreshApplication,
@@ -2458,7 +2601,7 @@ var _ = Describe("Operation Controller", func() {
 
 				By("check if SyncOperation not found error is handled")
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, infos []*appv1.Info, options fauxargocd.FauxSyncOperationOptions) error {
 						return nil
 					},
 				}
@@ -2486,7 +2629,7 @@ var _ = Describe("Operation Controller", func() {
 				createOperationDBAndCR(syncOperation.SyncOperation_id, gitopsEngineInstanceID)
 
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, infos []*appv1.Info, options fauxargocd.FauxSyncOperationOptions) error {
 						return nil
 					},
 				}
@@ -2584,6 +2727,200 @@ var _ = Describe("Operation Controller", func() {
 				Expect(retry).To(BeFalse())
 			})
 
+			It("should terminate the sync operation of a SyncOperation, for an Operation of type TerminateSyncOperation", func() {
+				By("create a running SyncOperation in the database")
+				syncOperation := db.SyncOperation{
+					SyncOperation_id:    "test-syncoperation",
+					Application_id:      applicationDB.Application_id,
+					DeploymentNameField: "test",
+					Revision:            "main",
+					DesiredState:        db.SyncOperation_DesiredState_Running,
+				}
+				err = dbQueries.CreateSyncOperation(ctx, &syncOperation)
+				Expect(err).To(BeNil())
+
+				By("create Operation DB row of type TerminateSyncOperation, and CR")
+				createOperationDBAndCR(syncOperation.SyncOperation_id, gitopsEngineInstanceID)
+
+				operationDB := &db.Operation{Operation_id: "test-operation"}
+				err = dbQueries.GetOperationById(ctx, operationDB)
+				Expect(err).To(BeNil())
+				operationDB.Resource_type = db.OperationResourceType_TerminateSyncOperation
+				err = dbQueries.UpdateOperation(ctx, operationDB)
+				Expect(err).To(BeNil())
+
+				terminateCalled := false
+				task.syncFuncs = &syncFuncs{
+					terminateOperation: func(ctx context.Context, s string, n corev1.Namespace, cs *utils.CredentialService, c client.Client, d time.Duration, l logr.Logger) error {
+						terminateCalled = true
+						return nil
+					},
+				}
+
+				By("verify the operation is not terminated if the running operation of the Application was started by another SyncOperation")
+				applicationCR.Operation = &appv1.Operation{
+					Sync: &appv1.SyncOperation{Revision: "123"},
+					Info: []*appv1.Info{{Name: argosharedutil.ArgoCDOperationInfoSyncOperationIDKey, Value: "another-syncoperation"}},
+				}
+				err = k8sClient.Update(ctx, applicationCR)
+				Expect(err).To(BeNil())
+
+				retry, err := task.PerformTask(ctx)
+				Expect(err).Should(BeNil())
+				Expect(retry).To(BeFalse())
+				Expect(terminateCalled).To(BeFalse())
+
+				By("verify the operation is terminated if the running operation of the Application was started by the SyncOperation")
+				err = dbQueries.GetOperationById(ctx, operationDB)
+				Expect(err).To(BeNil())
+				operationDB.State = db.OperationState_Waiting
+				err = dbQueries.UpdateOperation(ctx, operationDB)
+				Expect(err).To(BeNil())
+
+				err = k8sClient.Get(ctx, client.ObjectKeyFromObject(applicationCR), applicationCR)
+				Expect(err).To(BeNil())
+				applicationCR.Operation.Info = []*appv1.Info{{Name: argosharedutil.ArgoCDOperationInfoSyncOperationIDKey, Value: syncOperation.SyncOperation_id}}
+				err = k8sClient.Update(ctx, applicationCR)
+				Expect(err).To(BeNil())
+
+				retry, err = task.PerformTask(ctx)
+				Expect(err).Should(BeNil())
+				Expect(retry).To(BeFalse())
+				Expect(terminateCalled).To(BeTrue())
+			})
+
+			It("should refresh the Argo CD Application, for an Operation of type RefreshApplication", func() {
+				By("create Operation DB row of type RefreshApplication, and CR")
+				createOperationDBAndCR(applicationDB.Application_id, gitopsEngineInstanceID)
+
+				operationDB := &db.Operation{Operation_id: "test-operation"}
+				err = dbQueries.GetOperationById(ctx, operationDB)
+				Expect(err).To(BeNil())
+				operationDB.Resource_type = db.OperationResourceType_RefreshApplication
+				err = dbQueries.UpdateOperation(ctx, operationDB)
+				Expect(err).To(BeNil())
+
+				refreshedApp := ""
+				task.syncFuncs = &syncFuncs{
+					refreshApp: func(ctx context.Context, c client.Client, appName, appNS string) error {
+						refreshedApp = appName
+						return nil
+					},
+				}
+
+				retry, err := task.PerformTask(ctx)
+				Expect(err).Should(BeNil())
+				Expect(retry).To(BeFalse())
+				Expect(refreshedApp).To(Equal(applicationDB.Name))
+
+				By("verify the Operation is completed")
+				err = dbQueries.GetOperationById(ctx, operationDB)
+				Expect(err).To(BeNil())
+				Expect(operationDB.State).To(Equal(db.OperationState_Completed))
+			})
+
+			It("should store the result of the preview in the Preview DB row, for an Operation of type Preview", func() {
+				By("create a Preview in the database")
+				preview := db.Preview{
+					Preview_id:     "test-preview",
+					Application_id: applicationDB.Application_id,
+					Revision:       "my-branch",
+					State:          db.PreviewState_Waiting,
+				}
+				err = dbQueries.CreatePreview(ctx, &preview)
+				Expect(err).To(BeNil())
+
+				By("create Operation DB row of type Preview, and CR")
+				createOperationDBAndCR(preview.Preview_id, gitopsEngineInstanceID)
+
+				operationDB := &db.Operation{Operation_id: "test-operation"}
+				err = dbQueries.GetOperationById(ctx, operationDB)
+				Expect(err).To(BeNil())
+				operationDB.Resource_type = db.OperationResourceType_Preview
+				err = dbQueries.UpdateOperation(ctx, operationDB)
+				Expect(err).To(BeNil())
+
+				resourceDiffs := []managedgitopsv1alpha1.PreviewResourceDiff{{
+					Kind:      "ConfigMap",
+					Namespace: "my-namespace",
+					Name:      "my-config-map",
+					Change:    managedgitopsv1alpha1.PreviewResourceChange_Changed,
+					Diff:      "-a\n+b\n",
+				}}
+
+				previewedRevision := ""
+				task.syncFuncs = &syncFuncs{
+					appPreview: func(ctx context.Context, appName string, revision string, ns corev1.Namespace, cs *utils.CredentialService,
+						c client.Client) (string, []managedgitopsv1alpha1.PreviewResourceDiff, bool, error) {
+						Expect(appName).To(Equal(applicationDB.Name))
+						previewedRevision = revision
+						return "abc123", resourceDiffs, true, nil
+					},
+				}
+
+				retry, err := task.PerformTask(ctx)
+				Expect(err).Should(BeNil())
+				Expect(retry).To(BeFalse())
+				Expect(previewedRevision).To(Equal(preview.Revision))
+
+				By("verify the result is stored in the Preview, and the Operation is completed")
+				err = dbQueries.GetPreviewById(ctx, &preview)
+				Expect(err).To(BeNil())
+				Expect(preview.State).To(Equal(db.PreviewState_Completed))
+				Expect(preview.Resolved_revision).To(Equal("abc123"))
+				Expect(preview.Resources_truncated).To(BeTrue())
+
+				resourcesBytes, err := sharedutil.DecompressObject(preview.Resources)
+				Expect(err).To(BeNil())
+				var storedResourceDiffs []managedgitopsv1alpha1.PreviewResourceDiff
+				err = json.Unmarshal(resourcesBytes, &storedResourceDiffs)
+				Expect(err).To(BeNil())
+				Expect(storedResourceDiffs).To(Equal(resourceDiffs))
+
+				err = dbQueries.GetOperationById(ctx, operationDB)
+				Expect(err).To(BeNil())
+				Expect(operationDB.State).To(Equal(db.OperationState_Completed))
+			})
+
+			It("should mark the Preview as failed, if the preview could not be computed", func() {
+				preview := db.Preview{
+					Preview_id:     "test-preview",
+					Application_id: applicationDB.Application_id,
+					Revision:       "does-not-exist",
+					State:          db.PreviewState_Waiting,
+				}
+				err = dbQueries.CreatePreview(ctx, &preview)
+				Expect(err).To(BeNil())
+
+				createOperationDBAndCR(preview.Preview_id, gitopsEngineInstanceID)
+
+				operationDB := &db.Operation{Operation_id: "test-operation"}
+				err = dbQueries.GetOperationById(ctx, operationDB)
+				Expect(err).To(BeNil())
+				operationDB.Resource_type = db.OperationResourceType_Preview
+				err = dbQueries.UpdateOperation(ctx, operationDB)
+				Expect(err).To(BeNil())
+
+				task.syncFuncs = &syncFuncs{
+					appPreview: func(ctx context.Context, appName string, revision string, ns corev1.Namespace, cs *utils.CredentialService,
+						c client.Client) (string, []managedgitopsv1alpha1.PreviewResourceDiff, bool, error) {
+						return "", nil, false, fmt.Errorf("unable to resolve revision")
+					},
+				}
+
+				retry, err := task.PerformTask(ctx)
+				Expect(err).ShouldNot(BeNil())
+				Expect(retry).To(BeFalse())
+
+				err = dbQueries.GetPreviewById(ctx, &preview)
+				Expect(err).To(BeNil())
+				Expect(preview.State).To(Equal(db.PreviewState_Failed))
+				Expect(preview.Message).To(Equal("unable to resolve revision"))
+
+				err = dbQueries.GetOperationById(ctx, operationDB)
+				Expect(err).To(BeNil())
+				Expect(operationDB.State).To(Equal(db.OperationState_Failed))
+			})
 		})
 
 		Context("Test if Operation is running for an Application", func() {
@@ -2,16 +2,29 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
+	argocdcommon "github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/go-logr/logr"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
+	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
@@ -23,6 +36,9 @@ const (
 
 const (
 	argoCDResourcesFinalizer = "resources-finalizer.argocd.argoproj.io/background"
+
+	// argoCDResourcesFinalizerPrefix is the prefix of the finalizers that cause Argo CD to delete the resources of an Application
+	argoCDResourcesFinalizerPrefix = "resources-finalizer.argocd.argoproj.io"
 )
 
 const (
@@ -36,6 +52,10 @@ const (
 // - Issue a Delete to K8s API
 // - If the Application is not deleted after X minutes, remove the finalizer
 // - If the Application is not deleted after X+2 minutes, return an error
+//
+// If the Application has an orphan deletion policy (see ArgoCDApplicationDeletionPolicyAnnotation), the Application is
+// deleted without the Argo CD resources finalizer, so that the deployed resources are left in place. If the policy asks
+// for it, the Argo CD tracking labels/annotations are then removed from those resources.
 func DeleteArgoCDApplication(ctx context.Context, appFromList appv1.Application, eventClient client.Client, log logr.Logger) error {
 
 	log = log.WithValues("name", appFromList.Name, "namespace", appFromList.Namespace, "uid", string(appFromList.UID))
@@ -65,6 +85,11 @@ func DeleteArgoCDApplication(ctx context.Context, appFromList appv1.Application,
 		return nil
 	}
 
+	deletionPolicy := app.Annotations[argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation]
+	if managedgitopsv1alpha1.IsOrphanDeletionPolicy(deletionPolicy) {
+		return deleteArgoCDApplicationAndOrphanResources(ctx, app, deletionPolicy, eventClient, log)
+	}
+
 	if app.DeletionTimestamp == nil {
 
 		// Ensure finalizer is set
@@ -78,8 +103,7 @@ func DeleteArgoCDApplication(ctx context.Context, appFromList appv1.Application,
 			}
 
 			if !containsFinalizer {
-				app.Finalizers = append(app.Finalizers, argoCDResourcesFinalizer)
-				if err := eventClient.Update(ctx, app); err != nil {
+				if err := PatchApplicationFinalizers(ctx, eventClient, app, append(app.Finalizers, argoCDResourcesFinalizer)); err != nil {
 					log.Error(err, "unable to update application with finalizer")
 					return err
 				}
@@ -172,8 +196,7 @@ func DeleteArgoCDApplication(ctx context.Context, appFromList appv1.Application,
 				if len(app.Finalizers) != 0 {
 					// If the application exists, and it has a finalizer, remove the finalizer and try again
 					log.Info("removing finalizer from Application")
-					app.Finalizers = []string{}
-					if err := eventClient.Update(ctx, app); err != nil {
+					if err := PatchApplicationFinalizers(ctx, eventClient, app, []string{}); err != nil {
 						log.Error(err, "unable to remove finalizer from Application")
 						continue
 					}
@@ -195,6 +218,206 @@ func DeleteArgoCDApplication(ctx context.Context, appFromList appv1.Application,
 	return nil
 }
 
+// PatchApplicationMetadata applies a JSON merge patch to the '.metadata' field of an Argo CD Application, and updates
+// 'app' with the result.
+//
+// Argo CD Applications must not be modified via a typed Update: the appv1.Application type predates multi-source
+// Applications, and so an Update would remove the '.spec.sources' field of a multi-source Application.
+func PatchApplicationMetadata(ctx context.Context, k8sClient client.Client, app *appv1.Application, metadataPatch map[string]interface{}) error {
+
+	patchBytes, err := json.Marshal(map[string]interface{}{"metadata": metadataPatch})
+	if err != nil {
+		return fmt.Errorf("SEVERE: unable to marshal patch: %v", err)
+	}
+
+	// The patch is applied to an unstructured Application, so that the response is not required to be decoded into the
+	// Argo CD API types, before being converted into 'app'.
+	unstructuredApp := &unstructured.Unstructured{}
+	unstructuredApp.SetGroupVersionKind(appv1.SchemeGroupVersion.WithKind(application.ApplicationKind))
+	unstructuredApp.SetName(app.Name)
+	unstructuredApp.SetNamespace(app.Namespace)
+
+	if err := k8sClient.Patch(ctx, unstructuredApp, client.RawPatch(types.MergePatchType, patchBytes)); err != nil {
+		return err
+	}
+
+	appJSON, err := unstructuredApp.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("unable to marshal patched Application '%s': %v", app.Name, err)
+	}
+
+	patchedApp := appv1.Application{}
+	if err := json.Unmarshal(appJSON, &patchedApp); err != nil {
+		return fmt.Errorf("unable to unmarshal patched Application '%s': %v", app.Name, err)
+	}
+	*app = patchedApp
+
+	return nil
+}
+
+// PatchApplicationFinalizers replaces the finalizers of an Argo CD Application, via PatchApplicationMetadata. The
+// resourceVersion of 'app' is included in the patch, so that the patch fails with a conflict if the Application was
+// modified since it was retrieved (as a merge patch replaces the entire finalizers list).
+func PatchApplicationFinalizers(ctx context.Context, k8sClient client.Client, app *appv1.Application, finalizers []string) error {
+
+	if finalizers == nil {
+		finalizers = []string{}
+	}
+
+	return PatchApplicationMetadata(ctx, k8sClient, app, map[string]interface{}{
+		"finalizers":      finalizers,
+		"resourceVersion": app.ResourceVersion,
+	})
+}
+
+// deleteArgoCDApplicationAndOrphanResources deletes an Argo CD Application, without deleting the resources that it
+// deployed: Argo CD only deletes the resources of an Application if the Application has the resources finalizer, so
+// the finalizer is removed before the Application is deleted.
+func deleteArgoCDApplicationAndOrphanResources(ctx context.Context, app *appv1.Application, deletionPolicy string,
+	eventClient client.Client, log logr.Logger) error {
+
+	log = log.WithValues("deletionPolicy", deletionPolicy)
+
+	// The resources of the Application, before it is deleted
+	managedResources := app.Status.Resources
+
+	// Remove the Argo CD resources finalizer(s), if present
+	finalizers := []string{}
+	for _, finalizer := range app.Finalizers {
+		if !strings.HasPrefix(finalizer, argoCDResourcesFinalizerPrefix) {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	if len(finalizers) != len(app.Finalizers) {
+		if err := PatchApplicationFinalizers(ctx, eventClient, app, finalizers); err != nil {
+			log.Error(err, "unable to remove resources finalizer from Application with orphan deletion policy")
+			return err
+		}
+		logutil.LogAPIResourceChangeEvent(app.Namespace, app.Name, app, logutil.ResourceModified, log)
+	}
+
+	if app.DeletionTimestamp == nil {
+		if err := eventClient.Delete(ctx, app); err != nil && !apierr.IsNotFound(err) {
+			log.Error(err, "unable to delete application with orphan deletion policy")
+			return err
+		}
+		logutil.LogAPIResourceChangeEvent(app.Namespace, app.Name, app, logutil.ResourceDeleted, log)
+	}
+
+	log.Info("Argo CD Application was deleted, and its resources were orphaned")
+
+	if deletionPolicy == managedgitopsv1alpha1.GitOpsDeploymentDeletionPolicy_OrphanAndRemoveTracking && len(managedResources) > 0 {
+
+		destinationClient, err := getDestinationClusterClient(ctx, *app, eventClient)
+		if err != nil {
+			// Log the error, but continue: the Application has already been deleted.
+			log.Error(err, "unable to create a client for the destination cluster, to remove the Argo CD tracking metadata of the orphaned resources")
+			return nil
+		}
+
+		if err := RemoveArgoCDTrackingMetadata(ctx, app.Name, managedResources, destinationClient, log); err != nil {
+			// Log the error, but continue: the Application has already been deleted.
+			log.Error(err, "unable to remove the Argo CD tracking metadata of the orphaned resources")
+		}
+	}
+
+	return nil
+}
+
+// getDestinationClusterClient returns a K8s client for the cluster that an Argo CD Application deploys to: either the
+// local cluster, or the cluster described by the Argo CD cluster secret of the Application's destination.
+func getDestinationClusterClient(ctx context.Context, app appv1.Application, eventClient client.Client) (client.Client, error) {
+
+	var restConfig *rest.Config
+
+	if app.Spec.Destination.Name == argosharedutil.ArgoCDDefaultDestinationInCluster ||
+		(app.Spec.Destination.Name == "" && app.Spec.Destination.Server == appv1.KubernetesInternalAPIServerAddr) {
+
+		var err error
+		if restConfig, err = sharedutil.GetRESTConfig(); err != nil {
+			return nil, fmt.Errorf("unable to retrieve the REST config of the local cluster: %v", err)
+		}
+
+	} else {
+
+		clusterSecret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      app.Spec.Destination.Name,
+				Namespace: app.Namespace,
+			},
+		}
+		if err := eventClient.Get(ctx, client.ObjectKeyFromObject(&clusterSecret), &clusterSecret); err != nil {
+			return nil, fmt.Errorf("unable to retrieve the cluster secret '%s' of the Application: %v", clusterSecret.Name, err)
+		}
+
+		var err error
+		if restConfig, err = argosharedutil.GenerateRESTConfigFromClusterSecret(clusterSecret); err != nil {
+			return nil, err
+		}
+	}
+
+	return client.New(restConfig, client.Options{})
+}
+
+// RemoveArgoCDTrackingMetadata removes the label and annotation that Argo CD uses to track the resources of an
+// Application (named 'appName') from those resources, so that they are no longer associated with the Application.
+// The label/annotation are only removed if they reference the Application.
+func RemoveArgoCDTrackingMetadata(ctx context.Context, appName string, resources []appv1.ResourceStatus,
+	destinationClient client.Client, log logr.Logger) error {
+
+	var firstError error
+
+	for _, resource := range resources {
+
+		log := log.WithValues("group", resource.Group, "kind", resource.Kind, "resourceNamespace", resource.Namespace, "resourceName", resource.Name)
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: resource.Group, Version: resource.Version, Kind: resource.Kind})
+
+		if err := destinationClient.Get(ctx, client.ObjectKey{Namespace: resource.Namespace, Name: resource.Name}, obj); err != nil {
+			if apierr.IsNotFound(err) {
+				continue
+			}
+			log.Error(err, "unable to retrieve orphaned resource")
+			if firstError == nil {
+				firstError = err
+			}
+			continue
+		}
+
+		metadataPatch := map[string]interface{}{}
+
+		if obj.GetLabels()[argocdcommon.LabelKeyAppInstance] == appName {
+			metadataPatch["labels"] = map[string]interface{}{argocdcommon.LabelKeyAppInstance: nil}
+		}
+
+		if trackingID, exists := obj.GetAnnotations()[argocdcommon.AnnotationKeyAppInstance]; exists && strings.HasPrefix(trackingID, appName+":") {
+			metadataPatch["annotations"] = map[string]interface{}{argocdcommon.AnnotationKeyAppInstance: nil}
+		}
+
+		if len(metadataPatch) == 0 {
+			continue
+		}
+
+		patchBytes, err := json.Marshal(map[string]interface{}{"metadata": metadataPatch})
+		if err != nil {
+			return fmt.Errorf("SEVERE: unable to marshal patch: %v", err)
+		}
+
+		if err := destinationClient.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patchBytes)); err != nil {
+			log.Error(err, "unable to remove Argo CD tracking metadata from orphaned resource")
+			if firstError == nil {
+				firstError = err
+			}
+			continue
+		}
+
+		log.Info("Removed Argo CD tracking metadata from orphaned resource")
+	}
+
+	return firstError
+}
+
 // CompareApplication compares an Argo CD Application and the spec field of a DB Application row, returning "" if the same,
 // otherwise returning the specific difference.
 func CompareApplication(argoCDApp appv1.Application, dbApplication db.Application, log logr.Logger) (string, error) {
@@ -208,6 +431,25 @@ func CompareApplication(argoCDApp appv1.Application, dbApplication db.Applicatio
 				input.Spec.SyncPolicy.SyncOptions = appv1.SyncOptions{}
 			}
 		}
+
+		sanitizeApplicationSource(&input.Spec.Source)
+
+		if len(input.Spec.IgnoreDifferences) == 0 {
+			input.Spec.IgnoreDifferences = nil
+		}
+		for i := range input.Spec.IgnoreDifferences {
+			ignoreDifference := &input.Spec.IgnoreDifferences[i]
+			if len(ignoreDifference.JSONPointers) == 0 {
+				ignoreDifference.JSONPointers = nil
+			}
+			if len(ignoreDifference.JQPathExpressions) == 0 {
+				ignoreDifference.JQPathExpressions = nil
+			}
+			if len(ignoreDifference.ManagedFieldsManagers) == 0 {
+				ignoreDifference.ManagedFieldsManagers = nil
+			}
+		}
+
 		return input
 	}
 	argoCDApp = sanitizeApp(*argoCDApp.DeepCopy())
@@ -224,7 +466,11 @@ func CompareApplication(argoCDApp appv1.Application, dbApplication db.Applicatio
 	specFieldAppFromDB = sanitizeApp(specFieldAppFromDB)
 
 	var specDiff string
-	if !reflect.DeepEqual(specFieldAppFromDB.Spec.Source, argoCDApp.Spec.Source) {
+	if !reflect.DeepEqual(specFieldAppFromDB.Spec.Source.Helm, argoCDApp.Spec.Source.Helm) {
+		specDiff = "spec.source.helm fields differ"
+	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.Source.Kustomize, argoCDApp.Spec.Source.Kustomize) {
+		specDiff = "spec.source.kustomize fields differ"
+	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.Source, argoCDApp.Spec.Source) {
 		specDiff = "spec.source fields differ"
 	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.Destination, argoCDApp.Spec.Destination) {
 		specDiff = "spec.destination fields differ"
@@ -232,8 +478,270 @@ func CompareApplication(argoCDApp appv1.Application, dbApplication db.Applicatio
 		specDiff = "spec project fields differ"
 	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.SyncPolicy, argoCDApp.Spec.SyncPolicy) {
 		specDiff = "sync policy fields differ"
+	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.IgnoreDifferences, argoCDApp.Spec.IgnoreDifferences) {
+		specDiff = "spec.ignoreDifferences fields differ"
+	} else if deletionPolicy, err := GetSpecFieldDeletionPolicy(dbApplication.Spec_field); err != nil {
+		log.Error(err, "SEVERE: unable to unmarshal DB application deletion policy, on comparing with Application CR: "+argoCDApp.Name)
+		return "", nil
+	} else if argoCDApp.Annotations[argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation] != deletionPolicy {
+		specDiff = "deletion policy annotations differ"
 	}
 
 	return specDiff, nil
 
 }
+
+// sanitizeApplicationSource ensures that empty fields of an Argo CD Application source are defined consistently, for use
+// with reflect.DeepEqual.
+func sanitizeApplicationSource(source *appv1.ApplicationSource) {
+
+	// An empty helm field is equivalent to no helm field, and likewise for the slices within it
+	if helm := source.Helm; helm != nil {
+		if helm.IsZero() {
+			source.Helm = nil
+		} else {
+			if len(helm.ValueFiles) == 0 {
+				helm.ValueFiles = nil
+			}
+			if len(helm.Parameters) == 0 {
+				helm.Parameters = nil
+			}
+			if len(helm.FileParameters) == 0 {
+				helm.FileParameters = nil
+			}
+		}
+	}
+
+	// Likewise for the kustomize field, and the slices and maps within it
+	if kustomize := source.Kustomize; kustomize != nil {
+		if kustomize.IsZero() {
+			source.Kustomize = nil
+		} else {
+			if len(kustomize.Images) == 0 {
+				kustomize.Images = nil
+			}
+			if len(kustomize.CommonLabels) == 0 {
+				kustomize.CommonLabels = nil
+			}
+			if len(kustomize.CommonAnnotations) == 0 {
+				kustomize.CommonAnnotations = nil
+			}
+		}
+	}
+}
+
+// ApplicationSourceWithRef is an Argo CD Application source, plus the 'ref' field that is used by multi-source Applications.
+//
+// The version of the Argo CD API used by this project predates multi-source Applications, and so the fields of Argo CD
+// Applications that are specific to multi-source Applications ('.spec.sources', '.status.sync.comparedTo.sources' and
+// '.status.sync.revisions') are read and written via unstructured content.
+type ApplicationSourceWithRef struct {
+	appv1.ApplicationSource `json:",inline"`
+
+	// Ref is a reference to this source, for use by the other sources of the Application
+	Ref string `json:"ref,omitempty"`
+}
+
+// DeepCopy returns a deep copy of the ApplicationSourceWithRef
+func (in *ApplicationSourceWithRef) DeepCopy() *ApplicationSourceWithRef {
+	return &ApplicationSourceWithRef{
+		ApplicationSource: *in.ApplicationSource.DeepCopy(),
+		Ref:               in.Ref,
+	}
+}
+
+// MultiSourceApplicationFields contains the fields of an Argo CD Application that are specific to multi-source Applications.
+type MultiSourceApplicationFields struct {
+	// Sources is the '.spec.sources' field of the Application
+	Sources []ApplicationSourceWithRef
+	// ComparedToSources is the '.status.sync.comparedTo.sources' field of the Application
+	ComparedToSources []ApplicationSourceWithRef
+	// Revisions is the '.status.sync.revisions' field of the Application
+	Revisions []string
+}
+
+// multiSourceApplication is used to extract the multi-source fields from Argo CD Application JSON/YAML
+type multiSourceApplication struct {
+	Spec struct {
+		Sources []ApplicationSourceWithRef `json:"sources,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Sync struct {
+			ComparedTo struct {
+				Sources []ApplicationSourceWithRef `json:"sources,omitempty"`
+			} `json:"comparedTo"`
+			Revisions []string `json:"revisions,omitempty"`
+		} `json:"sync"`
+	} `json:"status"`
+}
+
+func (msa multiSourceApplication) toFields() MultiSourceApplicationFields {
+	return MultiSourceApplicationFields{
+		Sources:           msa.Spec.Sources,
+		ComparedToSources: msa.Status.Sync.ComparedTo.Sources,
+		Revisions:         msa.Status.Sync.Revisions,
+	}
+}
+
+// GetSpecFieldSources returns the sources defined in the spec field of a DB Application row. The returned slice is empty
+// for single-source Applications.
+func GetSpecFieldSources(specField string) ([]ApplicationSourceWithRef, error) {
+
+	msa := multiSourceApplication{}
+	if err := yaml.Unmarshal([]byte(specField), &msa); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal sources of spec field: %v", err)
+	}
+
+	return msa.Spec.Sources, nil
+}
+
+// specFieldMetadata is used to extract the metadata of the Argo CD Application from the spec field of a DB Application row
+type specFieldMetadata struct {
+	FauxObjectMeta struct {
+		Annotations map[string]string `json:"annotations,omitempty"`
+	} `json:"fauxobjectmeta"`
+}
+
+// GetSpecFieldDeletionPolicy returns the deletion policy defined in the spec field of a DB Application row, or "" if
+// the Application (and its resources) should be deleted as normal.
+func GetSpecFieldDeletionPolicy(specField string) (string, error) {
+
+	metadata := specFieldMetadata{}
+	if err := yaml.Unmarshal([]byte(specField), &metadata); err != nil {
+		return "", fmt.Errorf("unable to unmarshal metadata of spec field: %v", err)
+	}
+
+	return metadata.FauxObjectMeta.Annotations[argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation], nil
+}
+
+// SetDeletionPolicyAnnotation sets the deletion policy annotation of an Argo CD Application, or removes it if the
+// deletion policy is empty.
+func SetDeletionPolicyAnnotation(app *appv1.Application, deletionPolicy string) {
+
+	if deletionPolicy == "" {
+		delete(app.Annotations, argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation)
+		return
+	}
+
+	if app.Annotations == nil {
+		app.Annotations = map[string]string{}
+	}
+	app.Annotations[argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation] = deletionPolicy
+}
+
+// GetMultiSourceApplicationFields retrieves the given Argo CD Application from the cluster (as unstructured content),
+// and returns the fields that are specific to multi-source Applications.
+func GetMultiSourceApplicationFields(ctx context.Context, k8sClient client.Client, app appv1.Application) (MultiSourceApplicationFields, error) {
+
+	unstructuredApp := &unstructured.Unstructured{}
+	unstructuredApp.SetGroupVersionKind(appv1.SchemeGroupVersion.WithKind(application.ApplicationKind))
+
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&app), unstructuredApp); err != nil {
+		return MultiSourceApplicationFields{}, err
+	}
+
+	jsonBytes, err := unstructuredApp.MarshalJSON()
+	if err != nil {
+		return MultiSourceApplicationFields{}, fmt.Errorf("unable to marshal Application '%s': %v", app.Name, err)
+	}
+
+	msa := multiSourceApplication{}
+	if err := json.Unmarshal(jsonBytes, &msa); err != nil {
+		return MultiSourceApplicationFields{}, fmt.Errorf("unable to unmarshal multi-source fields of Application '%s': %v", app.Name, err)
+	}
+
+	return msa.toFields(), nil
+}
+
+// VerifyMultiSourceApplicationIsSupported returns an error if the given multi-source Application, as returned by the
+// cluster after it was created or updated, no longer contains '.spec.sources'. The Application CRD of Argo CD versions
+// before v2.6 does not define the field, in which case it is silently dropped by the API server.
+func VerifyMultiSourceApplicationIsSupported(app *unstructured.Unstructured) error {
+
+	sources, found, err := unstructured.NestedSlice(app.Object, "spec", "sources")
+	if err != nil || !found || len(sources) == 0 {
+		return fmt.Errorf("the sources of Application '%s' were not persisted: GitOpsDeployments with multiple sources require Argo CD v2.6 or later", app.GetName())
+	}
+
+	return nil
+}
+
+// ConvertToMultiSourceApplication returns an unstructured copy of the given Argo CD Application, with '.spec.sources' set
+// to the given sources (and '.spec.source' removed). This is used to create/update multi-source Applications.
+func ConvertToMultiSourceApplication(app appv1.Application, sources []ApplicationSourceWithRef) (*unstructured.Unstructured, error) {
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&app)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert Application '%s' to unstructured: %v", app.Name, err)
+	}
+
+	sourcesJSON, err := json.Marshal(sources)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal sources of Application '%s': %v", app.Name, err)
+	}
+
+	var sourcesContent []interface{}
+	if err := json.Unmarshal(sourcesJSON, &sourcesContent); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal sources of Application '%s': %v", app.Name, err)
+	}
+
+	res := &unstructured.Unstructured{Object: content}
+	res.SetGroupVersionKind(appv1.SchemeGroupVersion.WithKind(application.ApplicationKind))
+
+	unstructured.RemoveNestedField(res.Object, "spec", "source")
+	if err := unstructured.SetNestedSlice(res.Object, sourcesContent, "spec", "sources"); err != nil {
+		return nil, fmt.Errorf("unable to set sources of Application '%s': %v", app.Name, err)
+	}
+
+	return res, nil
+}
+
+// CompareApplicationSources compares the sources of a multi-source Argo CD Application with the sources from the spec field
+// of a DB Application row, returning "" if the same, otherwise returning the specific difference.
+func CompareApplicationSources(argoCDAppSources []ApplicationSourceWithRef, dbApplicationSources []ApplicationSourceWithRef) string {
+
+	sanitizeSources := func(input []ApplicationSourceWithRef) []ApplicationSourceWithRef {
+		res := []ApplicationSourceWithRef{}
+		for _, source := range input {
+			source := *source.DeepCopy()
+			sanitizeApplicationSource(&source.ApplicationSource)
+			res = append(res, source)
+		}
+		return res
+	}
+
+	if !reflect.DeepEqual(sanitizeSources(argoCDAppSources), sanitizeSources(dbApplicationSources)) {
+		return "spec.sources fields differ"
+	}
+
+	return ""
+}
+
+// CompareApplicationWithSources compares an Argo CD Application and the spec field of a DB Application row, like
+// CompareApplication. For multi-source Applications, the sources of the Argo CD Application are additionally retrieved
+// from the cluster and compared.
+func CompareApplicationWithSources(ctx context.Context, k8sClient client.Client, argoCDApp appv1.Application, dbApplication db.Application, log logr.Logger) (string, error) {
+
+	specDiff, err := CompareApplication(argoCDApp, dbApplication, log)
+	if err != nil || specDiff != "" {
+		return specDiff, err
+	}
+
+	dbApplicationSources, err := GetSpecFieldSources(dbApplication.Spec_field)
+	if err != nil {
+		log.Error(err, "SEVERE: unable to unmarshal DB application sources, on comparing with Application CR: "+argoCDApp.Name)
+		// As above, there is no need to keep retrying.
+		return "", nil
+	}
+
+	if len(dbApplicationSources) == 0 {
+		return "", nil
+	}
+
+	multiSourceFields, err := GetMultiSourceApplicationFields(ctx, k8sClient, argoCDApp)
+	if err != nil {
+		return "", err
+	}
+
+	return CompareApplicationSources(multiSourceFields.Sources, dbApplicationSources), nil
+}
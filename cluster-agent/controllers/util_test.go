@@ -3,15 +3,20 @@ package controllers
 import (
 	"context"
 
+	argocdcommon "github.com/argoproj/argo-cd/v2/common"
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -188,6 +193,289 @@ var _ = Describe("Tests for the small number of utility functions in cluster-age
 
 		})
 
+		It("should delete an Argo CD Application with an orphan deletion policy, without the resources finalizer", func() {
+
+			By("creating an Argo CD Application with a finalizer, a databaseID label, and an orphan deletion policy")
+			application := appv1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-name",
+					Namespace: "my-namespace",
+					Labels: map[string]string{
+						ArgoCDApplicationDatabaseIDLabel: "test-my-database-id-label",
+					},
+					Annotations: map[string]string{
+						argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation: managedgitopsv1alpha1.GitOpsDeploymentDeletionPolicy_Orphan,
+					},
+					Finalizers: []string{
+						argoCDResourcesFinalizer,
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, &application)
+			Expect(err).To(BeNil())
+
+			By("calling the DeleteArgoCDApplication function, which should not wait for Argo CD to delete the resources")
+			err = DeleteArgoCDApplication(ctx, application, k8sClient, logger)
+			Expect(err).To(BeNil())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&application), &application)
+			Expect(apierr.IsNotFound(err)).To(BeTrue(), "Application should not exist: it should have been deleted")
+		})
+
+	})
+
+	Context("RemoveArgoCDTrackingMetadata tests", func() {
+
+		It("should remove the Argo CD tracking label and annotation of the Application from its resources", func() {
+			ctx := context.Background()
+
+			scheme, _, _, _, err := tests.GenericTestSetup()
+			Expect(err).To(BeNil())
+
+			trackedConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tracked",
+					Namespace: "my-namespace",
+					Labels: map[string]string{
+						argocdcommon.LabelKeyAppInstance: "my-app",
+						"other-label":                    "other-value",
+					},
+					Annotations: map[string]string{
+						argocdcommon.AnnotationKeyAppInstance: "my-app:/ConfigMap:my-namespace/tracked",
+					},
+				},
+			}
+
+			otherAppConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tracked-by-another-app",
+					Namespace: "my-namespace",
+					Labels: map[string]string{
+						argocdcommon.LabelKeyAppInstance: "my-other-app",
+					},
+				},
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(trackedConfigMap, otherAppConfigMap).Build()
+
+			resources := []appv1.ResourceStatus{
+				{Version: "v1", Kind: "ConfigMap", Namespace: "my-namespace", Name: trackedConfigMap.Name},
+				{Version: "v1", Kind: "ConfigMap", Namespace: "my-namespace", Name: otherAppConfigMap.Name},
+				{Version: "v1", Kind: "ConfigMap", Namespace: "my-namespace", Name: "no-longer-exists"},
+			}
+
+			err = RemoveArgoCDTrackingMetadata(ctx, "my-app", resources, k8sClient, log.FromContext(ctx))
+			Expect(err).To(BeNil())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(trackedConfigMap), trackedConfigMap)
+			Expect(err).To(BeNil())
+			Expect(trackedConfigMap.Labels).To(Equal(map[string]string{"other-label": "other-value"}))
+			Expect(trackedConfigMap.Annotations).ToNot(HaveKey(argocdcommon.AnnotationKeyAppInstance))
+
+			By("verifying that resources tracked by another Application are not modified")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherAppConfigMap), otherAppConfigMap)
+			Expect(err).To(BeNil())
+			Expect(otherAppConfigMap.Labels).To(HaveKeyWithValue(argocdcommon.LabelKeyAppInstance, "my-other-app"))
+		})
+	})
+
+	Context("Multi-source Application tests", func() {
+
+		var ctx context.Context
+		var k8sClient client.WithWatch
+		var logger logr.Logger
+
+		var dbApplication db.Application
+
+		fauxSources := func(valuesRevision string) fauxargocd.ApplicationSources {
+			return fauxargocd.ApplicationSources{
+				{
+					RepoURL:        "https://charts.example.com",
+					Chart:          "my-chart",
+					TargetRevision: "1.2.3",
+					Helm: &fauxargocd.ApplicationSourceHelm{
+						ValueFiles: []string{"$values/charts/my-chart/values.yaml"},
+					},
+				},
+				{
+					RepoURL:        "https://github.com/redhat-appstudio/managed-gitops",
+					TargetRevision: valuesRevision,
+					Ref:            "values",
+				},
+			}
+		}
+
+		createSpecField := func(sources fauxargocd.ApplicationSources) string {
+			fauxApplication := fauxargocd.FauxApplication{
+				FauxTypeMeta: fauxargocd.FauxTypeMeta{
+					Kind:       "Application",
+					APIVersion: "argoproj.io/v1alpha1",
+				},
+				FauxObjectMeta: fauxargocd.FauxObjectMeta{
+					Name:      "test-my-application",
+					Namespace: "gitops-service-argocd",
+				},
+				Spec: fauxargocd.FauxApplicationSpec{
+					Sources: sources,
+					Destination: fauxargocd.ApplicationDestination{
+						Name:      "in-cluster",
+						Namespace: "test-fake-namespace",
+					},
+					Project: "default",
+				},
+			}
+			bytes, err := yaml.Marshal(&fauxApplication)
+			Expect(err).To(BeNil())
+			return string(bytes)
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			logger = log.FromContext(ctx)
+
+			scheme, argocdNamespace, kubesystemNamespace, workspace, err := tests.GenericTestSetup()
+			Expect(err).To(BeNil())
+
+			// The Argo CD types are intentionally not added to the scheme: the fake client would otherwise
+			// convert the Applications to appv1.Application, which (in this version of Argo CD) drops '.spec.sources'.
+			k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace, argocdNamespace, kubesystemNamespace).Build()
+
+			dbApplication = db.Application{Spec_field: createSpecField(fauxSources("main"))}
+		})
+
+		It("should return the sources of a spec field, and no sources for a single-source spec field", func() {
+			sources, err := GetSpecFieldSources(dbApplication.Spec_field)
+			Expect(err).To(BeNil())
+			Expect(sources).To(HaveLen(2))
+			Expect(sources[0].Chart).To(Equal("my-chart"))
+			Expect(sources[0].Helm.ValueFiles).To(Equal([]string{"$values/charts/my-chart/values.yaml"}))
+			Expect(sources[1].Ref).To(Equal("values"))
+
+			sources, err = GetSpecFieldSources(createSpecField(nil))
+			Expect(err).To(BeNil())
+			Expect(sources).To(BeEmpty())
+		})
+
+		It("should create a multi-source Application, and detect when its sources differ from the DB", func() {
+
+			By("creating a multi-source Argo CD Application from the spec field")
+			app := appv1.Application{}
+			Expect(yaml.Unmarshal([]byte(dbApplication.Spec_field), &app)).To(Succeed())
+			app.Name = "test-my-application"
+			app.Namespace = "gitops-service-argocd"
+
+			dbSources, err := GetSpecFieldSources(dbApplication.Spec_field)
+			Expect(err).To(BeNil())
+
+			unstructuredApp, err := ConvertToMultiSourceApplication(app, dbSources)
+			Expect(err).To(BeNil())
+			Expect(unstructuredApp.Object["spec"]).ToNot(HaveKey("source"))
+			Expect(k8sClient.Create(ctx, unstructuredApp)).To(Succeed())
+			Expect(VerifyMultiSourceApplicationIsSupported(unstructuredApp)).To(Succeed())
+
+			By("verifying that the sources of the Application are read back from the cluster")
+			multiSourceFields, err := GetMultiSourceApplicationFields(ctx, k8sClient, app)
+			Expect(err).To(BeNil())
+			Expect(multiSourceFields.Sources).To(HaveLen(2))
+			Expect(multiSourceFields.Sources[1].Ref).To(Equal("values"))
+
+			By("the sources are the same in Argo CD and DB, hence it is in sync")
+			result, err := CompareApplicationWithSources(ctx, k8sClient, app, dbApplication, logger)
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+
+			By("the revision of a source is different in Argo CD and DB, hence it is not in sync")
+			dbApplication.Spec_field = createSpecField(fauxSources("v2"))
+			result, err = CompareApplicationWithSources(ctx, k8sClient, app, dbApplication, logger)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal("spec.sources fields differ"))
+		})
+
+		It("should return an error if the sources of a multi-source Application are dropped, as Argo CD is older than v2.6", func() {
+			app := appv1.Application{}
+			Expect(yaml.Unmarshal([]byte(dbApplication.Spec_field), &app)).To(Succeed())
+			app.Name = "test-my-application"
+
+			dbSources, err := GetSpecFieldSources(dbApplication.Spec_field)
+			Expect(err).To(BeNil())
+
+			unstructuredApp, err := ConvertToMultiSourceApplication(app, dbSources)
+			Expect(err).To(BeNil())
+
+			unstructured.RemoveNestedField(unstructuredApp.Object, "spec", "sources")
+			err = VerifyMultiSourceApplicationIsSupported(unstructuredApp)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("require Argo CD v2.6 or later"))
+		})
+
+		It("should preserve the sources of a multi-source Application when it is refreshed, and its finalizers are removed", func() {
+
+			By("creating a multi-source Argo CD Application with the resources finalizer")
+			app := appv1.Application{}
+			Expect(yaml.Unmarshal([]byte(dbApplication.Spec_field), &app)).To(Succeed())
+			app.Name = "test-my-application"
+			app.Namespace = "gitops-service-argocd"
+			app.Finalizers = []string{argoCDResourcesFinalizer}
+
+			dbSources, err := GetSpecFieldSources(dbApplication.Spec_field)
+			Expect(err).To(BeNil())
+
+			unstructuredApp, err := ConvertToMultiSourceApplication(app, dbSources)
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Create(ctx, unstructuredApp)).To(Succeed())
+			app.ResourceVersion = unstructuredApp.GetResourceVersion()
+
+			By("adding the refresh annotation, and removing the finalizers")
+			Expect(PatchApplicationMetadata(ctx, k8sClient, &app, map[string]interface{}{
+				"annotations": map[string]interface{}{appv1.AnnotationKeyRefresh: string(appv1.RefreshTypeNormal)},
+			})).To(Succeed())
+			Expect(app.Annotations).To(HaveKeyWithValue(appv1.AnnotationKeyRefresh, string(appv1.RefreshTypeNormal)))
+
+			Expect(PatchApplicationFinalizers(ctx, k8sClient, &app, []string{})).To(Succeed())
+			Expect(app.Finalizers).To(BeEmpty())
+
+			By("verifying the finalizers were removed, and the sources of the Application are unchanged")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(unstructuredApp), unstructuredApp)).To(Succeed())
+			Expect(unstructuredApp.GetFinalizers()).To(BeEmpty())
+			Expect(unstructuredApp.GetAnnotations()).To(HaveKeyWithValue(appv1.AnnotationKeyRefresh, string(appv1.RefreshTypeNormal)))
+
+			multiSourceFields, err := GetMultiSourceApplicationFields(ctx, k8sClient, app)
+			Expect(err).To(BeNil())
+			Expect(multiSourceFields.Sources).To(HaveLen(2))
+			Expect(multiSourceFields.Sources[0].Chart).To(Equal("my-chart"))
+			Expect(multiSourceFields.Sources[1].Ref).To(Equal("values"))
+
+			By("verifying that the finalizers are not replaced if the Application was modified since it was retrieved")
+			app.ResourceVersion = "1"
+			err = PatchApplicationFinalizers(ctx, k8sClient, &app, []string{argoCDResourcesFinalizer})
+			Expect(apierr.IsConflict(err)).To(BeTrue())
+		})
+
+		It("should read the compared-to sources and revisions of a multi-source Application", func() {
+			app := appv1.Application{}
+			Expect(yaml.Unmarshal([]byte(dbApplication.Spec_field), &app)).To(Succeed())
+			app.Name = "test-my-application"
+			app.Namespace = "gitops-service-argocd"
+
+			dbSources, err := GetSpecFieldSources(dbApplication.Spec_field)
+			Expect(err).To(BeNil())
+
+			unstructuredApp, err := ConvertToMultiSourceApplication(app, dbSources)
+			Expect(err).To(BeNil())
+
+			sourcesContent, found, err := unstructured.NestedSlice(unstructuredApp.Object, "spec", "sources")
+			Expect(err).To(BeNil())
+			Expect(found).To(BeTrue())
+			Expect(unstructured.SetNestedSlice(unstructuredApp.Object, sourcesContent, "status", "sync", "comparedTo", "sources")).To(Succeed())
+			Expect(unstructured.SetNestedStringSlice(unstructuredApp.Object, []string{"1.2.3", "abcdef"}, "status", "sync", "revisions")).To(Succeed())
+			Expect(k8sClient.Create(ctx, unstructuredApp)).To(Succeed())
+
+			multiSourceFields, err := GetMultiSourceApplicationFields(ctx, k8sClient, app)
+			Expect(err).To(BeNil())
+			Expect(multiSourceFields.ComparedToSources).To(HaveLen(2))
+			Expect(multiSourceFields.ComparedToSources[0].Chart).To(Equal("my-chart"))
+			Expect(multiSourceFields.Revisions).To(Equal([]string{"1.2.3", "abcdef"}))
+		})
 	})
 
 	Context("Testing for CompareApplications function.", func() {
@@ -332,6 +620,49 @@ var _ = Describe("Tests for the small number of utility functions in cluster-age
 			applicationFromArgoCD.Spec.SyncPolicy.Automated.AllowEmpty = applicationFromDB.Spec.SyncPolicy.Automated.AllowEmpty
 		})
 
+		It("Should compare the deletion policy annotation of applications.", func() {
+
+			applicationFromDB, _, applicationFromArgoCD, err := createDummyApplicationData()
+			Expect(err).To(BeNil())
+
+			applicationFromDB.FauxObjectMeta.Annotations = map[string]string{
+				argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation: managedgitopsv1alpha1.GitOpsDeploymentDeletionPolicy_Orphan,
+			}
+			specFieldBytes, err := yaml.Marshal(applicationFromDB)
+			Expect(err).To(BeNil())
+			dbApp := db.Application{Spec_field: string(specFieldBytes)}
+
+			deletionPolicy, err := GetSpecFieldDeletionPolicy(dbApp.Spec_field)
+			Expect(err).To(BeNil())
+			Expect(deletionPolicy).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentDeletionPolicy_Orphan))
+
+			log := log.FromContext(context.Background())
+
+			By("the Argo CD Application does not have the annotation, so it is not in sync")
+			result, err := CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).To(BeNil())
+			Expect(result).ToNot(BeEmpty())
+
+			By("the Argo CD Application has the annotation, so it is in sync")
+			SetDeletionPolicyAnnotation(&applicationFromArgoCD, deletionPolicy)
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+
+			By("the annotation is removed from the DB entry, so it is not in sync")
+			applicationFromDB.FauxObjectMeta.Annotations = nil
+			specFieldBytes, err = yaml.Marshal(applicationFromDB)
+			Expect(err).To(BeNil())
+			dbApp.Spec_field = string(specFieldBytes)
+
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).To(BeNil())
+			Expect(result).ToNot(BeEmpty())
+
+			SetDeletionPolicyAnnotation(&applicationFromArgoCD, "")
+			Expect(applicationFromArgoCD.Annotations).ToNot(HaveKey(argosharedutil.ArgoCDApplicationDeletionPolicyAnnotation))
+		})
+
 		It("Should compare applications if fields are nil.", func() {
 
 			// Convert a FauxApplication into a db.Application, by marshalling the FA back into YAML
@@ -435,6 +766,168 @@ var _ = Describe("Tests for the small number of utility functions in cluster-age
 
 			Expect(result).To(BeEmpty())
 		})
+
+		It("Should detect differences in the Helm fields of the source.", func() {
+
+			appDB, _, appArgo, err := createDummyApplicationData()
+			Expect(err).To(BeNil())
+
+			convert := func(fa fauxargocd.FauxApplication) db.Application {
+				bytes, err := yaml.Marshal(&fa)
+				Expect(err).To(BeNil())
+				return db.Application{Spec_field: string(bytes)}
+			}
+
+			var ctx context.Context
+			log := log.FromContext(ctx)
+
+			appDB.Spec.Source.Path = ""
+			appDB.Spec.Source.Chart = "my-chart"
+			appDB.Spec.Source.Helm = &fauxargocd.ApplicationSourceHelm{
+				ValueFiles:  []string{"values-prod.yaml"},
+				ReleaseName: "my-release",
+				Parameters:  []fauxargocd.HelmParameter{{Name: "image.tag", Value: "v1"}},
+				Values:      "replicaCount: 2\n",
+			}
+
+			appArgo.Spec.Source.Path = ""
+			appArgo.Spec.Source.Chart = "my-chart"
+			appArgo.Spec.Source.Helm = &appv1.ApplicationSourceHelm{
+				ValueFiles:  []string{"values-prod.yaml"},
+				ReleaseName: "my-release",
+				Parameters:  []appv1.HelmParameter{{Name: "image.tag", Value: "v1"}},
+				Values:      "replicaCount: 2\n",
+			}
+
+			By("Helm fields are the same in Argo CD and DB, hence it is in sync.")
+			result, err := CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+
+			By("Helm values are different in Argo CD and DB, hence it is not in sync.")
+			appArgo.Spec.Source.Helm.Values = "replicaCount: 5\n"
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal("spec.source.helm fields differ"))
+			appArgo.Spec.Source.Helm.Values = appDB.Spec.Source.Helm.Values
+
+			By("Helm parameters are different in Argo CD and DB, hence it is not in sync.")
+			appArgo.Spec.Source.Helm.Parameters[0].Value = "v2"
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal("spec.source.helm fields differ"))
+			appArgo.Spec.Source.Helm.Parameters[0].Value = "v1"
+
+			By("Helm field was removed from Argo CD, hence it is not in sync.")
+			appArgo.Spec.Source.Helm = nil
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal("spec.source.helm fields differ"))
+
+			By("An empty Helm field in Argo CD is equivalent to no Helm field in the DB.")
+			appDB.Spec.Source.Helm = nil
+			appArgo.Spec.Source.Helm = &appv1.ApplicationSourceHelm{ValueFiles: []string{}}
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+
+			By("Chart is different in Argo CD and DB, hence it is not in sync.")
+			appArgo.Spec.Source.Chart = "other-chart"
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal("spec.source fields differ"))
+		})
+
+		It("Should detect differences in the Kustomize fields of the source.", func() {
+
+			appDB, _, appArgo, err := createDummyApplicationData()
+			Expect(err).To(BeNil())
+
+			convert := func(fa fauxargocd.FauxApplication) db.Application {
+				bytes, err := yaml.Marshal(&fa)
+				Expect(err).To(BeNil())
+				return db.Application{Spec_field: string(bytes)}
+			}
+
+			var ctx context.Context
+			log := log.FromContext(ctx)
+
+			appDB.Spec.Source.Kustomize = &fauxargocd.ApplicationSourceKustomize{
+				NamePrefix:   "dev-",
+				Images:       []string{"quay.io/org/app:v1"},
+				CommonLabels: map[string]string{"env": "dev"},
+			}
+
+			appArgo.Spec.Source.Kustomize = &appv1.ApplicationSourceKustomize{
+				NamePrefix:   "dev-",
+				Images:       appv1.KustomizeImages{"quay.io/org/app:v1"},
+				CommonLabels: map[string]string{"env": "dev"},
+			}
+
+			By("Kustomize fields are the same in Argo CD and DB, hence it is in sync.")
+			result, err := CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+
+			By("Kustomize images are different in Argo CD and DB, hence it is not in sync.")
+			appArgo.Spec.Source.Kustomize.Images = appv1.KustomizeImages{"quay.io/org/app:v2"}
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal("spec.source.kustomize fields differ"))
+
+			By("An empty Kustomize field in Argo CD is equivalent to no Kustomize field in the DB.")
+			appDB.Spec.Source.Kustomize = nil
+			appArgo.Spec.Source.Kustomize = &appv1.ApplicationSourceKustomize{CommonLabels: map[string]string{}}
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("Should detect differences in the ignoreDifferences fields.", func() {
+
+			appDB, _, appArgo, err := createDummyApplicationData()
+			Expect(err).To(BeNil())
+
+			convert := func(fa fauxargocd.FauxApplication) db.Application {
+				bytes, err := yaml.Marshal(&fa)
+				Expect(err).To(BeNil())
+				return db.Application{Spec_field: string(bytes)}
+			}
+
+			var ctx context.Context
+			log := log.FromContext(ctx)
+
+			appDB.Spec.IgnoreDifferences = []fauxargocd.ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}},
+			}
+
+			appArgo.Spec.IgnoreDifferences = []appv1.ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}, JQPathExpressions: []string{}},
+			}
+
+			By("ignoreDifferences fields are the same in Argo CD and DB, hence it is in sync.")
+			result, err := CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+
+			By("ignoreDifferences fields are different in Argo CD and DB, hence it is not in sync.")
+			appArgo.Spec.IgnoreDifferences[0].ManagedFieldsManagers = []string{"kube-controller-manager"}
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal("spec.ignoreDifferences fields differ"))
+
+			By("ignoreDifferences field is removed from the DB, hence it is not in sync.")
+			appDB.Spec.IgnoreDifferences = nil
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal("spec.ignoreDifferences fields differ"))
+
+			By("An empty ignoreDifferences field in Argo CD is equivalent to no ignoreDifferences field in the DB.")
+			appArgo.Spec.IgnoreDifferences = []appv1.ResourceIgnoreDifferences{}
+			result, err = CompareApplication(appArgo, convert(appDB), log)
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+		})
 	})
 
 })
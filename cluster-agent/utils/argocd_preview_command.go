@@ -0,0 +1,415 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+	"github.com/pmezard/go-difflib/difflib"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// This file is loosely based on the 'argocd app diff --revision' CLI command:
+// https://github.com/argoproj/argo-cd/blob/0a46d37fc6af9fe0aa963bdd845e3d799aa0320d/cmd/argocd/commands/app.go#L882
+
+// AppPreview calls the Argo CD GRPC API to render the resources of an Argo CD Application at the given revision, and
+// compares them with the live resources of the Application on the target cluster. The revision that the given
+// revision resolved to is returned, along with the resources that would be added, changed, or removed by deploying it,
+// and whether those resources were truncated (see truncatePreviewResourceDiffs).
+func AppPreview(ctx context.Context, appName string, revision string, argocdNamespace corev1.Namespace,
+	credentialService *CredentialService, k8sClient client.Client) (string, []managedgitopsv1alpha1.PreviewResourceDiff, bool, error) {
+
+	_, acdClient, err := credentialService.GetArgoCDLoginCredentials(ctx, argocdNamespace.Name,
+		string(argocdNamespace.UID), false, k8sClient)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	return appPreview(ctx, appName, revision, acdClient)
+}
+
+func appPreview(ctx context.Context, appName string, revision string, acdClient argocdclient.Client) (string, []managedgitopsv1alpha1.PreviewResourceDiff, bool, error) {
+
+	conn, appIf, err := acdClient.NewApplicationClient()
+	if err != nil {
+		return "", nil, false, fmt.Errorf("unable to create application client for preview: %v", err)
+	}
+	defer argoio.Close(conn)
+
+	manifests, err := appIf.GetManifests(ctx, &applicationpkg.ApplicationManifestQuery{Name: &appName, Revision: &revision})
+	if err != nil {
+		return "", nil, false, fmt.Errorf("unable to generate the manifests of application '%s' at revision '%s': %v", appName, revision, err)
+	}
+
+	managedResources, err := appIf.ManagedResources(ctx, &applicationpkg.ResourcesQuery{ApplicationName: &appName})
+	if err != nil {
+		return "", nil, false, fmt.Errorf("unable to retrieve the managed resources of application '%s': %v", appName, err)
+	}
+
+	resourceDiffs, err := computePreviewResourceDiffs(manifests.Manifests, managedResources.Items)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	resourceDiffs, truncated := truncatePreviewResourceDiffs(resourceDiffs, managedgitopsv1alpha1.MaxPreviewResources,
+		managedgitopsv1alpha1.MaxPreviewTotalDiffLength)
+
+	return manifests.Revision, resourceDiffs, truncated, nil
+}
+
+// previewResource is a resource that is defined at the previewed revision, or that exists on the target cluster
+type previewResource struct {
+	group     string
+	kind      string
+	namespace string
+	name      string
+
+	// object is the JSON object of the resource, or nil if it doesn't exist
+	object map[string]interface{}
+}
+
+func (r previewResource) key() string {
+	return r.group + "/" + r.kind + "/" + r.namespace + "/" + r.name
+}
+
+// computePreviewResourceDiffs compares the resources defined at the previewed revision (the target manifests) with the
+// live resources that are managed by the Argo CD Application, and returns the resources that would be added, changed,
+// or removed. Hooks are ignored, as they are not resources that are kept in sync with the target cluster.
+func computePreviewResourceDiffs(targetManifests []string, managedResources []*argoappv1.ResourceDiff) ([]managedgitopsv1alpha1.PreviewResourceDiff, error) {
+
+	// 1) Index the live resources, by key
+	liveResources := map[string]previewResource{}
+	for _, managedResource := range managedResources {
+		if managedResource == nil || managedResource.Hook {
+			continue
+		}
+
+		liveState := managedResource.NormalizedLiveState
+		if liveState == "" {
+			liveState = managedResource.LiveState
+		}
+
+		liveObject, err := unmarshalPreviewObject(liveState)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal the live state of '%s': %v", managedResource.FullName(), err)
+		}
+
+		resource := previewResource{
+			group:     managedResource.Group,
+			kind:      managedResource.Kind,
+			namespace: managedResource.Namespace,
+			name:      managedResource.Name,
+			object:    liveObject,
+		}
+		liveResources[resource.key()] = resource
+	}
+
+	var res []managedgitopsv1alpha1.PreviewResourceDiff
+
+	// 2) Compare each target resource with the corresponding live resource, if any
+	matchedLiveResources := map[string]bool{}
+	for _, manifest := range targetManifests {
+
+		targetObject, err := unmarshalPreviewObject(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal the manifest of a resource: %v", err)
+		}
+		if targetObject == nil || isPreviewHook(targetObject) {
+			continue
+		}
+
+		target := newPreviewResourceFromObject(targetObject)
+
+		live, exists := liveResources[target.key()]
+		if !exists && target.namespace == "" {
+			// The namespace of namespaced resources may be omitted from the manifest, in which case the resource is
+			// deployed to the destination namespace: so look for a live resource with any namespace.
+			live, exists = findPreviewResourceIgnoringNamespace(liveResources, target)
+		}
+
+		if exists {
+			matchedLiveResources[live.key()] = true
+		}
+
+		if !exists || live.object == nil {
+			resourceDiff, err := generatePreviewResourceDiff(target, managedgitopsv1alpha1.PreviewResourceChange_Added, nil, targetObject)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, resourceDiff)
+			continue
+		}
+
+		// Only compare the fields that are defined in the target resource: other fields are set by the cluster (or
+		// by other controllers), and are not modified by a sync.
+		prunedLiveObject, _ := pruneToTargetFields(live.object, targetObject).(map[string]interface{})
+
+		resourceDiff, err := generatePreviewResourceDiff(live, managedgitopsv1alpha1.PreviewResourceChange_Changed, prunedLiveObject, targetObject)
+		if err != nil {
+			return nil, err
+		}
+		if resourceDiff.Diff != "" {
+			res = append(res, resourceDiff)
+		}
+	}
+
+	// 3) Any live resources that are not defined at the previewed revision would be removed
+	for key, live := range liveResources {
+		if matchedLiveResources[key] || live.object == nil {
+			continue
+		}
+
+		resourceDiff, err := generatePreviewResourceDiff(live, managedgitopsv1alpha1.PreviewResourceChange_Removed, withoutClusterFields(live.object), nil)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, resourceDiff)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Group != res[j].Group {
+			return res[i].Group < res[j].Group
+		}
+		if res[i].Kind != res[j].Kind {
+			return res[i].Kind < res[j].Kind
+		}
+		if res[i].Namespace != res[j].Namespace {
+			return res[i].Namespace < res[j].Namespace
+		}
+		return res[i].Name < res[j].Name
+	})
+
+	return res, nil
+}
+
+// unmarshalPreviewObject unmarshals the JSON of a resource, returning nil if the resource doesn't exist
+func unmarshalPreviewObject(jsonStr string) (map[string]interface{}, error) {
+	if jsonStr == "" || jsonStr == "null" {
+		return nil, nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func newPreviewResourceFromObject(obj map[string]interface{}) previewResource {
+
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+
+	var group string
+	if gv, err := schema.ParseGroupVersion(apiVersion); err == nil {
+		group = gv.Group
+	}
+
+	var namespace, name string
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		namespace, _ = metadata["namespace"].(string)
+		name, _ = metadata["name"].(string)
+	}
+
+	return previewResource{group: group, kind: kind, namespace: namespace, name: name, object: obj}
+}
+
+func findPreviewResourceIgnoringNamespace(liveResources map[string]previewResource, target previewResource) (previewResource, bool) {
+	for _, live := range liveResources {
+		if live.group == target.group && live.kind == target.kind && live.name == target.name {
+			return live, true
+		}
+	}
+	return previewResource{}, false
+}
+
+// isPreviewHook returns true if the resource is an Argo CD (or Helm) hook
+func isPreviewHook(obj map[string]interface{}) bool {
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	_, argoCDHook := annotations["argocd.argoproj.io/hook"]
+	_, helmHook := annotations["helm.sh/hook"]
+
+	return argoCDHook || helmHook
+}
+
+// pruneToTargetFields returns the live value, without any of the fields that are not defined in the target value.
+// Lists are only pruned if they have the same number of elements: otherwise, the entire live list is returned.
+func pruneToTargetFields(live interface{}, target interface{}) interface{} {
+
+	switch targetValue := target.(type) {
+
+	case map[string]interface{}:
+		liveMap, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+
+		res := map[string]interface{}{}
+		for key, targetFieldValue := range targetValue {
+			if liveFieldValue, exists := liveMap[key]; exists {
+				res[key] = pruneToTargetFields(liveFieldValue, targetFieldValue)
+			}
+		}
+		return res
+
+	case []interface{}:
+		liveList, ok := live.([]interface{})
+		if !ok || len(liveList) != len(targetValue) {
+			return live
+		}
+
+		res := make([]interface{}, len(liveList))
+		for i := range liveList {
+			res[i] = pruneToTargetFields(liveList[i], targetValue[i])
+		}
+		return res
+
+	default:
+		return live
+	}
+}
+
+// withoutClusterFields returns a copy of the live resource, without the status and the metadata fields that are set
+// by the cluster.
+func withoutClusterFields(obj map[string]interface{}) map[string]interface{} {
+
+	res := map[string]interface{}{}
+	for key, value := range obj {
+		if key != "status" {
+			res[key] = value
+		}
+	}
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		resMetadata := map[string]interface{}{}
+		for key, value := range metadata {
+			switch key {
+			case "managedFields", "resourceVersion", "uid", "creationTimestamp", "generation", "selfLink":
+			default:
+				resMetadata[key] = value
+			}
+		}
+		res["metadata"] = resMetadata
+	}
+
+	return res
+}
+
+// generatePreviewResourceDiff returns the unified diff between the YAML of the live resource and of the target
+// resource. Either may be nil, if the resource is added or removed. The diff is empty if the resources are identical.
+func generatePreviewResourceDiff(resource previewResource, change managedgitopsv1alpha1.PreviewResourceChange,
+	liveObject map[string]interface{}, targetObject map[string]interface{}) (managedgitopsv1alpha1.PreviewResourceDiff, error) {
+
+	res := managedgitopsv1alpha1.PreviewResourceDiff{
+		Group:     resource.group,
+		Kind:      resource.kind,
+		Namespace: resource.namespace,
+		Name:      resource.name,
+		Change:    change,
+	}
+
+	toYAML := func(obj map[string]interface{}) (string, error) {
+		if obj == nil {
+			return "", nil
+		}
+		yamlBytes, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal resource '%s' to YAML: %v", resource.key(), err)
+		}
+		return string(yamlBytes), nil
+	}
+
+	liveYAML, err := toYAML(liveObject)
+	if err != nil {
+		return res, err
+	}
+
+	targetYAML, err := toYAML(targetObject)
+	if err != nil {
+		return res, err
+	}
+
+	if liveYAML == targetYAML {
+		return res, nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(liveYAML),
+		B:        difflib.SplitLines(targetYAML),
+		FromFile: "live",
+		ToFile:   "target",
+		Context:  3,
+	})
+	if err != nil {
+		return res, fmt.Errorf("unable to generate the diff of resource '%s': %v", resource.key(), err)
+	}
+
+	res.Diff, res.Truncated = truncatePreviewDiff(diff, managedgitopsv1alpha1.MaxPreviewResourceDiffLength)
+
+	return res, nil
+}
+
+// truncatePreviewResourceDiffs limits the size of the result of a preview, which is stored in the database, and in the
+// status of the GitOpsDeploymentPreview: only the first maxResources resources are kept, and, once the total length of
+// their diffs reaches maxTotalDiffLength, the diffs of the remaining resources are omitted (and those resources are
+// marked as truncated). Returns true if any resources, or diffs, were omitted.
+func truncatePreviewResourceDiffs(resourceDiffs []managedgitopsv1alpha1.PreviewResourceDiff, maxResources int,
+	maxTotalDiffLength int) ([]managedgitopsv1alpha1.PreviewResourceDiff, bool) {
+
+	truncated := false
+
+	if len(resourceDiffs) > maxResources {
+		resourceDiffs = resourceDiffs[:maxResources]
+		truncated = true
+	}
+
+	totalDiffLength := 0
+	for idx := range resourceDiffs {
+		resourceDiff := &resourceDiffs[idx]
+
+		if totalDiffLength+len(resourceDiff.Diff) > maxTotalDiffLength {
+			resourceDiff.Diff = ""
+			resourceDiff.Truncated = true
+			truncated = true
+			continue
+		}
+
+		totalDiffLength += len(resourceDiff.Diff)
+	}
+
+	return resourceDiffs, truncated
+}
+
+// truncatePreviewDiff truncates the diff to at most maxLength characters, at a line boundary
+func truncatePreviewDiff(diff string, maxLength int) (string, bool) {
+	if len(diff) <= maxLength {
+		return diff, false
+	}
+
+	truncated := diff[:maxLength]
+	if lastNewline := strings.LastIndex(truncated, "\n"); lastNewline >= 0 {
+		truncated = truncated[:lastNewline+1]
+	}
+
+	return truncated, true
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/cluster-agent/utils/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("Preview of an Argo CD Application at an alternate revision", func() {
+
+	const (
+		targetConfigMap = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config-map","namespace":"my-namespace"},"data":{"key":"new-value"}}`
+		liveConfigMap   = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config-map","namespace":"my-namespace","uid":"abc","resourceVersion":"1","labels":{"app.kubernetes.io/instance":"my-app"}},"data":{"key":"old-value"}}`
+
+		unchangedService = `{"apiVersion":"v1","kind":"Service","metadata":{"name":"my-service"},"spec":{"ports":[{"port":80}]}}`
+		liveService      = `{"apiVersion":"v1","kind":"Service","metadata":{"name":"my-service","namespace":"my-namespace","uid":"def"},"spec":{"clusterIP":"10.0.0.1","ports":[{"port":80,"protocol":"TCP"}]},"status":{}}`
+
+		addedDeployment = `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"my-deployment","namespace":"my-namespace"},"spec":{"replicas":1}}`
+
+		liveSecret = `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"my-secret","namespace":"my-namespace","uid":"ghi","managedFields":[]},"type":"Opaque"}`
+
+		hookJob = `{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"my-hook","namespace":"my-namespace","annotations":{"argocd.argoproj.io/hook":"PreSync"}}}`
+	)
+
+	managedResources := []*argoappv1.ResourceDiff{
+		{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config-map", LiveState: liveConfigMap},
+		{Kind: "Service", Namespace: "my-namespace", Name: "my-service", LiveState: liveService},
+		{Kind: "Secret", Namespace: "my-namespace", Name: "my-secret", LiveState: liveSecret},
+		{Group: "apps", Kind: "Deployment", Namespace: "my-namespace", Name: "my-deployment", LiveState: "null"},
+	}
+
+	It("should return the resources that would be added, changed, or removed", func() {
+
+		res, err := computePreviewResourceDiffs([]string{targetConfigMap, unchangedService, addedDeployment, hookJob}, managedResources)
+		Expect(err).To(BeNil())
+		Expect(res).To(HaveLen(3))
+
+		By("verifying the resources are sorted, and unchanged resources and hooks are not included")
+		Expect(res[0].Kind).To(Equal("ConfigMap"))
+		Expect(res[1].Kind).To(Equal("Secret"))
+		Expect(res[2].Kind).To(Equal("Deployment"))
+		Expect(res[2].Group).To(Equal("apps"))
+
+		By("verifying the changed resource only includes the fields that are defined in the target resource")
+		Expect(res[0].Change).To(Equal(managedgitopsv1alpha1.PreviewResourceChange_Changed))
+		Expect(res[0].Diff).To(ContainSubstring("-  key: old-value\n+  key: new-value\n"))
+		Expect(res[0].Diff).To(ContainSubstring("--- live\n+++ target\n"))
+		Expect(res[0].Diff).ToNot(ContainSubstring("resourceVersion"))
+		Expect(res[0].Diff).ToNot(ContainSubstring("app.kubernetes.io/instance"))
+
+		By("verifying the removed resource does not include the fields that are set by the cluster")
+		Expect(res[1].Change).To(Equal(managedgitopsv1alpha1.PreviewResourceChange_Removed))
+		Expect(res[1].Diff).To(ContainSubstring("-type: Opaque\n"))
+		Expect(res[1].Diff).ToNot(ContainSubstring("managedFields"))
+		Expect(res[1].Diff).ToNot(ContainSubstring("uid"))
+
+		By("verifying the added resource is diffed against an empty resource")
+		Expect(res[2].Change).To(Equal(managedgitopsv1alpha1.PreviewResourceChange_Added))
+		Expect(res[2].Diff).To(ContainSubstring("+  replicas: 1\n"))
+	})
+
+	It("should truncate diffs that are longer than the maximum length, at a line boundary", func() {
+
+		diff := strings.Repeat("+line\n", 10)
+
+		truncated, isTruncated := truncatePreviewDiff(diff, 15)
+		Expect(isTruncated).To(BeTrue())
+		Expect(truncated).To(Equal("+line\n+line\n"))
+
+		notTruncated, isTruncated := truncatePreviewDiff(diff, len(diff))
+		Expect(isTruncated).To(BeFalse())
+		Expect(notTruncated).To(Equal(diff))
+	})
+
+	It("should limit the number of resources, and the total length of their diffs", func() {
+		newResourceDiffs := func() []managedgitopsv1alpha1.PreviewResourceDiff {
+			var res []managedgitopsv1alpha1.PreviewResourceDiff
+			for _, name := range []string{"a", "b", "c", "d"} {
+				res = append(res, managedgitopsv1alpha1.PreviewResourceDiff{
+					Kind:   "ConfigMap",
+					Name:   name,
+					Change: managedgitopsv1alpha1.PreviewResourceChange_Changed,
+					Diff:   "-old\n+new\n",
+				})
+			}
+			return res
+		}
+
+		By("not truncating resources that are within the limits")
+		res, truncated := truncatePreviewResourceDiffs(newResourceDiffs(), 4, 4*len("-old\n+new\n"))
+		Expect(truncated).To(BeFalse())
+		Expect(res).To(Equal(newResourceDiffs()))
+
+		By("omitting the resources beyond the maximum number of resources")
+		res, truncated = truncatePreviewResourceDiffs(newResourceDiffs(), 3, 1024)
+		Expect(truncated).To(BeTrue())
+		Expect(res).To(HaveLen(3))
+		Expect(res[2].Name).To(Equal("c"))
+		Expect(res[2].Truncated).To(BeFalse())
+
+		By("omitting the diffs of the resources beyond the maximum total length of the diffs")
+		res, truncated = truncatePreviewResourceDiffs(newResourceDiffs(), 4, 2*len("-old\n+new\n")+1)
+		Expect(truncated).To(BeTrue())
+		Expect(res).To(HaveLen(4))
+		Expect(res[1].Diff).To(Equal("-old\n+new\n"))
+		Expect(res[1].Truncated).To(BeFalse())
+		for _, resourceDiff := range res[2:] {
+			Expect(resourceDiff.Diff).To(BeEmpty())
+			Expect(resourceDiff.Truncated).To(BeTrue())
+		}
+	})
+
+	It("should render the manifests at the given revision, using the Argo CD API", func() {
+
+		appName := "my-app"
+		revision := "my-branch"
+
+		mockAppServiceClient := &mocks.ApplicationServiceClient{}
+		mockAppClient := &mocks.Client{}
+
+		mockAppClient.On("NewApplicationClient").Return(mockCloser{}, mockAppServiceClient, nil)
+		mockAppServiceClient.On("GetManifests", mock.Anything, &application.ApplicationManifestQuery{Name: &appName, Revision: &revision}).
+			Return(&apiclient.ManifestResponse{Manifests: []string{targetConfigMap}, Revision: "abc123"}, nil)
+		mockAppServiceClient.On("ManagedResources", mock.Anything, &application.ResourcesQuery{ApplicationName: &appName}).
+			Return(&application.ManagedResourcesResponse{Items: managedResources[0:1]}, nil)
+
+		resolvedRevision, res, truncated, err := appPreview(context.Background(), appName, revision, mockAppClient)
+		Expect(err).To(BeNil())
+		Expect(resolvedRevision).To(Equal("abc123"))
+		Expect(truncated).To(BeFalse())
+		Expect(res).To(HaveLen(1))
+		Expect(res[0].Name).To(Equal("my-config-map"))
+		Expect(res[0].Change).To(Equal(managedgitopsv1alpha1.PreviewResourceChange_Changed))
+	})
+})
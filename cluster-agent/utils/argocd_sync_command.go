@@ -15,6 +15,7 @@ import (
 	"github.com/argoproj/gitops-engine/pkg/health"
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -26,8 +27,10 @@ import (
 // https://github.com/argoproj/argo-cd/blob/0a46d37fc6af9fe0aa963bdd845e3d799aa0320d/cmd/argocd/commands/app.go#L1333
 
 // AppSync will trigger a synchronize application on the given Argo CD appliatication, in the given namespace.
+// - infos are added to the sync operation, and are thus available from the Application's .status.operationState.operation.info field.
+// - options are the (optional) prune, dryRun, force, resources, syncOptions and strategy options of the sync.
 func AppSync(ctx context.Context, appName string, revision string, namespaceName string, k8sClient client.Client,
-	credentialsService *CredentialService, skipTLSTest bool) error {
+	credentialsService *CredentialService, skipTLSTest bool, infos []*argoappv1.Info, options fauxargocd.FauxSyncOperationOptions) error {
 
 	namespace := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -45,7 +48,18 @@ func AppSync(ctx context.Context, appName string, revision string, namespaceName
 		return err
 	}
 
-	err = appSync(ctx, acdClient, appName, false, false, revision, false, "", false, false, 0, 0, 0, 0, 0)
+	var resources []argoappv1.SyncOperationResource
+	for _, resource := range options.Resources {
+		resources = append(resources, argoappv1.SyncOperationResource{
+			Group:     resource.Group,
+			Kind:      resource.Kind,
+			Name:      resource.Name,
+			Namespace: resource.Namespace,
+		})
+	}
+
+	err = appSync(ctx, acdClient, appName, options.DryRun, false, revision, options.Prune, options.Strategy, options.Force, false, 0, 0, 0, 0, 0,
+		infos, resources, options.SyncOptions)
 	if err != nil {
 		return err
 	}
@@ -54,9 +68,70 @@ func AppSync(ctx context.Context, appName string, revision string, namespaceName
 
 }
 
+// AppRollback will trigger a rollback of the given Argo CD Application, in the given namespace, to the entry of the
+// Application's deployment history (.status.history) with the given ID.
+// - Argo CD does not allow rolling back an Application that has an automated sync policy.
+// - if prune is true, resources that are not part of the deployment history entry are deleted by the rollback.
+func AppRollback(ctx context.Context, appName string, historyID int64, prune bool, namespaceName string, k8sClient client.Client,
+	credentialsService *CredentialService, skipTLSTest bool) error {
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespaceName,
+		},
+	}
+
+	err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve namespace in AppRollback: %s, %v", namespaceName, err)
+	}
+
+	_, acdClient, err := credentialsService.GetArgoCDLoginCredentials(ctx, namespaceName, string(namespace.UID), false, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	return appRollback(ctx, acdClient, appName, historyID, prune, 0)
+}
+
+// appRollback is loosely based on the 'argocd app rollback' CLI command.
+func appRollback(ctx context.Context, acdClient argocdclient.Client, appName string, historyID int64, prune bool, timeout uint) error {
+
+	conn, appIf, err := acdClient.NewApplicationClient()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve acd client: %v", err)
+	}
+	defer argoio.Close(conn)
+
+	_, err = appIf.Rollback(ctx, &applicationpkg.ApplicationRollbackRequest{
+		Name:  &appName,
+		Id:    &historyID,
+		Prune: &prune,
+	})
+	if err != nil {
+		return err
+	}
+
+	app, err := waitOnApplicationStatus(ctx, acdClient, appName, timeout, false, false, true, false, []argoappv1.SyncOperationResource{})
+	if err != nil {
+		return err
+	}
+
+	operationState := app.Status.OperationState
+	if operationState == nil {
+		return fmt.Errorf("operation state of Application '%s' was not available after rollback", appName)
+	}
+	if !operationState.Phase.Successful() {
+		return fmt.Errorf("operation has completed with phase: %s and message: %s", operationState.Phase, operationState.Message)
+	}
+
+	return nil
+}
+
 func appSync(ctx context.Context, acdClient argocdclient.Client, appName string, dryRun bool, replace bool, revision string, prune bool,
 	strategy string, force bool, async bool, timeout uint, retryLimit int64, retryBackoffDuration time.Duration,
-	retryBackoffMaxDuration time.Duration, retryBackoffFactor int64) error {
+	retryBackoffMaxDuration time.Duration, retryBackoffFactor int64, infos []*argoappv1.Info,
+	selectedResources []argoappv1.SyncOperationResource, additionalSyncOptions []string) error {
 
 	conn, appIf, err := acdClient.NewApplicationClient()
 	if err != nil {
@@ -70,6 +145,7 @@ func appSync(ctx context.Context, acdClient argocdclient.Client, appName string,
 		if replace {
 			items = append(items, common.SyncOptionReplace)
 		}
+		items = append(items, additionalSyncOptions...)
 
 		if len(items) == 0 {
 			// for prevent send even empty array if not need
@@ -79,14 +155,19 @@ func appSync(ctx context.Context, acdClient argocdclient.Client, appName string,
 		return &syncOptions
 	}
 
+	var syncResources []*argoappv1.SyncOperationResource
+	for i := range selectedResources {
+		syncResources = append(syncResources, &selectedResources[i])
+	}
+
 	syncReq := applicationpkg.ApplicationSyncRequest{
 		Name:        &appName,
 		DryRun:      &dryRun,
 		Revision:    &revision,
-		Resources:   nil,
+		Resources:   syncResources,
 		Prune:       &prune,
 		Manifests:   nil,
-		Infos:       []*argoappv1.Info{},
+		Infos:       infos,
 		SyncOptions: syncOptionsFactory(),
 	}
 
@@ -116,7 +197,7 @@ func appSync(ctx context.Context, acdClient argocdclient.Client, appName string,
 	}
 
 	if !async {
-		app, err := waitOnApplicationStatus(ctx, acdClient, appName, timeout, false, false, true, false, []argoappv1.SyncOperationResource{})
+		app, err := waitOnApplicationStatus(ctx, acdClient, appName, timeout, false, false, true, false, selectedResources)
 		if err != nil {
 			return err
 		}
@@ -125,7 +206,7 @@ func appSync(ctx context.Context, acdClient argocdclient.Client, appName string,
 			operationState := app.Status.OperationState
 			if !operationState.Phase.Successful() {
 				return fmt.Errorf("operation has completed with phase: %s and message: %s", operationState.Phase, operationState.Message)
-			} else if /*len(selectedResources) == 0 &&*/ app.Status.Sync.Status != argoappv1.SyncStatusCodeSynced {
+			} else if len(selectedResources) == 0 && app.Status.Sync.Status != argoappv1.SyncStatusCodeSynced {
 				// Only get resources to be pruned if sync was application-wide and final status is not synced
 				pruningRequired := operationState.SyncResult.Resources.PruningRequired()
 				if pruningRequired > 0 {
@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	routev1 "github.com/openshift/api/route/v1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/utils/mocks"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
@@ -124,7 +125,7 @@ var _ = Describe("ArgoCD AppSync Command", func() {
 			}
 
 			cs := NewCredentialService(&clientGenerator, true)
-			err = AppSync(context.Background(), appName, "master", "openshift-gitops", k8sClient, cs, true)
+			err = AppSync(context.Background(), appName, "master", "openshift-gitops", k8sClient, cs, true, nil, fauxargocd.FauxSyncOperationOptions{})
 			Expect(err).To(BeNil())
 		})
 	})
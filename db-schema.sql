@@ -36,6 +36,15 @@ CREATE TABLE ClusterCredentials (
 	-- State 2) The namespace of the ServiceAccount
 	serviceaccount_ns VARCHAR (128),
 
+	-- State 2) As an alternative to a ServiceAccount bearer token: the PEM-encoded client certificate and key of the
+	-- user in the kubeconfig context
+	client_certificate_data VARCHAR (16384),
+	client_key_data VARCHAR (16384),
+
+	-- The PEM-encoded certificate authorities of the cluster, from the kubeconfig context. If NULL, the system
+	-- certificate authorities are used.
+	certificate_authority_data VARCHAR (65000),
+
 	seq_id serial,
 
 	allowinsecure_skiptlsverify BOOLEAN DEFAULT FALSE,
@@ -483,6 +492,14 @@ CREATE TABLE SyncOperation (
 	-- values: Running, Terminated
 	desired_state VARCHAR(16) NOT NULL,	
 
+	-- If the SyncOperation is a rollback, the ID of the entry of the Argo CD Application's deployment history
+	-- (.status.history[].id) to roll back to. NULL if the SyncOperation is a regular sync.
+	rollback_history_id BIGINT,
+
+	-- The sync options of the GitOpsDeploymentSyncRun CR (prune, dryRun, force, resources, syncOptions and strategy),
+	-- as JSON. Empty/NULL if no options were specified.
+	options VARCHAR (4096),
+
 	seq_id serial,
 
 	-- When SyncOperation was created, which allow us to tell how old the resources are
@@ -581,6 +598,123 @@ CREATE TABLE AppProjectManagedEnvironment (
 -- Add an index on clusteruser_id
 CREATE INDEX idx_userid_cluster_me ON AppProjectManagedEnvironment(clusteruser_id);
 
+-- DeploymentHistory is a record of a successful sync of an Application, by Argo CD.
+-- A bounded number of entries are kept per Application: older entries are removed as new entries are added.
+CREATE TABLE DeploymentHistory (
+
+	-- Primary key for the DeploymentHistory (UID), is a random UUID
+	deploymenthistory_id VARCHAR(48) NOT NULL PRIMARY KEY,
+
+	-- The Application that was synchronized
+	-- Foreign key to: Application.application_id
+	-- - The DeploymentHistory rows of an Application are deleted with the Application.
+	application_id VARCHAR(48) NOT NULL,
+	CONSTRAINT fk_dh_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE CASCADE ON UPDATE NO ACTION,
+
+	-- The revision that was deployed, from Argo CD Application CR's .status.operationState.syncResult.revision field
+	-- - For multi-source Applications, this is a comma-separated list of the revision of each source.
+	revision VARCHAR(1024) NOT NULL,
+
+	-- source is a JSON string, which contains the source (or sources, for multi-source Applications) that was deployed
+	source VARCHAR(4096) NOT NULL,
+
+	-- When the sync operation finished, from Argo CD Application CR's .status.operationState.finishedAt field
+	deployed_at TIMESTAMP NOT NULL,
+
+	-- Who initiated the sync: either 'automated', or the name of the user that requested the sync
+	initiated_by VARCHAR(256),
+
+	-- The name of the GitOpsDeploymentSyncRun CR that requested the sync, if any
+	sync_run_name VARCHAR(256),
+
+	-- The ID of the corresponding entry of the Argo CD Application's deployment history (.status.history[].id), if known
+	history_id BIGINT,
+
+	seq_id serial,
+
+	-- When DeploymentHistory was created, which allow us to tell how old the resources are
+	created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Add an index on application_id
+CREATE INDEX idx_deploymenthistory_application_id ON DeploymentHistory(application_id);
+
+-- DeploymentDependencyGate records whether a GitOpsDeployment that depends on other GitOpsDeployments (via its
+-- .spec.dependsOn field) is still waiting for those dependencies to be synced and healthy, before its Application is
+-- created. Once the dependencies have been satisfied, the gate remains open, even if a dependency later becomes unhealthy.
+CREATE TABLE DeploymentDependencyGate (
+
+	-- uid of our gitops deployment CR within the K8s namespace
+	deploymentdependencygate_uid_id VARCHAR(48) UNIQUE NOT NULL PRIMARY KEY,
+
+	-- name of the GitOpsDeployment CR in the API namespace
+	name VARCHAR ( 256 ) NOT NULL,
+	-- name of the API namespace
+	namespace VARCHAR ( 96 ) NOT NULL,
+	-- uid of the API namespace
+	namespace_uid VARCHAR ( 48 ) NOT NULL,
+
+	-- Whether the dependencies of the GitOpsDeployment have been satisfied: 'Waiting' or 'Satisfied'
+	state VARCHAR ( 32 ) NOT NULL,
+
+	-- Comma-separated list of the names of the dependencies that are not yet synced and healthy, if the state is 'Waiting'
+	pending_dependencies VARCHAR ( 4096 ),
+
+	-- SHA-256 hash (hex-encoded) of the names in the .spec.dependsOn field of the GitOpsDeployment, when the gate was last
+	-- evaluated: if the dependencies are modified, the gate is evaluated again, even if it was previously 'Satisfied'
+	dependencies_hash VARCHAR ( 64 ),
+
+	seq_id serial,
+
+	-- When DeploymentDependencyGate was created, which allow us to tell how old the resources are
+	created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_deploymentdependencygate_1 ON DeploymentDependencyGate(namespace_uid);
+CREATE INDEX idx_deploymentdependencygate_2 ON DeploymentDependencyGate(name, namespace, namespace_uid);
+
+-- Preview is a request, from the backend to the cluster-agent, to compute the difference between the live resources
+-- of an Application, and the resources at an alternate revision (for example, of a pull request).
+-- A Preview row only exists for as long as the backend is waiting for the result.
+CREATE TABLE Preview (
+
+	-- Primary key for the Preview (UID), is a random UUID
+	preview_id VARCHAR(48) NOT NULL PRIMARY KEY,
+
+	-- The Application whose live resources are compared
+	-- Foreign key to: Application.application_id
+	-- - The Preview rows of an Application are deleted with the Application.
+	application_id VARCHAR(48) NOT NULL,
+	CONSTRAINT fk_pv_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE CASCADE ON UPDATE NO ACTION,
+
+	-- The alternate revision to compare the live resources with, from the GitOpsDeploymentPreview CR's .spec.revision field
+	revision VARCHAR(1024) NOT NULL,
+
+	-- Possible values:
+	-- * Waiting: the cluster-agent has not yet computed the preview
+	-- * Completed: the preview was computed, and is available in the 'resources' field
+	-- * Failed: the preview could not be computed: see the 'message' field for details
+	state VARCHAR(30) NOT NULL,
+
+	-- The revision that 'revision' resolved to (for example, the git commit SHA of a branch), once the Preview is Completed
+	resolved_revision VARCHAR(1024),
+
+	-- If the Preview Failed, the reason it failed
+	message VARCHAR(4096),
+
+	-- Compressed JSON, containing the resources that would be added, changed, or removed by deploying the revision
+	resources bytea,
+
+	-- True if not all resources, or not all of their diffs, are included in 'resources', as the preview was too large
+	resources_truncated BOOLEAN DEFAULT FALSE,
+
+	seq_id serial,
+
+	-- When Preview was created, which allow us to tell how old the resources are
+	created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Add an index on application_id
+CREATE INDEX idx_preview_application_id ON Preview(application_id);
+
 -- ApplicationOwner indicates which Applications are owned by which user(s)
 CREATE TABLE ApplicationOwner (
 
@@ -643,6 +777,10 @@ SyncOperation -> Application
 
 ApplicationState ->  Application
 
+DeploymentHistory -> Application
+
+Preview -> Application
+
 DeploymentToApplicationMapping -> Application
 
 Operation -> ClusterUser
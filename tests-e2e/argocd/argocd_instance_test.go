@@ -8,6 +8,7 @@ import (
 	"github.com/argoproj/gitops-engine/pkg/health"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	argocdv1 "github.com/redhat-appstudio/managed-gitops/cluster-agent/utils"
 	"github.com/redhat-appstudio/managed-gitops/tests-e2e/fixture"
 	appFixture "github.com/redhat-appstudio/managed-gitops/tests-e2e/fixture/application"
@@ -114,7 +115,7 @@ var _ = Describe("Standalone ArgoCD instance E2E tests", func() {
 			By("calling AppSync and waiting for it to return with no error")
 			Eventually(func() bool {
 				GinkgoWriter.Println("Attempting to sync application: ", app.Name)
-				err := argocdv1.AppSync(context.Background(), app.Name, "", app.Namespace, k8sClient, cs, true)
+				err := argocdv1.AppSync(context.Background(), app.Name, "", app.Namespace, k8sClient, cs, true, nil, fauxargocd.FauxSyncOperationOptions{})
 				GinkgoWriter.Println("- AppSync result: ", err)
 				return err == nil
 			}).WithTimeout(time.Minute * 4).WithPolling(time.Second * 1).Should(BeTrue())
@@ -1,6 +1,8 @@
 package addtestvalues
 
 import (
+	"time"
+
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 )
 
@@ -123,6 +125,7 @@ var (
 		Revision:            "master",
 		DeploymentNameField: AddTest_PreDTAM.DeploymentName,
 		DesiredState:        "Synced",
+		Options:             `{"prune":true}`,
 	}
 
 	AddTest_PreATDMForSyncOperation = db.APICRToDatabaseMapping{
@@ -152,4 +155,32 @@ var (
 		ApplicationOwnerApplicationID: AddTest_PreApplicationDB.Application_id,
 		ApplicationOwnerUserID:        AddTest_PreClusterUser.Clusteruser_id,
 	}
+
+	AddTest_PreDeploymentHistory = db.DeploymentHistory{
+		Deploymenthistory_id: "test-deployment-history-1",
+		Application_id:       AddTest_PreApplicationDB.Application_id,
+		Revision:             "revision",
+		Source:               "{}",
+		Deployed_at:          time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Initiated_by:         db.DeploymentHistory_InitiatedBy_Automated,
+		History_id:           &addTestHistoryID,
+	}
+
+	AddTest_PreDeploymentDependencyGate = db.DeploymentDependencyGate{
+		Deploymentdependencygate_uid_id: "test-deployment-dependency-gate-1",
+		Name:                            "test-gitopsdepl",
+		Namespace:                       "test-namespace",
+		Namespace_uid:                   "test-namespace-uid",
+		State:                           db.DeploymentDependencyGateState_Waiting,
+		Pending_dependencies:            "test-dependency",
+	}
+
+	AddTest_PrePreview = db.Preview{
+		Preview_id:     "test-preview-1",
+		Application_id: AddTest_PreApplicationDB.Application_id,
+		Revision:       "revision",
+		State:          db.PreviewState_Waiting,
+	}
 )
+
+var addTestHistoryID int64 = 1
@@ -114,6 +114,21 @@ var _ = Describe("Test to populate the database fields", func() {
 			err = dbq.CreateApplicationOwner(ctx, &applicationOwner)
 			Expect(err).To(BeNil())
 
+			By("Create a DeploymentHistory pointing to the Application")
+			deploymentHistory := AddTest_PreDeploymentHistory
+			err = dbq.CreateDeploymentHistory(ctx, &deploymentHistory)
+			Expect(err).To(BeNil())
+
+			By("Create a DeploymentDependencyGate")
+			deploymentDependencyGate := AddTest_PreDeploymentDependencyGate
+			err = dbq.CreateDeploymentDependencyGate(ctx, &deploymentDependencyGate)
+			Expect(err).To(BeNil())
+
+			By("Create a Preview pointing to the Application")
+			preview := AddTest_PrePreview
+			err = dbq.CreatePreview(ctx, &preview)
+			Expect(err).To(BeNil())
+
 		})
 
 	})
@@ -207,6 +207,31 @@ var _ = Describe("Test to verify that the data added to database is still presen
 			err = dbq.GetApplicationOwnerByApplicationID(ctx, &applicationOwner)
 			Expect(err).To(BeNil())
 
+			By("Get DeploymentHistory pointing to the Application")
+			var deploymentHistory []db.DeploymentHistory
+			err = dbq.ListDeploymentHistoryByApplicationId(ctx, applicationDB.Application_id, &deploymentHistory)
+			Expect(err).To(BeNil())
+			Expect(deploymentHistory).To(HaveLen(1))
+			Expect(deploymentHistory[0].Revision).To(Equal(addtestvalues.AddTest_PreDeploymentHistory.Revision))
+
+			By("Get DeploymentDependencyGate")
+			deploymentDependencyGate := db.DeploymentDependencyGate{
+				Deploymentdependencygate_uid_id: addtestvalues.AddTest_PreDeploymentDependencyGate.Deploymentdependencygate_uid_id,
+			}
+			err = dbq.GetDeploymentDependencyGateById(ctx, &deploymentDependencyGate)
+			Expect(err).To(BeNil())
+			Expect(deploymentDependencyGate.State).To(Equal(addtestvalues.AddTest_PreDeploymentDependencyGate.State))
+			Expect(deploymentHistory[0].History_id).To(Equal(addtestvalues.AddTest_PreDeploymentHistory.History_id))
+
+			By("Get Preview pointing to the Application")
+			preview := db.Preview{
+				Preview_id: addtestvalues.AddTest_PrePreview.Preview_id,
+			}
+			err = dbq.GetPreviewById(ctx, &preview)
+			Expect(err).To(BeNil())
+			Expect(preview.Application_id).To(Equal(applicationDB.Application_id))
+			Expect(preview.State).To(Equal(addtestvalues.AddTest_PrePreview.State))
+
 		})
 
 	})
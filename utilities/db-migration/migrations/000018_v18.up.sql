@@ -0,0 +1,35 @@
+-- DeploymentHistory is a record of a successful sync of an Application, by Argo CD.
+-- A bounded number of entries are kept per Application: older entries are removed as new entries are added.
+CREATE TABLE DeploymentHistory (
+
+	-- Primary key for the DeploymentHistory (UID), is a random UUID
+	deploymenthistory_id VARCHAR(48) NOT NULL PRIMARY KEY,
+
+	-- The Application that was synchronized
+	-- Foreign key to: Application.application_id
+	application_id VARCHAR(48) NOT NULL,
+	CONSTRAINT fk_dh_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+
+	-- The revision that was deployed, from Argo CD Application CR's .status.operationState.syncResult.revision field
+	-- - For multi-source Applications, this is a comma-separated list of the revision of each source.
+	revision VARCHAR(1024) NOT NULL,
+
+	-- source is a JSON string, which contains the source (or sources, for multi-source Applications) that was deployed
+	source VARCHAR(4096) NOT NULL,
+
+	-- When the sync operation finished, from Argo CD Application CR's .status.operationState.finishedAt field
+	deployed_at TIMESTAMP NOT NULL,
+
+	-- Who initiated the sync: either 'automated', or the name of the user that requested the sync
+	initiated_by VARCHAR(256),
+
+	-- The name of the GitOpsDeploymentSyncRun CR that requested the sync, if any
+	sync_run_name VARCHAR(256),
+
+	seq_id serial,
+
+	-- When DeploymentHistory was created, which allow us to tell how old the resources are
+	created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Add an index on application_id
+CREATE INDEX idx_deploymenthistory_application_id ON DeploymentHistory(application_id);
@@ -0,0 +1,2 @@
+ALTER TABLE DeploymentHistory DROP COLUMN history_id;
+ALTER TABLE SyncOperation DROP COLUMN rollback_history_id;
@@ -0,0 +1,2 @@
+ALTER TABLE SyncOperation ADD COLUMN rollback_history_id BIGINT;
+ALTER TABLE DeploymentHistory ADD COLUMN history_id BIGINT;
@@ -0,0 +1 @@
+ALTER TABLE SyncOperation ADD COLUMN options VARCHAR (4096);
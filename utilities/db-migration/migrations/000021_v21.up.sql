@@ -0,0 +1,29 @@
+-- DeploymentDependencyGate records whether a GitOpsDeployment that depends on other GitOpsDeployments (via its
+-- .spec.dependsOn field) is still waiting for those dependencies to be synced and healthy, before its Application is
+-- created. Once the dependencies have been satisfied, the gate remains open, even if a dependency later becomes unhealthy.
+CREATE TABLE DeploymentDependencyGate (
+
+	-- uid of our gitops deployment CR within the K8s namespace
+	deploymentdependencygate_uid_id VARCHAR(48) UNIQUE NOT NULL PRIMARY KEY,
+
+	-- name of the GitOpsDeployment CR in the API namespace
+	name VARCHAR ( 256 ) NOT NULL,
+	-- name of the API namespace
+	namespace VARCHAR ( 96 ) NOT NULL,
+	-- uid of the API namespace
+	namespace_uid VARCHAR ( 48 ) NOT NULL,
+
+	-- Whether the dependencies of the GitOpsDeployment have been satisfied: 'Waiting' or 'Satisfied'
+	state VARCHAR ( 32 ) NOT NULL,
+
+	-- Comma-separated list of the names of the dependencies that are not yet synced and healthy, if the state is 'Waiting'
+	pending_dependencies VARCHAR ( 4096 ),
+
+	seq_id serial,
+
+	-- When DeploymentDependencyGate was created, which allow us to tell how old the resources are
+	created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_deploymentdependencygate_1 ON DeploymentDependencyGate(namespace_uid);
+CREATE INDEX idx_deploymentdependencygate_2 ON DeploymentDependencyGate(name, namespace, namespace_uid);
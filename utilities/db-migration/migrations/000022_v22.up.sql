@@ -0,0 +1,38 @@
+-- Preview is a request, from the backend to the cluster-agent, to compute the difference between the live resources
+-- of an Application, and the resources at an alternate revision (for example, of a pull request).
+-- A Preview row only exists for as long as the backend is waiting for the result.
+CREATE TABLE Preview (
+
+	-- Primary key for the Preview (UID), is a random UUID
+	preview_id VARCHAR(48) NOT NULL PRIMARY KEY,
+
+	-- The Application whose live resources are compared
+	-- Foreign key to: Application.application_id
+	application_id VARCHAR(48) NOT NULL,
+	CONSTRAINT fk_pv_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+
+	-- The alternate revision to compare the live resources with, from the GitOpsDeploymentPreview CR's .spec.revision field
+	revision VARCHAR(1024) NOT NULL,
+
+	-- Possible values:
+	-- * Waiting: the cluster-agent has not yet computed the preview
+	-- * Completed: the preview was computed, and is available in the 'resources' field
+	-- * Failed: the preview could not be computed: see the 'message' field for details
+	state VARCHAR(30) NOT NULL,
+
+	-- The revision that 'revision' resolved to (for example, the git commit SHA of a branch), once the Preview is Completed
+	resolved_revision VARCHAR(1024),
+
+	-- If the Preview Failed, the reason it failed
+	message VARCHAR(4096),
+
+	-- Compressed JSON, containing the resources that would be added, changed, or removed by deploying the revision
+	resources bytea,
+
+	seq_id serial,
+
+	-- When Preview was created, which allow us to tell how old the resources are
+	created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Add an index on application_id
+CREATE INDEX idx_preview_application_id ON Preview(application_id);
@@ -0,0 +1,3 @@
+ALTER TABLE ClusterCredentials DROP COLUMN certificate_authority_data;
+ALTER TABLE ClusterCredentials DROP COLUMN client_key_data;
+ALTER TABLE ClusterCredentials DROP COLUMN client_certificate_data;
@@ -0,0 +1,3 @@
+ALTER TABLE ClusterCredentials ADD COLUMN client_certificate_data VARCHAR (16384);
+ALTER TABLE ClusterCredentials ADD COLUMN client_key_data VARCHAR (16384);
+ALTER TABLE ClusterCredentials ADD COLUMN certificate_authority_data VARCHAR (65000);
@@ -0,0 +1 @@
+ALTER TABLE Preview DROP COLUMN resources_truncated;
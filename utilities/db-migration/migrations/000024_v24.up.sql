@@ -0,0 +1 @@
+ALTER TABLE Preview ADD COLUMN resources_truncated BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE DeploymentHistory DROP CONSTRAINT fk_dh_app_id;
+ALTER TABLE DeploymentHistory ADD CONSTRAINT fk_dh_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION;
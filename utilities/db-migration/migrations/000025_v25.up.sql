@@ -0,0 +1,4 @@
+-- Delete the DeploymentHistory rows of an Application when the Application is deleted: a row that is recorded while the
+-- Application is being cleaned up would otherwise prevent the Application from being deleted.
+ALTER TABLE DeploymentHistory DROP CONSTRAINT fk_dh_app_id;
+ALTER TABLE DeploymentHistory ADD CONSTRAINT fk_dh_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE CASCADE ON UPDATE NO ACTION;
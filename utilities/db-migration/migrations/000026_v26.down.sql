@@ -0,0 +1,2 @@
+ALTER TABLE Preview DROP CONSTRAINT fk_pv_app_id;
+ALTER TABLE Preview ADD CONSTRAINT fk_pv_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION;
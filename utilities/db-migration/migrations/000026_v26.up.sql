@@ -0,0 +1,4 @@
+-- Delete the Preview rows of an Application when the Application is deleted: a Preview that is created while the
+-- Application is being cleaned up would otherwise prevent the Application from being deleted.
+ALTER TABLE Preview DROP CONSTRAINT fk_pv_app_id;
+ALTER TABLE Preview ADD CONSTRAINT fk_pv_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE CASCADE ON UPDATE NO ACTION;
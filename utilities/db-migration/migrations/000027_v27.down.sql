@@ -0,0 +1 @@
+ALTER TABLE DeploymentDependencyGate DROP COLUMN dependencies_hash;
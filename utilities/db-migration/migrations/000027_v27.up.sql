@@ -0,0 +1,3 @@
+-- The hash of the .spec.dependsOn of the GitOpsDeployment when the gate was last evaluated: if the dependencies are
+-- modified, the gate is evaluated again, even if the previous dependencies were satisfied.
+ALTER TABLE DeploymentDependencyGate ADD COLUMN dependencies_hash VARCHAR(64);